@@ -117,6 +117,22 @@ func newRunCmd() *cobra.Command {
 		"expected address in the master TLS certificate (if different than the one used for connecting)",
 	)
 
+	// Container runtime flags.
+	cmd.Flags().StringVar(&opts.ContainerRuntime, "container-runtime", "docker",
+		"Container runtime to run tasks with (docker, podman, process)")
+	cmd.Flags().StringVar(&opts.PodmanSocket, "podman-socket", "unix:///run/podman/podman.sock",
+		"Address of the Podman API service used by the podman container runtime")
+
+	// Reconnection flags.
+	cmd.Flags().IntVar(&opts.ReconnectAttempts, "reconnect-attempts", 20,
+		"Number of attempts to reconnect to the master before shutting down the agent")
+	cmd.Flags().IntVar(&opts.ReconnectBackoff, "reconnect-backoff", 30,
+		"Maximum number of seconds to wait between attempts to reconnect to the master")
+
+	// Health check flags.
+	cmd.Flags().IntVar(&opts.HealthCheckInterval, "health-check-interval", 60,
+		"Number of seconds between GPU health checks; 0 disables health checks")
+
 	// Debug flags.
 	cmd.Flags().IntVar(&opts.ArtificialSlots, "artificial-slots", 0, "")
 	cmd.Flags().Lookup("artificial-slots").Hidden = true
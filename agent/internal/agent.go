@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/http/pprof"
 	"os"
+	"reflect"
 	"runtime"
 	"strings"
 	"syscall"
@@ -27,6 +28,7 @@ import (
 	"github.com/determined-ai/determined/master/pkg/actor/actors"
 	"github.com/determined-ai/determined/master/pkg/actor/api"
 	proto "github.com/determined-ai/determined/master/pkg/agent"
+	cproto "github.com/determined-ai/determined/master/pkg/container"
 	"github.com/determined-ai/determined/master/pkg/device"
 	"github.com/determined-ai/determined/master/pkg/logger"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -51,6 +53,28 @@ type agent struct {
 
 	masterProto  string
 	masterClient *http.Client
+
+	// reconnecting is set from a master socket disconnection until the agent has announced its
+	// containers to the master again. Container state changes are held in pendingStateChanges
+	// meanwhile.
+	reconnecting        bool
+	pendingStateChanges []proto.ContainerStateChanged
+
+	// deviceHealth is the health of the devices that was last reported to the master.
+	deviceHealth []proto.DeviceHealth
+}
+
+// reconnectToMaster asks the agent to try to reconnect to the master after its socket went away.
+type reconnectToMaster struct {
+	attempt int
+}
+
+// checkDeviceHealth asks the agent to probe the health of its devices.
+type checkDeviceHealth struct{}
+
+// deviceHealthProbed holds the result of probing the health of the devices of the agent.
+type deviceHealthProbed struct {
+	health []proto.DeviceHealth
 }
 
 func newAgent(version string, options Options) *agent {
@@ -68,8 +92,8 @@ func (a *agent) Receive(ctx *actor.Context) error {
 		switch {
 		case msg.MasterSetAgentOptions != nil:
 			if a.MasterSetAgentOptions != nil {
-				return fmt.Errorf("received MasterStepAgentOptions more than once: %v",
-					*msg.MasterSetAgentOptions)
+				// The master sends its options on every connection; the agent keeps the first ones.
+				return a.reconnected(ctx)
 			}
 			a.MasterSetAgentOptions = msg.MasterSetAgentOptions
 			return a.setup(ctx)
@@ -86,9 +110,12 @@ func (a *agent) Receive(ctx *actor.Context) error {
 		}
 
 	case proto.ContainerStateChanged:
-		if a.socket != nil {
+		switch {
+		case a.reconnecting:
+			a.pendingStateChanges = append(a.pendingStateChanges, msg)
+		case a.socket != nil:
 			ctx.Ask(a.socket, api.WriteMessage{Message: proto.MasterMessage{ContainerStateChanged: &msg}})
-		} else {
+		default:
 			ctx.Log().Warnf("Not sending container state change to the master: %+v", msg)
 		}
 	case proto.ContainerLog:
@@ -97,11 +124,39 @@ func (a *agent) Receive(ctx *actor.Context) error {
 		}
 
 	case model.TrialLog:
-		return a.postTrialLog(msg)
+		if err := a.postTrialLog(msg); err != nil {
+			ctx.Log().WithError(err).Warn("failed to post trial log")
+		}
+
+	case reconnectToMaster:
+		return a.reconnect(ctx, msg.attempt)
+
+	case checkDeviceHealth:
+		// Probe in the background since nvidia-smi can be slow on failing GPUs.
+		self, devices := ctx.Self(), a.Devices
+		go func() {
+			self.System().Tell(self, deviceHealthProbed{health: probeDeviceHealth(devices)})
+		}()
+	case deviceHealthProbed:
+		if !reflect.DeepEqual(msg.health, a.deviceHealth) {
+			for _, h := range msg.health {
+				if !h.Healthy {
+					ctx.Log().Warnf("device %s is unhealthy: %s", h.Device.String(), h.Reason)
+				}
+			}
+			a.deviceHealth = msg.health
+			a.reportDeviceHealth(ctx)
+		}
+		actors.NotifyAfter(ctx, a.healthCheckInterval(), checkDeviceHealth{})
 
 	case actor.ChildFailed:
 		switch msg.Child {
 		case a.socket:
+			if a.canReconnect() {
+				ctx.Log().WithError(msg.Error).Warn("master socket disconnected, reconnecting...")
+				a.socketDisconnected(ctx)
+				return nil
+			}
 			ctx.Log().Warn("master socket disconnected, shutting down agent...")
 		case a.cm:
 			ctx.Log().Warn("container manager failed, shutting down agent...")
@@ -119,6 +174,11 @@ func (a *agent) Receive(ctx *actor.Context) error {
 		return errors.Wrapf(msg.Error, "unexpected child failure: %s", msg.Child.Address())
 
 	case actor.ChildStopped:
+		if msg.Child == a.socket && a.canReconnect() {
+			ctx.Log().Warn("master socket closed, reconnecting...")
+			a.socketDisconnected(ctx)
+			return nil
+		}
 		return errors.Errorf("unexpected child stopped: %s", msg.Child.Address())
 
 	case os.Signal:
@@ -148,6 +208,113 @@ func (a *agent) Receive(ctx *actor.Context) error {
 	return nil
 }
 
+// canReconnect returns whether the agent should try to reconnect to the master instead of shutting
+// down when its socket goes away. Only an agent that has been set up by the master reconnects.
+func (a *agent) canReconnect() bool {
+	return a.ReconnectAttempts > 0 && a.MasterSetAgentOptions != nil
+}
+
+// socketDisconnected keeps the containers of the agent running and schedules the first attempt
+// to reconnect to the master.
+func (a *agent) socketDisconnected(ctx *actor.Context) {
+	a.socket = nil
+	a.reconnecting = true
+	actors.NotifyAfter(ctx, reconnectBackoff(0, a.maxReconnectBackoff()), reconnectToMaster{})
+}
+
+// reconnect tries to reconnect to the master, retrying with exponential backoff until the
+// configured number of attempts is exhausted.
+func (a *agent) reconnect(ctx *actor.Context, attempt int) error {
+	err := a.makeMasterWebsocket(ctx)
+	if err == nil {
+		ctx.Log().Infof("reconnected to master after %d attempts", attempt+1)
+		return nil
+	}
+	if attempt+1 >= a.ReconnectAttempts {
+		return errors.Wrapf(err, "failed to reconnect to master after %d attempts", attempt+1)
+	}
+	backoff := reconnectBackoff(attempt+1, a.maxReconnectBackoff())
+	ctx.Log().WithError(err).Warnf("failed to reconnect to master, retrying in %s", backoff)
+	actors.NotifyAfter(ctx, backoff, reconnectToMaster{attempt: attempt + 1})
+	return nil
+}
+
+func (a *agent) maxReconnectBackoff() time.Duration {
+	return time.Duration(a.ReconnectBackoff) * time.Second
+}
+
+// reconnectBackoff returns how long to wait before the given reconnection attempt: one second
+// before the first one, doubling for each attempt up to max.
+func reconnectBackoff(attempt int, max time.Duration) time.Duration {
+	if attempt > 16 {
+		return max
+	}
+	backoff := time.Duration(math.Pow(2, float64(attempt))) * time.Second
+	if backoff > max {
+		return max
+	}
+	return backoff
+}
+
+// reconnected sends the container state changes that the master missed while the agent was
+// disconnected and announces the containers that are still running, so that the master can
+// re-adopt them.
+func (a *agent) reconnected(ctx *actor.Context) error {
+	for _, msg := range a.pendingStateChanges {
+		msg := msg
+		ctx.Ask(a.socket, api.WriteMessage{Message: proto.MasterMessage{ContainerStateChanged: &msg}})
+	}
+	a.pendingStateChanges = nil
+
+	containers, ok := ctx.Ask(a.cm, listContainers{}).Get().([]cproto.Container)
+	if !ok {
+		return errors.New("failed to list the running containers")
+	}
+	a.reconnecting = false
+	a.announce(ctx, containers)
+	ctx.Log().Infof("announced %d running containers to the master", len(containers))
+	a.reportDeviceHealth(ctx)
+	return nil
+}
+
+func (a *agent) healthCheckInterval() time.Duration {
+	return time.Duration(a.HealthCheckInterval) * time.Second
+}
+
+// startHealthChecks starts probing the health of the GPUs of the agent periodically.
+func (a *agent) startHealthChecks(ctx *actor.Context) {
+	if a.HealthCheckInterval == 0 {
+		return
+	}
+	for _, d := range a.Devices {
+		if d.Type == device.GPU {
+			ctx.Tell(ctx.Self(), checkDeviceHealth{})
+			return
+		}
+	}
+}
+
+// reportDeviceHealth tells the master about the health of the devices of the agent, unless the
+// agent is disconnected; it reports the health again once it reconnects.
+func (a *agent) reportDeviceHealth(ctx *actor.Context) {
+	if a.socket == nil || a.reconnecting || len(a.deviceHealth) == 0 {
+		return
+	}
+	ctx.Ask(a.socket, api.WriteMessage{Message: proto.MasterMessage{
+		DevicesHealthChanged: &proto.DevicesHealthChanged{Devices: a.deviceHealth},
+	}})
+}
+
+// announce tells the master about the devices and running containers of the agent.
+func (a *agent) announce(ctx *actor.Context, containers []cproto.Container) {
+	ctx.Ask(a.socket, api.WriteMessage{Message: proto.MasterMessage{AgentStarted: &proto.AgentStarted{
+		Version:    a.Version,
+		Devices:    a.Devices,
+		Label:      a.Label,
+		Containers: containers,
+	}}})
+}
+
 func (a *agent) restartFluent(ctx *actor.Context) error {
 	i := 0
 	for {
@@ -343,11 +510,21 @@ func (a *agent) connect(ctx *actor.Context) error {
 }
 
 func (a *agent) setup(ctx *actor.Context) error {
-	fluentActor, err := newFluentActor(a.Options, *a.MasterSetAgentOptions)
+	runtime, err := newContainerRuntime(a.Options)
 	if err != nil {
-		return errors.Wrap(err, "failed to start Fluent daemon")
+		return errors.Wrapf(err, "error connecting to container runtime %s", a.ContainerRuntime)
+	}
+
+	// Containers of runtimes without Fluent logging send their logs through the agent instead.
+	fluentPort := 0
+	if runtime.SupportsFluentLogging() {
+		fluentActor, fErr := newFluentActor(a.Options, *a.MasterSetAgentOptions)
+		if fErr != nil {
+			return errors.Wrap(fErr, "failed to start Fluent daemon")
+		}
+		a.fluent, _ = ctx.ActorOf("fluent", fluentActor)
+		fluentPort = fluentActor.port
 	}
-	a.fluent, _ = ctx.ActorOf("fluent", fluentActor)
 
 	if err = a.detect(); err != nil {
 		return err
@@ -372,17 +549,14 @@ func (a *agent) setup(ctx *actor.Context) error {
 		}
 	}
 
-	cm, err := newContainerManager(a, fluentActor.port)
+	cm, err := newContainerManager(a, fluentPort, runtime)
 	if err != nil {
 		return errors.Wrap(err, "error initializing container manager")
 	}
 	a.cm, _ = ctx.ActorOf("containers", cm)
 
-	ctx.Ask(a.socket, api.WriteMessage{Message: proto.MasterMessage{AgentStarted: &proto.AgentStarted{
-		Version: a.Version,
-		Devices: a.Devices,
-		Label:   a.Label,
-	}}})
+	a.announce(ctx, nil)
+	a.startHealthChecks(ctx)
 	return nil
 }
 
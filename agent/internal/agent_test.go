@@ -3,6 +3,7 @@ package internal
 import (
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types/container"
 	"github.com/golang-collections/collections/set"
@@ -74,6 +75,21 @@ func TestAlreadyAddedProxy(t *testing.T) {
 	}
 }
 
+func TestReconnectBackoff(t *testing.T) {
+	max := 30 * time.Second
+	expected := []time.Duration{
+		time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, max, max,
+	}
+	for attempt, backoff := range expected {
+		if actual := reconnectBackoff(attempt, max); actual != backoff {
+			t.Errorf("attempt %d: expected %s but got %s", attempt, backoff, actual)
+		}
+	}
+	if actual := reconnectBackoff(100, max); actual != max {
+		t.Errorf("expected %s but got %s", max, actual)
+	}
+}
+
 func compareSlices(env []string, ans []string) bool {
 	output := set.New()
 	correct := set.New()
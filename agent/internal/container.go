@@ -10,7 +10,6 @@ import (
 	"time"
 
 	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/client"
 	"github.com/labstack/echo"
 	"github.com/pkg/errors"
 
@@ -29,11 +28,14 @@ const (
 type containerActor struct {
 	cproto.Container
 	spec          *cproto.Spec
-	client        *client.Client
-	docker        *actor.Ref
+	runtime       containerRuntime
+	runtimeActor  *actor.Ref
+	runtimeID     string
 	containerInfo *types.ContainerJSON
 
-	baseTrialLog model.TrialLog
+	// useFluentLogging is kept from the spec, which is evicted once the container starts.
+	useFluentLogging bool
+	baseTrialLog     model.TrialLog
 }
 
 type (
@@ -41,8 +43,8 @@ type (
 	containerReady      struct{}
 )
 
-func newContainerActor(msg aproto.StartContainer, client *client.Client) actor.Actor {
-	return &containerActor{Container: msg.Container, spec: &msg.Spec, client: client}
+func newContainerActor(msg aproto.StartContainer, runtime containerRuntime) actor.Actor {
+	return &containerActor{Container: msg.Container, spec: &msg.Spec, runtime: runtime}
 }
 
 // getExtraFluentValues computes the container-specific extra fields to be injected into each Fluent
@@ -73,10 +75,11 @@ func getBaseTrialLog(spec *cproto.Spec) model.TrialLog {
 func (c *containerActor) Receive(ctx *actor.Context) error {
 	switch msg := ctx.Message().(type) {
 	case actor.PreStart:
-		c.docker, _ = ctx.ActorOf("docker", &dockerActor{Client: c.client, spec: c.spec})
+		c.runtimeActor, _ = ctx.ActorOf("runtime", &runtimeActor{runtime: c.runtime})
 		c.transition(ctx, cproto.Pulling)
 		pull := pullImage{PullSpec: c.spec.PullSpec, Name: c.spec.RunSpec.ContainerConfig.Image}
-		ctx.Tell(c.docker, pull)
+		ctx.Tell(c.runtimeActor, pull)
+		c.useFluentLogging = c.spec.RunSpec.UseFluentLogging
 		c.baseTrialLog = getBaseTrialLog(c.spec)
 
 	case getContainerSummary:
@@ -84,9 +87,10 @@ func (c *containerActor) Receive(ctx *actor.Context) error {
 
 	case imagePulled:
 		c.transition(ctx, cproto.Starting)
-		ctx.Tell(c.docker, c.spec.RunSpec)
+		ctx.Tell(c.runtimeActor, c.spec.RunSpec)
 
 	case containerStarted:
+		c.runtimeID = msg.runtimeID
 		c.containerInfo = &msg.containerInfo
 
 		if len(c.spec.RunSpec.ChecksConfig.Checks) == 0 {
@@ -127,7 +131,7 @@ func (c *containerActor) Receive(ctx *actor.Context) error {
 			ctx.Tell(ctx.Self(), msg)
 		case cproto.Running:
 			ctx.Log().Infof("sending signal to container: %s", msg.Signal)
-			ctx.Tell(c.docker, signalContainer{dockerID: c.containerInfo.ID, signal: msg.Signal})
+			ctx.Tell(c.runtimeActor, signalContainer{runtimeID: c.runtimeID, signal: msg.Signal})
 		case cproto.Terminated:
 			ctx.Log().Warnf("ignoring signal, container already terminated: %s", msg.Signal)
 		}
@@ -135,7 +139,7 @@ func (c *containerActor) Receive(ctx *actor.Context) error {
 	case aproto.ContainerLog:
 		msg.Container = c.Container
 		ctx.Log().Debug(msg)
-		if c.spec.RunSpec.UseFluentLogging {
+		if c.useFluentLogging {
 			ctx.Tell(ctx.Self().Parent(), c.makeTrialLog(msg))
 		} else {
 			ctx.Tell(ctx.Self().Parent(), msg)
@@ -146,7 +150,7 @@ func (c *containerActor) Receive(ctx *actor.Context) error {
 		c.containerStopped(ctx, aproto.ContainerError(aproto.ContainerFailed, msg.Error))
 		return msg.Error
 
-	case dockerErr:
+	case runtimeErr:
 		c.containerStopped(ctx, aproto.ContainerError(aproto.ContainerFailed, msg.Error))
 		return msg.Error
 
@@ -155,7 +159,7 @@ func (c *containerActor) Receive(ctx *actor.Context) error {
 
 	case actor.PostStop:
 		if c.State == cproto.Running {
-			ctx.Log().Infof("disconnecting from container: %s", c.containerInfo.ID)
+			ctx.Log().Infof("disconnecting from container: %s", c.runtimeID)
 		} else {
 			ctx.Log().Info("container stopped")
 		}
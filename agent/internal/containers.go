@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	dcontainer "github.com/docker/docker/api/types/container"
-	"github.com/docker/docker/client"
 	"github.com/labstack/echo"
 	"github.com/pkg/errors"
 
@@ -29,6 +28,9 @@ const (
 	dockerMasterLabel           = "ai.determined.container.master"
 )
 
+// listContainers asks the container manager for the containers that have not terminated.
+type listContainers struct{}
+
 type containerManager struct {
 	Options       Options           `json:"-"`
 	MasterInfo    proto.MasterInfo  `json:"-"`
@@ -37,27 +39,24 @@ type containerManager struct {
 	Devices       []device.Device   `json:"devices"`
 
 	fluentPort int
-	docker     *client.Client
+	runtime    containerRuntime
 }
 
-func newContainerManager(a *agent, fluentPort int) (*containerManager, error) {
+func newContainerManager(
+	a *agent, fluentPort int, runtime containerRuntime,
+) (*containerManager, error) {
 	return &containerManager{
 		MasterInfo: a.MasterSetAgentOptions.MasterInfo,
 		Options:    a.Options,
 		Devices:    a.Devices,
 		fluentPort: fluentPort,
+		runtime:    runtime,
 	}, nil
 }
 
 func (c *containerManager) Receive(ctx *actor.Context) error {
 	switch msg := ctx.Message().(type) {
 	case actor.PreStart:
-		d, err := client.NewClientWithOpts(client.FromEnv)
-		if err != nil {
-			return err
-		}
-		c.docker = d
-
 		masterScheme := httpInsecureScheme
 		if c.Options.Security.TLS.Enabled {
 			masterScheme = httpSecureScheme
@@ -99,7 +98,7 @@ func (c *containerManager) Receive(ctx *actor.Context) error {
 
 	case proto.StartContainer:
 		msg.Spec = c.overwriteSpec(msg.Container, msg.Spec)
-		if ref, ok := ctx.ActorOf(msg.Container.ID, newContainerActor(msg, c.docker)); !ok {
+		if ref, ok := ctx.ActorOf(msg.Container.ID, newContainerActor(msg, c.runtime)); !ok {
 			ctx.Log().Warnf("container already created: %s", msg.Container.ID)
 			if ctx.ExpectingResponse() {
 				ctx.Respond(errors.Errorf("container already created: %s", msg.Container.ID))
@@ -108,6 +107,15 @@ func (c *containerManager) Receive(ctx *actor.Context) error {
 			ctx.Respond(ctx.Ask(ref, getContainerSummary{}))
 		}
 
+	case listContainers:
+		var containers []cproto.Container
+		for _, result := range ctx.AskAll(getContainerSummary{}, ctx.Children()...).GetAll() {
+			if c, ok := result.(cproto.Container); ok && c.State != cproto.Terminated {
+				containers = append(containers, c)
+			}
+		}
+		ctx.Respond(containers)
+
 	case proto.SignalContainer:
 		if ref := ctx.Child(msg.ContainerID); ref != nil {
 			ctx.Tell(ref, msg)
@@ -139,6 +147,9 @@ func (c *containerManager) handleAPIRequest(ctx *actor.Context, apiCtx echo.Cont
 }
 
 func (c *containerManager) overwriteSpec(cont cproto.Container, spec cproto.Spec) cproto.Spec {
+	if !c.runtime.SupportsFluentLogging() {
+		spec.RunSpec.UseFluentLogging = false
+	}
 	return overwriteSpec(cont, spec, c.GlobalEnvVars, c.Labels, c.fluentPort)
 }
 
@@ -93,6 +93,13 @@ func detectGPUs(visibleGPUs string) ([]device.Device, error) {
 		record, err := r.Read()
 		switch {
 		case err == io.EOF:
+			topology, err := getNvidiaTopology()
+			if err != nil {
+				return nil, err
+			}
+			for i := range devices {
+				devices[i].Topology = topology[devices[i].ID]
+			}
 			return devices, nil
 		case err != nil:
 			return nil, errors.Wrap(err, "error parsing output of nvidia-smi as CSV")
@@ -17,39 +17,26 @@ import (
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/sys/unix"
 
-	"github.com/determined-ai/determined/master/pkg/actor"
 	aproto "github.com/determined-ai/determined/master/pkg/agent"
 	"github.com/determined-ai/determined/master/pkg/archive"
 	"github.com/determined-ai/determined/master/pkg/container"
 )
 
-type dockerActor struct {
+// dockerRuntime runs containers through the Docker Engine API.
+type dockerRuntime struct {
 	*client.Client
-	credentialStores map[string]*credentialStore
-	spec             *container.Spec
 }
 
-type (
-	signalContainer struct {
-		dockerID string
-		signal   syscall.Signal
-	}
-	pullImage struct {
-		container.PullSpec
-		Name string
-	}
-	imagePulled      struct{}
-	containerStarted struct {
-		dockerID      string
-		containerInfo types.ContainerJSON
-	}
-	containerTerminated struct {
-		ExitCode int64
+func newDockerRuntime() (*dockerRuntime, error) {
+	d, err := client.NewClientWithOpts(client.FromEnv)
+	if err != nil {
+		return nil, err
 	}
-	dockerErr struct{ Error error }
-)
+	return &dockerRuntime{Client: d}, nil
+}
 
 // registryToString converts the Registry struct to a base64 encoding for json strings.
 func registryToString(reg types.AuthConfig) (string, error) {
@@ -61,86 +48,48 @@ func registryToString(reg types.AuthConfig) (string, error) {
 	return base64.URLEncoding.EncodeToString(bs), nil
 }
 
-func (d *dockerActor) Receive(ctx *actor.Context) error {
-	switch msg := ctx.Message().(type) {
-	case actor.PreStart:
-		stores, err := getAllCredentialStores()
-		if err != nil {
-			ctx.Log().Info(fmt.Sprintf(
-				"can't find any docker credential stores, continuing without them %v", err))
-		}
-		d.credentialStores = stores
-
-	case pullImage:
-		go d.pullImage(ctx, msg)
-
-	case container.RunSpec:
-		go d.runContainer(ctx, msg)
-
-	case signalContainer:
-		go d.signalContainer(ctx, msg)
-
-	case actor.PostStop:
-	}
-	return nil
+// SupportsFluentLogging implements containerRuntime.
+func (d *dockerRuntime) SupportsFluentLogging() bool {
+	return true
 }
 
-func (d *dockerActor) pullImage(ctx *actor.Context, msg pullImage) {
+// PullImage implements containerRuntime.
+func (d *dockerRuntime) PullImage(ctx context.Context, msg pullImage, logs logSink) error {
 	ref, err := reference.ParseNormalizedNamed(msg.Name)
 	if err != nil {
-		sendErr(ctx, errors.Wrapf(err, "error parsing image name: %s", msg.Name))
-		return
+		return errors.Wrapf(err, "error parsing image name: %s", msg.Name)
 	}
 	ref = reference.TagNameOnly(ref)
 
-	_, _, err = d.ImageInspectWithRaw(context.Background(), ref.String())
+	_, _, err = d.ImageInspectWithRaw(ctx, ref.String())
 	switch {
 	case err == nil && msg.ForcePull:
-		d.sendAuxLog(ctx, fmt.Sprintf("attempting to remove cached image: %s", ref.String()))
+		logs(auxLog(fmt.Sprintf("attempting to remove cached image: %s", ref.String())))
 		opts := types.ImageRemoveOptions{Force: true, PruneChildren: false}
-		removed, rerr := d.ImageRemove(context.Background(), ref.String(), opts)
+		removed, rerr := d.ImageRemove(ctx, ref.String(), opts)
 		if rerr != nil {
-			sendErr(ctx, errors.Wrapf(rerr, "error removing image: %s", ref.String()))
-			return
+			return errors.Wrapf(rerr, "error removing image: %s", ref.String())
 		}
 		for _, r := range removed {
 			switch {
 			case r.Untagged != "":
-				d.sendAuxLog(ctx, fmt.Sprintf("untagged image: %s", r.Untagged))
+				logs(auxLog(fmt.Sprintf("untagged image: %s", r.Untagged)))
 			case r.Deleted != "":
-				d.sendAuxLog(ctx, fmt.Sprintf("deleted image: %s", r.Deleted))
+				logs(auxLog(fmt.Sprintf("deleted image: %s", r.Deleted)))
 			}
 		}
 	case err == nil:
-		d.sendAuxLog(ctx, fmt.Sprintf("image already found, skipping pull phase: %s", ref.String()))
-		ctx.Tell(ctx.Sender(), imagePulled{})
-		return
+		logs(auxLog(fmt.Sprintf("image already found, skipping pull phase: %s", ref.String())))
+		return nil
 	case client.IsErrNotFound(err):
-		d.sendAuxLog(ctx, fmt.Sprintf("image not found, pulling image: %s", ref.String()))
+		logs(auxLog(fmt.Sprintf("image not found, pulling image: %s", ref.String())))
 	default:
-		sendErr(ctx, errors.Wrapf(err, "error checking if image exists: %s", ref.String()))
-		return
+		return errors.Wrapf(err, "error checking if image exists: %s", ref.String())
 	}
 
-	// TODO: replace with command.EncodeAuthToBase64
-	reg := ""
-	if msg.Registry != nil {
-		if reg, err = registryToString(*msg.Registry); err != nil {
-			sendErr(ctx, errors.Wrap(err, "error encoding registry credentials"))
-			return
-		}
-	} else if store, ok := d.credentialStores[reference.Domain(ref)]; ok {
-		var creds types.AuthConfig
-		creds, err = store.get()
-		if err != nil {
-			sendErr(ctx, errors.Wrap(err, "unable to get credentials from helper"))
-			return
-		}
-		reg, err = registryToString(creds)
-		if err != nil {
-			sendErr(ctx, errors.Wrap(err, "error encoding registry credentials from helper"))
-			return
-		}
+	reg, err := registryAuth(ref, msg.Registry)
+	if err != nil {
+		return err
 	}
 
 	opts := types.ImagePullOptions{
@@ -148,130 +97,147 @@ func (d *dockerActor) pullImage(ctx *actor.Context, msg pullImage) {
 		RegistryAuth: reg,
 	}
 
-	logs, err := d.ImagePull(context.Background(), ref.String(), opts)
+	pullLogs, err := d.ImagePull(ctx, ref.String(), opts)
 	if err != nil {
-		sendErr(ctx, errors.Wrapf(err, "error pulling image: %s", ref.String()))
-		return
+		return errors.Wrapf(err, "error pulling image: %s", ref.String())
 	}
 
-	if err = d.sendPullLogs(ctx, logs); err != nil {
-		sendErr(ctx, errors.Wrap(err, "error parsing log stream"))
-		return
+	if err = sendPullLogs(pullLogs, logs); err != nil {
+		return errors.Wrap(err, "error parsing log stream")
 	}
-	if err = logs.Close(); err != nil {
-		sendErr(ctx, errors.Wrap(err, "error closing log stream"))
-		return
+	if err = pullLogs.Close(); err != nil {
+		return errors.Wrap(err, "error closing log stream")
 	}
-	ctx.Tell(ctx.Sender(), imagePulled{})
+	return nil
 }
 
-func (d *dockerActor) runContainer(ctx *actor.Context, msg container.RunSpec) {
-	if !d.spec.RunSpec.UseFluentLogging {
-		msg.HostConfig.AutoRemove = false
+// registryAuth returns the encoded credentials to pull the image with: the registry credentials
+// of the pull spec if there are any, or else those of the Docker credential helper for the
+// registry of the image.
+func registryAuth(ref reference.Named, registry *types.AuthConfig) (string, error) {
+	// TODO: replace with command.EncodeAuthToBase64
+	if registry != nil {
+		reg, err := registryToString(*registry)
+		if err != nil {
+			return "", errors.Wrap(err, "error encoding registry credentials")
+		}
+		return reg, nil
 	}
 
-	response, err := d.ContainerCreate(
-		context.Background(), &msg.ContainerConfig, &msg.HostConfig, &msg.NetworkingConfig, "")
+	stores, err := getAllCredentialStores()
 	if err != nil {
-		sendErr(ctx, errors.Wrap(err, "error creating container"))
-		return
+		logrus.Info(fmt.Sprintf(
+			"can't find any docker credential stores, continuing without them %v", err))
 	}
-	containerID := response.ID
-	for _, w := range response.Warnings {
-		d.sendAuxLog(ctx, fmt.Sprintf("warning when creating container: %s", w))
+	store, ok := stores[reference.Domain(ref)]
+	if !ok {
+		return "", nil
 	}
-
-	if !d.spec.RunSpec.UseFluentLogging {
-		defer func() {
-			if err = d.Client.ContainerRemove(
-				context.Background(), containerID, types.ContainerRemoveOptions{},
-			); err != nil {
-				sendErr(ctx, errors.Wrap(err, "error removing container"))
-			}
-		}()
+	creds, err := store.get()
+	if err != nil {
+		return "", errors.Wrap(err, "unable to get credentials from helper")
 	}
-
-	for _, copyArx := range msg.Archives {
-		d.sendAuxLog(ctx, fmt.Sprintf("copying files to container: %s", copyArx.Path))
-		files, aerr := archive.ToIOReader(copyArx.Archive)
-		if aerr != nil {
-			sendErr(ctx, errors.Wrap(aerr, "error converting RunSpec Archive files to io.Reader"))
-			return
-		}
-		if cerr := d.CopyToContainer(
-			context.Background(),
-			containerID,
-			copyArx.Path,
-			files,
-			copyArx.CopyOptions,
-		); cerr != nil {
-			sendErr(ctx, errors.Wrap(cerr, "error copying files to container"))
-			return
-		}
+	reg, err := registryToString(creds)
+	if err != nil {
+		return "", errors.Wrap(err, "error encoding registry credentials from helper")
 	}
+	return reg, nil
+}
 
-	exit, eerr := d.ContainerWait(
-		context.Background(), containerID, dcontainer.WaitConditionNextExit)
-
-	if err = d.ContainerStart(context.Background(), containerID,
-		types.ContainerStartOptions{}); err != nil {
-		sendErr(ctx, errors.Wrap(err, "error starting container"))
-		return
+// CreateContainer implements containerRuntime.
+func (d *dockerRuntime) CreateContainer(
+	ctx context.Context, spec container.RunSpec, logs logSink,
+) (string, error) {
+	if !spec.UseFluentLogging {
+		// The container is removed once its logs are read.
+		spec.HostConfig.AutoRemove = false
 	}
 
-	// If we specified a port to expose but not the host port to bind, Docker assigns an arbitrary host
-	// port, which we ask for here. (If we did specify a host port, this gives the same one back.)
-	containerInfo, err := d.ContainerInspect(context.Background(), containerID)
+	response, err := d.ContainerCreate(
+		ctx, &spec.ContainerConfig, &spec.HostConfig, &spec.NetworkingConfig, "")
 	if err != nil {
-		sendErr(ctx, errors.Wrap(err, "error inspecting container"))
-		return
+		return "", errors.Wrap(err, "error creating container")
 	}
+	for _, w := range response.Warnings {
+		logs(auxLog(fmt.Sprintf("warning when creating container: %s", w)))
+	}
+	return response.ID, nil
+}
 
-	ctx.Tell(
-		ctx.Sender(),
-		containerStarted{dockerID: response.ID, containerInfo: containerInfo},
-	)
-
-	if !d.spec.RunSpec.UseFluentLogging {
-		if lerr := trackLogs(ctx, d.Client, containerID, ctx.Sender()); lerr != nil {
-			sendErr(ctx, lerr)
-		}
+// CopyArchive implements containerRuntime.
+func (d *dockerRuntime) CopyArchive(
+	ctx context.Context, id string, arx container.RunArchive,
+) error {
+	files, err := archive.ToIOReader(arx.Archive)
+	if err != nil {
+		return errors.Wrap(err, "error converting RunSpec Archive files to io.Reader")
 	}
-	select {
-	case err = <-eerr:
-		sendErr(ctx, errors.Wrap(err, "error while waiting for container to exit"))
-	case exit := <-exit:
-		ctx.Tell(ctx.Sender(), containerTerminated{ExitCode: exit.StatusCode})
+	if err = d.CopyToContainer(ctx, id, arx.Path, files, arx.CopyOptions); err != nil {
+		return errors.Wrap(err, "error copying files to container")
 	}
+	return nil
 }
 
-func (d *dockerActor) signalContainer(ctx *actor.Context, msg signalContainer) {
-	err := d.ContainerKill(context.Background(), msg.dockerID, unix.SignalName(msg.signal))
-	if err != nil {
-		sendErr(ctx, errors.Wrap(err, "error while killing container"))
-		return
+// StartContainer implements containerRuntime.
+func (d *dockerRuntime) StartContainer(
+	ctx context.Context, id string,
+) (<-chan containerExit, error) {
+	// Wait for the container before starting it so that its exit cannot be missed.
+	waitCtx, cancel := context.WithCancel(context.Background())
+	exit, eerr := d.ContainerWait(waitCtx, id, dcontainer.WaitConditionNextExit)
+
+	if err := d.ContainerStart(ctx, id, types.ContainerStartOptions{}); err != nil {
+		cancel()
+		return nil, err
 	}
+
+	exits := make(chan containerExit, 1)
+	go func() {
+		defer cancel()
+		select {
+		case err := <-eerr:
+			exits <- containerExit{Err: err}
+		case exit := <-exit:
+			exits <- containerExit{ExitCode: exit.StatusCode}
+		}
+	}()
+	return exits, nil
 }
 
-func sendErr(ctx *actor.Context, err error) {
-	ctx.Tell(ctx.Sender(), dockerErr{Error: err})
+// InspectContainer implements containerRuntime.
+func (d *dockerRuntime) InspectContainer(
+	ctx context.Context, id string,
+) (types.ContainerJSON, error) {
+	// If we specified a port to expose but not the host port to bind, Docker assigns an arbitrary host
+	// port, which we ask for here. (If we did specify a host port, this gives the same one back.)
+	return d.ContainerInspect(ctx, id)
 }
 
-func (d *dockerActor) sendAuxLog(ctx *actor.Context, msg string) {
-	ctx.Tell(ctx.Sender(), aproto.ContainerLog{
-		Timestamp:  time.Now(),
-		AuxMessage: &msg,
-	})
+// FollowLogs implements containerRuntime.
+func (d *dockerRuntime) FollowLogs(ctx context.Context, id string, logs logSink) error {
+	return trackLogs(d.Client, id, logs)
+}
+
+// SignalContainer implements containerRuntime.
+func (d *dockerRuntime) SignalContainer(
+	ctx context.Context, id string, signal syscall.Signal,
+) error {
+	return d.ContainerKill(ctx, id, unix.SignalName(signal))
 }
 
-func (d *dockerActor) sendPullLogs(ctx *actor.Context, r io.Reader) error {
+// RemoveContainer implements containerRuntime.
+func (d *dockerRuntime) RemoveContainer(ctx context.Context, id string) error {
+	return d.ContainerRemove(ctx, id, types.ContainerRemoveOptions{})
+}
+
+func sendPullLogs(r io.Reader, logs logSink) error {
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		log := jsonmessage.JSONMessage{}
 		if err := json.Unmarshal(scanner.Bytes(), &log); err != nil {
 			return errors.Wrapf(err, "error parsing log message: %#v", log)
 		}
-		ctx.Tell(ctx.Sender(), aproto.ContainerLog{
+		logs(aproto.ContainerLog{
 			Timestamp:   time.Now(),
 			PullMessage: &log,
 		})
@@ -280,13 +246,12 @@ func (d *dockerActor) sendPullLogs(ctx *actor.Context, r io.Reader) error {
 }
 
 type demultiplexer struct {
-	ctx       *actor.Context
-	stdType   stdcopy.StdType
-	recipient *actor.Ref
+	stdType stdcopy.StdType
+	logs    logSink
 }
 
 func (d demultiplexer) Write(p []byte) (n int, err error) {
-	d.ctx.Tell(d.recipient, aproto.ContainerLog{
+	d.logs(aproto.ContainerLog{
 		Timestamp: time.Now(),
 		RunMessage: &aproto.RunMessage{
 			Value:   string(p),
@@ -296,10 +261,8 @@ func (d demultiplexer) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
-func trackLogs(
-	ctx *actor.Context, docker *client.Client, containerID string, recipient *actor.Ref,
-) error {
-	logs, lErr := docker.ContainerLogs(
+func trackLogs(docker *client.Client, containerID string, logs logSink) error {
+	containerLogs, lErr := docker.ContainerLogs(
 		context.Background(),
 		containerID,
 		types.ContainerLogsOptions{
@@ -316,12 +279,12 @@ func trackLogs(
 		return errors.Wrap(lErr, "error grabbing container logs")
 	}
 
-	stdout := demultiplexer{ctx: ctx, stdType: stdcopy.Stdout, recipient: recipient}
-	stderr := demultiplexer{ctx: ctx, stdType: stdcopy.Stderr, recipient: recipient}
-	if _, lErr = stdcopy.StdCopy(stdout, stderr, logs); lErr != nil {
+	stdout := demultiplexer{stdType: stdcopy.Stdout, logs: logs}
+	stderr := demultiplexer{stdType: stdcopy.Stderr, logs: logs}
+	if _, lErr = stdcopy.StdCopy(stdout, stderr, containerLogs); lErr != nil {
 		return errors.Wrap(lErr, "error scanning logs")
 	}
-	if lErr = logs.Close(); lErr != nil {
+	if lErr = containerLogs.Close(); lErr != nil {
 		return errors.Wrap(lErr, "error closing log stream")
 	}
 	return nil
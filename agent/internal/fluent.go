@@ -301,7 +301,8 @@ func (f *fluentActor) trackLogs(ctx *actor.Context) {
 	exitChan, errChan := f.docker.ContainerWait(
 		context.Background(), f.containerID, container.WaitConditionNotRunning)
 
-	if err := trackLogs(ctx, f.docker, f.containerID, ctx.Self()); err != nil {
+	logs := func(log aproto.ContainerLog) { ctx.Tell(ctx.Self(), log) }
+	if err := trackLogs(f.docker, f.containerID, logs); err != nil {
 		ctx.Log().Errorf("error tracking Fluent Bit logs: %s", err)
 	}
 	// This message also allows us to synchronize with the buffer before dumping logs.
@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"fmt"
+
+	proto "github.com/determined-ai/determined/master/pkg/agent"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+// probeDeviceHealth probes the health of the GPUs among the given devices. A GPU is unhealthy if
+// nvidia-smi cannot see it anymore, e.g. because it fell off the bus, or if it reports
+// uncorrectable ECC errors. Other devices are not probed.
+func probeDeviceHealth(devices []device.Device) []proto.DeviceHealth {
+	var gpus []device.Device
+	for _, d := range devices {
+		if d.Type == device.GPU {
+			gpus = append(gpus, d)
+		}
+	}
+	if len(gpus) == 0 {
+		return nil
+	}
+
+	eccErrors, err := getNvidiaHealth()
+	health := make([]proto.DeviceHealth, 0, len(gpus))
+	for _, d := range gpus {
+		h := proto.DeviceHealth{Device: d, Healthy: true}
+		switch errs, ok := eccErrors[d.UUID]; {
+		case err != nil:
+			h.Healthy, h.Reason = false, err.Error()
+		case !ok:
+			h.Healthy, h.Reason = false, "GPU is no longer visible to nvidia-smi"
+		case errs > 0:
+			h.Healthy, h.Reason = false, fmt.Sprintf("GPU reported %d uncorrectable ECC errors", errs)
+		}
+		health = append(health, h)
+	}
+	return health
+}
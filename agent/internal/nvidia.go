@@ -1,13 +1,18 @@
 package internal
 
 import (
+	"context"
 	"encoding/csv"
 	"io"
 	"os/exec"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/pkg/device"
 )
 
 const (
@@ -39,3 +44,182 @@ func getNvidiaVersion() (string, error) {
 	}
 	return record[0], nil
 }
+
+var nvidiaHealthArgs = []string{
+	"nvidia-smi", "--query-gpu=uuid,ecc.errors.uncorrected.volatile.total", "--format=csv,noheader",
+}
+
+// nvidiaHealthTimeout bounds how long nvidia-smi may take to report the health of the GPUs, since
+// it can hang on GPUs that are failing.
+const nvidiaHealthTimeout = time.Minute
+
+// getNvidiaHealth returns the number of uncorrectable ECC errors of the GPUs that nvidia-smi can
+// see by their UUID.
+func getNvidiaHealth() (map[string]int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), nvidiaHealthTimeout)
+	defer cancel()
+
+	// #nosec G204
+	cmd := exec.CommandContext(ctx, nvidiaHealthArgs[0], nvidiaHealthArgs[1:]...)
+	out, err := cmd.Output()
+
+	health := parseNvidiaHealth(string(out))
+	switch {
+	case ctx.Err() != nil:
+		return nil, errors.New("nvidia-smi timed out")
+	case err != nil && len(health) == 0:
+		return nil, errors.Wrapf(
+			err, "error while executing nvidia-smi: %s", strings.TrimSpace(string(out)))
+	}
+	// nvidia-smi fails when some GPUs are lost but still reports the others.
+	return health, nil
+}
+
+// parseNvidiaHealth parses the health records printed by nvidia-smi. Lines that are not records,
+// like the errors printed for lost GPUs, are skipped. GPUs without ECC support report no errors.
+func parseNvidiaHealth(out string) map[string]int {
+	health := make(map[string]int)
+	for _, line := range strings.Split(out, "\n") {
+		record := strings.Split(line, ",")
+		if len(record) != 2 {
+			continue
+		}
+		uuid := strings.TrimSpace(record[0])
+		if !strings.HasPrefix(uuid, "GPU-") {
+			continue
+		}
+		errs, err := strconv.Atoi(strings.TrimSpace(record[1]))
+		if err != nil {
+			errs = 0
+		}
+		health[uuid] = errs
+	}
+	return health
+}
+
+// getNvidiaTopology returns the topology of the GPUs on this machine by their index, as reported
+// by `nvidia-smi topo -m`. GPUs are missing from the result if their topology cannot be detected.
+func getNvidiaTopology() (map[int]device.Topology, error) {
+	// #nosec G204
+	cmd := exec.Command("nvidia-smi", "topo", "-m")
+	out, err := cmd.Output()
+
+	if execError, ok := err.(*exec.Error); ok && execError.Err == exec.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		log.WithError(err).WithField("output", string(out)).Warnf(
+			"error while executing nvidia-smi topo")
+		return nil, nil
+	}
+
+	topology, err := parseNvidiaTopology(string(out))
+	if err != nil {
+		log.WithError(err).Warnf("GPU topology is unknown")
+		return nil, nil
+	}
+	return topology, nil
+}
+
+// parseNvidiaTopology parses the topology matrix printed by `nvidia-smi topo -m`. The matrix has a
+// tab-separated header row naming the devices and the affinity columns, followed by one row per
+// device. GPUs that are connected by NVLink (entries like "NV2") are grouped into NVLink islands,
+// which are identified by the lowest index of the GPUs in them.
+func parseNvidiaTopology(out string) (map[int]device.Topology, error) {
+	var header []string
+	gpus := make(map[int][]string)
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if header != nil {
+				// The matrix is followed by a legend.
+				break
+			}
+			continue
+		}
+		fields := strings.Split(stripANSIEscapes(line), "\t")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		if header == nil {
+			// The header row has no name for the column of the row names.
+			if fields[0] != "" {
+				fields = append([]string{""}, fields...)
+			}
+			header = fields
+			continue
+		}
+		if !strings.HasPrefix(fields[0], "GPU") {
+			continue
+		}
+		index, err := strconv.Atoi(strings.TrimPrefix(fields[0], "GPU"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing GPU %s of nvidia-smi topo", fields[0])
+		}
+		gpus[index] = fields
+	}
+	if len(gpus) == 0 {
+		return nil, errors.New("nvidia-smi topo printed no GPUs")
+	}
+
+	// islands maps each GPU to the lowest index of the GPUs that it is connected to over NVLink.
+	islands := make(map[int]int)
+	var find func(int) int
+	find = func(i int) int {
+		if parent, ok := islands[i]; ok && parent != i {
+			root := find(parent)
+			islands[i] = root
+			return root
+		}
+		return i
+	}
+
+	topology := make(map[int]device.Topology, len(gpus))
+	for index, fields := range gpus {
+		var t device.Topology
+		for column, name := range header {
+			if column >= len(fields) {
+				break
+			}
+			value := fields[column]
+			switch {
+			case strings.HasPrefix(name, "GPU") && strings.HasPrefix(value, "NV"):
+				peer, err := strconv.Atoi(strings.TrimPrefix(name, "GPU"))
+				if err != nil {
+					return nil, errors.Wrapf(err, "error parsing GPU %s of nvidia-smi topo", name)
+				}
+				a, b := find(index), find(peer)
+				if a > b {
+					a, b = b, a
+				}
+				islands[a], islands[b] = a, a
+			case name == "NUMA Affinity":
+				if node, err := strconv.Atoi(value); err == nil {
+					t.NUMANode, t.HasNUMANode = node, true
+				}
+			}
+		}
+		topology[index] = t
+	}
+	for index, t := range topology {
+		if _, ok := islands[index]; ok {
+			t.NVLinkIsland, t.HasNVLinkIsland = find(index), true
+			topology[index] = t
+		}
+	}
+	return topology, nil
+}
+
+// stripANSIEscapes removes the terminal escape sequences that nvidia-smi uses to underline the
+// header of the topology matrix.
+func stripANSIEscapes(line string) string {
+	var b strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\x1b' {
+			for i < len(line) && line[i] != 'm' {
+				i++
+			}
+			continue
+		}
+		b.WriteByte(line[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+const nvidiaTopology = "\t\x1b[4mGPU0\tGPU1\tGPU2\tGPU3\tmlx5_0\tCPU Affinity\tNUMA Affinity\x1b[0m\n" +
+	"GPU0\t X \tNV2\tSYS\tSYS\tPIX\t0-19,40-59\t0\n" +
+	"GPU1\tNV2\t X \tSYS\tSYS\tPIX\t0-19,40-59\t0\n" +
+	"GPU2\tSYS\tSYS\t X \tNV1\tSYS\t20-39,60-79\t1\n" +
+	"GPU3\tSYS\tSYS\tNV1\t X \tSYS\t20-39,60-79\t1\n" +
+	"mlx5_0\tPIX\tPIX\tSYS\tSYS\t X \t\t\n" +
+	"\n" +
+	"Legend:\n" +
+	"\n" +
+	"  X    = Self\n" +
+	"  NV#  = Connection traversing a bonded set of # NVLinks\n"
+
+func TestParseNvidiaTopology(t *testing.T) {
+	topology, err := parseNvidiaTopology(nvidiaTopology)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[int]device.Topology{
+		0: {NUMANode: 0, HasNUMANode: true, NVLinkIsland: 0, HasNVLinkIsland: true},
+		1: {NUMANode: 0, HasNUMANode: true, NVLinkIsland: 0, HasNVLinkIsland: true},
+		2: {NUMANode: 1, HasNUMANode: true, NVLinkIsland: 2, HasNVLinkIsland: true},
+		3: {NUMANode: 1, HasNUMANode: true, NVLinkIsland: 2, HasNVLinkIsland: true},
+	}
+	if !reflect.DeepEqual(topology, expected) {
+		t.Errorf("Expected: %v But got: %v", expected, topology)
+	}
+}
+
+func TestParseNvidiaTopologyWithoutNVLink(t *testing.T) {
+	topology, err := parseNvidiaTopology(
+		"\tGPU0\tGPU1\tCPU Affinity\n" +
+			"GPU0\t X \tPHB\t0-7\n" +
+			"GPU1\tPHB\t X \t0-7\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[int]device.Topology{0: {}, 1: {}}
+	if !reflect.DeepEqual(topology, expected) {
+		t.Errorf("Expected: %v But got: %v", expected, topology)
+	}
+
+	if _, err := parseNvidiaTopology("No devices were found\n"); err == nil {
+		t.Errorf("Expected an error for output without GPUs")
+	}
+}
+
+func TestParseNvidiaHealth(t *testing.T) {
+	health := parseNvidiaHealth(
+		"GPU-5e8c1b1a-0d6b-4e4c-9d1c-2f2a7a8e9b01, 0\n" +
+			"Unable to determine the device handle for GPU 0000:3B:00.0: GPU is lost.\n" +
+			"GPU-7a1f9c3e-6b2d-4f0a-8e5c-1d3b2c4a5f02, 2\n" +
+			"GPU-9d4e2a6b-1c3f-4b5d-a7e9-0f8c6b2d4e03, [N/A]\n")
+	expected := map[string]int{
+		"GPU-5e8c1b1a-0d6b-4e4c-9d1c-2f2a7a8e9b01": 0,
+		"GPU-7a1f9c3e-6b2d-4f0a-8e5c-1d3b2c4a5f02": 2,
+		"GPU-9d4e2a6b-1c3f-4b5d-a7e9-0f8c6b2d4e03": 0,
+	}
+	if !reflect.DeepEqual(health, expected) {
+		t.Errorf("Expected: %v But got: %v", expected, health)
+	}
+}
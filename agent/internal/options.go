@@ -30,6 +30,11 @@ type Options struct {
 
 	VisibleGPUs string `json:"visible_gpus"`
 
+	// ContainerRuntime is the engine that task containers are run with: docker or podman.
+	ContainerRuntime string `json:"container_runtime"`
+	// PodmanSocket is the address of the Podman API service, used by the podman runtime.
+	PodmanSocket string `json:"podman_socket"`
+
 	TLS      bool   `json:"tls"`
 	CertFile string `json:"cert_file"`
 	KeyFile  string `json:"key_file"`
@@ -41,6 +46,16 @@ type Options struct {
 
 	Security SecurityOptions `json:"security"`
 
+	// ReconnectAttempts is how many times the agent tries to reconnect to the master after losing
+	// its connection before shutting down; zero shuts the agent down right away.
+	ReconnectAttempts int `json:"reconnect_attempts"`
+	// ReconnectBackoff is the maximum number of seconds to wait between reconnection attempts.
+	ReconnectBackoff int `json:"reconnect_backoff"`
+
+	// HealthCheckInterval is the number of seconds between probes of the health of the GPUs; zero
+	// disables health checks.
+	HealthCheckInterval int `json:"health_check_interval"`
+
 	Fluent FluentOptions `json:"fluent"`
 }
 
@@ -49,6 +64,10 @@ func (o Options) Validate() []error {
 	return []error{
 		o.validateTLS(),
 		check.In(o.SlotType, []string{"gpu", "auto", "none"}),
+		check.In(o.ContainerRuntime, containerRuntimes),
+		check.GreaterThanOrEqualTo(o.ReconnectAttempts, 0, "reconnect_attempts must be >= 0"),
+		check.GreaterThan(o.ReconnectBackoff, 0, "reconnect_backoff must be > 0"),
+		check.GreaterThanOrEqualTo(o.HealthCheckInterval, 0, "health_check_interval must be >= 0"),
 	}
 }
 
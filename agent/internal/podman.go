@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/container"
+)
+
+// podmanRuntime runs containers through the Docker-compatible API of the Podman API service, for
+// hosts that do not run a Docker daemon. Podman supports neither the Fluentd log driver nor Docker
+// device requests, so the agent follows the logs of containers itself and GPUs are handed to
+// containers by the NVIDIA Container Toolkit hook for Podman instead.
+type podmanRuntime struct {
+	*dockerRuntime
+}
+
+func newPodmanRuntime(socket string) (*podmanRuntime, error) {
+	c, err := client.NewClientWithOpts(client.WithHost(socket), client.WithVersion("1.40"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error connecting to Podman API service at %s", socket)
+	}
+	return &podmanRuntime{dockerRuntime: &dockerRuntime{Client: c}}, nil
+}
+
+// SupportsFluentLogging implements containerRuntime.
+func (p *podmanRuntime) SupportsFluentLogging() bool {
+	return false
+}
+
+// CreateContainer implements containerRuntime.
+func (p *podmanRuntime) CreateContainer(
+	ctx context.Context, spec container.RunSpec, logs logSink,
+) (string, error) {
+	spec.ContainerConfig.Env = append(
+		spec.ContainerConfig.Env, nvidiaHookEnvVars(spec.HostConfig.DeviceRequests)...)
+	spec.HostConfig.DeviceRequests = nil
+	return p.dockerRuntime.CreateContainer(ctx, spec, logs)
+}
+
+// nvidiaHookEnvVars returns the environment variables that ask the NVIDIA Container Toolkit hook
+// to expose the GPUs of the Docker device requests to the container.
+func nvidiaHookEnvVars(requests []dcontainer.DeviceRequest) []string {
+	var uuids []string
+	for _, r := range requests {
+		if r.Driver == "nvidia" {
+			uuids = append(uuids, r.DeviceIDs...)
+		}
+	}
+	if len(uuids) == 0 {
+		return nil
+	}
+	return []string{
+		fmt.Sprintf("NVIDIA_VISIBLE_DEVICES=%s", strings.Join(uuids, ",")),
+		"NVIDIA_DRIVER_CAPABILITIES=compute,utility",
+	}
+}
@@ -0,0 +1,25 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+
+	dcontainer "github.com/docker/docker/api/types/container"
+)
+
+func TestNvidiaHookEnvVars(t *testing.T) {
+	env := nvidiaHookEnvVars([]dcontainer.DeviceRequest{
+		{Driver: "nvidia", DeviceIDs: []string{"GPU-0", "GPU-1"}},
+	})
+	expected := []string{
+		"NVIDIA_VISIBLE_DEVICES=GPU-0,GPU-1",
+		"NVIDIA_DRIVER_CAPABILITIES=compute,utility",
+	}
+	if !reflect.DeepEqual(env, expected) {
+		t.Errorf("Expected: %v But got: %v", expected, env)
+	}
+
+	if env := nvidiaHookEnvVars(nil); env != nil {
+		t.Errorf("Expected no environment variables without GPUs but got: %v", env)
+	}
+}
@@ -0,0 +1,475 @@
+package internal
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	aproto "github.com/determined-ai/determined/master/pkg/agent"
+	"github.com/determined-ai/determined/master/pkg/container"
+)
+
+// maxProcessLogLine is the longest line of output of a process that is sent as a single log.
+const maxProcessLogLine = 1024 * 1024
+
+// processContainerDirs are the directories in task containers that Determined puts the files of
+// tasks in. The process runtime maps them into the root directory of each process.
+var processContainerDirs = []string{"/run/determined", "/opt/determined"}
+
+// processRuntime runs the commands of task containers as plain processes on the host, for laptops
+// and CI where running Docker is too heavy. Processes are not isolated: they run as the agent user
+// with the environment of the agent, and the image of the container is ignored. The archives of a
+// container are unpacked into a temporary root directory of the process, which is passed to the
+// process as DET_CONTAINER_ROOT so that the entrypoints of tasks find their files in it. The paths
+// in processContainerDirs are mapped into that directory wherever they appear in the command and
+// environment variables of the container; the working directory is always in that directory.
+// Bind mounts are linked into that directory at their container path, and their container paths
+// are mapped to their host paths in the same way; other kinds of mounts are not supported.
+type processRuntime struct {
+	mu        sync.Mutex
+	processes map[string]*process
+}
+
+// process is a task container that is run as a process.
+type process struct {
+	root   string
+	mounts []processMount
+	spec   container.RunSpec
+	cmd    *exec.Cmd
+	stdout *os.File
+	stderr *os.File
+}
+
+// processMount is a bind mount of a host path into a task container.
+type processMount struct {
+	source string
+	target string
+}
+
+// processMounts returns the bind mounts of a container. The process runtime cannot provide other
+// kinds of mounts, such as volumes.
+func processMounts(config dcontainer.HostConfig) ([]processMount, error) {
+	var mounts []processMount
+	for _, m := range config.Mounts {
+		if m.Type != mount.TypeBind {
+			return nil, errors.Errorf(
+				"the process runtime only supports bind mounts, not %s mounts: %s", m.Type, m.Target)
+		}
+		mounts = append(mounts, processMount{source: m.Source, target: m.Target})
+	}
+	for _, bind := range config.Binds {
+		parts := strings.Split(bind, ":")
+		if len(parts) < 2 || !filepath.IsAbs(parts[0]) {
+			return nil, errors.Errorf(
+				"the process runtime only supports bind mounts of host paths: %s", bind)
+		}
+		mounts = append(mounts, processMount{source: parts[0], target: parts[1]})
+	}
+	for _, m := range mounts {
+		if !filepath.IsAbs(m.source) {
+			return nil, errors.Errorf("invalid bind mount source: %s", m.source)
+		}
+		if !filepath.IsAbs(m.target) || filepath.Clean(m.target) == "/" {
+			return nil, errors.Errorf("invalid bind mount target: %s", m.target)
+		}
+	}
+	// Paths are mapped through the most specific mount that contains them.
+	sort.SliceStable(mounts, func(i, j int) bool {
+		return len(filepath.Clean(mounts[i].target)) > len(filepath.Clean(mounts[j].target))
+	})
+	return mounts, nil
+}
+
+func newProcessRuntime() *processRuntime {
+	return &processRuntime{processes: make(map[string]*process)}
+}
+
+func (r *processRuntime) process(id string) (*process, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.processes[id]
+	if !ok {
+		return nil, errors.Errorf("process not found: %s", id)
+	}
+	return p, nil
+}
+
+// SupportsFluentLogging implements containerRuntime.
+func (r *processRuntime) SupportsFluentLogging() bool {
+	return false
+}
+
+// PullImage implements containerRuntime.
+func (r *processRuntime) PullImage(ctx context.Context, msg pullImage, logs logSink) error {
+	logs(auxLog(fmt.Sprintf("running as a process, skipping pull phase: %s", msg.Name)))
+	return nil
+}
+
+// CreateContainer implements containerRuntime.
+func (r *processRuntime) CreateContainer(
+	ctx context.Context, spec container.RunSpec, logs logSink,
+) (string, error) {
+	mounts, err := processMounts(spec.HostConfig)
+	if err != nil {
+		return "", err
+	}
+	id := uuid.New().String()
+	root, err := ioutil.TempDir("", fmt.Sprintf("determined-process-%s-", id))
+	if err != nil {
+		return "", errors.Wrap(err, "error creating process root directory")
+	}
+	logs(auxLog(fmt.Sprintf("running container as a process in: %s", root)))
+	for _, m := range mounts {
+		link := filepath.Join(root, m.target)
+		if err = os.MkdirAll(filepath.Dir(link), 0700); err == nil {
+			err = os.Symlink(m.source, link)
+		}
+		if err != nil {
+			_ = os.RemoveAll(root)
+			return "", errors.Wrapf(err, "error linking bind mount: %s", m.target)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.processes[id] = &process{root: root, mounts: mounts, spec: spec}
+	return id, nil
+}
+
+// CopyArchive implements containerRuntime.
+func (r *processRuntime) CopyArchive(
+	ctx context.Context, id string, arx container.RunArchive,
+) error {
+	p, err := r.process(id)
+	if err != nil {
+		return err
+	}
+	for _, item := range arx.Archive {
+		var path string
+		if path, err = p.archivePath(filepath.Join(arx.Path, item.Path)); err != nil {
+			return err
+		}
+		switch item.Type {
+		case tar.TypeDir:
+			err = os.MkdirAll(path, item.FileMode|0700)
+		case tar.TypeReg:
+			if err = os.MkdirAll(filepath.Dir(path), 0700); err == nil {
+				err = removeSymlink(path)
+			}
+			if err == nil {
+				err = ioutil.WriteFile(path, item.Content, item.FileMode|0600)
+			}
+		case tar.TypeSymlink:
+			target := string(item.Content)
+			if !filepath.IsAbs(target) && !withinDir(p.root, filepath.Join(filepath.Dir(path), target)) {
+				return errors.Errorf("symbolic link %s leads out of the container: %s", item.Path, target)
+			}
+			if err = os.MkdirAll(filepath.Dir(path), 0700); err == nil {
+				err = os.Symlink(p.hostPath(target), path)
+			}
+		default:
+			err = errors.Errorf("unsupported file type: %c", item.Type)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "error copying file to process: %s", item.Path)
+		}
+	}
+	return nil
+}
+
+// StartContainer implements containerRuntime.
+func (r *processRuntime) StartContainer(
+	ctx context.Context, id string,
+) (<-chan containerExit, error) {
+	p, err := r.process(id)
+	if err != nil {
+		return nil, err
+	}
+
+	args := append(
+		append([]string{}, p.spec.ContainerConfig.Entrypoint...), p.spec.ContainerConfig.Cmd...)
+	if len(args) == 0 {
+		return nil, errors.New("no command specified")
+	}
+	for i, arg := range args {
+		args[i] = p.hostPath(arg)
+	}
+
+	// #nosec G204
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), p.env()...)
+	cmd.Dir = p.root
+	if dir := p.spec.ContainerConfig.WorkingDir; dir != "" {
+		cmd.Dir = filepath.Join(p.root, dir)
+		if err = os.MkdirAll(cmd.Dir, 0700); err != nil {
+			return nil, errors.Wrap(err, "error creating working directory")
+		}
+	}
+	// Run the process in its own process group so that signals reach all of its children.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	// The agent reads the output of the process through pipes that it owns, so that waiting for the
+	// process does not race with reading its output.
+	stdout, stdoutW, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, stderrW, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stdout, cmd.Stderr = stdoutW, stderrW
+	err = cmd.Start()
+	for _, f := range []*os.File{stdoutW, stderrW} {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.cmd, p.stdout, p.stderr = cmd, stdout, stderr
+
+	exits := make(chan containerExit, 1)
+	go func() {
+		exits <- processExit(cmd.Wait())
+	}()
+	return exits, nil
+}
+
+// processExit converts the result of waiting for a process into the exit of its container. Like
+// Docker, it reports processes killed by a signal as exiting with 128 plus the signal number.
+func processExit(err error) containerExit {
+	exitErr, ok := err.(*exec.ExitError)
+	switch {
+	case err == nil:
+		return containerExit{}
+	case !ok:
+		return containerExit{Err: err}
+	}
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return containerExit{ExitCode: 128 + int64(status.Signal())}
+	}
+	return containerExit{ExitCode: int64(exitErr.ExitCode())}
+}
+
+// InspectContainer implements containerRuntime. Processes share the network of the host, so they
+// are described as containers in host network mode.
+func (r *processRuntime) InspectContainer(
+	ctx context.Context, id string,
+) (types.ContainerJSON, error) {
+	p, err := r.process(id)
+	if err != nil {
+		return types.ContainerJSON{}, err
+	}
+	if p.cmd == nil {
+		return types.ContainerJSON{}, errors.Errorf("process not started: %s", id)
+	}
+	config := p.spec.ContainerConfig
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:      id,
+			Created: time.Now().Format(time.RFC3339Nano),
+			Path:    p.cmd.Path,
+			Args:    p.cmd.Args[1:],
+			State: &types.ContainerState{
+				Status:  "running",
+				Running: true,
+				Pid:     p.cmd.Process.Pid,
+			},
+			HostConfig: &dcontainer.HostConfig{NetworkMode: "host"},
+		},
+		Config: &config,
+		NetworkSettings: &types.NetworkSettings{
+			Networks: map[string]*network.EndpointSettings{"host": {}},
+		},
+	}, nil
+}
+
+// FollowLogs implements containerRuntime.
+func (r *processRuntime) FollowLogs(ctx context.Context, id string, logs logSink) error {
+	p, err := r.process(id)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, output := range []struct {
+		file    *os.File
+		stdType stdcopy.StdType
+	}{{p.stdout, stdcopy.Stdout}, {p.stderr, stdcopy.Stderr}} {
+		i, output := i, output
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = followProcessOutput(output.file, output.stdType, logs)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return errors.Wrap(err, "error reading process output")
+		}
+	}
+	return nil
+}
+
+// followProcessOutput sends each line of the output of a process as a log until the output is
+// closed.
+func followProcessOutput(r io.ReadCloser, stdType stdcopy.StdType, logs logSink) error {
+	defer func() {
+		_ = r.Close()
+	}()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxProcessLogLine)
+	for scanner.Scan() {
+		logs(aproto.ContainerLog{
+			Timestamp: time.Now(),
+			RunMessage: &aproto.RunMessage{
+				Value:   scanner.Text() + "\n",
+				StdType: stdType,
+			},
+		})
+	}
+	return scanner.Err()
+}
+
+// SignalContainer implements containerRuntime.
+func (r *processRuntime) SignalContainer(
+	ctx context.Context, id string, signal syscall.Signal,
+) error {
+	p, err := r.process(id)
+	if err != nil {
+		return err
+	}
+	if p.cmd == nil {
+		return errors.Errorf("process not started: %s", id)
+	}
+	return syscall.Kill(-p.cmd.Process.Pid, signal)
+}
+
+// RemoveContainer implements containerRuntime.
+func (r *processRuntime) RemoveContainer(ctx context.Context, id string) error {
+	p, err := r.process(id)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	delete(r.processes, id)
+	r.mu.Unlock()
+	return os.RemoveAll(p.root)
+}
+
+// archivePath returns the path in the root directory of the process that a file that is copied to
+// the given path in the container is written to. Symbolic links, e.g., of archives or bind mounts,
+// may lead out of the root directory, so files are never written through them.
+func (p *process) archivePath(containerPath string) (string, error) {
+	path := filepath.Join(p.root, containerPath)
+	if !withinDir(p.root, path) {
+		return "", errors.Errorf("path leads out of the container: %s", containerPath)
+	}
+	root, err := filepath.EvalSymlinks(p.root)
+	if err != nil {
+		return "", err
+	}
+	// The directories of the path that do not exist yet are created in the deepest one that does.
+	dir := filepath.Dir(path)
+	for {
+		if _, err = os.Lstat(dir); err == nil || !os.IsNotExist(err) {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+	if dir, err = filepath.EvalSymlinks(dir); err != nil {
+		return "", err
+	}
+	if !withinDir(root, dir) {
+		return "", errors.Errorf("path leads out of the container: %s", containerPath)
+	}
+	return path, nil
+}
+
+// removeSymlink removes the file at the path if it is a symbolic link, so that the file can be
+// replaced rather than written through the link.
+func removeSymlink(path string) error {
+	info, err := os.Lstat(path)
+	switch {
+	case os.IsNotExist(err):
+		return nil
+	case err != nil:
+		return err
+	case info.Mode()&os.ModeSymlink != 0:
+		return os.Remove(path)
+	default:
+		return nil
+	}
+}
+
+// withinDir returns whether the path is the directory or in it.
+func withinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+}
+
+// hostPath maps a path in one of processContainerDirs to the root directory of the process and a
+// path in a bind mount to the host path of the mount. Other paths are left as they are, so that
+// the process can use the files and tools of the host.
+func (p *process) hostPath(path string) string {
+	clean := filepath.Clean(path)
+	for _, m := range p.mounts {
+		target := filepath.Clean(m.target)
+		if clean == target || strings.HasPrefix(clean, target+"/") {
+			return filepath.Join(m.source, strings.TrimPrefix(clean, target))
+		}
+	}
+	for _, dir := range processContainerDirs {
+		if clean == dir || strings.HasPrefix(clean, dir+"/") {
+			return filepath.Join(p.root, clean)
+		}
+	}
+	return path
+}
+
+// env returns the environment variables of the container, mapping the paths in their values to the
+// root directory of the process. The GPUs of the container are made visible to CUDA.
+func (p *process) env() []string {
+	env := []string{fmt.Sprintf("DET_CONTAINER_ROOT=%s", p.root)}
+	for _, v := range p.spec.ContainerConfig.Env {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) == 2 {
+			v = fmt.Sprintf("%s=%s", kv[0], p.hostPath(kv[1]))
+		}
+		env = append(env, v)
+	}
+	var gpus []string
+	for _, r := range p.spec.HostConfig.DeviceRequests {
+		if r.Driver == "nvidia" {
+			gpus = append(gpus, r.DeviceIDs...)
+		}
+	}
+	if len(gpus) > 0 {
+		env = append(env, fmt.Sprintf("CUDA_VISIBLE_DEVICES=%s", strings.Join(gpus, ",")))
+	}
+	return env
+}
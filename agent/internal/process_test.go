@@ -0,0 +1,287 @@
+package internal
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+
+	aproto "github.com/determined-ai/determined/master/pkg/agent"
+	"github.com/determined-ai/determined/master/pkg/archive"
+	"github.com/determined-ai/determined/master/pkg/container"
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/tasks"
+	"github.com/determined-ai/determined/master/version"
+)
+
+func runProcess(t *testing.T, spec container.RunSpec) (containerExit, []string) {
+	ctx := context.Background()
+	r := newProcessRuntime()
+	var lines []string
+	logs := func(log aproto.ContainerLog) {
+		if log.RunMessage != nil {
+			lines = append(lines, log.RunMessage.Value)
+		}
+	}
+
+	id, err := r.CreateContainer(ctx, spec, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := r.RemoveContainer(ctx, id); err != nil {
+			t.Error(err)
+		}
+	}()
+	for _, arx := range spec.Archives {
+		if err = r.CopyArchive(ctx, id, arx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exit, err := r.StartContainer(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := r.InspectContainer(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := info.NetworkSettings.Networks["host"]; !ok {
+		t.Errorf("Expected process to be in the host network but got: %v", info.NetworkSettings)
+	}
+	if err = r.FollowLogs(ctx, id, logs); err != nil {
+		t.Fatal(err)
+	}
+	return <-exit, lines
+}
+
+func TestProcessRuntime(t *testing.T) {
+	script := "#!/bin/sh\necho \"$GREETING $HOST_PATH\"\ncat \"$DATA\" >&2\nexit 3\n"
+	spec := container.RunSpec{
+		ContainerConfig: dcontainer.Config{
+			Cmd: []string{"/run/determined/test/script.sh"},
+			Env: []string{
+				"GREETING=hello",
+				"DATA=/run/determined/test/data/message.txt",
+				"HOST_PATH=/opt/test",
+			},
+			WorkingDir: "/run/determined/test/workdir",
+		},
+		Archives: []container.RunArchive{{
+			Path: "/run/determined/test",
+			Archive: archive.Archive{
+				archive.RootItem("script.sh", []byte(script), 0700, tar.TypeReg),
+				archive.RootItem("data", nil, 0700, tar.TypeDir),
+				archive.RootItem("data/message.txt", []byte("world\n"), 0600, tar.TypeReg),
+			},
+		}},
+	}
+
+	exit, lines := runProcess(t, spec)
+	if exit.Err != nil || exit.ExitCode != 3 {
+		t.Errorf("Expected exit code 3 but got: %v", exit)
+	}
+	// Paths outside of the directories of Determined are not mapped.
+	expected := []string{"hello /opt/test\n", "world\n"}
+	if len(lines) != 2 || !(reflect.DeepEqual(lines, expected) ||
+		reflect.DeepEqual(lines, []string{expected[1], expected[0]})) {
+		t.Errorf("Expected: %v But got: %v", expected, lines)
+	}
+}
+
+func TestProcessExit(t *testing.T) {
+	exit, _ := runProcess(t, container.RunSpec{
+		ContainerConfig: dcontainer.Config{Cmd: []string{"sh", "-c", "kill -TERM $$"}},
+	})
+	if expected := 128 + int64(syscall.SIGTERM); exit.ExitCode != expected {
+		t.Errorf("Expected exit code %d but got: %v", expected, exit)
+	}
+}
+
+func TestProcessBindMounts(t *testing.T) {
+	source, err := ioutil.TempDir("", "determined-process-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(source)
+	}()
+	if err = ioutil.WriteFile(filepath.Join(source, "in"), []byte("data\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	exit, lines := runProcess(t, container.RunSpec{
+		ContainerConfig: dcontainer.Config{
+			Cmd: []string{"sh", "-c", `cat "$DET_CONTAINER_ROOT/data/in" > "$OUT"`},
+			Env: []string{"OUT=/data/out"},
+		},
+		HostConfig: dcontainer.HostConfig{Mounts: []mount.Mount{{
+			Type:   mount.TypeBind,
+			Source: source,
+			Target: "/data",
+		}}},
+	})
+	if exit.Err != nil || exit.ExitCode != 0 {
+		t.Fatalf("Expected the process to succeed but got: %v: %v", exit, lines)
+	}
+	if out, err := ioutil.ReadFile(filepath.Join(source, "out")); err != nil {
+		t.Error(err)
+	} else if string(out) != "data\n" {
+		t.Errorf("Expected the output in the mount but got: %q", out)
+	}
+}
+
+func TestProcessUnsupportedMounts(t *testing.T) {
+	r := newProcessRuntime()
+	for _, config := range []dcontainer.HostConfig{
+		{Mounts: []mount.Mount{{Type: mount.TypeVolume, Source: "data", Target: "/data"}}},
+		{Binds: []string{"data:/data"}},
+	} {
+		if _, err := r.CreateContainer(context.Background(), container.RunSpec{
+			HostConfig: config,
+		}, func(aproto.ContainerLog) {}); err == nil {
+			t.Errorf("Expected an error for mounts: %v", config)
+		}
+	}
+}
+
+func TestProcessArchiveEscape(t *testing.T) {
+	ctx := context.Background()
+	outside, err := ioutil.TempDir("", "determined-process-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(outside)
+	}()
+
+	for _, arx := range []archive.Archive{
+		{archive.RootItem("escape", []byte("../../.."), 0700, tar.TypeSymlink)},
+		{
+			archive.RootItem("link", []byte(outside), 0700, tar.TypeSymlink),
+			archive.RootItem("link/escaped", []byte("x"), 0600, tar.TypeReg),
+		},
+	} {
+		r := newProcessRuntime()
+		id, err := r.CreateContainer(ctx, container.RunSpec{}, func(aproto.ContainerLog) {})
+		if err != nil {
+			t.Fatal(err)
+		}
+		root := r.processes[id].root
+		err = r.CopyArchive(ctx, id, container.RunArchive{Path: "/run/determined", Archive: arx})
+		if err == nil {
+			t.Errorf("Expected an error for archive: %v", arx)
+		}
+		if _, err = os.Stat(filepath.Join(outside, "escaped")); !os.IsNotExist(err) {
+			t.Errorf("Expected no file outside of the container but got: %v", err)
+		}
+		if _, err = os.Stat(filepath.Join(filepath.Dir(root), "escaped")); !os.IsNotExist(err) {
+			t.Errorf("Expected no file outside of the container but got: %v", err)
+		}
+		if err = r.RemoveContainer(ctx, id); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+// fakePython stands in for the Python of the container. It reports how the trial entrypoint runs
+// it, so that the test does not need the harness installed on the host.
+const fakePython = `#!/bin/sh
+echo "python3.6 $* in $(pwd)"
+echo "root: $DET_CONTAINER_ROOT"
+echo "user base: $PYTHONUSERBASE"
+test -L "$DET_CONTAINER_ROOT/run/determined/train/logs/stdout.log" && echo "stdout linked"
+test -f "$DET_LATEST_CHECKPOINT" && echo "checkpoint found"
+`
+
+func TestProcessRuntimeTrial(t *testing.T) {
+	if err := etc.SetRootPath("../../master/static/srv"); err != nil {
+		t.Fatal(err)
+	}
+	bin, err := ioutil.TempDir("", "determined-process-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(bin)
+	}()
+	wheel := fmt.Sprintf("determined-%s-py3-none-any.whl", version.Version)
+	if err = ioutil.WriteFile(filepath.Join(bin, wheel), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(bin, "python3.6"), []byte(fakePython), 0700); err != nil {
+		t.Fatal(err)
+	}
+	path := os.Getenv("PATH")
+	defer func() {
+		_ = os.Setenv("PATH", path)
+	}()
+	if err = os.Setenv("PATH", bin+":"+path); err != nil {
+		t.Fatal(err)
+	}
+
+	aug := &model.AgentUserGroup{User: "determined", UID: os.Getuid(), Group: "determined",
+		GID: os.Getgid()}
+	config := model.DefaultExperimentConfig(nil)
+	config.CheckpointStorage.SharedFSConfig.HostPath = bin
+	config.Searcher = model.SearcherConfig{
+		Metric:       "loss",
+		SingleConfig: &model.SingleConfig{MaxLength: model.NewLengthInBatches(1)},
+	}
+	spec := tasks.ToContainerSpec(tasks.TaskSpec{
+		TaskID:      "task",
+		Devices:     []device.Device{{ID: 0, Type: device.CPU}},
+		HarnessPath: bin,
+		StartContainer: &tasks.StartContainer{
+			AgentUserGroup:   aug,
+			ExperimentConfig: config,
+			AdditionalFiles: archive.Archive{aug.OwnedArchiveItem(
+				"/run/determined/train/entrypoint.sh",
+				etc.MustStaticFile(etc.TrialEntrypointScriptResource),
+				0700,
+				tar.TypeReg,
+			)},
+		},
+	})
+
+	exit, lines := runProcess(t, spec.RunSpec)
+	if exit.Err != nil || exit.ExitCode != 0 {
+		t.Fatalf("Expected the trial to succeed but got: %v: %v", exit, lines)
+	}
+	var root string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "root: ") {
+			root = strings.TrimSpace(strings.TrimPrefix(line, "root: "))
+		}
+	}
+	if root == "" {
+		t.Fatalf("Expected the trial to run in a root directory but got: %v", lines)
+	}
+	for _, expected := range []string{
+		fmt.Sprintf("python3.6 -m pip install -q --user %s/opt/determined/wheels/%s in %s\n",
+			root, wheel, root+tasks.ContainerWorkDir),
+		fmt.Sprintf("python3.6 -m determined.exec.harness in %s\n", root+tasks.ContainerWorkDir),
+		fmt.Sprintf("user base: %s/run/determined/pythonuserbase\n", root),
+		"stdout linked\n",
+		"checkpoint found\n",
+	} {
+		found := false
+		for _, line := range lines {
+			found = found || line == expected
+		}
+		if !found {
+			t.Errorf("Expected output %q but got: %v", expected, lines)
+		}
+	}
+}
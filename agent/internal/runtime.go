@@ -0,0 +1,210 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/actor"
+	aproto "github.com/determined-ai/determined/master/pkg/agent"
+	"github.com/determined-ai/determined/master/pkg/container"
+)
+
+const (
+	dockerRuntimeName  = "docker"
+	podmanRuntimeName  = "podman"
+	processRuntimeName = "process"
+)
+
+// containerRuntimes are the container runtimes that can be selected in the agent options.
+var containerRuntimes = []string{dockerRuntimeName, podmanRuntimeName, processRuntimeName}
+
+// containerRuntime is the engine that the agent runs task containers with. Containers are
+// described by Docker container specs, and runtimes describe started containers as Docker does.
+type containerRuntime interface {
+	// SupportsFluentLogging returns whether containers can send their logs to Fluent Bit. When they
+	// cannot, the agent follows the logs of containers itself.
+	SupportsFluentLogging() bool
+	// PullImage makes the image available to the runtime, pulling it unless it is cached.
+	PullImage(ctx context.Context, req pullImage, logs logSink) error
+	// CreateContainer creates the container without starting it and returns its runtime ID.
+	CreateContainer(ctx context.Context, spec container.RunSpec, logs logSink) (string, error)
+	// CopyArchive copies the files of the archive into the created container.
+	CopyArchive(ctx context.Context, id string, arx container.RunArchive) error
+	// StartContainer starts the created container. The returned channel receives the exit of the
+	// container.
+	StartContainer(ctx context.Context, id string) (<-chan containerExit, error)
+	// InspectContainer returns the details of a started container.
+	InspectContainer(ctx context.Context, id string) (types.ContainerJSON, error)
+	// FollowLogs sends the output of the container to logs until the container exits.
+	FollowLogs(ctx context.Context, id string, logs logSink) error
+	// SignalContainer sends the signal to the running container.
+	SignalContainer(ctx context.Context, id string, signal syscall.Signal) error
+	// RemoveContainer removes the container once it exited.
+	RemoveContainer(ctx context.Context, id string) error
+}
+
+// logSink receives the logs that are produced while a container is managed by a runtime.
+type logSink func(log aproto.ContainerLog)
+
+// containerExit is the outcome of waiting for a container to exit.
+type containerExit struct {
+	ExitCode int64
+	Err      error
+}
+
+// newContainerRuntime returns the container runtime that is selected in the options.
+func newContainerRuntime(opts Options) (containerRuntime, error) {
+	switch opts.ContainerRuntime {
+	case dockerRuntimeName:
+		return newDockerRuntime()
+	case podmanRuntimeName:
+		return newPodmanRuntime(opts.PodmanSocket)
+	case processRuntimeName:
+		return newProcessRuntime(), nil
+	default:
+		return nil, errors.Errorf("unknown container runtime: %s", opts.ContainerRuntime)
+	}
+}
+
+// runtimeActor pulls, runs and signals the container of its parent container actor through the
+// container runtime.
+type runtimeActor struct {
+	runtime containerRuntime
+}
+
+type (
+	signalContainer struct {
+		runtimeID string
+		signal    syscall.Signal
+	}
+	pullImage struct {
+		container.PullSpec
+		Name string
+	}
+	imagePulled      struct{}
+	containerStarted struct {
+		runtimeID     string
+		containerInfo types.ContainerJSON
+	}
+	containerTerminated struct {
+		ExitCode int64
+	}
+	runtimeErr struct{ Error error }
+)
+
+func (r *runtimeActor) Receive(ctx *actor.Context) error {
+	switch msg := ctx.Message().(type) {
+	case actor.PreStart:
+
+	case pullImage:
+		go r.pullImage(ctx, msg)
+
+	case container.RunSpec:
+		go r.runContainer(ctx, msg)
+
+	case signalContainer:
+		go r.signalContainer(ctx, msg)
+
+	case actor.PostStop:
+	}
+	return nil
+}
+
+func (r *runtimeActor) pullImage(ctx *actor.Context, msg pullImage) {
+	if err := r.runtime.PullImage(context.Background(), msg, r.logs(ctx)); err != nil {
+		sendErr(ctx, err)
+		return
+	}
+	ctx.Tell(ctx.Sender(), imagePulled{})
+}
+
+func (r *runtimeActor) runContainer(ctx *actor.Context, msg container.RunSpec) {
+	containerID, err := r.runtime.CreateContainer(context.Background(), msg, r.logs(ctx))
+	if err != nil {
+		sendErr(ctx, err)
+		return
+	}
+
+	if !msg.UseFluentLogging {
+		defer func() {
+			if err = r.runtime.RemoveContainer(context.Background(), containerID); err != nil {
+				sendErr(ctx, errors.Wrap(err, "error removing container"))
+			}
+		}()
+	}
+
+	for _, copyArx := range msg.Archives {
+		sendAuxLog(ctx, fmt.Sprintf("copying files to container: %s", copyArx.Path))
+		if cerr := r.runtime.CopyArchive(context.Background(), containerID, copyArx); cerr != nil {
+			sendErr(ctx, cerr)
+			return
+		}
+	}
+
+	exit, err := r.runtime.StartContainer(context.Background(), containerID)
+	if err != nil {
+		sendErr(ctx, errors.Wrap(err, "error starting container"))
+		return
+	}
+
+	containerInfo, err := r.runtime.InspectContainer(context.Background(), containerID)
+	if err != nil {
+		sendErr(ctx, errors.Wrap(err, "error inspecting container"))
+		return
+	}
+
+	ctx.Tell(
+		ctx.Sender(),
+		containerStarted{runtimeID: containerID, containerInfo: containerInfo},
+	)
+
+	if !msg.UseFluentLogging {
+		if lerr := r.runtime.FollowLogs(
+			context.Background(), containerID, r.logs(ctx),
+		); lerr != nil {
+			sendErr(ctx, lerr)
+		}
+	}
+	switch result := <-exit; {
+	case result.Err != nil:
+		sendErr(ctx, errors.Wrap(result.Err, "error while waiting for container to exit"))
+	default:
+		ctx.Tell(ctx.Sender(), containerTerminated{ExitCode: result.ExitCode})
+	}
+}
+
+func (r *runtimeActor) signalContainer(ctx *actor.Context, msg signalContainer) {
+	err := r.runtime.SignalContainer(context.Background(), msg.runtimeID, msg.signal)
+	if err != nil {
+		sendErr(ctx, errors.Wrap(err, "error while killing container"))
+		return
+	}
+}
+
+// logs returns a log sink that forwards logs to the container actor.
+func (r *runtimeActor) logs(ctx *actor.Context) logSink {
+	sender := ctx.Sender()
+	return func(log aproto.ContainerLog) {
+		ctx.Tell(sender, log)
+	}
+}
+
+func sendErr(ctx *actor.Context, err error) {
+	ctx.Tell(ctx.Sender(), runtimeErr{Error: err})
+}
+
+func sendAuxLog(ctx *actor.Context, msg string) {
+	ctx.Tell(ctx.Sender(), auxLog(msg))
+}
+
+func auxLog(msg string) aproto.ContainerLog {
+	return aproto.ContainerLog{
+		Timestamp:  time.Now(),
+		AuxMessage: &msg,
+	}
+}
@@ -31,6 +31,8 @@ def list_agents(args: argparse.Namespace) -> None:
                 ("num_containers", agent["num_containers"]),
                 ("resource_pool", agent["resource_pool"]),
                 ("label", agent["label"]),
+                ("draining", agent["draining"]),
+                ("quarantined", agent["quarantined"]),
             ]
         )
         for agent_id, agent in sorted(agents.items())
@@ -40,7 +42,16 @@ def list_agents(args: argparse.Namespace) -> None:
         print(json.dumps(agents, indent=4))
         return
 
-    headers = ["Agent ID", "Registered Time", "Slots", "Containers", "Resource Pool", "Label"]
+    headers = [
+        "Agent ID",
+        "Registered Time",
+        "Slots",
+        "Containers",
+        "Resource Pool",
+        "Label",
+        "Draining",
+        "Quarantined",
+    ]
     values = [a.values() for a in agents]
 
     render.tabulate_or_csv(headers, values, args.csv)
@@ -76,6 +87,7 @@ def list_slots(args: argparse.Namespace) -> None:
                 ),
                 ("type", slot["device"]["type"]),
                 ("device", slot["device"]["brand"]),
+                ("disabled_reason", slot.get("disabled_reason", "")),
             ]
         )
         for agent_id, agent in sorted(agents.items())
@@ -95,6 +107,7 @@ def list_slots(args: argparse.Namespace) -> None:
         "Task Name",
         "Type",
         "Device",
+        "Disabled Reason",
     ]
     values = [s.values() for s in slots]
 
@@ -117,17 +130,46 @@ def patch_agent(enabled: bool) -> Callable[[argparse.Namespace], None]:
             agent_ids = sorted(local_id(a) for a in r.json().keys())
 
         for agent_id in agent_ids:
-            path = "agents/{}/slots".format(agent_id)
-            headers = {"Content-Type": "application/merge-patch+json"}
-            payload = {"enabled": enabled}
-
-            api.patch(args.master, path, body=payload, headers=headers)
+            # Enabling an agent through the v1 API also stops draining it.
+            action = "enable" if enabled else "disable"
+            api.post(args.master, "/api/v1/agents/{}/{}".format(agent_id, action))
             status = "Disabled" if not enabled else "Enabled"
             print("{} agent {}".format(status, agent_id))
 
     return patch
 
 
+@authentication_required
+def drain_agent(args: argparse.Namespace) -> None:
+    api.post(args.master, "/api/v1/agents/{}/drain".format(args.agent_id))
+    print("Draining agent {}".format(args.agent_id))
+
+
+@authentication_required
+def drain_status(args: argparse.Namespace) -> None:
+    r = api.get(args.master, "/api/v1/agents/{}/drain".format(args.agent_id)).json()
+
+    if args.json:
+        print(json.dumps(r, indent=4))
+        return
+
+    if not r.get("draining"):
+        print("Agent {} is not draining".format(args.agent_id))
+    elif r.get("drained"):
+        print("Agent {} is drained and can be removed".format(args.agent_id))
+    else:
+        print("Agent {} is draining".format(args.agent_id))
+
+    tasks = r.get("tasks", [])
+    if tasks:
+        headers = ["Task ID", "Name", "Releasing", "Non-Preemptible"]
+        values = [
+            [t["taskId"], t["name"], t.get("releasing", False), t.get("nonPreemptible", False)]
+            for t in tasks
+        ]
+        render.tabulate_or_csv(headers, values, False)
+
+
 def patch_slot(enabled: bool) -> Callable[[argparse.Namespace], None]:
     @authentication_required
     def patch(args: argparse.Namespace) -> None:
@@ -169,6 +211,13 @@ args_description = [
                 Arg("--all", action="store_true", help="disable all agents"),
             )
         ]),
+        Cmd("drain", drain_agent, "drain agent: release its tasks and stop scheduling on it", [
+            Arg("agent_id", help="agent ID", completer=agent_id_completer),
+        ]),
+        Cmd("drain-status", drain_status, "show drain progress of agent", [
+            Arg("agent_id", help="agent ID", completer=agent_id_completer),
+            Arg("--json", action="store_true", help="print as JSON"),
+        ]),
     ]),
     Cmd("s|lot", None, "manage slots", [
         Cmd("list", list_slots, "list slots in cluster", [
@@ -0,0 +1,62 @@
+import json
+from argparse import Namespace
+from typing import Any, Dict, List
+
+from determined_common import api
+from determined_common.api.authentication import authentication_required
+
+from . import render
+from .declarative_argparse import Arg, Cmd
+
+
+@authentication_required
+def list_events(args: Namespace) -> None:
+    params = {
+        "username": args.username,
+        "resourceType": args.resource_type,
+        "resourceId": args.resource_id,
+        "startTime": args.since,
+        "endTime": args.until,
+    }  # type: Dict[str, Any]
+    params = {k: v for k, v in params.items() if v}
+    events = api.get(args.master, "/api/v1/audit-events", params=params).json()["events"]
+    if args.json:
+        print(json.dumps(events, indent=2))
+        return
+
+    headers = ["Time", "User", "Method", "Resource", "ID", "Success", "Error"]
+    values = [
+        [
+            render.format_time(e["time"]),
+            e.get("username", ""),
+            e["method"],
+            e.get("resourceType", ""),
+            e.get("resourceId", ""),
+            e["success"],
+            e.get("error", ""),
+        ]
+        for e in events
+    ]
+    render.tabulate_or_csv(headers, values, args.csv)
+
+
+# fmt: off
+
+args_description = [
+    Cmd("audit", None, "view the audit log (admins only)", [
+        Cmd("list ls", list_events, "list the requests that changed the cluster", [
+            Arg("--username", help="only list requests made by this user"),
+            Arg("--resource-type", help="only list requests that acted on this type of "
+                "resource, e.g., experiment or agent"),
+            Arg("--resource-id", help="only list requests that acted on the resource "
+                "with this ID"),
+            Arg("--since", help="only list requests made at or after this time "
+                "(RFC 3339, e.g., 2021-01-01T00:00:00Z)"),
+            Arg("--until", help="only list requests made before this time (RFC 3339)"),
+            Arg("--csv", action="store_true", help="print as CSV"),
+            Arg("--json", action="store_true", help="print as JSON"),
+        ], is_default=True),
+    ])
+]  # type: List[Any]
+
+# fmt: on
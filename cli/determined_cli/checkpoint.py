@@ -1,8 +1,8 @@
 import json
-from argparse import Namespace
+from argparse import FileType, Namespace
 from typing import Any, Dict, List, Optional
 
-from determined_common import api, constants, experimental
+from determined_common import api, constants, experimental, util, yaml
 from determined_common.api.authentication import authentication_required
 from determined_common.experimental import Determined
 
@@ -113,6 +113,76 @@ def describe(args: Namespace) -> None:
     render_checkpoint(checkpoint)
 
 
+def format_usage(usage: Dict[str, Any]) -> List[Any]:
+    quota = usage.get("quotaBytes")
+    return [
+        usage.get("numCheckpoints", 0),
+        util.sizeof_fmt(int(usage.get("sizeBytes", 0))),
+        util.sizeof_fmt(int(quota)) if quota is not None else None,
+    ]
+
+
+@authentication_required
+def usage(args: Namespace) -> None:
+    r = api.get(args.master, "/api/v1/checkpoint-usage").json()
+    if args.json:
+        print(json.dumps(r, indent=4))
+        return
+
+    headers = ["# of Checkpoints", "Size", "Quota"]
+    if args.by == "experiment":
+        headers = ["Experiment ID", "Owner", "Storage Type"] + headers
+        values = [
+            [u["experimentId"], u["username"], u["storageType"]] + format_usage(u["usage"])
+            for u in r["experiments"]
+        ]
+    elif args.by == "user":
+        headers = ["Username"] + headers
+        values = [[u["username"]] + format_usage(u["usage"]) for u in r["users"]]
+    else:
+        headers = ["Storage Type"] + headers
+        values = [[u["storageType"]] + format_usage(u["usage"]) for u in r["storageBackends"]]
+    render.tabulate_or_csv(headers, values, args.csv)
+
+
+@authentication_required
+def export(args: Namespace) -> None:
+    r = api.get(args.master, "/api/v1/checkpoints/{}/export".format(args.uuid)).json()
+    output = args.output or "{}.json".format(args.uuid)
+    with open(output, "w") as f:
+        json.dump(r["bundle"], f, indent=2)
+    print("Exported checkpoint {} to {}".format(args.uuid, output))
+
+
+@authentication_required
+def import_checkpoint(args: Namespace) -> None:
+    body = {"bundle": json.load(args.bundle_file)}  # type: Dict[str, Any]
+    if args.checkpoint_storage:
+        body["checkpointStorage"] = yaml.safe_load(args.checkpoint_storage.read())
+    post_import(args, body)
+
+
+@authentication_required
+def import_uri(args: Namespace) -> None:
+    body = {
+        "storageUri": args.storage_uri,
+        "checkpoint": yaml.safe_load(args.metadata_file.read()),
+    }  # type: Dict[str, Any]
+    post_import(args, body)
+
+
+def post_import(args: Namespace, body: Dict[str, Any]) -> None:
+    if args.project_id:
+        body["projectId"] = args.project_id
+    r = api.post(args.master, "/api/v1/checkpoints/import", body=body).json()
+    checkpoint = r["checkpoint"]
+    print(
+        "Imported checkpoint {} as experiment {}, trial {}".format(
+            checkpoint["uuid"], checkpoint["experimentId"], checkpoint["trialId"]
+        )
+    )
+
+
 args_description = Cmd(
     "c|heckpoint",
     None,
@@ -144,5 +214,67 @@ args_description = Cmd(
             "describe checkpoint",
             [Arg("uuid", type=str, help="checkpoint uuid to describe")],
         ),
+        Cmd(
+            "export",
+            export,
+            "export a checkpoint and its metadata as a bundle for another master",
+            [
+                Arg("uuid", type=str, help="checkpoint uuid to export"),
+                Arg(
+                    "-o",
+                    "--output",
+                    type=str,
+                    help="file to write the bundle to (default: <uuid>.json)",
+                ),
+            ],
+        ),
+        Cmd(
+            "import",
+            import_checkpoint,
+            "register a checkpoint exported from another master",
+            [
+                Arg("bundle_file", type=FileType("r"), help="checkpoint bundle to import"),
+                Arg(
+                    "--checkpoint-storage",
+                    type=FileType("r"),
+                    help="YAML file with the checkpoint storage that holds the checkpoint, "
+                    "if it is not the checkpoint storage in the bundle",
+                ),
+                Arg("--project-id", type=int, help="project to register the checkpoint in"),
+            ],
+        ),
+        Cmd(
+            "import-uri",
+            import_uri,
+            "register a checkpoint from the URI of its files and its metadata",
+            [
+                Arg(
+                    "storage_uri",
+                    type=str,
+                    help="URI of the directory of the checkpoint, e.g. s3://bucket/<uuid>",
+                ),
+                Arg(
+                    "metadata_file",
+                    type=FileType("r"),
+                    help="JSON or YAML file with the metadata of the checkpoint",
+                ),
+                Arg("--project-id", type=int, help="project to register the checkpoint in"),
+            ],
+        ),
+        Cmd(
+            "usage",
+            usage,
+            "show the storage used by checkpoints",
+            [
+                Arg(
+                    "--by",
+                    choices=["experiment", "user", "storage"],
+                    default="experiment",
+                    help="group checkpoints by experiment, user or type of checkpoint storage",
+                ),
+                Arg("--csv", action="store_true", help="print as CSV"),
+                Arg("--json", action="store_true", help="print as JSON"),
+            ],
+        ),
     ],
 )
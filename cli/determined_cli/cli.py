@@ -1,4 +1,5 @@
 import hashlib
+import json
 import os
 import socket
 import ssl
@@ -17,15 +18,18 @@ import determined_cli
 import determined_common.api.authentication as auth
 from determined_cli import checkpoint, experiment, render
 from determined_cli.agent import args_description as agent_args_description
-from determined_cli.declarative_argparse import Arg, Cmd, add_args
+from determined_cli.audit import args_description as audit_args_description
+from determined_cli.declarative_argparse import Arg, Cmd, Group, add_args
 from determined_cli.experiment import args_description as experiment_args_description
 from determined_cli.master import args_description as master_args_description
 from determined_cli.model import args_description as model_args_description
 from determined_cli.notebook import args_description as notebook_args_description
+from determined_cli.project import args_description as project_args_description
 from determined_cli.remote import args_description as remote_args_description
 from determined_cli.shell import args_description as shell_args_description
 from determined_cli.template import args_description as template_args_description
 from determined_cli.tensorboard import args_description as tensorboard_args_description
+from determined_cli.token import args_description as token_args_description
 from determined_cli.trial import args_description as trial_args_description
 from determined_cli.user import args_description as user_args_description
 from determined_cli.version import args_description as version_args_description
@@ -58,6 +62,7 @@ def list_tasks(args: Namespace) -> None:
         "Agent",
         "Priority",
         "Resource Pool",
+        "Queue Reason",
     ]
     values = [
         [
@@ -68,6 +73,7 @@ def list_tasks(args: Namespace) -> None:
             agent_info(task),
             task["priority"] if task["scheduler_type"] == "priority" else "N/A",
             task["resource_pool"],
+            task.get("queue_reason", ""),
         ]
         for task_id, task in sorted(
             tasks.items(),
@@ -78,6 +84,35 @@ def list_tasks(args: Namespace) -> None:
     render.tabulate_or_csv(headers, values, args.csv)
 
 
+@authentication_required
+def list_queue(args: Namespace) -> None:
+    r = api.get(args.master, "/api/v1/resource-pools/{}/queue".format(args.resource_pool))
+    tasks = r.json()["tasks"]
+    headers = [
+        "Position",
+        "ID",
+        "Name",
+        "Slots Needed",
+        "Blocking Reason",
+        "Details",
+        "Fitting Agents",
+    ]
+    values = [
+        [
+            position,
+            task["taskId"],
+            task["name"],
+            task["slotsNeeded"],
+            task["blockingReason"].replace("QUEUE_BLOCKING_REASON_", ""),
+            task["details"],
+            ", ".join(task["fittingAgents"]),
+        ]
+        for position, task in enumerate(tasks, start=1)
+    ]
+
+    render.tabulate_or_csv(headers, values, args.csv)
+
+
 @authentication_required
 def preview_search(args: Namespace) -> None:
     experiment_config = yaml.safe_load(args.config_file.read())
@@ -133,6 +168,28 @@ def preview_search(args: Namespace) -> None:
     print(tabulate.tabulate(values, headers, tablefmt="presto"), flush=False)
 
 
+@authentication_required
+def replay_search(args: Namespace) -> None:
+    experiment_config = yaml.safe_load(args.config_file.read())
+    args.config_file.close()
+
+    if "searcher" not in experiment_config:
+        print("Experiment configuration must have 'searcher' section")
+        sys.exit(1)
+    body = {"config": experiment_config, "seed": args.seed}  # type: Dict[str, Any]
+    if args.experiment_id is not None:
+        body["experiment_id"] = args.experiment_id
+    else:
+        body["learning_curves"] = json.load(args.learning_curves)
+        args.learning_curves.close()
+    j = api.post(args.master, "searcher/replay", body=body).json()
+
+    headers = ["Total Length", "Best Metric"]
+    values = [(p["total_units"], p["best_metric"]) for p in j["progress"]]
+    print("This search would create a total of {} trial(s).".format(sum(j["results"].values())))
+    print(tabulate.tabulate(values, headers, tablefmt="presto"), flush=False)
+
+
 # fmt: off
 
 args_description = [
@@ -154,12 +211,30 @@ args_description = [
         Cmd("list", list_tasks, "list tasks in cluster", [
             Arg("--csv", action="store_true", help="print as CSV"),
         ], is_default=True),
+        Cmd("queue", list_queue, "list the pending tasks of a resource pool and why they are "
+            "pending", [
+            Arg("resource_pool", type=str, help="name of the resource pool"),
+            Arg("--csv", action="store_true", help="print as CSV"),
+        ]),
     ]),
 
     Cmd("preview-search", preview_search, "preview search", [
         Arg("config_file", type=FileType("r"),
             help="experiment config file (.yaml)")
     ]),
+
+    Cmd("replay-search", replay_search, "replay search against recorded learning curves", [
+        Arg("config_file", type=FileType("r"),
+            help="experiment config file (.yaml)"),
+        Group(
+            Arg("--experiment-id", type=int,
+                help="replay the validation history of this experiment"),
+            Arg("--learning-curves", type=FileType("r"),
+                help="replay the learning curves in this file (.json)"),
+            required=True,
+        ),
+        Arg("--seed", type=int, default=0, help="replay seed"),
+    ]),
 ]  # type: List[object]
 
 # fmt: on
@@ -170,10 +245,13 @@ all_args_description = (
     + master_args_description
     + model_args_description
     + agent_args_description
+    + audit_args_description
     + notebook_args_description
+    + project_args_description
     + shell_args_description
     + template_args_description
     + tensorboard_args_description
+    + token_args_description
     + trial_args_description
     + remote_args_description
     + user_args_description
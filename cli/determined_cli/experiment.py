@@ -9,7 +9,7 @@ import time
 from argparse import FileType, Namespace
 from pathlib import Path
 from pprint import pformat
-from typing import Any, Dict, List, Optional, Set, Tuple
+from typing import Any, Dict, List, Optional, Set, Tuple, cast
 
 import tabulate
 
@@ -125,7 +125,9 @@ def submit_experiment(args: Namespace) -> None:
     experiment_config = _parse_config_file_or_exit(args.config_file)
     model_context = context.Context.from_local(args.model_def, constants.MAX_CONTEXT_SIZE)
 
-    additional_body_fields = {}
+    additional_body_fields = {}  # type: Dict[str, Any]
+    if args.project is not None:
+        additional_body_fields["project_id"] = args.project
     if args.git:
         (
             additional_body_fields["git_remote"],
@@ -406,6 +408,17 @@ def kill_experiment(args: Namespace) -> None:
     print("Killed experiment {}".format(args.experiment_id))
 
 
+@authentication_required
+def continue_experiment(args: Namespace) -> None:
+    body = {}
+    if args.max_trials is not None:
+        body["max_trials"] = args.max_trials
+    if args.max_length is not None:
+        body["max_length"] = args.max_length
+    api.post(args.master, "experiments/{}/continue".format(args.experiment_id), body=body)
+    print("Continued experiment {}".format(args.experiment_id))
+
+
 @authentication_required
 def wait(args: Namespace) -> None:
     while True:
@@ -555,6 +568,21 @@ def set_weight(args: Namespace) -> None:
     print("Set `weight` of experiment {} to {}".format(args.experiment_id, args.weight))
 
 
+def _set_checkpoint_retention(args: Namespace, policy: Dict[str, Any], dry_run: bool) -> List[str]:
+    body = {
+        "saveExperimentBest": policy["save_experiment_best"],
+        "saveTrialBest": policy["save_trial_best"],
+        "saveTrialLatest": policy["save_trial_latest"],
+        "dryRun": dry_run,
+    }
+    r = api.post(
+        args.master,
+        "api/v1/experiments/{}/checkpoint-retention".format(args.experiment_id),
+        body=body,
+    )
+    return cast(List[str], r.json().get("checkpointUuids", []))
+
+
 @authentication_required
 def set_gc_policy(args: Namespace) -> None:
     policy = {
@@ -563,6 +591,13 @@ def set_gc_policy(args: Namespace) -> None:
         "save_trial_latest": args.save_trial_latest,
     }
 
+    if args.dry_run:
+        uuids = _set_checkpoint_retention(args, policy, dry_run=True)
+        print("This policy would delete {} checkpoints:".format(len(uuids)))
+        for uuid in uuids:
+            print(uuid)
+        return
+
     if not args.yes:
         r = api.get(
             args.master, "experiments/{}/preview_gc".format(args.experiment_id), params=policy
@@ -611,8 +646,9 @@ def set_gc_policy(args: Namespace) -> None:
         "in the unrecoverable deletion of checkpoints.  Do you wish to "
         "proceed?"
     ):
-        patch_experiment(args, "change gc policy of", {"checkpoint_storage": policy})
+        uuids = _set_checkpoint_retention(args, policy, dry_run=False)
         print("Set GC policy of experiment {} to\n{}".format(args.experiment_id, pformat(policy)))
+        print("Started garbage collection of {} checkpoints".format(len(uuids)))
     else:
         print("Aborting operations.")
 
@@ -737,6 +773,11 @@ args_description = Cmd(
                     type=str,
                     help="name of template to apply to the experiment configuration",
                 ),
+                Arg(
+                    "--project",
+                    type=int,
+                    help="ID of the project to create the experiment in",
+                ),
                 Group(
                     Arg(
                         "-f",
@@ -832,6 +873,20 @@ args_description = Cmd(
         Cmd(
             "kill", kill_experiment, "kill experiment", [Arg("experiment_id", help="experiment ID")]
         ),
+        Cmd(
+            "continue",
+            continue_experiment,
+            "continue a completed experiment with a larger searcher budget",
+            [
+                experiment_id_arg("experiment ID"),
+                Arg("--max-trials", type=int, help="new maximum number of trials"),
+                Arg(
+                    "--max-length",
+                    type=int,
+                    help="new maximum length of trials, in the units of the searcher's max_length",
+                ),
+            ],
+        ),
         Cmd(
             "wait",
             wait,
@@ -904,6 +959,12 @@ args_description = Cmd(
                             required=True,
                             help="number of latest checkpoints per trial to save",
                         ),
+                        Arg(
+                            "--dry-run",
+                            action="store_true",
+                            default=False,
+                            help="only list the checkpoints that the policy would delete",
+                        ),
                         Arg(
                             "--yes",
                             action="store_true",
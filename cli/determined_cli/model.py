@@ -4,7 +4,14 @@ from typing import Any, List
 
 from determined_common import api
 from determined_common.api.authentication import authentication_required
-from determined_common.experimental import Checkpoint, Determined, Model, ModelOrderBy, ModelSortBy
+from determined_common.experimental import (
+    Checkpoint,
+    Determined,
+    Model,
+    ModelOrderBy,
+    ModelSortBy,
+    ModelVersionStage,
+)
 
 from . import render
 from .declarative_argparse import Arg, Cmd
@@ -67,35 +74,45 @@ def list_models(args: Namespace) -> None:
 
 @authentication_required
 def list_versions(args: Namespace) -> None:
+    params = {}
+    if args.stage:
+        params["stage"] = "STAGE_" + args.stage.upper()
+    r = api.get(args.master, "models/{}/versions".format(args.name), params=params)
+    data = r.json()
+
     if args.json:
-        r = api.get(args.master, "models/{}/versions".format(args.name))
-        data = r.json()
         print(json.dumps(data, indent=2))
 
     else:
-        model = Determined(args.master).get_model(args.name)
+        model = Model.from_json(data["model"], args.master)
         render_model(model)
         print("\n")
 
         headers = [
             "Version #",
+            "Stage",
+            "Aliases",
+            "Tags",
+            "Experiment ID",
             "Trial ID",
             "Batch #",
             "Checkpoint UUID",
             "Validation Metrics",
-            "Metadata",
         ]
 
         values = [
             [
-                ckpt.model_version,
-                ckpt.trial_id,
-                ckpt.batch_number,
-                ckpt.uuid,
-                json.dumps(ckpt.validation, indent=2),
-                json.dumps(ckpt.metadata, indent=2),
+                version["version"],
+                version["stage"][len("STAGE_") :],
+                ", ".join(version.get("aliases", [])),
+                ", ".join(version.get("tags", [])),
+                version.get("experimentId"),
+                version.get("trialId"),
+                version["checkpoint"].get("batchNumber"),
+                version["checkpoint"]["uuid"],
+                json.dumps(version["checkpoint"].get("metrics"), indent=2),
             ]
-            for ckpt in model.get_versions()
+            for version in data["modelVersions"]
         ]
 
         render.tabulate_or_csv(headers, values, False)
@@ -141,6 +158,54 @@ def register_version(args: Namespace) -> None:
         render_model_version(checkpoint)
 
 
+@authentication_required
+def update_version(args: Namespace) -> None:
+    model = Determined(args.master, None).get_model(args.name)
+    model.update_version(
+        args.version,
+        stage=ModelVersionStage[args.stage.upper()] if args.stage else None,
+        notes=args.notes,
+        tags=args.tags,
+    )
+    print("Updated version {} of model {}".format(args.version, args.name))
+
+
+@authentication_required
+def delete_version(args: Namespace) -> None:
+    Determined(args.master, None).get_model(args.name).delete_version(args.version)
+    print("Deleted version {} of model {}".format(args.version, args.name))
+
+
+@authentication_required
+def set_alias(args: Namespace) -> None:
+    Determined(args.master, None).get_model(args.name).set_alias(args.alias, args.version)
+    print("Alias {} of model {} points to version {}".format(args.alias, args.name, args.version))
+
+
+@authentication_required
+def delete_alias(args: Namespace) -> None:
+    Determined(args.master, None).get_model(args.name).delete_alias(args.alias)
+    print("Deleted alias {} of model {}".format(args.alias, args.name))
+
+
+@authentication_required
+def resolve(args: Namespace) -> None:
+    name, _, ref = args.ref.partition(":")
+    model = Determined(args.master, None).get_model(name)
+    if ref.isdigit():
+        checkpoint = model.get_version(int(ref))
+    elif ref:
+        checkpoint = model.get_alias(ref)
+    else:
+        checkpoint = model.get_version()
+
+    if checkpoint is None:
+        raise ValueError("model {} has no versions".format(name))
+    print(checkpoint.uuid)
+
+
+stage_choices = [stage.name.lower() for stage in ModelVersionStage]
+
 args_description = [
     Cmd(
         "m|odel",
@@ -201,9 +266,74 @@ args_description = [
                 "list the versions of a model",
                 [
                     Arg("name", type=str, help="unique name of the model"),
+                    Arg("--stage", type=str, choices=stage_choices, help="only list this stage"),
                     Arg("--json", action="store_true", help="print as JSON"),
                 ],
             ),
+            Cmd(
+                "update-version",
+                update_version,
+                "update the stage, notes or tags of a version of a model",
+                [
+                    Arg("name", type=str, help="name of the model"),
+                    Arg("version", type=int, help="version of the model"),
+                    Arg("--stage", type=str, choices=stage_choices, help="stage of the version"),
+                    Arg("--notes", type=str, help="notes about the version"),
+                    Arg(
+                        "--tag",
+                        dest="tags",
+                        action="append",
+                        help="tag of the version; may be repeated and replaces existing tags",
+                    ),
+                ],
+            ),
+            Cmd(
+                "delete-version",
+                delete_version,
+                "delete a version of a model",
+                [
+                    Arg("name", type=str, help="name of the model"),
+                    Arg("version", type=int, help="version of the model"),
+                ],
+            ),
+            Cmd(
+                "alias",
+                None,
+                "manage the aliases of a model",
+                [
+                    Cmd(
+                        "set",
+                        set_alias,
+                        "point an alias to a version of a model",
+                        [
+                            Arg("name", type=str, help="name of the model"),
+                            Arg("alias", type=str, help="name of the alias, e.g. prod"),
+                            Arg("version", type=int, help="version of the model"),
+                        ],
+                    ),
+                    Cmd(
+                        "delete",
+                        delete_alias,
+                        "delete an alias of a model",
+                        [
+                            Arg("name", type=str, help="name of the model"),
+                            Arg("alias", type=str, help="name of the alias"),
+                        ],
+                    ),
+                ],
+            ),
+            Cmd(
+                "resolve",
+                resolve,
+                "print the checkpoint UUID of a model version",
+                [
+                    Arg(
+                        "ref",
+                        type=str,
+                        help="<model>:<alias>, <model>:<version> or <model> for the latest version",
+                    ),
+                ],
+            ),
             Cmd(
                 "create",
                 create,
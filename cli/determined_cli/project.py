@@ -0,0 +1,114 @@
+import json
+from argparse import FileType, Namespace
+from typing import Any, Dict, List
+
+from termcolor import colored
+
+from determined_common import api, yaml
+from determined_common.api.authentication import authentication_required
+
+from . import render
+from .declarative_argparse import Arg, Cmd
+
+
+def render_projects(projects: List[Dict[str, Any]]) -> None:
+    headers = ["ID", "Name", "Description", "Owner", "Members", "Resource Pool"]
+    values = [
+        [
+            p["id"],
+            p["name"],
+            p["description"],
+            p["owner"],
+            ", ".join(p["members"]),
+            p["resourcePool"],
+        ]
+        for p in projects
+    ]
+    render.tabulate_or_csv(headers, values, False)
+
+
+@authentication_required
+def list_projects(args: Namespace) -> None:
+    projects = api.get(args.master, "/api/v1/projects").json()["projects"]
+    if args.json:
+        print(json.dumps(projects, indent=2))
+    else:
+        render_projects(projects)
+
+
+@authentication_required
+def describe_project(args: Namespace) -> None:
+    project = api.get(args.master, "/api/v1/projects/{}".format(args.project_id)).json()["project"]
+    if args.json:
+        print(json.dumps(project, indent=2))
+    else:
+        render_projects([project])
+        if project.get("checkpointStorage"):
+            print("\nCheckpoint storage:")
+            print(yaml.safe_dump(project["checkpointStorage"], default_flow_style=False))
+
+
+@authentication_required
+def create_project(args: Namespace) -> None:
+    body = {
+        "name": args.name,
+        "description": args.description,
+        "resourcePool": args.resource_pool,
+    }  # type: Dict[str, Any]
+    if args.checkpoint_storage:
+        with args.checkpoint_storage:
+            body["checkpointStorage"] = yaml.safe_load(args.checkpoint_storage)
+    project = api.post(args.master, "/api/v1/projects", body=body).json()["project"]
+    print(colored("Created project {} with ID {}".format(project["name"], project["id"]), "green"))
+
+
+@authentication_required
+def delete_project(args: Namespace) -> None:
+    api.delete(args.master, "/api/v1/projects/{}".format(args.project_id))
+    print(colored("Deleted project {}".format(args.project_id), "green"))
+
+
+@authentication_required
+def add_member(args: Namespace) -> None:
+    api.post(args.master, "/api/v1/projects/{}/members/{}".format(args.project_id, args.username))
+
+
+@authentication_required
+def remove_member(args: Namespace) -> None:
+    api.delete(args.master, "/api/v1/projects/{}/members/{}".format(args.project_id, args.username))
+
+
+# fmt: off
+
+args_description = [
+    Cmd("project", None, "manage projects", [
+        Cmd("list ls", list_projects, "list projects", [
+            Arg("--json", action="store_true", help="print as JSON"),
+        ], is_default=True),
+        Cmd("describe", describe_project, "describe project", [
+            Arg("project_id", type=int, help="ID of the project"),
+            Arg("--json", action="store_true", help="print as JSON"),
+        ]),
+        Cmd("create", create_project, "create project", [
+            Arg("name", help="unique name of the project"),
+            Arg("--description", default="", help="description of the project"),
+            Arg("--resource-pool", default="",
+                help="resource pool of the experiments in the project"),
+            Arg("--checkpoint-storage", type=FileType("r"),
+                help="checkpoint storage of the experiments in the project (.yaml)"),
+        ]),
+        Cmd("delete", delete_project, "delete an empty project", [
+            Arg("project_id", type=int, help="ID of the project"),
+        ]),
+        Cmd("add-member", add_member, "add a member to a project", [
+            Arg("project_id", type=int, help="ID of the project"),
+            Arg("username", help="name of the user to add"),
+        ]),
+        Cmd("remove-member", remove_member, "remove a member from a project", [
+            Arg("project_id", type=int, help="ID of the project"),
+            Arg("username", help="name of the user to remove"),
+        ]),
+    ])
+]  # type: List[Any]
+
+# fmt: on
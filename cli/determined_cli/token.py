@@ -0,0 +1,86 @@
+import json
+from argparse import Namespace
+from datetime import datetime, timedelta, timezone
+from typing import Any, Dict, List
+
+from termcolor import colored
+
+from determined_common import api
+from determined_common.api.authentication import authentication_required
+
+from . import render
+from .declarative_argparse import Arg, Cmd
+
+
+def render_tokens(tokens: List[Dict[str, Any]]) -> None:
+    headers = ["ID", "User", "Description", "Read Only", "Expiry", "Created", "Last Used"]
+    values = [
+        [
+            t["id"],
+            t["username"],
+            t["description"],
+            t["readOnly"],
+            render.format_time(t.get("expiry")),
+            render.format_time(t["creationTime"]),
+            render.format_time(t.get("lastUsed")),
+        ]
+        for t in tokens
+    ]
+    render.tabulate_or_csv(headers, values, False)
+
+
+@authentication_required
+def list_tokens(args: Namespace) -> None:
+    params = {"username": args.username} if args.username else {}
+    tokens = api.get(args.master, "/api/v1/tokens", params=params).json()["tokens"]
+    if args.json:
+        print(json.dumps(tokens, indent=2))
+    else:
+        render_tokens(tokens)
+
+
+@authentication_required
+def create_token(args: Namespace) -> None:
+    body = {
+        "username": args.username or "",
+        "description": args.description,
+        "readOnly": args.read_only,
+    }  # type: Dict[str, Any]
+    if args.expires_in is not None:
+        expiry = datetime.now(timezone.utc) + timedelta(days=args.expires_in)
+        body["expiry"] = expiry.isoformat()
+    resp = api.post(args.master, "/api/v1/tokens", body=body).json()
+    print(colored("Created API token with ID {}".format(resp["token"]["id"]), "green"))
+    print("This is the only time that the token is shown:")
+    print(resp["secret"])
+
+
+@authentication_required
+def revoke_token(args: Namespace) -> None:
+    api.delete(args.master, "/api/v1/tokens/{}".format(args.token_id))
+    print(colored("Revoked API token {}".format(args.token_id), "green"))
+
+
+# fmt: off
+
+args_description = [
+    Cmd("token", None, "manage API tokens", [
+        Cmd("list ls", list_tokens, "list API tokens", [
+            Arg("--username", help="list the tokens of this user (admins only)"),
+            Arg("--json", action="store_true", help="print as JSON"),
+        ], is_default=True),
+        Cmd("create", create_token, "create API token", [
+            Arg("--username", help="create the token for this user (admins only)"),
+            Arg("--description", default="", help="what the token is used for"),
+            Arg("--read-only", action="store_true",
+                help="only allow the token to read from the cluster"),
+            Arg("--expires-in", type=int, metavar="DAYS",
+                help="number of days that the token is valid (default: until revoked)"),
+        ]),
+        Cmd("revoke", revoke_token, "revoke API token", [
+            Arg("token_id", type=int, help="ID of the token"),
+        ]),
+    ])
+]  # type: List[Any]
+
+# fmt: on
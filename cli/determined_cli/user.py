@@ -16,9 +16,20 @@ from .declarative_argparse import Arg, Cmd
 
 FullUser = namedtuple(
     "FullUser",
-    ["username", "admin", "active", "agent_uid", "agent_gid", "agent_user", "agent_group"],
+    [
+        "username",
+        "admin",
+        "active",
+        "role",
+        "agent_uid",
+        "agent_gid",
+        "agent_user",
+        "agent_group",
+    ],
 )
 
+ROLES = ["viewer", "editor", "admin"]
+
 
 def authentication_optional(func: Callable[[Namespace], Any]) -> Callable[[Namespace], Any]:
     @wraps(func)
@@ -40,8 +51,9 @@ def update_user(
     active: Optional[bool] = None,
     password: Optional[str] = None,
     agent_user_group: Optional[Dict[str, Any]] = None,
+    role: Optional[str] = None,
 ) -> Response:
-    if active is None and password is None and agent_user_group is None:
+    if active is None and password is None and agent_user_group is None and role is None:
         raise Exception("Internal error (must supply at least one kwarg to update_user).")
 
     request = {}  # type: Dict[str, Any]
@@ -54,6 +66,9 @@ def update_user(
     if agent_user_group is not None:
         request["agent_user_group"] = agent_user_group
 
+    if role is not None:
+        request["role"] = role
+
     return api.patch(master_address, "users/{}".format(username), body=request)
 
 
@@ -79,6 +94,11 @@ def deactivate_user(parsed_args: Namespace) -> None:
     update_user(parsed_args.username, parsed_args.master, active=False)
 
 
+@authentication_required
+def set_role(parsed_args: Namespace) -> None:
+    update_user(parsed_args.username, parsed_args.master, role=parsed_args.role)
+
+
 def log_in_user(parsed_args: Namespace) -> None:
     if parsed_args.username is None:
         username = input("Username: ")
@@ -188,6 +208,8 @@ def create_user(parsed_args: Namespace) -> None:
     admin = bool(parsed_args.admin)
 
     request = {"username": username, "admin": admin, "active": True}
+    if parsed_args.role is not None:
+        request["role"] = parsed_args.role
     api.post(parsed_args.master, "users", body=request)
 
 
@@ -224,6 +246,12 @@ args_description = [
         Cmd("create", create_user, "create user", [
             Arg("username", help="name of new user"),
             Arg("--admin", action="store_true", help="give new user admin rights"),
+            Arg("--role", choices=ROLES, default=None,
+                help="role of new user (default: admin with --admin, editor otherwise)"),
+        ]),
+        Cmd("set-role", set_role, "change the role of a user", [
+            Arg("username", help="name of user to change the role of"),
+            Arg("role", choices=ROLES, help="new role of the user"),
         ]),
         Cmd("link-with-agent-user", link_with_agent_user, "link a user with UID/GID on agent", [
             Arg("det_username", help="name of Determined user to link"),
@@ -213,6 +213,14 @@ def initialize_session(
 ) -> None:
     auth = Authentication.instance()
 
+    # An API token in the environment, e.g. on a CI system, takes precedence over logging in.
+    env_token = os.environ.get("DET_USER_TOKEN")
+    if env_token:
+        headers = {"Authorization": "Bearer {}".format(env_token)}
+        r = api.get(master_address, "users/me", headers=headers, authenticated=False)
+        auth.session = api.Session(r.json()["username"], env_token)
+        return
+
     session_user = (
         requested_user or auth.token_store.get_active_user() or constants.DEFAULT_DETERMINED_USER
     )
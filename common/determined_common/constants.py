@@ -1,3 +1,5 @@
+import os
+
 # The maximum size of a WebSocket message that can be sent or received
 # by the Determined agent and trial-runner. The master uses a different limit,
 # because it uses the uwsgi WebSocket implementation; see
@@ -30,4 +32,6 @@ ERROR = "ERROR"
 
 TERMINAL_STATES = {COMPLETED, CANCELED, ERROR}
 
-SHARED_FS_CONTAINER_PATH = "/determined_shared_fs"
+# The path that shared_fs storage is mounted at in containers. Tasks that run as plain processes
+# find the mount linked into the directory that their container paths are relative to.
+SHARED_FS_CONTAINER_PATH = os.environ.get("DET_CONTAINER_ROOT", "") + "/determined_shared_fs"
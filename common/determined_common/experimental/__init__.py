@@ -3,4 +3,9 @@ from determined_common.experimental.determined import Determined
 from determined_common.experimental.experiment import ExperimentReference
 from determined_common.experimental.session import Session
 from determined_common.experimental.trial import TrialReference
-from determined_common.experimental.model import Model, ModelOrderBy, ModelSortBy
+from determined_common.experimental.model import (
+    Model,
+    ModelOrderBy,
+    ModelSortBy,
+    ModelVersionStage,
+)
@@ -44,6 +44,23 @@ class ModelOrderBy(enum.Enum):
     DESC = 2
 
 
+class ModelVersionStage(enum.Enum):
+    """
+    Specifies the stage of a model version in its lifecycle.
+
+    Attributes:
+        NONE
+        STAGING
+        PRODUCTION
+        ARCHIVED
+    """
+
+    NONE = "STAGE_NONE"
+    STAGING = "STAGE_STAGING"
+    PRODUCTION = "STAGE_PRODUCTION"
+    ARCHIVED = "STAGE_ARCHIVED"
+
+
 class Model:
     """
     Class representing a model in the model registry. It contains methods for model
@@ -112,7 +129,11 @@ class Model:
         data = resp.json()
         return Checkpoint.from_json(data["modelVersion"]["checkpoint"], self._master)
 
-    def get_versions(self, order_by: ModelOrderBy = ModelOrderBy.DESC) -> List[Checkpoint]:
+    def get_versions(
+        self,
+        order_by: ModelOrderBy = ModelOrderBy.DESC,
+        stage: Optional[ModelVersionStage] = None,
+    ) -> List[Checkpoint]:
         """
         Get a list of checkpoints corresponding to versions of this model. The
         models are sorted by version number and are returned in descending
@@ -120,11 +141,16 @@ class Model:
 
         Arguments:
             order_by (enum): A member of the :class:`ModelOrderBy` enum.
+            stage (enum, optional): If this parameter is set, only versions in
+                this :class:`ModelVersionStage` are returned.
         """
+        params = {"order_by": order_by.value}  # type: Dict[str, Any]
+        if stage is not None:
+            params["stage"] = stage.value
         resp = api.get(
             self._master,
             "/api/v1/models/{}/versions/".format(self.name),
-            params={"order_by": order_by.value},
+            params=params,
         )
         data = resp.json()
 
@@ -166,6 +192,91 @@ class Model:
             self._master,
         )
 
+    def update_version(
+        self,
+        version: int,
+        stage: Optional[ModelVersionStage] = None,
+        notes: Optional[str] = None,
+        tags: Optional[List[str]] = None,
+    ) -> None:
+        """
+        Updates the stage, notes or tags of a version of the model. Fields that
+        are not passed are left unchanged.
+
+        Arguments:
+            version (int): The model version number to update.
+            stage (enum, optional): A member of the :class:`ModelVersionStage` enum.
+            notes (string, optional): Free-form notes about the version.
+            tags (List[string], optional): Tags that replace the tags of the version.
+        """
+        body = {}  # type: Dict[str, Any]
+        if stage is not None:
+            body["stage"] = stage.value
+        if notes is not None:
+            body["notes"] = notes
+        if tags is not None:
+            body["tags"] = tags
+        api.patch(
+            self._master, "/api/v1/models/{}/versions/{}".format(self.name, version), body=body
+        )
+
+    def delete_version(self, version: int) -> None:
+        """
+        Deletes a version of the model along with the aliases that point to it.
+        The checkpoint of the version is not deleted.
+
+        Arguments:
+            version (int): The model version number to delete.
+        """
+        api.delete(self._master, "/api/v1/models/{}/versions/{}".format(self.name, version))
+
+    def get_alias(self, alias: str) -> Checkpoint:
+        """
+        Retrieve the checkpoint corresponding to the version of the model that
+        an alias, e.g. ``"prod"``, points to. If the alias does not exist, an
+        exception is raised.
+
+        Arguments:
+            alias (string): The name of the alias.
+        """
+        resp = api.get(self._master, "/api/v1/models/{}/aliases/{}".format(self.name, alias))
+        return self._version_checkpoint(resp.json()["modelVersion"])
+
+    def set_alias(self, alias: str, version: int) -> None:
+        """
+        Points an alias, e.g. ``"prod"``, to a version of the model. If the
+        alias already points to another version, it is moved.
+
+        Arguments:
+            alias (string): The name of the alias.
+            version (int): The model version number that the alias points to.
+        """
+        api.put(
+            self._master,
+            "/api/v1/models/{}/aliases/{}".format(self.name, alias),
+            body={"model_version": version},
+        )
+
+    def delete_alias(self, alias: str) -> None:
+        """
+        Deletes an alias of the model. The version that it points to is not
+        deleted.
+
+        Arguments:
+            alias (string): The name of the alias.
+        """
+        api.delete(self._master, "/api/v1/models/{}/aliases/{}".format(self.name, alias))
+
+    def _version_checkpoint(self, version: Dict[str, Any]) -> Checkpoint:
+        return Checkpoint.from_json(
+            {
+                **version["checkpoint"],
+                "model_version": version["version"],
+                "model_name": self.name,
+            },
+            self._master,
+        )
+
     def add_metadata(self, metadata: Dict[str, Any]) -> None:
         """
         Adds user-defined metadata to the model. The ``metadata`` argument must be a
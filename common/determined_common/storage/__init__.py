@@ -5,6 +5,7 @@ from typing import Any, Dict, Optional, Type
 
 from determined_common.check import check_eq, check_in, check_type
 
+from .azure import AzureStorageManager
 from .base import StorageManager, StorageMetadata
 from .gcs import GCSStorageManager
 from .hdfs import HDFSStorageManager
@@ -12,6 +13,7 @@ from .s3 import S3StorageManager
 from .shared import SharedFSStorageManager
 
 __all__ = [
+    "AzureStorageManager",
     "GCSStorageManager",
     "StorageManager",
     "StorageMetadata",
@@ -21,6 +23,7 @@ __all__ = [
 
 
 _STORAGE_MANAGERS = {
+    "azure": AzureStorageManager,
     "gcs": GCSStorageManager,
     "s3": S3StorageManager,
     "shared_fs": SharedFSStorageManager,
@@ -53,6 +56,7 @@ def build(config: Dict[str, Any], container_path: Optional[str]) -> StorageManag
     config.pop("save_experiment_best", None)
     config.pop("save_trial_best", None)
     config.pop("save_trial_latest", None)
+    config.pop("max_storage_bytes", None)
 
     # For shared_fs maintain backwards compatibility by folding old keys into
     # storage_path.
@@ -0,0 +1,127 @@
+import contextlib
+import logging
+import os
+import tempfile
+from typing import Iterator, Optional
+
+from azure.core.exceptions import ResourceExistsError
+from azure.storage.blob import BlobServiceClient, ContainerClient
+
+from determined_common import util
+from determined_common.storage.base import StorageManager, StorageMetadata
+
+
+def container_client(
+    container: str,
+    connection_string: Optional[str] = None,
+    account_url: Optional[str] = None,
+    credential: Optional[str] = None,
+    create: bool = True,
+) -> ContainerClient:
+    """
+    Return a client for an Azure Blob Storage container, creating the container if it does not
+    exist and create is set. The storage account is given either by a connection string or by an
+    account URL and an optional credential, such as an account key or a SAS token.
+    """
+    if connection_string:
+        service = BlobServiceClient.from_connection_string(connection_string)
+    elif account_url:
+        service = BlobServiceClient(account_url, credential=credential)
+    else:
+        raise ValueError("Either connection_string or account_url must be set for Azure storage")
+
+    client = service.get_container_client(container)
+    if not create:
+        return client
+    try:
+        client.create_container()
+    except ResourceExistsError:
+        pass
+    return client
+
+
+class AzureStorageManager(StorageManager):
+    """
+    Store and load checkpoints on Azure Blob Storage. Checkpoints are stored as
+    blobs named "<storage id>/<path>" in the configured container.
+
+    This works with the Azurite emulator as well as with Azure, by passing a
+    connection string with the BlobEndpoint of the emulator.
+    """
+
+    def __init__(
+        self,
+        container: str,
+        connection_string: Optional[str] = None,
+        account_url: Optional[str] = None,
+        credential: Optional[str] = None,
+        temp_dir: Optional[str] = None,
+    ) -> None:
+        super().__init__(temp_dir if temp_dir is not None else tempfile.gettempdir())
+        self.container = container
+        self.client = container_client(container, connection_string, account_url, credential)
+
+    def post_store_path(self, storage_id: str, storage_dir: str, metadata: StorageMetadata) -> None:
+        """post_store_path uploads the checkpoint to Azure and deletes the original files."""
+        try:
+            logging.info("Uploading checkpoint {} to Azure".format(storage_id))
+            self.upload(metadata, storage_dir)
+        finally:
+            self._remove_checkpoint_directory(metadata.storage_id)
+
+    @contextlib.contextmanager
+    def restore_path(self, metadata: StorageMetadata) -> Iterator[str]:
+        storage_dir = os.path.join(self._base_path, metadata.storage_id)
+        os.makedirs(storage_dir, exist_ok=True)
+
+        logging.info("Downloading checkpoint {} from Azure".format(metadata.storage_id))
+        self.download(metadata, storage_dir)
+
+        try:
+            yield os.path.join(self._base_path, metadata.storage_id)
+        finally:
+            self._remove_checkpoint_directory(metadata.storage_id)
+
+    @util.preserve_random_state
+    def upload(self, metadata: StorageMetadata, storage_dir: str) -> None:
+        for rel_path in metadata.resources.keys():
+            # Azure discourages blob names that end with "/", so directories are not uploaded;
+            # `download` recreates them from the resources of the checkpoint.
+            if rel_path.endswith("/"):
+                continue
+
+            blob_name = "{}/{}".format(metadata.storage_id, rel_path)
+            logging.debug("Uploading to Azure: {}".format(blob_name))
+
+            abs_path = os.path.join(storage_dir, rel_path)
+            with open(abs_path, "rb") as f:
+                self.client.upload_blob(blob_name, f, overwrite=True)
+
+    @util.preserve_random_state
+    def download(self, metadata: StorageMetadata, storage_dir: str) -> None:
+        for rel_path in metadata.resources.keys():
+            abs_path = os.path.join(storage_dir, rel_path)
+            os.makedirs(os.path.dirname(abs_path), exist_ok=True)
+
+            # Directories are not stored in Azure. See `upload` method for more context.
+            if rel_path.endswith("/"):
+                continue
+
+            blob_name = "{}/{}".format(metadata.storage_id, rel_path)
+            logging.debug("Downloading from Azure: {}".format(blob_name))
+            download_blob(self.client, blob_name, abs_path)
+
+    @util.preserve_random_state
+    def delete(self, metadata: StorageMetadata) -> None:
+        logging.info("Deleting checkpoint {} from Azure".format(metadata.storage_id))
+
+        for rel_path in metadata.resources.keys():
+            if rel_path.endswith("/"):
+                continue
+            logging.debug("Deleting {} from Azure".format(rel_path))
+            self.client.delete_blob("{}/{}".format(metadata.storage_id, rel_path))
+
+
+def download_blob(client: ContainerClient, blob_name: str, path: str) -> None:
+    with open(path, "wb") as f:
+        client.download_blob(blob_name).readinto(f)
@@ -14,6 +14,7 @@ setup(
     python_requires=">=3.5",
     package_data={"determined_common": ["py.typed"]},
     install_requires=[
+        "azure-storage-blob>=12.0.0",
         "google-cloud-storage>=1.20.0",
         # google-cloud-core 1.4.2 breaks our windows cli tests for python 3.5.
         "google-cloud-core<1.4.2",
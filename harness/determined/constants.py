@@ -1,3 +1,4 @@
+import os
 from pathlib import Path
 
 # The port to use (from the container's point of view; it will be mapped to
@@ -51,6 +52,11 @@ INTER_TRAIN_PROCESS_COMM_PORT_2 = INTER_TRAIN_PROCESS_COMM_PORT_1 + MAX_SLOTS_PE
 # specifies that the network interface must be auto-detected.
 AUTO_DETECT_TRIAL_RUNNER_NETWORK_INTERFACE = "DET_AUTO_DETECT_NETWORK_INTERFACE"
 
+# How many seconds a trial container keeps trying to reconnect to the master after losing its
+# connection, e.g., while the master restarts, and the maximum wait between attempts.
+MASTER_RECONNECT_TIMEOUT_SECONDS = 600
+MASTER_RECONNECT_MAX_BACKOFF_SECONDS = 30
+
 # How many seconds horovod waits for startup to complete before failing.
 HOROVOD_STARTUP_TIMEOUT_SECONDS = 1200
 
@@ -63,6 +69,10 @@ HOROVOD_AUTOTUNE_LOG_FILEPATH = "/tmp/autotune_log.csv"
 # large number of machines.
 HOROVOD_GLOO_TIMEOUT_SECONDS = 240
 
+# The directory that the paths of the executing container are relative to. It is only set when the
+# task runs as a plain process rather than in a container.
+CONTAINER_ROOT = os.environ.get("DET_CONTAINER_ROOT", "")
+
 # The well-known locations of the executing container's STDOUT and STDERR.
-CONTAINER_STDOUT = "/run/determined/train/logs/stdout.log"
-CONTAINER_STDERR = "/run/determined/train/logs/stderr.log"
+CONTAINER_STDOUT = CONTAINER_ROOT + "/run/determined/train/logs/stdout.log"
+CONTAINER_STDERR = CONTAINER_ROOT + "/run/determined/train/logs/stderr.log"
@@ -1,13 +1,20 @@
 import json
 import os
+import pathlib
 import subprocess
 import sys
+import threading
 import time
-from typing import Callable, List
+from typing import Callable, Dict, List, Tuple
 
 import boto3
 import requests
 
+from determined_common.storage.azure import container_client, download_blob
+
+AZURE_SCHEME = "az://"
+AZURE_SYNC_PERIOD = 10
+
 
 def set_s3_region(bucket: str) -> None:
     endpoint_url = os.environ.get("DET_S3_ENDPOINT", None)
@@ -24,6 +31,64 @@ def set_s3_region(bucket: str) -> None:
         os.environ["AWS_REGION"] = str(region)
 
 
+def localize_azure_logdirs(
+    args: List[str], local_root: pathlib.Path
+) -> Tuple[List[str], List[Tuple[str, str, pathlib.Path]]]:
+    """
+    TensorBoard cannot read from Azure Blob Storage, so replace the az://<container>/<prefix>
+    directories of the --logdir argument with local directories. Return the new arguments and the
+    container, blob prefix and local directory of each replaced directory.
+    """
+    args = list(args)
+    sources = []
+    for i, arg in enumerate(args[:-1]):
+        if arg != "--logdir":
+            continue
+        logdirs = []
+        for logdir in args[i + 1].split(","):
+            name, sep, path = "", "", logdir
+            if not logdir.startswith(AZURE_SCHEME) and ":" in logdir:
+                name, sep, path = logdir.partition(":")
+            if path.startswith(AZURE_SCHEME):
+                container, _, prefix = path[len(AZURE_SCHEME) :].partition("/")
+                local = local_root.joinpath(container, prefix)
+                sources.append((container, prefix, local))
+                path = str(local)
+            logdirs.append(name + sep + path)
+        args[i + 1] = ",".join(logdirs)
+    return args, sources
+
+
+def sync_azure_logs(sources: List[Tuple[str, str, pathlib.Path]]) -> None:
+    """Periodically copy new and grown files under the Azure directories to local disk."""
+    sizes = {}  # type: Dict[str, int]
+    clients = {
+        container: container_client(
+            container,
+            os.environ.get("AZURE_STORAGE_CONNECTION_STRING"),
+            os.environ.get("DET_AZURE_ACCOUNT_URL"),
+            os.environ.get("DET_AZURE_CREDENTIAL"),
+            create=False,
+        )
+        for container, _, _ in sources
+    }
+    while True:
+        for container, prefix, local in sources:
+            client = clients[container]
+            try:
+                for blob in client.list_blobs(name_starts_with=prefix):
+                    key = f"{container}/{blob.name}"
+                    if blob.name.endswith("/") or sizes.get(key) == blob.size:
+                        continue
+                    path = local.joinpath(blob.name[len(prefix) :].lstrip("/"))
+                    path.parent.mkdir(parents=True, exist_ok=True)
+                    download_blob(client, blob.name, str(path))
+                    sizes[key] = blob.size
+            except Exception as e:
+                print(f"Failed to copy logs from {AZURE_SCHEME}{container}/{prefix}: {e}")
+        time.sleep(AZURE_SYNC_PERIOD)
+
+
 def wait_for_tensorboard(max_seconds: float, url: str, still_alive_fn: Callable[[], bool]) -> bool:
     """Return True if the process successfully comes up before a deadline."""
 
@@ -63,12 +128,17 @@ def wait_for_tensorboard(max_seconds: float, url: str, still_alive_fn: Callable[
 
 
 def main(args: List[str]) -> int:
-    with open("/run/determined/workdir/experiment_config.json") as f:
+    root = os.environ.get("DET_CONTAINER_ROOT", "")
+    with open(root + "/run/determined/workdir/experiment_config.json") as f:
         exp_conf = json.load(f)
 
     if exp_conf["checkpoint_storage"]["type"] == "s3":
         set_s3_region(exp_conf["checkpoint_storage"]["bucket"])
 
+    args, azure_sources = localize_azure_logdirs(args, pathlib.Path("/tmp/tensorboard-azure"))
+    if azure_sources:
+        threading.Thread(target=sync_azure_logs, args=(azure_sources,), daemon=True).start()
+
     task_id = os.environ["DET_TASK_ID"]
     port = os.environ["TENSORBOARD_PORT"]
     tensorboard_addr = f"http://localhost:{port}/proxy/{task_id}"
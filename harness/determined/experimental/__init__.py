@@ -5,6 +5,7 @@ from determined_common.experimental import (
     Model,
     ModelOrderBy,
     ModelSortBy,
+    ModelVersionStage,
     TrialReference,
 )
 from determined.experimental._native import (
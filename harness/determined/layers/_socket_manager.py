@@ -1,14 +1,16 @@
 import logging
 import socket
 import ssl
-from typing import Any, Optional
+import time
+from typing import Any, Iterator, Optional
 
 import lomond
+import lomond.errors
 import lomond.session
 import simplejson
 
 import determined as det
-from determined import layers, util, workload
+from determined import constants, layers, util, workload
 
 
 class CustomSSLWebsocketSession(lomond.session.WebsocketSession):  # type: ignore
@@ -41,7 +43,12 @@ class SocketManager(workload.Source):
     def __init__(self, env: det.EnvContext) -> None:
         self.env = env
 
-        url = "{}://{}:{}/ws/trial/{}/{}/{}".format(
+        # The last response sent to the master, which is resent after reconnecting in case the
+        # master missed it.
+        self.last_response = None  # type: Optional[workload.Metrics]
+        self.closed = False
+
+        self.url = "{}://{}:{}/ws/trial/{}/{}/{}".format(
             "wss" if self.env.use_tls else "ws",
             self.env.master_addr,
             self.env.master_port,
@@ -50,13 +57,7 @@ class SocketManager(workload.Source):
             self.env.container_id,
         )
 
-        # Disable reading proxy configuration because we shouldn't proxy our
-        # own connection to the master.
-        self.socket = lomond.WebSocket(url, proxies={})
-
-        self.ws_events = self.socket.connect(
-            ping_rate=0, session_class=lambda socket: CustomSSLWebsocketSession(socket, env)
-        )
+        self.ws_events = self.connect()
 
         # Handle the messages up to and including the rendezvous message.
         for ws_event in self.ws_events:
@@ -72,9 +73,14 @@ class SocketManager(workload.Source):
         # Always yield the initial workload first.
         yield from self.yield_workload(self.env.initial_workload)
 
-        # Then pass workloads which arrive on the websocket.
-        for ws_event in self.ws_events:
-            yield from self.handle_event(ws_event)
+        # Then pass workloads which arrive on the websocket, reconnecting to the master whenever
+        # the connection is lost, e.g., because the master restarted.
+        while True:
+            for ws_event in self.ws_events:
+                yield from self.handle_event(ws_event)
+            if self.closed:
+                return
+            self.reconnect()
 
     def __enter__(self) -> "SocketManager":
         return self
@@ -82,7 +88,43 @@ class SocketManager(workload.Source):
     def __exit__(self, *_: Any) -> None:
         self.close()
 
+    def connect(self) -> Iterator[Any]:
+        # Disable reading proxy configuration because we shouldn't proxy our
+        # own connection to the master.
+        self.socket = lomond.WebSocket(self.url, proxies={})
+
+        return self.socket.connect(  # type: ignore
+            ping_rate=0, session_class=lambda socket: CustomSSLWebsocketSession(socket, self.env)
+        )
+
+    def reconnect(self) -> None:
+        """
+        Reconnect to the master with a backoff, and resend the last response once connected.
+        Raise an error if the master cannot be reached in time.
+        """
+
+        deadline = time.time() + constants.MASTER_RECONNECT_TIMEOUT_SECONDS
+        backoff = 1
+        while time.time() < deadline:
+            time.sleep(backoff)
+            backoff = min(backoff * 2, constants.MASTER_RECONNECT_MAX_BACKOFF_SECONDS)
+
+            self.ws_events = self.connect()
+            for ws_event in self.ws_events:
+                if isinstance(ws_event, lomond.events.Ready):
+                    self.message_is_log_only(ws_event)
+                    if self.last_response is not None:
+                        logging.info("Resending the last response to the master")
+                        resent = dict(self.last_response, type="WORKLOAD_COMPLETED_RESENT")
+                        self.socket.send_text(util.json_encode(resent))
+                    return
+                if not self.message_is_log_only(ws_event):
+                    logging.warning(f"Unexpected websocket event: {ws_event}")
+
+        raise ValueError("Failed to reconnect to the master")
+
     def close(self) -> None:
+        self.closed = True
         self.socket.close()
 
         # Empty the websocket.
@@ -197,7 +239,12 @@ class SocketManager(workload.Source):
             duration = metrics["end_time"] - metrics["start_time"]
             logging.info(f"Workload completed: {metrics['workload']} (duration {duration})")
 
-            self.socket.send_text(util.json_encode(metrics))
+            self.last_response = metrics
+            try:
+                self.socket.send_text(util.json_encode(metrics))
+            except lomond.errors.Error as e:
+                # The response is resent once the socket reconnects.
+                logging.warning(f"Failed to send response to master: {e}")
 
         yield wkld, [], respond
 
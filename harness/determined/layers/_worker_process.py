@@ -189,7 +189,7 @@ class SubprocessLauncher:
             "-p",
             str(constants.HOROVOD_SSH_PORT),
             "-f",
-            "/run/determined/ssh/sshd_config",
+            constants.CONTAINER_ROOT + "/run/determined/ssh/sshd_config",
             "-D",
         ]
         logging.debug(
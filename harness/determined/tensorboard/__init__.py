@@ -1,3 +1,4 @@
+from determined.tensorboard.azure import AzureTensorboardManager
 from determined.tensorboard.base import TensorboardManager
 from determined.tensorboard.build import build, get_base_path, get_sync_path
 from determined.tensorboard.metric_writers import BatchMetricWriter, MetricWriter
@@ -0,0 +1,35 @@
+import logging
+from typing import Any, Optional
+
+from determined.tensorboard import base
+from determined_common import util
+from determined_common.storage.azure import container_client
+
+
+class AzureTensorboardManager(base.TensorboardManager):
+    """
+    Store and load tf event logs from Azure Blob Storage.
+    """
+
+    def __init__(
+        self,
+        container: str,
+        connection_string: Optional[str],
+        account_url: Optional[str],
+        credential: Optional[str],
+        *args: Any,
+        **kwargs: Any,
+    ) -> None:
+        super().__init__(*args, **kwargs)
+        self.container = container
+        self.client = container_client(container, connection_string, account_url, credential)
+
+    @util.preserve_random_state
+    def sync(self) -> None:
+        for path in self.to_sync():
+            blob_name = str(self.sync_path.joinpath(path.relative_to(self.base_path)))
+            logging.debug(f"Uploading to Azure: {self.container}/{blob_name}")
+
+            with path.open("rb") as f:
+                self.client.upload_blob(blob_name, f, overwrite=True)
+            self._synced_event_sizes[path] = path.stat().st_size
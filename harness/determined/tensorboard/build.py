@@ -3,7 +3,7 @@ import pathlib
 from typing import Any, Dict, Optional
 
 import determined as det
-from determined.tensorboard import base, gcs, hdfs, s3, shared
+from determined.tensorboard import azure, base, gcs, hdfs, s3, shared
 from determined_common.storage.shared import _full_storage_path
 
 
@@ -70,6 +70,16 @@ def build(
     elif type_name == "gcs":
         return gcs.GCSTensorboardManager(checkpoint_config["bucket"], base_path, sync_path)
 
+    elif type_name == "azure":
+        return azure.AzureTensorboardManager(
+            checkpoint_config["container"],
+            checkpoint_config.get("connection_string", None),
+            checkpoint_config.get("account_url", None),
+            checkpoint_config.get("credential", None),
+            base_path,
+            sync_path,
+        )
+
     elif type_name == "s3":
         return s3.S3TensorboardManager(
             checkpoint_config["bucket"],
@@ -0,0 +1,66 @@
+"""
+These tests run against the Azurite emulator, e.g.:
+
+    docker run -p 10000:10000 mcr.microsoft.com/azure-storage/azurite \
+        azurite-blob --blobHost 0.0.0.0
+    AZURITE_CONNECTION_STRING="DefaultEndpointsProtocol=http;AccountName=devstoreaccount1;\
+AccountKey=<key>;BlobEndpoint=http://127.0.0.1:10000/devstoreaccount1;" pytest tests/storage
+"""
+import os
+import uuid
+from pathlib import Path
+
+import pytest
+from azure.core.exceptions import ResourceNotFoundError
+
+from determined_common import storage
+from tests.storage import util
+
+CONNECTION_STRING = os.environ.get("AZURITE_CONNECTION_STRING")
+
+pytestmark = pytest.mark.skipif(
+    CONNECTION_STRING is None, reason="AZURITE_CONNECTION_STRING is not set"
+)
+
+
+@pytest.fixture
+def manager(tmp_path: Path) -> storage.AzureStorageManager:
+    return storage.AzureStorageManager(
+        container="test-{}".format(uuid.uuid4()),
+        connection_string=CONNECTION_STRING,
+        temp_dir=str(tmp_path),
+    )
+
+
+def test_azure_lifecycle(manager: storage.AzureStorageManager) -> None:
+    assert len(os.listdir(manager._base_path)) == 0
+
+    checkpoints = []
+    for _ in range(3):
+        with manager.store_path() as (storage_id, path):
+            # Ensure no checkpoint directories exist yet.
+            assert len(os.listdir(manager._base_path)) == 0
+            util.create_checkpoint(path)
+            metadata = storage.StorageMetadata(storage_id, manager._list_directory(path))
+            checkpoints.append(metadata)
+            assert set(metadata.resources) == set(util.EXPECTED_FILES.keys())
+
+    for metadata in checkpoints:
+        with manager.restore_path(metadata) as path:
+            util.validate_checkpoint(path)
+        manager.delete(metadata)
+        with pytest.raises(ResourceNotFoundError):
+            with manager.restore_path(metadata) as path:
+                pass
+
+
+def test_azure_build(tmp_path: Path) -> None:
+    config = {
+        "type": "azure",
+        "container": "test-{}".format(uuid.uuid4()),
+        "connection_string": CONNECTION_STRING,
+        "save_experiment_best": 0,
+        "max_storage_bytes": None,
+        "temp_dir": str(tmp_path),
+    }
+    storage.validate_config(config, container_path=None)
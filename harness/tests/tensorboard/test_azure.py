@@ -0,0 +1,39 @@
+import pathlib
+
+from determined.exec import tensorboard
+
+
+def test_localize_azure_logdirs() -> None:
+    args = [
+        "--logdir",
+        "trial_1:az://logs/cluster/tensorboard/experiment/1/trial/1/,"
+        "az://logs/cluster/tensorboard/experiment/2/,"
+        "trial_3:s3://bucket/cluster/tensorboard/experiment/3/trial/3/",
+        "--samples_per_plugin",
+        "images=0",
+    ]
+    local_root = pathlib.Path("/tmp/tensorboard-azure")
+
+    localized, sources = tensorboard.localize_azure_logdirs(args, local_root)
+
+    assert localized == [
+        "--logdir",
+        "trial_1:/tmp/tensorboard-azure/logs/cluster/tensorboard/experiment/1/trial/1,"
+        "/tmp/tensorboard-azure/logs/cluster/tensorboard/experiment/2,"
+        "trial_3:s3://bucket/cluster/tensorboard/experiment/3/trial/3/",
+        "--samples_per_plugin",
+        "images=0",
+    ]
+    assert sources == [
+        (
+            "logs",
+            "cluster/tensorboard/experiment/1/trial/1/",
+            local_root.joinpath("logs/cluster/tensorboard/experiment/1/trial/1"),
+        ),
+        (
+            "logs",
+            "cluster/tensorboard/experiment/2/",
+            local_root.joinpath("logs/cluster/tensorboard/experiment/2"),
+        ),
+    ]
+    assert tensorboard.localize_azure_logdirs(args[2:], local_root) == (args[2:], [])
@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"fmt"
 	"net/http"
 	"sort"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/telemetry"
 	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/actor/actors"
 	ws "github.com/determined-ai/determined/master/pkg/actor/api"
 	aproto "github.com/determined-ai/determined/master/pkg/agent"
 	"github.com/determined-ai/determined/master/pkg/check"
@@ -27,9 +29,35 @@ type agent struct {
 	resourcePool     *actor.Ref
 	socket           *actor.Ref
 	slots            *actor.Ref
-	containers       map[container.ID]*actor.Ref
+	containers       map[container.ID]*agentContainer
 	resourcePoolName string
 	label            string
+	// started is set once the agent has announced its devices to the master.
+	started bool
+	// draining is set while the agent is being drained.
+	draining bool
+
+	// quarantineFailures is how many distinct tasks must fail on the agent, without a task
+	// container succeeding in between, before it is quarantined; zero disables quarantining.
+	// quarantineTaskFailures is how many times the containers of a single task must fail on the
+	// agent in such a run; it is higher, since a task may also keep failing because of a bug in
+	// its code. failures counts the failures of each task in the current run.
+	quarantineFailures     int
+	quarantineTaskFailures int
+	failures               map[*actor.Ref]int
+	quarantined            bool
+	quarantineReason       string
+
+	// reconnectWait is how long the containers of the agent are kept after its socket disconnects
+	// before they are considered lost; zero disables reconnection.
+	reconnectWait time.Duration
+	// disconnects counts the socket disconnections, so that a reconnect timeout only applies to
+	// the disconnection that scheduled it.
+	disconnects int
+	// reconnecting is set from a socket disconnection until the agent announces its containers
+	// again. Messages for the agent are held in pending meanwhile.
+	reconnecting bool
+	pending      []aproto.AgentMessage
 
 	// uuid is an anonymous ID that is used when reporting telemetry
 	// information to allow agent connection and disconnection events
@@ -40,6 +68,27 @@ type agent struct {
 	opts *aproto.MasterSetAgentOptions
 }
 
+// agentContainer is a container on the agent, along with the task actor that it belongs to. The
+// task actor is nil for containers that the agent started for a previous master until a task that
+// this master restored reattaches to them.
+type agentContainer struct {
+	container.Container
+	taskActor *actor.Ref
+	// signaled is set once the master signals the container, so that it exiting does not count as
+	// a failure of the agent.
+	signaled bool
+}
+
+// reconnectTimeout notifies the agent that it has waited long enough for its socket to reconnect
+// after the given disconnection.
+type reconnectTimeout struct {
+	disconnect int
+}
+
+// reattachTimeout notifies the agent that the tasks restored by this master had long enough to
+// reattach to the containers that the agent ran for a previous master.
+type reattachTimeout struct{}
+
 // AgentSummary summarizes the state on an agent.
 type AgentSummary struct {
 	ID             string       `json:"id"`
@@ -48,6 +97,10 @@ type AgentSummary struct {
 	NumContainers  int          `json:"num_containers"`
 	ResourcePool   string       `json:"resource_pool"`
 	Label          string       `json:"label"`
+	Draining       bool         `json:"draining"`
+	Quarantined    bool         `json:"quarantined"`
+	// QuarantineReason describes why the agent was quarantined, if it is.
+	QuarantineReason string `json:"quarantine_reason,omitempty"`
 }
 
 func (a *agent) Receive(ctx *actor.Context) error {
@@ -55,11 +108,19 @@ func (a *agent) Receive(ctx *actor.Context) error {
 	case actor.PreStart:
 		a.uuid = uuid.New()
 		a.slots, _ = ctx.ActorOf("slots", &slots{resourcePool: a.resourcePool})
-		a.containers = make(map[container.ID]*actor.Ref)
+		a.containers = make(map[container.ID]*agentContainer)
 	case AgentSummary:
 		ctx.Respond(a.summarize(ctx))
 	case ws.WebSocketConnected:
-		check.Panic(check.True(a.socket == nil, "websocket already connected"))
+		if a.socket != nil {
+			ctx.Respond(errors.Errorf("agent already connected: %s", ctx.Self().Address().Local()))
+			return nil
+		}
+		if pool := msg.Ctx.QueryParam("resource_pool"); pool != "" && pool != a.resourcePoolName {
+			ctx.Respond(errors.Errorf("agent %s cannot reconnect to resource pool %s, it is in %s",
+				ctx.Self().Address().Local(), pool, a.resourcePoolName))
+			return nil
+		}
 		socket, ok := msg.Accept(ctx, aproto.MasterMessage{}, true)
 		check.Panic(check.True(ok, "failed to accept websocket connection"))
 		a.socket = socket
@@ -70,24 +131,34 @@ func (a *agent) Receive(ctx *actor.Context) error {
 			a.address = msg.Ctx.Request().RemoteAddr[0:lastColonIndex]
 		}
 		ctx.Ask(a.socket, ws.WriteMessage{Message: aproto.AgentMessage{MasterSetAgentOptions: a.opts}})
+		if a.started {
+			ctx.Log().Infof("agent reconnected ip: %v", a.address)
+		}
 	case sproto.KillTaskContainer:
 		ctx.Log().Infof("killing container id: %s", msg.ContainerID)
-		killMsg := aproto.SignalContainer{
-			ContainerID: msg.ContainerID, Signal: syscall.SIGKILL,
+		a.killContainer(ctx, msg.ContainerID)
+	case sproto.ReattachTaskContainer:
+		a.reattachContainer(ctx, msg)
+	case reattachTimeout:
+		for id, c := range a.containers {
+			if c.taskActor == nil {
+				ctx.Log().Infof("killing container id: %s that no task reattached to", id)
+				a.killContainer(ctx, id)
+			}
 		}
-		ctx.Ask(a.socket, ws.WriteMessage{Message: aproto.AgentMessage{SignalContainer: &killMsg}})
 	case aproto.SignalContainer:
-		ctx.Ask(a.socket, ws.WriteMessage{Message: aproto.AgentMessage{SignalContainer: &msg}})
+		a.containerSignaled(msg.ContainerID)
+		a.send(ctx, aproto.AgentMessage{SignalContainer: &msg})
 	case sproto.StartTaskContainer:
 		ctx.Log().Infof("starting container id: %s slots: %d task handler: %s",
 			msg.StartContainer.Container.ID, len(msg.StartContainer.Container.Devices),
 			msg.TaskActor.Address())
 
-		ctx.Ask(a.socket, ws.WriteMessage{Message: aproto.AgentMessage{
-			StartContainer: &msg.StartContainer,
-		}})
+		a.send(ctx, aproto.AgentMessage{StartContainer: &msg.StartContainer})
 		ctx.Tell(a.slots, msg.StartContainer)
-		a.containers[msg.Container.ID] = msg.TaskActor
+		a.containers[msg.Container.ID] = &agentContainer{
+			Container: msg.StartContainer.Container, taskActor: msg.TaskActor,
+		}
 	case aproto.MasterMessage:
 		a.handleIncomingWSMessage(ctx, msg)
 	case *proto.GetAgentRequest:
@@ -101,28 +172,42 @@ func (a *agent) Receive(ctx *actor.Context) error {
 		ctx.Respond(&proto.GetSlotsResponse{Slots: slots})
 	case *proto.EnableAgentRequest:
 		ctx.Tell(a.slots, patchSlot{Enabled: true})
+		a.setDraining(ctx, false)
+		a.setQuarantined(ctx, false, "")
 		ctx.Respond(&proto.EnableAgentResponse{Agent: ToProtoAgent(a.summarize(ctx))})
 	case *proto.DisableAgentRequest:
 		ctx.Tell(a.slots, patchSlot{Enabled: false})
 		ctx.Respond(&proto.DisableAgentResponse{Agent: ToProtoAgent(a.summarize(ctx))})
+	case *proto.DrainAgentRequest:
+		a.setDraining(ctx, true)
+		ctx.Respond(&proto.DrainAgentResponse{Agent: ToProtoAgent(a.summarize(ctx))})
+	case *proto.GetAgentDrainStatusRequest:
+		ctx.Respond(ctx.Ask(a.resourcePool, sproto.GetAgentDrainStatus{Agent: ctx.Self()}).Get())
 	case echo.Context:
 		a.handleAPIRequest(ctx, msg)
 	case actor.ChildFailed:
+		if msg.Child == a.socket {
+			ctx.Log().WithError(msg.Error).Warn("agent socket failed")
+			return a.socketDisconnected(ctx)
+		}
 		telemetry.ReportAgentDisconnected(ctx.Self().System(), a.uuid)
 
 		return errors.Wrapf(msg.Error, "child failed: %s", msg.Child.Address())
+	case actor.ChildStopped:
+		if msg.Child != a.socket {
+			return actor.ErrUnexpectedMessage(ctx)
+		}
+		return a.socketDisconnected(ctx)
+	case reconnectTimeout:
+		if a.socket == nil && msg.disconnect == a.disconnects {
+			ctx.Log().Warnf("agent did not reconnect within %s", a.reconnectWait)
+			telemetry.ReportAgentDisconnected(ctx.Self().System(), a.uuid)
+			ctx.Self().Stop()
+		}
 	case actor.PostStop:
 		ctx.Log().Infof("agent disconnected")
-		for cid := range a.containers {
-			stopped := aproto.ContainerError(
-				aproto.AgentFailed, errors.New("agent failed while container was running"))
-			a.containerStateChanged(ctx, aproto.ContainerStateChanged{
-				Container: container.Container{
-					ID:    cid,
-					State: container.Terminated,
-				},
-				ContainerStopped: &stopped,
-			})
+		for _, c := range a.containers {
+			a.containerLost(ctx, c.Container, "agent failed while container was running")
 		}
 		ctx.Tell(a.resourcePool, sproto.RemoveAgent{Agent: ctx.Self()})
 	default:
@@ -140,23 +225,218 @@ func (a *agent) handleAPIRequest(ctx *actor.Context, apiCtx echo.Context) {
 	}
 }
 
+// setDraining starts or stops draining the agent, which asks its tasks to release their resources
+// and stops scheduling tasks on it.
+func (a *agent) setDraining(ctx *actor.Context, draining bool) {
+	if a.draining == draining {
+		return
+	}
+	a.draining = draining
+	ctx.Tell(a.resourcePool, sproto.SetAgentDraining{Agent: ctx.Self(), Draining: draining})
+}
+
+// socketDisconnected waits for the agent to reconnect after its socket went away, keeping its
+// containers and slots, or stops the agent if reconnection is disabled.
+func (a *agent) socketDisconnected(ctx *actor.Context) error {
+	a.socket = nil
+	if a.reconnectWait == 0 || !a.started {
+		telemetry.ReportAgentDisconnected(ctx.Self().System(), a.uuid)
+		ctx.Self().Stop()
+		return nil
+	}
+
+	a.disconnects++
+	a.reconnecting = true
+	ctx.Log().Warnf("agent disconnected, waiting %s for it to reconnect", a.reconnectWait)
+	actors.NotifyAfter(ctx, a.reconnectWait, reconnectTimeout{disconnect: a.disconnects})
+	return nil
+}
+
+// send writes the message to the agent, or holds it until the agent has reconnected.
+func (a *agent) send(ctx *actor.Context, msg aproto.AgentMessage) {
+	if a.socket == nil || a.reconnecting {
+		a.pending = append(a.pending, msg)
+		return
+	}
+	ctx.Ask(a.socket, ws.WriteMessage{Message: msg})
+}
+
+func (a *agent) killContainer(ctx *actor.Context, id container.ID) {
+	a.containerSignaled(id)
+	a.send(ctx, aproto.AgentMessage{SignalContainer: &aproto.SignalContainer{
+		ContainerID: id, Signal: syscall.SIGKILL,
+	}})
+}
+
+func (a *agent) containerSignaled(id container.ID) {
+	if c, ok := a.containers[id]; ok {
+		c.signaled = true
+	}
+}
+
+// setQuarantined quarantines the agent, which stops scheduling tasks on it but leaves the tasks
+// that run on it alone, or releases it.
+func (a *agent) setQuarantined(ctx *actor.Context, quarantined bool, reason string) {
+	a.failures = nil
+	if a.quarantined == quarantined {
+		return
+	}
+	a.quarantined = quarantined
+	a.quarantineReason = reason
+	ctx.Tell(a.resourcePool, sproto.SetAgentQuarantined{Agent: ctx.Self(), Quarantined: quarantined})
+}
+
+// taskContainerStopped counts the failures of the containers that fail on their own on the agent
+// in a row, by task, and quarantines the agent once too many distinct tasks failed on it or a
+// single task failed on it too many times.
+func (a *agent) taskContainerStopped(
+	ctx *actor.Context, c *agentContainer, stopped aproto.ContainerStopped,
+) {
+	switch {
+	case a.quarantineFailures == 0 || c.taskActor == nil || c.signaled:
+		return
+	case stopped.Failure == nil:
+		a.failures = nil
+		return
+	case stopped.Failure.FailureType != aproto.ContainerFailed:
+		return
+	}
+	if a.failures == nil {
+		a.failures = make(map[*actor.Ref]int)
+	}
+	a.failures[c.taskActor]++
+
+	var reason string
+	switch {
+	case a.quarantined:
+		return
+	case len(a.failures) >= a.quarantineFailures:
+		reason = fmt.Sprintf("containers of %d tasks failed in a row", len(a.failures))
+	case a.quarantineTaskFailures > 0 && a.failures[c.taskActor] >= a.quarantineTaskFailures:
+		reason = fmt.Sprintf("containers of task %s failed %d times in a row",
+			c.taskActor.Address(), a.failures[c.taskActor])
+	default:
+		return
+	}
+	ctx.Log().Warnf("quarantining agent: %s", reason)
+	a.setQuarantined(ctx, true, reason)
+}
+
+// reconnected re-adopts the containers that are still running on the agent after it reconnects
+// and reports the containers that the agent no longer runs as lost. It then sends the messages
+// that were held while the agent was disconnected.
+func (a *agent) reconnected(ctx *actor.Context, started aproto.AgentStarted) {
+	ctx.Log().Infof("agent resumed with %d running containers", len(started.Containers))
+
+	running := make(map[container.ID]bool)
+	for _, c := range started.Containers {
+		running[c.ID] = true
+		if _, ok := a.containers[c.ID]; !ok {
+			ctx.Log().Infof("killing unknown container id: %s", c.ID)
+			a.killContainer(ctx, c.ID)
+		}
+	}
+	starting := make(map[container.ID]bool)
+	for _, msg := range a.pending {
+		if msg.StartContainer != nil {
+			starting[msg.StartContainer.Container.ID] = true
+		}
+	}
+	for id, c := range a.containers {
+		if !running[id] && !starting[id] {
+			a.containerLost(ctx, c.Container, "container was lost while the agent was disconnected")
+		}
+	}
+
+	a.reconnecting = false
+	pending := a.pending
+	a.pending = nil
+	for _, msg := range pending {
+		a.send(ctx, msg)
+	}
+}
+
+// reattachContainer hands a container that the agent ran for a previous master over to the task
+// that reattaches to it, and tells the task the state of the container.
+func (a *agent) reattachContainer(ctx *actor.Context, msg sproto.ReattachTaskContainer) {
+	c, ok := a.containers[msg.ContainerID]
+	if !ok {
+		stopped := aproto.ContainerError(
+			aproto.AgentFailed, errors.New("container exited before it was reattached"))
+		ctx.Tell(msg.TaskActor, sproto.TaskContainerStateChanged{
+			Container:        container.Container{ID: msg.ContainerID, State: container.Terminated},
+			ContainerStopped: &sproto.TaskContainerStopped{ContainerStopped: stopped},
+		})
+		return
+	}
+	ctx.Log().Infof("reattaching container id: %s task handler: %s",
+		msg.ContainerID, msg.TaskActor.Address())
+	c.taskActor = msg.TaskActor
+	rsc := sproto.TaskContainerStateChanged{Container: c.Container}
+	if c.State == container.Running {
+		// The addresses of the container are only needed for the rendezvous of the task, which
+		// its containers went through before the master restarted.
+		rsc.ContainerStarted = &sproto.TaskContainerStarted{}
+	}
+	ctx.Tell(c.taskActor, rsc)
+}
+
+func (a *agent) containerLost(ctx *actor.Context, c container.Container, reason string) {
+	stopped := aproto.ContainerError(aproto.AgentFailed, errors.New(reason))
+	c.State = container.Terminated
+	a.containerStateChanged(ctx, aproto.ContainerStateChanged{
+		Container: c, ContainerStopped: &stopped,
+	})
+}
+
 func (a *agent) handleIncomingWSMessage(ctx *actor.Context, msg aproto.MasterMessage) {
 	switch {
+	case msg.AgentStarted != nil && a.started:
+		a.reconnected(ctx, *msg.AgentStarted)
 	case msg.AgentStarted != nil:
 		telemetry.ReportAgentConnected(ctx.Self().System(), a.uuid, msg.AgentStarted.Devices)
 		ctx.Log().Infof("agent connected ip: %v resource pool: %s slots: %d",
 			a.address, a.resourcePoolName, len(msg.AgentStarted.Devices))
 
-		ctx.Tell(a.resourcePool, sproto.AddAgent{Agent: ctx.Self(), Label: msg.AgentStarted.Label})
+		add := sproto.AddAgent{Agent: ctx.Self(), Label: msg.AgentStarted.Label}
 		ctx.Tell(a.slots, *msg.AgentStarted)
 		a.label = msg.AgentStarted.Label
+		a.started = true
+
+		// The containers that the agent started for a previous master are kept for as long as
+		// disconnected agents are, so that the tasks that this master restores can reattach to
+		// them; the containers that no task reattaches to are killed then. The slots keep the
+		// devices of the containers busy until they are gone.
+		for _, c := range msg.AgentStarted.Containers {
+			a.containers[c.ID] = &agentContainer{Container: c}
+			if a.reconnectWait == 0 {
+				ctx.Log().Infof("killing container id: %s from a previous master", c.ID)
+				a.killContainer(ctx, c.ID)
+			}
+		}
+		if a.reconnectWait > 0 && len(msg.AgentStarted.Containers) > 0 {
+			ctx.Log().Infof("waiting %s for tasks to reattach to %d containers from a previous master",
+				a.reconnectWait, len(msg.AgentStarted.Containers))
+			actors.NotifyAfter(ctx, a.reconnectWait, reattachTimeout{})
+			add.Containers = msg.AgentStarted.Containers
+		}
+		ctx.Tell(a.resourcePool, add)
 	case msg.ContainerStateChanged != nil:
 		a.containerStateChanged(ctx, *msg.ContainerStateChanged)
+	case msg.DevicesHealthChanged != nil:
+		for _, d := range msg.DevicesHealthChanged.Devices {
+			if !d.Healthy {
+				ctx.Log().Warnf("disabling unhealthy device %s: %s", d.Device.String(), d.Reason)
+			}
+		}
+		ctx.Tell(a.slots, *msg.DevicesHealthChanged)
 	case msg.ContainerLog != nil:
-		ref, ok := a.containers[msg.ContainerLog.Container.ID]
-		check.Panic(check.True(ok,
-			"container not allocated to agent: container %s", msg.ContainerLog.Container.ID))
-		ctx.Tell(ref, sproto.ContainerLog{
+		c, ok := a.containers[msg.ContainerLog.Container.ID]
+		if !ok || c.taskActor == nil {
+			ctx.Log().Debugf("ignoring log of unknown container: %s", msg.ContainerLog.Container.ID)
+			return
+		}
+		ctx.Tell(c.taskActor, sproto.ContainerLog{
 			Container:   msg.ContainerLog.Container,
 			Timestamp:   msg.ContainerLog.Timestamp,
 			PullMessage: msg.ContainerLog.PullMessage,
@@ -169,8 +449,13 @@ func (a *agent) handleIncomingWSMessage(ctx *actor.Context, msg aproto.MasterMes
 }
 
 func (a *agent) containerStateChanged(ctx *actor.Context, sc aproto.ContainerStateChanged) {
-	taskActor, ok := a.containers[sc.Container.ID]
-	check.Panic(check.True(ok, "container not allocated to agent: container %s", sc.Container.ID))
+	c, ok := a.containers[sc.Container.ID]
+	if !ok {
+		// The container was already reported lost while the agent was disconnected.
+		ctx.Log().Warnf("ignoring state change of unknown container: %s", sc.Container.ID)
+		return
+	}
+	c.Container = sc.Container
 
 	rsc := sproto.TaskContainerStateChanged{Container: sc.Container}
 	switch sc.Container.State {
@@ -189,17 +474,25 @@ func (a *agent) containerStateChanged(ctx *actor.Context, sc aproto.ContainerSta
 		}
 	}
 
-	ctx.Tell(taskActor, rsc)
+	if c.taskActor != nil {
+		ctx.Tell(c.taskActor, rsc)
+	}
 	ctx.Tell(a.slots, sc)
+	if sc.Container.State == container.Terminated {
+		a.taskContainerStopped(ctx, c, *sc.ContainerStopped)
+	}
 }
 
 func (a *agent) summarize(ctx *actor.Context) AgentSummary {
 	return AgentSummary{
-		ID:             ctx.Self().Address().Local(),
-		RegisteredTime: ctx.Self().RegisteredTime(),
-		Slots:          ctx.Ask(a.slots, SlotsSummary{}).Get().(SlotsSummary),
-		NumContainers:  len(a.containers),
-		ResourcePool:   a.resourcePoolName,
-		Label:          a.label,
+		ID:               ctx.Self().Address().Local(),
+		RegisteredTime:   ctx.Self().RegisteredTime(),
+		Slots:            ctx.Ask(a.slots, SlotsSummary{}).Get().(SlotsSummary),
+		NumContainers:    len(a.containers),
+		ResourcePool:     a.resourcePoolName,
+		Label:            a.label,
+		Draining:         a.draining,
+		Quarantined:      a.quarantined,
+		QuarantineReason: a.quarantineReason,
 	}
 }
@@ -0,0 +1,410 @@
+package agent
+
+import (
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	ws "github.com/determined-ai/determined/master/pkg/actor/api"
+	aproto "github.com/determined-ai/determined/master/pkg/agent"
+	"github.com/determined-ai/determined/master/pkg/container"
+	"github.com/determined-ai/determined/master/pkg/device"
+	proto "github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+// mockActor records the messages that it receives. Messages written to it as a websocket are
+// recorded as the message that is written.
+type mockActor struct {
+	messages []actor.Message
+}
+
+type getMessages struct{}
+
+func (m *mockActor) Receive(ctx *actor.Context) error {
+	switch msg := ctx.Message().(type) {
+	case actor.PreStart, actor.PostStop:
+	case getMessages:
+		ctx.Respond(append([]actor.Message(nil), m.messages...))
+	case ws.WriteMessage:
+		m.messages = append(m.messages, msg.Message)
+		ctx.Respond(ws.WriteResponse{})
+	default:
+		m.messages = append(m.messages, msg)
+	}
+	return nil
+}
+
+// testAgent is an agent actor whose socket, resource pool and task are mock actors.
+type testAgent struct {
+	system  *actor.System
+	agent   *agent
+	ref     *actor.Ref
+	pool    *actor.Ref
+	task    *actor.Ref
+	socket  *actor.Ref
+	sockets int
+	devices []device.Device
+}
+
+// newTestAgent starts an agent that announces the containers that it ran for a previous master.
+func newTestAgent(
+	t *testing.T, reconnectWait time.Duration, quarantineFailures int, running ...container.Container,
+) *testAgent {
+	system := actor.NewSystem(t.Name())
+	ta := &testAgent{
+		system:  system,
+		devices: []device.Device{{ID: 0, Type: device.GPU}, {ID: 1, Type: device.GPU}},
+	}
+	ta.pool, _ = system.ActorOf(actor.Addr("pool"), &mockActor{})
+	ta.task, _ = system.ActorOf(actor.Addr("task"), &mockActor{})
+	ta.socket = ta.newSocket()
+	ta.agent = &agent{
+		resourcePool:       ta.pool,
+		socket:             ta.socket,
+		reconnectWait:      reconnectWait,
+		quarantineFailures: quarantineFailures,
+		// Like the default configuration, a single task must fail twice as often.
+		quarantineTaskFailures: 2 * quarantineFailures,
+	}
+	var ok bool
+	ta.ref, ok = system.ActorOf(actor.Addr("agent"), ta.agent)
+	assert.Assert(t, ok)
+	ta.ask(aproto.MasterMessage{AgentStarted: &aproto.AgentStarted{
+		Devices: ta.devices, Containers: running,
+	}})
+	return ta
+}
+
+func (ta *testAgent) newSocket() *actor.Ref {
+	ta.sockets++
+	socket, _ := ta.system.ActorOf(actor.Addr(fmt.Sprintf("socket-%d", ta.sockets)), &mockActor{})
+	return socket
+}
+
+// ask sends the message to the agent and waits for the agent to process it.
+func (ta *testAgent) ask(msg actor.Message) {
+	ta.system.Ask(ta.ref, msg).Get()
+}
+
+func (ta *testAgent) messages(ref *actor.Ref) []actor.Message {
+	return ta.system.Ask(ref, getMessages{}).Get().([]actor.Message)
+}
+
+// startContainer starts a container of the task on the device.
+func (ta *testAgent) startContainer(deviceID int) container.Container {
+	return ta.startTaskContainer(ta.task, deviceID)
+}
+
+// startTaskContainer starts a container of the given task on the device.
+func (ta *testAgent) startTaskContainer(task *actor.Ref, deviceID int) container.Container {
+	c := container.Container{
+		ID:      container.NewID(),
+		State:   container.Assigned,
+		Devices: []device.Device{ta.devices[deviceID]},
+	}
+	ta.ask(sproto.StartTaskContainer{
+		TaskActor:      task,
+		StartContainer: aproto.StartContainer{Container: c},
+	})
+	return c
+}
+
+// stopContainer reports that the container exited with the given failure.
+func (ta *testAgent) stopContainer(c container.Container, failure *aproto.ContainerFailure) {
+	c.State = container.Terminated
+	ta.ask(aproto.MasterMessage{ContainerStateChanged: &aproto.ContainerStateChanged{
+		Container: c, ContainerStopped: &aproto.ContainerStopped{Failure: failure},
+	}})
+}
+
+// failTask starts a container of the task on the first device that fails on its own.
+func (ta *testAgent) failTask(task *actor.Ref) {
+	ta.stopContainer(ta.startTaskContainer(task, 0), &aproto.ContainerFailure{
+		FailureType: aproto.ContainerFailed, ErrMsg: "exit code 1",
+	})
+}
+
+func (ta *testAgent) summary() AgentSummary {
+	return ta.system.Ask(ta.ref, AgentSummary{}).Get().(AgentSummary)
+}
+
+// slotSummaries returns the summaries of the slots of the agent by their device ID.
+func (ta *testAgent) slotSummaries() map[int]SlotSummary {
+	slots := make(map[int]SlotSummary)
+	for _, s := range ta.summary().Slots {
+		slots[s.Device.ID] = s
+	}
+	return slots
+}
+
+// quarantineMessages returns whether the agent was quarantined by each message that told its
+// resource pool about it.
+func (ta *testAgent) quarantineMessages() []bool {
+	var quarantined []bool
+	for _, msg := range ta.messages(ta.pool) {
+		if m, ok := msg.(sproto.SetAgentQuarantined); ok {
+			quarantined = append(quarantined, m.Quarantined)
+		}
+	}
+	return quarantined
+}
+
+func (ta *testAgent) disconnect() {
+	ta.ask(actor.ChildStopped{Child: ta.socket})
+}
+
+// reconnect connects a new socket to the agent, over which the agent announces the containers that
+// it still runs.
+func (ta *testAgent) reconnect(running ...container.Container) {
+	// The agent is idle once it processed the last message, so the socket can be replaced as a
+	// websocket connection would.
+	ta.socket = ta.newSocket()
+	ta.agent.socket = ta.socket
+	ta.ask(aproto.MasterMessage{AgentStarted: &aproto.AgentStarted{
+		Devices: ta.devices, Containers: running,
+	}})
+}
+
+// stoppedContainers returns the containers that the task was told stopped.
+func (ta *testAgent) stoppedContainers() map[container.ID]*sproto.TaskContainerStopped {
+	stopped := make(map[container.ID]*sproto.TaskContainerStopped)
+	for _, msg := range ta.messages(ta.task) {
+		if sc, ok := msg.(sproto.TaskContainerStateChanged); ok && sc.ContainerStopped != nil {
+			stopped[sc.Container.ID] = sc.ContainerStopped
+		}
+	}
+	return stopped
+}
+
+// killedContainers returns the containers that the agent was told over its socket to kill.
+func (ta *testAgent) killedContainers() []container.ID {
+	var killed []container.ID
+	for _, msg := range ta.messages(ta.socket) {
+		if m, ok := msg.(aproto.AgentMessage); ok && m.SignalContainer != nil &&
+			m.SignalContainer.Signal == syscall.SIGKILL {
+			killed = append(killed, m.SignalContainer.ContainerID)
+		}
+	}
+	return killed
+}
+
+func TestAgentReconnectKeepsContainers(t *testing.T) {
+	ta := newTestAgent(t, time.Hour, 0)
+	c := ta.startContainer(0)
+
+	ta.disconnect()
+	ta.reconnect(c)
+
+	assert.Equal(t, len(ta.stoppedContainers()), 0)
+	assert.Equal(t, len(ta.killedContainers()), 0)
+	assert.Equal(t, ta.summary().NumContainers, 1)
+}
+
+func TestAgentReconnectLostContainer(t *testing.T) {
+	ta := newTestAgent(t, time.Hour, 0)
+	lost := ta.startContainer(0)
+	unknown := container.Container{ID: container.NewID(), State: container.Running}
+
+	ta.disconnect()
+	ta.reconnect(unknown)
+
+	stopped := ta.stoppedContainers()
+	assert.Equal(t, len(stopped), 1)
+	assert.Assert(t, stopped[lost.ID] != nil)
+	assert.Equal(t, stopped[lost.ID].Failure.FailureType, aproto.AgentFailed)
+	// Containers that the master does not know are killed.
+	assert.DeepEqual(t, ta.killedContainers(), []container.ID{unknown.ID})
+}
+
+func TestAgentReconnectFlushesPendingMessages(t *testing.T) {
+	ta := newTestAgent(t, time.Hour, 0)
+
+	ta.disconnect()
+	c := ta.startContainer(0)
+	ta.reconnect()
+
+	// The container was started while the agent was disconnected, so it is started now rather than
+	// reported lost.
+	assert.Equal(t, len(ta.stoppedContainers()), 0)
+	messages := ta.messages(ta.socket)
+	assert.Equal(t, len(messages), 1)
+	start := messages[0].(aproto.AgentMessage).StartContainer
+	assert.Assert(t, start != nil)
+	assert.Equal(t, start.Container.ID, c.ID)
+}
+
+func TestAgentReconnectTimeout(t *testing.T) {
+	ta := newTestAgent(t, time.Hour, 0)
+	c := ta.startContainer(0)
+
+	ta.disconnect()
+	// A timeout of an earlier disconnection is ignored.
+	ta.ask(reconnectTimeout{disconnect: 0})
+	assert.Equal(t, len(ta.stoppedContainers()), 0)
+
+	ta.ask(reconnectTimeout{disconnect: 1})
+	assert.NilError(t, ta.ref.AwaitTermination())
+	stopped := ta.stoppedContainers()
+	assert.Assert(t, stopped[c.ID] != nil)
+	assert.Equal(t, stopped[c.ID].Failure.FailureType, aproto.AgentFailed)
+	var removed bool
+	for _, msg := range ta.messages(ta.pool) {
+		if _, ok := msg.(sproto.RemoveAgent); ok {
+			removed = true
+		}
+	}
+	assert.Assert(t, removed, "agent was not removed from its resource pool")
+}
+
+func TestAgentReattachContainers(t *testing.T) {
+	reattached := container.Container{
+		ID: container.NewID(), State: container.Running,
+		Devices: []device.Device{{ID: 0, Type: device.GPU}},
+	}
+	orphan := container.Container{
+		ID: container.NewID(), State: container.Running,
+		Devices: []device.Device{{ID: 1, Type: device.GPU}},
+	}
+	ta := newTestAgent(t, time.Hour, 0, reattached, orphan)
+
+	// The containers of the previous master are kept and announced to the resource pool.
+	assert.Equal(t, len(ta.killedContainers()), 0)
+	var announced []container.ID
+	for _, msg := range ta.messages(ta.pool) {
+		if m, ok := msg.(sproto.AddAgent); ok {
+			for _, c := range m.Containers {
+				announced = append(announced, c.ID)
+			}
+		}
+	}
+	assert.DeepEqual(t, announced, []container.ID{reattached.ID, orphan.ID})
+
+	ta.ask(sproto.ReattachTaskContainer{TaskActor: ta.task, ContainerID: reattached.ID})
+	messages := ta.messages(ta.task)
+	assert.Equal(t, len(messages), 1)
+	rsc := messages[0].(sproto.TaskContainerStateChanged)
+	assert.Equal(t, rsc.Container.ID, reattached.ID)
+	assert.Equal(t, rsc.Container.State, container.Running)
+	assert.Assert(t, rsc.ContainerStarted != nil)
+
+	// A task that reattaches to a container that is gone is told that it stopped.
+	gone := container.NewID()
+	ta.ask(sproto.ReattachTaskContainer{TaskActor: ta.task, ContainerID: gone})
+	stopped := ta.stoppedContainers()
+	assert.Assert(t, stopped[gone] != nil)
+	assert.Equal(t, stopped[gone].Failure.FailureType, aproto.AgentFailed)
+
+	// The containers that no task reattached to are killed.
+	ta.ask(reattachTimeout{})
+	assert.DeepEqual(t, ta.killedContainers(), []container.ID{orphan.ID})
+}
+
+func TestAgentQuarantine(t *testing.T) {
+	ta := newTestAgent(t, 0, 2)
+	other, _ := ta.system.ActorOf(actor.Addr("other"), &mockActor{})
+
+	ta.failTask(ta.task)
+	assert.Assert(t, !ta.summary().Quarantined)
+
+	running := ta.startContainer(1)
+	ta.failTask(other)
+	summary := ta.summary()
+	assert.Assert(t, summary.Quarantined)
+	assert.Equal(t, summary.QuarantineReason, "containers of 2 tasks failed in a row")
+
+	// The resource pool stops scheduling tasks on the agent, but the slots of the agent stay
+	// enabled and the container that runs on it is left alone.
+	assert.DeepEqual(t, ta.quarantineMessages(), []bool{true})
+	for _, s := range ta.slotSummaries() {
+		assert.Assert(t, s.Enabled)
+		assert.Equal(t, s.DisabledReason, "")
+	}
+	assert.Equal(t, ta.slotSummaries()[1].Container.ID, running.ID)
+	ta.ask(actor.Ping{})
+	assert.Equal(t, len(ta.killedContainers()), 0)
+	ta.stopContainer(running, nil)
+	assert.Assert(t, ta.summary().Quarantined)
+
+	ta.ask(&proto.EnableAgentRequest{})
+	summary = ta.summary()
+	assert.Assert(t, !summary.Quarantined)
+	assert.Equal(t, summary.QuarantineReason, "")
+	assert.DeepEqual(t, ta.quarantineMessages(), []bool{true, false})
+}
+
+func TestAgentQuarantineRepeatedTaskFailures(t *testing.T) {
+	ta := newTestAgent(t, 0, 2)
+
+	// A single task that keeps failing counts, but only after more failures than distinct tasks.
+	for i := 0; i < 3; i++ {
+		ta.failTask(ta.task)
+	}
+	assert.Assert(t, !ta.summary().Quarantined)
+
+	ta.failTask(ta.task)
+	summary := ta.summary()
+	assert.Assert(t, summary.Quarantined)
+	assert.Equal(t, summary.QuarantineReason,
+		fmt.Sprintf("containers of task %s failed 4 times in a row", ta.task.Address()))
+}
+
+func TestAgentQuarantineResetOnSuccess(t *testing.T) {
+	ta := newTestAgent(t, 0, 2)
+	tasks := make([]*actor.Ref, 3)
+	for i := range tasks {
+		tasks[i], _ = ta.system.ActorOf(actor.Addr(fmt.Sprintf("task-%d", i)), &mockActor{})
+	}
+
+	ta.failTask(tasks[0])
+	ta.stopContainer(ta.startTaskContainer(tasks[1], 0), nil)
+	ta.failTask(tasks[2])
+	assert.Assert(t, !ta.summary().Quarantined)
+
+	// Containers that the master kills do not count as failures.
+	c := ta.startTaskContainer(tasks[0], 0)
+	ta.ask(sproto.KillTaskContainer{ContainerID: c.ID})
+	ta.stopContainer(c, &aproto.ContainerFailure{FailureType: aproto.ContainerFailed})
+	assert.Assert(t, !ta.summary().Quarantined)
+
+	ta.failTask(tasks[1])
+	assert.Assert(t, ta.summary().Quarantined)
+}
+
+func TestSlotDeviceHealth(t *testing.T) {
+	ta := newTestAgent(t, 0, 0)
+	setHealth := func(healthy bool, reason string) {
+		ta.ask(aproto.MasterMessage{DevicesHealthChanged: &aproto.DevicesHealthChanged{
+			Devices: []aproto.DeviceHealth{
+				{Device: ta.devices[0], Healthy: true},
+				{Device: ta.devices[1], Healthy: healthy, Reason: reason},
+			},
+		}})
+	}
+
+	setHealth(false, "ECC errors")
+	slots := ta.slotSummaries()
+	assert.Assert(t, slots[0].Enabled)
+	assert.Equal(t, slots[0].DisabledReason, "")
+	assert.Assert(t, !slots[1].Enabled)
+	assert.Equal(t, slots[1].DisabledReason, "device is unhealthy: ECC errors")
+
+	// Quarantining the agent does not disable its slots.
+	ta.agent.quarantineFailures = 1
+	ta.failTask(ta.task)
+	assert.Assert(t, ta.summary().Quarantined)
+	slots = ta.slotSummaries()
+	assert.Assert(t, slots[0].Enabled)
+	assert.Equal(t, slots[1].DisabledReason, "device is unhealthy: ECC errors")
+
+	ta.ask(&proto.EnableAgentRequest{})
+	setHealth(true, "")
+	for _, s := range ta.slotSummaries() {
+		assert.Assert(t, s.Enabled)
+		assert.Equal(t, s.DisabledReason, "")
+	}
+}
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo"
 	"github.com/pkg/errors"
@@ -14,18 +15,28 @@ import (
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 )
 
-// Initialize creates a new global agent actor.
+// Initialize creates a new global agent actor. Disconnected agents keep their containers for
+// reconnectWait; a zero reconnectWait disables agent reconnection. Agents are quarantined once
+// the containers of quarantineFailures distinct tasks, or quarantineTaskFailures containers of a
+// single task, fail in a row; a zero quarantineFailures disables quarantining.
 func Initialize(
 	system *actor.System, e *echo.Echo, opts *aproto.MasterSetAgentOptions,
+	reconnectWait time.Duration, quarantineFailures, quarantineTaskFailures int,
 ) {
-	_, ok := system.ActorOf(sproto.AgentsAddr, &agents{opts: opts})
+	_, ok := system.ActorOf(sproto.AgentsAddr, &agents{
+		opts: opts, reconnectWait: reconnectWait, quarantineFailures: quarantineFailures,
+		quarantineTaskFailures: quarantineTaskFailures,
+	})
 	check.Panic(check.True(ok, "agents address already taken"))
 	// Route /agents and /agents/<agent id>/slots to the agents actor and slots actors.
 	e.Any("/agents*", api.Route(system, nil))
 }
 
 type agents struct {
-	opts *aproto.MasterSetAgentOptions
+	opts                   *aproto.MasterSetAgentOptions
+	reconnectWait          time.Duration
+	quarantineFailures     int
+	quarantineTaskFailures int
 }
 
 type agentsSummary map[string]AgentSummary
@@ -34,7 +45,10 @@ func (a *agents) Receive(ctx *actor.Context) error {
 	switch msg := ctx.Message().(type) {
 	case api.WebSocketConnected:
 		id, resourcePool := msg.Ctx.QueryParam("id"), msg.Ctx.QueryParam("resource_pool")
-		if ref, err := a.createAgentActor(ctx, id, resourcePool, a.opts); err != nil {
+		if ref := ctx.Child(id); ref != nil {
+			// The agent is reconnecting; its actor decides whether to accept the new socket.
+			ctx.Respond(ctx.Ask(ref, msg).Get())
+		} else if ref, err := a.createAgentActor(ctx, id, resourcePool, a.opts); err != nil {
 			ctx.Respond(err)
 		} else {
 			ctx.Respond(ctx.Ask(ref, msg).Get())
@@ -68,9 +82,12 @@ func (a *agents) createAgentActor(
 		return nil, errors.Wrapf(err, "cannot find specified resource pool for agent %s", id)
 	}
 	ref, ok := ctx.ActorOf(id, &agent{
-		resourcePool:     sproto.GetRP(ctx.Self().System(), resourcePool),
-		resourcePoolName: resourcePool,
-		opts:             opts,
+		resourcePool:           sproto.GetRP(ctx.Self().System(), resourcePool),
+		resourcePoolName:       resourcePool,
+		opts:                   opts,
+		reconnectWait:          a.reconnectWait,
+		quarantineFailures:     a.quarantineFailures,
+		quarantineTaskFailures: a.quarantineTaskFailures,
 	})
 	if !ok {
 		return nil, errors.Errorf("agent already connected: %s", id)
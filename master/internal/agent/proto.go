@@ -12,20 +12,24 @@ func ToProtoAgent(a AgentSummary) *proto.Agent {
 		slots[s.ID] = toProtoSlot(s)
 	}
 	return &proto.Agent{
-		Id:             a.ID,
-		RegisteredTime: protoutils.ToTimestamp(a.RegisteredTime),
-		Slots:          slots,
-		Containers:     nil,
-		Label:          a.Label,
-		ResourcePool:   a.ResourcePool,
+		Id:               a.ID,
+		RegisteredTime:   protoutils.ToTimestamp(a.RegisteredTime),
+		Slots:            slots,
+		Containers:       nil,
+		Label:            a.Label,
+		ResourcePool:     a.ResourcePool,
+		Draining:         a.Draining,
+		Quarantined:      a.Quarantined,
+		QuarantineReason: a.QuarantineReason,
 	}
 }
 
 func toProtoSlot(s SlotSummary) *proto.Slot {
 	return &proto.Slot{
-		Id:        s.ID,
-		Device:    s.Device.Proto(),
-		Enabled:   s.Enabled,
-		Container: s.Container.Proto(),
+		Id:             s.ID,
+		Device:         s.Device.Proto(),
+		Enabled:        s.Enabled,
+		Container:      s.Container.Proto(),
+		DisabledReason: s.DisabledReason,
 	}
 }
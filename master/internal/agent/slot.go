@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/labstack/echo"
@@ -21,16 +22,19 @@ type slot struct {
 	device       device.Device
 	enabled      slotEnabled
 	container    *container.Container
+	// unhealthyReason describes why the slot was disabled automatically.
+	unhealthyReason string
 }
 
 type slotEnabled struct {
-	deviceAdded  bool
-	agentEnabled bool
-	userEnabled  bool
+	deviceAdded   bool
+	agentEnabled  bool
+	userEnabled   bool
+	deviceHealthy bool
 }
 
 func (s slotEnabled) Enabled() bool {
-	return s.agentEnabled && s.userEnabled
+	return s.agentEnabled && s.userEnabled && s.deviceHealthy
 }
 
 // SlotSummary summarizes the state of a slot.
@@ -39,12 +43,19 @@ type SlotSummary struct {
 	Device    device.Device        `json:"device"`
 	Enabled   bool                 `json:"enabled"`
 	Container *container.Container `json:"container"`
+	// DisabledReason describes why the slot was disabled automatically, if it was.
+	DisabledReason string `json:"disabled_reason,omitempty"`
 }
 
 type (
 	patchSlot struct {
 		Enabled bool `json:"enabled"`
 	}
+	// setDeviceHealth disables the slot while its device is unhealthy.
+	setDeviceHealth struct {
+		Healthy bool
+		Reason  string
+	}
 )
 
 func (s *slot) Receive(ctx *actor.Context) error {
@@ -56,6 +67,13 @@ func (s *slot) Receive(ctx *actor.Context) error {
 	case patchSlot:
 		s.enabled.userEnabled = msg.Enabled
 		s.patch(ctx)
+	case setDeviceHealth:
+		s.enabled.deviceHealthy = msg.Healthy
+		s.unhealthyReason = ""
+		if !msg.Healthy {
+			s.unhealthyReason = msg.Reason
+		}
+		s.patch(ctx)
 	case aproto.StartContainer:
 		check.Panic(check.True(s.enabled.Enabled(), "container allocated but slot is not enabled"))
 		check.Panic(check.True(s.container == nil, "container already allocated to slot"))
@@ -134,9 +152,17 @@ func (s *slot) deviceID(ctx *actor.Context) sproto.DeviceID {
 
 func (s *slot) summarize(ctx *actor.Context) SlotSummary {
 	return SlotSummary{
-		ID:        ctx.Self().Address().Local(),
-		Device:    s.device,
-		Enabled:   s.enabled.Enabled(),
-		Container: s.container,
+		ID:             ctx.Self().Address().Local(),
+		Device:         s.device,
+		Enabled:        s.enabled.Enabled(),
+		Container:      s.container,
+		DisabledReason: s.disabledReason(),
+	}
+}
+
+func (s *slot) disabledReason() string {
+	if !s.enabled.deviceHealthy {
+		return fmt.Sprintf("device is unhealthy: %s", s.unhealthyReason)
 	}
+	return ""
 }
@@ -27,12 +27,24 @@ func (s *slots) Receive(ctx *actor.Context) error {
 	case SlotsSummary:
 		ctx.Respond(s.summarize(ctx))
 	case aproto.AgentStarted:
+		// Devices of containers that are already running on the agent stay allocated to them.
+		running := make(map[int]container.Container)
+		for _, c := range msg.Containers {
+			for _, d := range c.Devices {
+				running[d.ID] = c
+			}
+		}
 		for _, d := range msg.Devices {
 			enabled := slotEnabled{
-				agentEnabled: true,
-				userEnabled:  true,
+				agentEnabled:  true,
+				userEnabled:   true,
+				deviceHealthy: true,
+			}
+			slot := &slot{resourcePool: s.resourcePool, enabled: enabled, device: d}
+			if c, ok := running[d.ID]; ok {
+				slot.container = &c
 			}
-			_, ok := ctx.ActorOf(d.ID, &slot{resourcePool: s.resourcePool, enabled: enabled, device: d})
+			_, ok := ctx.ActorOf(d.ID, slot)
 			check.Panic(check.True(ok, "error registering slot, slot %s already created", d.ID))
 		}
 	case aproto.StartContainer:
@@ -41,6 +53,12 @@ func (s *slots) Receive(ctx *actor.Context) error {
 		for _, child := range ctx.Children() {
 			ctx.Tell(child, msg)
 		}
+	case aproto.DevicesHealthChanged:
+		for _, d := range msg.Devices {
+			if child := ctx.Child(d.Device.ID); child != nil {
+				ctx.Tell(child, setDeviceHealth{Healthy: d.Healthy, Reason: d.Reason})
+			}
+		}
 	case aproto.ContainerStateChanged:
 		s.sendToSlots(ctx, msg.Container, msg)
 	case echo.Context:
@@ -63,6 +63,19 @@ func (a *apiServer) DisableAgent(
 	return resp, err
 }
 
+func (a *apiServer) DrainAgent(
+	_ context.Context, req *apiv1.DrainAgentRequest) (resp *apiv1.DrainAgentResponse, err error) {
+	err = a.actorRequest(fmt.Sprintf("/agents/%s", req.AgentId), req, &resp)
+	return resp, err
+}
+
+func (a *apiServer) GetAgentDrainStatus(
+	_ context.Context, req *apiv1.GetAgentDrainStatusRequest,
+) (resp *apiv1.GetAgentDrainStatusResponse, err error) {
+	err = a.actorRequest(fmt.Sprintf("/agents/%s", req.AgentId), req, &resp)
+	return resp, err
+}
+
 func (a *apiServer) EnableSlot(
 	_ context.Context, req *apiv1.EnableSlotRequest) (resp *apiv1.EnableSlotResponse, err error) {
 	err = a.actorRequest(fmt.Sprintf("/agents/%s/slots/%s", req.AgentId, req.SlotId), req, &resp)
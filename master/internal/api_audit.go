@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/grpc"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func (a *apiServer) GetAuditEvents(
+	ctx context.Context, req *apiv1.GetAuditEventsRequest,
+) (*apiv1.GetAuditEventsResponse, error) {
+	user, _, err := grpc.GetUser(ctx, a.m.db)
+	if err != nil {
+		return nil, err
+	}
+	if !user.Admin {
+		return nil, grpc.ErrPermissionDenied
+	}
+
+	var startTime, endTime *time.Time
+	if req.StartTime != nil {
+		t, err := ptypes.Timestamp(req.StartTime)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid start time: %s", err)
+		}
+		startTime = &t
+	}
+	if req.EndTime != nil {
+		t, err := ptypes.Timestamp(req.EndTime)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid end time: %s", err)
+		}
+		endTime = &t
+	}
+
+	resp := &apiv1.GetAuditEventsResponse{}
+	if err = a.m.db.QueryProto(
+		"get_audit_events", &resp.Events,
+		startTime, endTime, req.Username, req.ResourceType, req.ResourceId,
+	); err != nil {
+		return nil, errors.Wrap(err, "error fetching audit events from database")
+	}
+	return resp, a.paginate(&resp.Pagination, &resp.Events, req.Offset, req.Limit)
+}
@@ -2,7 +2,9 @@ package internal
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
@@ -10,6 +12,7 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/grpc"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 	"github.com/determined-ai/determined/proto/pkg/checkpointv1"
 )
@@ -58,3 +61,101 @@ func (a *apiServer) PostCheckpointMetadata(
 	return &apiv1.PostCheckpointMetadataResponse{Checkpoint: currCheckpoint},
 		errors.Wrapf(err, "error updating checkpoint %s in database", req.Checkpoint.Uuid)
 }
+
+func (a *apiServer) ExportCheckpoint(
+	ctx context.Context, req *apiv1.ExportCheckpointRequest,
+) (*apiv1.ExportCheckpointResponse, error) {
+	getResp, err := a.GetCheckpoint(ctx,
+		&apiv1.GetCheckpointRequest{CheckpointUuid: req.CheckpointUuid})
+	if err != nil {
+		return nil, err
+	}
+	ckpt := getResp.Checkpoint
+	if ckpt.State != checkpointv1.State_STATE_COMPLETED {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"checkpoint %s is in state %s", ckpt.Uuid, ckpt.State)
+	}
+
+	modelDef, err := a.m.db.ExperimentModelDefinitionRaw(int(ckpt.ExperimentId))
+	if err != nil {
+		return nil, errors.Wrapf(err,
+			"error fetching model definition of experiment %d", ckpt.ExperimentId)
+	}
+	return &apiv1.ExportCheckpointResponse{Bundle: &checkpointv1.CheckpointBundle{
+		FormatVersion:   checkpointBundleFormatVersion,
+		SourceClusterId: a.m.ClusterID,
+		Checkpoint:      ckpt,
+		ModelDefinition: modelDef,
+	}}, nil
+}
+
+func (a *apiServer) ImportCheckpoint(
+	ctx context.Context, req *apiv1.ImportCheckpointRequest,
+) (*apiv1.ImportCheckpointResponse, error) {
+	user, _, err := grpc.GetUser(ctx, a.m.db)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get the user: %s", err)
+	}
+
+	var imported *importedCheckpoint
+	source := fmt.Sprintf("cluster %s", req.Bundle.GetSourceClusterId())
+	switch {
+	case req.Bundle != nil && req.StorageUri != "":
+		return nil, status.Error(codes.InvalidArgument,
+			"only one of a bundle and a storage URI may be given")
+	case req.Bundle != nil:
+		if imported, err = a.m.parseCheckpointBundle(
+			req.Bundle, req.CheckpointStorage, optionalProjectID(req.ProjectId),
+		); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid checkpoint bundle: %s", err)
+		}
+	case req.StorageUri != "":
+		if req.CheckpointStorage != nil {
+			return nil, status.Error(codes.InvalidArgument,
+				"the storage URI locates the checkpoint storage of the checkpoint")
+		}
+		if imported, err = a.m.parseExternalCheckpoint(
+			req.StorageUri, req.Checkpoint, optionalProjectID(req.ProjectId),
+		); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid checkpoint: %s", err)
+		}
+		source = req.StorageUri
+	default:
+		return nil, status.Error(codes.InvalidArgument,
+			"either a bundle or a storage URI and checkpoint metadata must be given")
+	}
+	imported.experiment.OwnerID = &user.ID
+
+	switch err = a.m.db.ImportCheckpoint(imported.experiment, imported.trial, imported.step,
+		imported.validation, imported.checkpoint); {
+	case errors.Cause(err) == db.ErrCheckpointExists:
+		return nil, status.Errorf(codes.AlreadyExists,
+			"checkpoint %s already exists", *imported.checkpoint.UUID)
+	case err != nil:
+		return nil, errors.Wrapf(err, "error importing checkpoint %s", *imported.checkpoint.UUID)
+	}
+	log.Infof("imported checkpoint %s from %s as experiment %d",
+		*imported.checkpoint.UUID, source, imported.experiment.ID)
+
+	getResp, err := a.GetCheckpoint(ctx,
+		&apiv1.GetCheckpointRequest{CheckpointUuid: *imported.checkpoint.UUID})
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.ImportCheckpointResponse{Checkpoint: getResp.Checkpoint}, nil
+}
+
+func (a *apiServer) GetCheckpointUsage(
+	_ context.Context, _ *apiv1.GetCheckpointUsageRequest,
+) (*apiv1.GetCheckpointUsageResponse, error) {
+	resp := &apiv1.GetCheckpointUsageResponse{}
+	if err := a.m.db.QueryProto("get_checkpoint_usage", resp); err != nil {
+		return nil, errors.Wrap(err, "error fetching checkpoint usage from database")
+	}
+	for _, u := range resp.Users {
+		if quota, ok := a.m.config.CheckpointQuotas.userQuota(u.Username); ok {
+			u.Usage.QuotaBytes = &wrappers.Int64Value{Value: quota}
+		}
+	}
+	return resp, nil
+}
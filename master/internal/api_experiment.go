@@ -14,6 +14,7 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 
+	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/pkg/errors"
 
 	"github.com/determined-ai/determined/master/internal/db"
@@ -25,6 +26,7 @@ import (
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/protoutils"
 	"github.com/determined-ai/determined/master/pkg/searcher"
+	"github.com/determined-ai/determined/master/pkg/workload"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 	"github.com/determined-ai/determined/proto/pkg/checkpointv1"
 	"github.com/determined-ai/determined/proto/pkg/experimentv1"
@@ -135,6 +137,7 @@ func (a *apiServer) GetExperiments(
 		req.Description,
 		req.Offset,
 		req.Limit,
+		req.ProjectId,
 	)
 }
 
@@ -178,6 +181,78 @@ func (a *apiServer) GetExperimentValidationHistory(
 	return &resp, nil
 }
 
+func (a *apiServer) GetExperimentParetoFront(
+	_ context.Context, req *apiv1.GetExperimentParetoFrontRequest,
+) (*apiv1.GetExperimentParetoFrontResponse, error) {
+	if err := a.checkExperimentExists(int(req.ExperimentId)); err != nil {
+		return nil, err
+	}
+	conf, err := a.m.db.ExperimentConfig(int(req.ExperimentId))
+	if err != nil {
+		return nil, errors.Wrapf(err,
+			"error fetching experiment config from database: %d", req.ExperimentId)
+	}
+	validations, err := a.m.db.ExperimentCompletedValidations(int(req.ExperimentId))
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetExperimentParetoFrontResponse{
+		ParetoFront: paretoFront(validations, conf.Searcher.Metrics()),
+	}, nil
+}
+
+// paretoFront returns the trials with a validation that is not dominated by any other validation
+// in the searcher metrics, ordered by the first searcher metric. Validations that are missing a
+// searcher metric are ignored.
+func paretoFront(
+	validations []*model.Validation, searcherMetrics []model.SearcherMetric,
+) []*experimentv1.ParetoFrontEntry {
+	var candidates []*model.Validation
+	var points [][]float64
+	for _, v := range validations {
+		raw, _ := v.Metrics["validation_metrics"].(map[string]interface{})
+		objectives, err := searcher.Objectives(workload.ValidationMetrics{Metrics: raw}, searcherMetrics)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, v)
+		points = append(points, objectives)
+	}
+
+	var front []int
+	for i, rank := range searcher.ParetoRanks(points) {
+		if rank == 0 {
+			front = append(front, i)
+		}
+	}
+	sort.SliceStable(front, func(i, j int) bool {
+		return points[front[i]][0] < points[front[j]][0]
+	})
+
+	var entries []*experimentv1.ParetoFrontEntry
+	seen := make(map[int]bool)
+	for _, i := range front {
+		v := candidates[i]
+		if seen[v.TrialID] {
+			continue
+		}
+		seen[v.TrialID] = true
+		values := make(map[string]interface{}, len(searcherMetrics))
+		for _, m := range searcherMetrics {
+			values[m.Name] = v.Metrics["validation_metrics"].(map[string]interface{})[m.Name]
+		}
+		entry := &experimentv1.ParetoFrontEntry{
+			TrialId:         int32(v.TrialID),
+			SearcherMetrics: protoutils.ToStruct(values),
+		}
+		if v.EndTime != nil {
+			entry.EndTime = protoutils.ToTimestamp(*v.EndTime)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
 func (a *apiServer) PreviewHPSearch(
 	_ context.Context, req *apiv1.PreviewHPSearchRequest) (*apiv1.PreviewHPSearchResponse, error) {
 	bytes, err := protojson.Marshal(req.Config)
@@ -198,7 +273,67 @@ func (a *apiServer) PreviewHPSearch(
 	if err != nil {
 		return nil, err
 	}
-	protoSim := &experimentv1.ExperimentSimulation{Seed: req.Seed}
+	protoSim, err := protoSimulation(sim, req.Seed)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.PreviewHPSearchResponse{Simulation: protoSim}, nil
+}
+
+func (a *apiServer) ReplayHPSearch(
+	_ context.Context, req *apiv1.ReplayHPSearchRequest) (*apiv1.ReplayHPSearchResponse, error) {
+	bytes, err := protojson.Marshal(req.Config)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error parsing experiment config: %s", err)
+	}
+	config := model.DefaultExperimentConfig(&a.m.config.TaskContainerDefaults)
+	if err = json.Unmarshal(bytes, &config); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error parsing experiment config: %s", err)
+	}
+	if err = check.Validate(config.Searcher); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid experiment config: %s", err)
+	}
+
+	curves := make([]searcher.LearningCurve, 0, len(req.LearningCurves))
+	for _, c := range req.LearningCurves {
+		curve := make(searcher.LearningCurve, 0, len(c.Points))
+		for _, p := range c.Points {
+			point := searcher.LearningCurvePoint{Length: int(p.Length)}
+			if bytes, err = protojson.Marshal(p.Metrics); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "error parsing metrics: %s", err)
+			}
+			if err = json.Unmarshal(bytes, &point.Metrics); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "error parsing metrics: %s", err)
+			}
+			curve = append(curve, point)
+		}
+		curves = append(curves, curve)
+	}
+	replay, err := a.m.replaySearch(config, curves, int(req.ExperimentId), int64(req.Seed))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error replaying search: %s", err)
+	}
+
+	protoSim, err := protoSimulation(replay.Simulation, req.Seed)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.ReplayHPSearchResponse{Simulation: protoSim}
+	for _, p := range replay.Progress {
+		resp.Progress = append(resp.Progress, &experimentv1.ReplayPoint{
+			TotalUnits: int32(p.TotalUnits),
+			BestMetric: p.BestMetric,
+		})
+	}
+	return resp, nil
+}
+
+// protoSimulation converts a searcher simulation to its protobuf representation, grouping trials
+// that ran the same operations.
+func protoSimulation(
+	sim searcher.Simulation, seed uint32,
+) (*experimentv1.ExperimentSimulation, error) {
+	protoSim := &experimentv1.ExperimentSimulation{Seed: seed}
 	indexes := make(map[string]int)
 	toProto := func(op searcher.Runnable) (experimentv1.RunnableOperation, error) {
 		switch op := op.(type) {
@@ -263,7 +398,7 @@ func (a *apiServer) PreviewHPSearch(
 			indexes[hash] = len(protoSim.Trials) - 1
 		}
 	}
-	return &apiv1.PreviewHPSearchResponse{Simulation: protoSim}, nil
+	return protoSim, nil
 }
 
 func (a *apiServer) ActivateExperiment(
@@ -333,6 +468,88 @@ func (a *apiServer) KillExperiment(
 	return resp, err
 }
 
+func (a *apiServer) ContinueExperiment(
+	ctx context.Context, req *apiv1.ContinueExperimentRequest,
+) (*apiv1.ContinueExperimentResponse, error) {
+	id := int(req.Id)
+
+	dbExp, err := a.m.db.ExperimentByID(id)
+	switch {
+	case errors.Cause(err) == db.ErrNotFound:
+		return nil, status.Errorf(codes.NotFound, "experiment not found: %d", id)
+	case err != nil:
+		return nil, errors.Wrapf(err, "loading experiment %v", id)
+	}
+
+	var maxTrials, maxLength *int
+	if req.MaxTrials != nil {
+		v := int(req.MaxTrials.Value)
+		maxTrials = &v
+	}
+	if req.MaxLength != nil {
+		v := int(req.MaxLength.Value)
+		maxLength = &v
+	}
+	config, err := continuedSearcherConfig(dbExp.Config.Searcher, maxTrials, maxLength)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid searcher budget: %s", err)
+	}
+	if err = a.m.continueExperiment(dbExp, config); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return &apiv1.ContinueExperimentResponse{}, nil
+}
+
+func (a *apiServer) SetCheckpointRetention(
+	ctx context.Context, req *apiv1.SetCheckpointRetentionRequest,
+) (*apiv1.SetCheckpointRetentionResponse, error) {
+	id := int(req.Id)
+
+	dbExp, err := a.m.db.ExperimentByID(id)
+	switch {
+	case errors.Cause(err) == db.ErrNotFound:
+		return nil, status.Errorf(codes.NotFound, "experiment not found: %d", id)
+	case err != nil:
+		return nil, errors.Wrapf(err, "loading experiment %v", id)
+	}
+
+	config := dbExp.Config.CheckpointStorage
+	for _, setting := range []struct {
+		name  string
+		value *wrappers.Int32Value
+		dest  *int
+	}{
+		{"save_experiment_best", req.SaveExperimentBest, &config.SaveExperimentBest},
+		{"save_trial_best", req.SaveTrialBest, &config.SaveTrialBest},
+		{"save_trial_latest", req.SaveTrialLatest, &config.SaveTrialLatest},
+	} {
+		if setting.value == nil {
+			continue
+		}
+		if setting.value.Value < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "%s must be >= 0", setting.name)
+		}
+		*setting.dest = int(setting.value.Value)
+	}
+	if _, ok := model.TerminalStates[dbExp.State]; !ok && config.SaveTrialLatest < 1 {
+		return nil, status.Error(codes.FailedPrecondition,
+			"experiments that have not finished must keep the latest checkpoint of each trial")
+	}
+
+	resp := &apiv1.SetCheckpointRetentionResponse{}
+	if req.DryRun {
+		toDelete, err := a.m.db.ExperimentCheckpointsToGCRaw(id,
+			&config.SaveExperimentBest, &config.SaveTrialBest, &config.SaveTrialLatest, false)
+		if err != nil {
+			return nil, err
+		}
+		resp.CheckpointUuids, err = checkpointUUIDs(toDelete)
+		return resp, err
+	}
+	resp.CheckpointUuids, err = a.m.setCheckpointRetention(dbExp, config)
+	return resp, err
+}
+
 func (a *apiServer) ArchiveExperiment(
 	ctx context.Context, req *apiv1.ArchiveExperimentRequest,
 ) (*apiv1.ArchiveExperimentResponse, error) {
@@ -503,6 +720,7 @@ func (a *apiServer) CreateExperiment(
 		parentID := int(req.ParentId)
 		detParams.ParentID = &parentID
 	}
+	detParams.ProjectID = optionalProjectID(req.ProjectId)
 
 	dbExp, validateOnly, err := a.m.parseCreateExperiment(&detParams)
 
@@ -754,6 +972,8 @@ func (a *apiServer) topTrials(experimentID int, maxTrials int, s model.SearcherC
 		ranking = ByMetricOfInterest
 	case s.GridConfig != nil:
 		ranking = ByMetricOfInterest
+	case s.BayesianConfig != nil:
+		ranking = ByMetricOfInterest
 	case s.SyncHalvingConfig != nil:
 		ranking = ByTrainingLength
 	case s.AdaptiveConfig != nil:
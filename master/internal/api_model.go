@@ -13,6 +13,7 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 	"github.com/determined-ai/determined/proto/pkg/checkpointv1"
 	"github.com/determined-ai/determined/proto/pkg/modelv1"
@@ -41,6 +42,10 @@ func (a *apiServer) GetModels(
 	a.filter(&resp.Models, func(i int) bool {
 		v := resp.Models[i]
 
+		if req.ProjectId != 0 && v.ProjectId != req.ProjectId {
+			return false
+		}
+
 		if !strings.Contains(strings.ToLower(v.Name), strings.ToLower(req.Name)) {
 			return false
 		}
@@ -62,6 +67,7 @@ func (a *apiServer) PostModel(
 	m := &modelv1.Model{}
 	err = a.m.db.QueryProto(
 		"insert_model", m, req.Model.Name, req.Model.Description, b, time.Now(), time.Now(),
+		optionalProjectID(req.Model.ProjectId),
 	)
 
 	return &apiv1.PostModelResponse{Model: m},
@@ -110,19 +116,33 @@ func (a *apiServer) PatchModel(
 		errors.Wrapf(err, "error updating model %s in database", req.Model.Name)
 }
 
-func (a *apiServer) GetModelVersion(
-	_ context.Context, req *apiv1.GetModelVersionRequest) (*apiv1.GetModelVersionResponse, error) {
-	resp := &apiv1.GetModelVersionResponse{}
-	resp.ModelVersion = &modelv1.ModelVersion{}
+// stageName returns the name of a stage in the database, or "" if it is unspecified.
+func stageName(stage modelv1.Stage) model.ModelVersionStage {
+	if stage == modelv1.Stage_STAGE_UNSPECIFIED {
+		return ""
+	}
+	return model.ModelVersionStage(strings.TrimPrefix(stage.String(), "STAGE_"))
+}
 
-	switch err := a.m.db.QueryProto(
-		"get_model_version", resp.ModelVersion, req.ModelName, req.ModelVersion); {
+func (a *apiServer) getModelVersion(
+	modelName string, version int32,
+) (*modelv1.ModelVersion, error) {
+	mv := &modelv1.ModelVersion{}
+	switch err := a.m.db.QueryProto("get_model_version", mv, modelName, version); {
 	case err == db.ErrNotFound:
 		return nil, status.Errorf(
-			codes.NotFound, "model %s version %d not found", req.ModelName, req.ModelVersion)
-	default:
-		return resp, err
+			codes.NotFound, "model %s version %d not found", modelName, version)
+	case err != nil:
+		return nil, errors.Wrapf(
+			err, "error fetching model %s version %d from database", modelName, version)
 	}
+	return mv, nil
+}
+
+func (a *apiServer) GetModelVersion(
+	_ context.Context, req *apiv1.GetModelVersionRequest) (*apiv1.GetModelVersionResponse, error) {
+	mv, err := a.getModelVersion(req.ModelName, req.ModelVersion)
+	return &apiv1.GetModelVersionResponse{ModelVersion: mv}, err
 }
 
 func (a *apiServer) GetModelVersions(
@@ -133,7 +153,9 @@ func (a *apiServer) GetModelVersions(
 	}
 
 	resp := &apiv1.GetModelVersionsResponse{Model: getResp.Model}
-	if err := a.m.db.QueryProto("get_model_versions", &resp.ModelVersions, req.ModelName); err != nil {
+	if err := a.m.db.QueryProto(
+		"get_model_versions", &resp.ModelVersions, req.ModelName, stageName(req.Stage),
+	); err != nil {
 		return nil, err
 	}
 
@@ -167,18 +189,113 @@ func (a *apiServer) PostModelVersion(
 		)
 	}
 
-	respModelVersion := &apiv1.PostModelVersionResponse{}
-	respModelVersion.ModelVersion = &modelv1.ModelVersion{}
-
-	err = a.m.db.QueryProto(
+	inserted := &modelv1.ModelVersion{}
+	if err = a.m.db.QueryProto(
 		"insert_model_version",
-		respModelVersion.ModelVersion,
-		req.ModelName,
+		inserted,
+		getResp.Model.Name,
 		req.CheckpointUuid,
-	)
+	); err != nil {
+		return nil, errors.Wrapf(err, "error adding model version to model %s", req.ModelName)
+	}
+
+	mv, err := a.getModelVersion(req.ModelName, inserted.Version)
+	return &apiv1.PostModelVersionResponse{ModelVersion: mv}, err
+}
+
+func (a *apiServer) PatchModelVersion(
+	_ context.Context, req *apiv1.PatchModelVersionRequest,
+) (*apiv1.PatchModelVersionResponse, error) {
+	if req.ModelVersion == nil {
+		return nil, status.Error(codes.InvalidArgument, "no model version specified")
+	}
+	mv, err := a.getModelVersion(req.ModelName, req.ModelVersion.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range req.UpdateMask.GetPaths() {
+		switch {
+		case path == "stage":
+			if req.ModelVersion.Stage == modelv1.Stage_STAGE_UNSPECIFIED {
+				return nil, status.Error(codes.InvalidArgument, "no stage specified")
+			}
+			mv.Stage = req.ModelVersion.Stage
+		case path == "notes":
+			mv.Notes = req.ModelVersion.Notes
+		case path == "tags":
+			mv.Tags = req.ModelVersion.Tags
+		case !strings.HasPrefix(path, "update_mask"):
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"only stage, notes and tags fields are mutable. cannot update %s", path)
+		}
+	}
+
+	if err = a.m.db.UpdateModelVersion(
+		req.ModelName, int(mv.Version), stageName(mv.Stage), mv.Notes, mv.Tags,
+	); err != nil {
+		return nil, errors.Wrapf(err, "error updating model %s version %d in database",
+			req.ModelName, mv.Version)
+	}
+	mv, err = a.getModelVersion(req.ModelName, mv.Version)
+	return &apiv1.PatchModelVersionResponse{ModelVersion: mv}, err
+}
+
+func (a *apiServer) DeleteModelVersion(
+	_ context.Context, req *apiv1.DeleteModelVersionRequest,
+) (*apiv1.DeleteModelVersionResponse, error) {
+	switch err := a.m.db.DeleteModelVersion(req.ModelName, int(req.ModelVersion)); {
+	case err == db.ErrNotFound:
+		return nil, status.Errorf(
+			codes.NotFound, "model %s version %d not found", req.ModelName, req.ModelVersion)
+	case err != nil:
+		return nil, errors.Wrapf(err, "error deleting model %s version %d",
+			req.ModelName, req.ModelVersion)
+	}
+	return &apiv1.DeleteModelVersionResponse{}, nil
+}
+
+func (a *apiServer) GetModelAlias(
+	_ context.Context, req *apiv1.GetModelAliasRequest,
+) (*apiv1.GetModelAliasResponse, error) {
+	version, err := a.m.db.ModelAliasVersion(req.ModelName, req.Alias)
+	switch {
+	case err == db.ErrNotFound:
+		return nil, status.Errorf(
+			codes.NotFound, "model %s has no alias %s", req.ModelName, req.Alias)
+	case err != nil:
+		return nil, err
+	}
+	mv, err := a.getModelVersion(req.ModelName, int32(version))
+	return &apiv1.GetModelAliasResponse{ModelVersion: mv}, err
+}
 
-	respModelVersion.ModelVersion.Model = getResp.Model
-	respModelVersion.ModelVersion.Checkpoint = c
+func (a *apiServer) PutModelAlias(
+	_ context.Context, req *apiv1.PutModelAliasRequest,
+) (*apiv1.PutModelAliasResponse, error) {
+	if req.Alias == "" {
+		return nil, status.Error(codes.InvalidArgument, "no alias specified")
+	}
+	if _, err := a.getModelVersion(req.ModelName, req.ModelVersion); err != nil {
+		return nil, err
+	}
+	if err := a.m.db.PutModelAlias(req.ModelName, req.Alias, int(req.ModelVersion)); err != nil {
+		return nil, err
+	}
+	mv, err := a.getModelVersion(req.ModelName, req.ModelVersion)
+	return &apiv1.PutModelAliasResponse{ModelVersion: mv}, err
+}
 
-	return respModelVersion, errors.Wrapf(err, "error adding model version to model %s", req.ModelName)
+func (a *apiServer) DeleteModelAlias(
+	_ context.Context, req *apiv1.DeleteModelAliasRequest,
+) (*apiv1.DeleteModelAliasResponse, error) {
+	switch err := a.m.db.DeleteModelAlias(req.ModelName, req.Alias); {
+	case err == db.ErrNotFound:
+		return nil, status.Errorf(
+			codes.NotFound, "model %s has no alias %s", req.ModelName, req.Alias)
+	case err != nil:
+		return nil, err
+	}
+	return &apiv1.DeleteModelAliasResponse{}, nil
 }
@@ -0,0 +1,236 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/grpc"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/projectv1"
+)
+
+// optionalProjectID converts the project ID of a request, where 0 means no project.
+func optionalProjectID(id int32) *int {
+	if id == 0 {
+		return nil
+	}
+	projectID := int(id)
+	return &projectID
+}
+
+// applyProjectDefaults sets the checkpoint storage and resource pool of an experiment
+// configuration to the defaults of its project.
+func applyProjectDefaults(config *model.ExperimentConfig, project *model.Project) error {
+	if project.CheckpointStorage != nil {
+		storage, err := json.Marshal(project.CheckpointStorage)
+		if err != nil {
+			return errors.Wrap(err, "error marshaling project checkpoint storage")
+		}
+		if err = yaml.Unmarshal(
+			storage, &config.CheckpointStorage, yaml.DisallowUnknownFields,
+		); err != nil {
+			return errors.Wrapf(err, "invalid checkpoint storage of project %s", project.Name)
+		}
+	}
+	if project.ResourcePool != "" {
+		config.Resources.ResourcePool = project.ResourcePool
+	}
+	return nil
+}
+
+func (a *apiServer) validateProject(project *model.Project) error {
+	if project.Name == "" {
+		return status.Error(codes.InvalidArgument, "no project name specified")
+	}
+	config := model.DefaultExperimentConfig(&a.m.config.TaskContainerDefaults)
+	checkpointStorage, err := a.m.config.CheckpointStorage.ToModel()
+	if err != nil {
+		return err
+	}
+	config.CheckpointStorage = *checkpointStorage
+	if err = applyProjectDefaults(&config, project); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err = check.Validate(config.CheckpointStorage); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid checkpoint storage: %s", err)
+	}
+	if project.ResourcePool != "" {
+		if err = sproto.ValidateRP(a.m.system, project.ResourcePool); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	return nil
+}
+
+// setProjectFields sets the fields of a project that users can change from a Protobuf project.
+func setProjectFields(project *model.Project, p *projectv1.Project) error {
+	if p.Name != "" {
+		project.Name = p.Name
+	}
+	project.Description = p.Description
+	project.ResourcePool = p.ResourcePool
+	project.CheckpointStorage = nil
+	if p.CheckpointStorage != nil {
+		storage, err := protojson.Marshal(p.CheckpointStorage)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid checkpoint storage: %s", err)
+		}
+		if err = json.Unmarshal(storage, &project.CheckpointStorage); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid checkpoint storage: %s", err)
+		}
+	}
+	return nil
+}
+
+func (a *apiServer) getProject(id int32) (*projectv1.Project, error) {
+	p := &projectv1.Project{}
+	switch err := a.m.db.QueryProto("get_project", p, id); {
+	case err == db.ErrNotFound:
+		return nil, status.Errorf(codes.NotFound, "project %d not found", id)
+	case err != nil:
+		return nil, errors.Wrapf(err, "error fetching project %d from database", id)
+	}
+	return p, nil
+}
+
+func (a *apiServer) GetProjects(
+	_ context.Context, req *apiv1.GetProjectsRequest) (*apiv1.GetProjectsResponse, error) {
+	resp := &apiv1.GetProjectsResponse{}
+	if err := a.m.db.QueryProto("get_projects", &resp.Projects); err != nil {
+		return nil, errors.Wrap(err, "error fetching projects from database")
+	}
+	a.filter(&resp.Projects, func(i int) bool {
+		return strings.Contains(strings.ToLower(resp.Projects[i].Name), strings.ToLower(req.Name))
+	})
+	return resp, a.paginate(&resp.Pagination, &resp.Projects, req.Offset, req.Limit)
+}
+
+func (a *apiServer) GetProject(
+	_ context.Context, req *apiv1.GetProjectRequest) (*apiv1.GetProjectResponse, error) {
+	p, err := a.getProject(req.ProjectId)
+	return &apiv1.GetProjectResponse{Project: p}, err
+}
+
+func (a *apiServer) PostProject(
+	ctx context.Context, req *apiv1.PostProjectRequest) (*apiv1.PostProjectResponse, error) {
+	user, _, err := grpc.GetUser(ctx, a.m.db)
+	if err != nil {
+		return nil, err
+	}
+	if req.Project == nil {
+		return nil, status.Error(codes.InvalidArgument, "no project specified")
+	}
+	project := &model.Project{OwnerID: user.ID}
+	if err = setProjectFields(project, req.Project); err != nil {
+		return nil, err
+	}
+	if err = a.validateProject(project); err != nil {
+		return nil, err
+	}
+	switch err = a.m.db.AddProject(project); {
+	case errors.Cause(err) == db.ErrDuplicateRecord:
+		return nil, status.Errorf(codes.AlreadyExists, "project %s already exists", project.Name)
+	case err != nil:
+		return nil, errors.Wrapf(err, "error creating project %s in database", project.Name)
+	}
+	p, err := a.getProject(int32(project.ID))
+	return &apiv1.PostProjectResponse{Project: p}, err
+}
+
+func (a *apiServer) PatchProject(
+	_ context.Context, req *apiv1.PatchProjectRequest) (*apiv1.PatchProjectResponse, error) {
+	if req.Project == nil {
+		return nil, status.Error(codes.InvalidArgument, "no project specified")
+	}
+	project, err := a.m.db.ProjectByID(int(req.Project.Id))
+	switch {
+	case errors.Cause(err) == db.ErrNotFound:
+		return nil, status.Errorf(codes.NotFound, "project %d not found", req.Project.Id)
+	case err != nil:
+		return nil, errors.Wrapf(err, "error fetching project %d from database", req.Project.Id)
+	}
+	if err = setProjectFields(project, req.Project); err != nil {
+		return nil, err
+	}
+	if err = a.validateProject(project); err != nil {
+		return nil, err
+	}
+	switch err = a.m.db.UpdateProject(project); {
+	case errors.Cause(err) == db.ErrDuplicateRecord:
+		return nil, status.Errorf(codes.AlreadyExists, "project %s already exists", project.Name)
+	case err != nil:
+		return nil, err
+	}
+	p, err := a.getProject(req.Project.Id)
+	return &apiv1.PatchProjectResponse{Project: p}, err
+}
+
+func (a *apiServer) DeleteProject(
+	_ context.Context, req *apiv1.DeleteProjectRequest) (*apiv1.DeleteProjectResponse, error) {
+	err := a.m.db.DeleteProject(int(req.ProjectId))
+	switch errors.Cause(err) {
+	case nil:
+	case db.ErrNotFound:
+		return nil, status.Errorf(codes.NotFound, "project %d not found", req.ProjectId)
+	case db.ErrProjectNotEmpty:
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"project %d still has experiments, models or templates", req.ProjectId)
+	default:
+		return nil, err
+	}
+	return &apiv1.DeleteProjectResponse{}, nil
+}
+
+// projectMember looks up the project and the user with the given username.
+func (a *apiServer) projectMember(projectID int32, username string) (*model.User, error) {
+	if _, err := a.getProject(projectID); err != nil {
+		return nil, err
+	}
+	switch user, err := a.m.db.UserByUsername(username); {
+	case err == db.ErrNotFound:
+		return nil, errUserNotFound
+	case err != nil:
+		return nil, err
+	default:
+		return user, nil
+	}
+}
+
+func (a *apiServer) PostProjectMember(
+	_ context.Context, req *apiv1.PostProjectMemberRequest,
+) (*apiv1.PostProjectMemberResponse, error) {
+	user, err := a.projectMember(req.ProjectId, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	if err = a.m.db.AddProjectMember(int(req.ProjectId), user.ID); err != nil {
+		return nil, err
+	}
+	p, err := a.getProject(req.ProjectId)
+	return &apiv1.PostProjectMemberResponse{Project: p}, err
+}
+
+func (a *apiServer) DeleteProjectMember(
+	_ context.Context, req *apiv1.DeleteProjectMemberRequest,
+) (*apiv1.DeleteProjectMemberResponse, error) {
+	user, err := a.projectMember(req.ProjectId, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	if err = a.m.db.RemoveProjectMember(int(req.ProjectId), user.ID); err != nil {
+		return nil, err
+	}
+	p, err := a.getProject(req.ProjectId)
+	return &apiv1.DeleteProjectMemberResponse{Project: p}, err
+}
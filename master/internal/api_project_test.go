@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestApplyProjectDefaults(t *testing.T) {
+	config := model.DefaultExperimentConfig(nil)
+	config.CheckpointStorage.SharedFSConfig = &model.SharedFSConfig{HostPath: "/tmp"}
+	project := &model.Project{
+		Name:              "vision",
+		ResourcePool:      "gpus",
+		CheckpointStorage: model.JSONObj{"type": "s3", "bucket": "vision", "save_trial_best": 3},
+	}
+	assert.NilError(t, applyProjectDefaults(&config, project))
+	assert.Equal(t, config.Resources.ResourcePool, "gpus")
+	assert.Assert(t, config.CheckpointStorage.SharedFSConfig == nil)
+	assert.Equal(t, config.CheckpointStorage.S3Config.Bucket, "vision")
+	assert.Equal(t, config.CheckpointStorage.SaveTrialBest, 3)
+
+	// The experiment configuration takes precedence over the defaults of the project.
+	assert.NilError(t, yaml.Unmarshal([]byte(`
+checkpoint_storage:
+  type: shared_fs
+  host_path: /mnt
+resources:
+  resource_pool: cpus
+`), &config, yaml.DisallowUnknownFields))
+	assert.Equal(t, config.Resources.ResourcePool, "cpus")
+	assert.Equal(t, config.CheckpointStorage.SharedFSConfig.HostPath, "/mnt")
+	assert.Assert(t, config.CheckpointStorage.S3Config == nil)
+
+	project.CheckpointStorage = model.JSONObj{"type": "s3", "unknown": true}
+	assert.ErrorContains(t, applyProjectDefaults(&config, project), "invalid checkpoint storage")
+}
@@ -28,3 +28,17 @@ func (a *apiServer) GetResourcePools(
 
 	return resp, a.paginate(&resp.Pagination, &resp.ResourcePools, req.Offset, req.Limit)
 }
+
+func (a *apiServer) GetResourcePoolQueue(
+	_ context.Context, req *apiv1.GetResourcePoolQueueRequest,
+) (resp *apiv1.GetResourcePoolQueueResponse, err error) {
+	switch {
+	case sproto.UseAgentRM(a.m.system):
+		err = a.actorRequest(sproto.AgentRMAddr.String(), req, &resp)
+	case sproto.UseK8sRM(a.m.system):
+		err = a.actorRequest(sproto.K8sRMAddr.String(), req, &resp)
+	default:
+		err = status.Error(codes.NotFound, "cannot find appropriate resource manager")
+	}
+	return resp, err
+}
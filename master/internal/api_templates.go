@@ -10,6 +10,7 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/grpc"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 	"github.com/determined-ai/determined/proto/pkg/templatev1"
 )
@@ -21,7 +22,11 @@ func (a *apiServer) GetTemplates(
 		return nil, errors.Wrap(err, "error fetching templates from database")
 	}
 	a.filter(&resp.Templates, func(i int) bool {
-		return strings.Contains(strings.ToLower(resp.Templates[i].Name), strings.ToLower(req.Name))
+		v := resp.Templates[i]
+		if req.ProjectId != 0 && v.ProjectId != req.ProjectId {
+			return false
+		}
+		return strings.Contains(strings.ToLower(v.Name), strings.ToLower(req.Name))
 	})
 	a.sort(resp.Templates, req.OrderBy, req.SortBy, apiv1.GetTemplatesRequest_SORT_BY_NAME)
 	return resp, a.paginate(&resp.Pagination, &resp.Templates, req.Offset, req.Limit)
@@ -41,12 +46,17 @@ func (a *apiServer) GetTemplate(
 }
 
 func (a *apiServer) PutTemplate(
-	_ context.Context, req *apiv1.PutTemplateRequest) (*apiv1.PutTemplateResponse, error) {
+	ctx context.Context, req *apiv1.PutTemplateRequest) (*apiv1.PutTemplateResponse, error) {
+	user, _, err := grpc.GetUser(ctx, a.m.db)
+	if err != nil {
+		return nil, err
+	}
 	config, err := protojson.Marshal(req.Template.Config)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid config provided: %s", err.Error())
 	}
-	err = a.m.db.QueryProto("put_template", req.Template, req.Template.Name, config)
+	err = a.m.db.QueryProto("put_template", req.Template, req.Template.Name, config, user.ID,
+		optionalProjectID(req.Template.ProjectId))
 	return &apiv1.PutTemplateResponse{Template: req.Template},
 		errors.Wrapf(err, "error putting template")
 }
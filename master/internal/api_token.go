@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/grpc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/userv1"
+)
+
+// tokenUser returns the user whose API tokens a request acts on: the current user if no username
+// is given, or else the named user, which only admins may act on.
+func (a *apiServer) tokenUser(ctx context.Context, username string) (*model.User, error) {
+	curUser, _, err := grpc.GetUser(ctx, a.m.db)
+	if err != nil {
+		return nil, err
+	}
+	if username == "" || username == curUser.Username {
+		return curUser, nil
+	}
+	if !curUser.Admin {
+		return nil, grpc.ErrPermissionDenied
+	}
+	switch user, err := a.m.db.UserByUsername(username); {
+	case err == db.ErrNotFound:
+		return nil, errUserNotFound
+	case err != nil:
+		return nil, err
+	default:
+		return user, nil
+	}
+}
+
+func (a *apiServer) GetAPITokens(
+	ctx context.Context, req *apiv1.GetAPITokensRequest,
+) (*apiv1.GetAPITokensResponse, error) {
+	user, err := a.tokenUser(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetAPITokensResponse{}
+	if err = a.m.db.QueryProto("get_api_tokens", &resp.Tokens, user.ID); err != nil {
+		return nil, errors.Wrap(err, "error fetching API tokens from database")
+	}
+	return resp, nil
+}
+
+func (a *apiServer) PostAPIToken(
+	ctx context.Context, req *apiv1.PostAPITokenRequest,
+) (*apiv1.PostAPITokenResponse, error) {
+	user, err := a.tokenUser(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	token := &model.APIToken{
+		UserID:      user.ID,
+		Description: req.Description,
+		ReadOnly:    req.ReadOnly,
+	}
+	if req.Expiry != nil {
+		expiry, err := ptypes.Timestamp(req.Expiry)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid expiry: %s", err)
+		}
+		if !expiry.After(time.Now()) {
+			return nil, status.Error(codes.InvalidArgument, "expiry must be in the future")
+		}
+		token.Expiry = &expiry
+	}
+	secret, err := a.m.db.AddAPIToken(token)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.PostAPITokenResponse{Token: &userv1.APIToken{}, Secret: secret}
+	if err = a.m.db.QueryProto("get_api_token", resp.Token, token.ID); err != nil {
+		return nil, errors.Wrapf(err, "error fetching API token %d from database", token.ID)
+	}
+	return resp, nil
+}
+
+func (a *apiServer) DeleteAPIToken(
+	ctx context.Context, req *apiv1.DeleteAPITokenRequest,
+) (*apiv1.DeleteAPITokenResponse, error) {
+	curUser, _, err := grpc.GetUser(ctx, a.m.db)
+	if err != nil {
+		return nil, err
+	}
+	notFound := status.Errorf(codes.NotFound, "API token %d not found", req.TokenId)
+	token, err := a.m.db.APITokenByID(int(req.TokenId))
+	switch {
+	case errors.Cause(err) == db.ErrNotFound:
+		return nil, notFound
+	case err != nil:
+		return nil, err
+	case token.UserID != curUser.ID && !curUser.Admin:
+		return nil, notFound
+	}
+	switch err = a.m.db.DeleteAPIToken(token.ID); {
+	case err == db.ErrNotFound:
+		return nil, notFound
+	case err != nil:
+		return nil, err
+	}
+	return &apiv1.DeleteAPITokenResponse{}, nil
+}
@@ -16,6 +16,21 @@ import (
 
 var errUserNotFound = status.Error(codes.NotFound, "user not found")
 
+var protoRoles = map[model.Role]userv1.Role{
+	model.RoleViewer: userv1.Role_ROLE_VIEWER,
+	model.RoleEditor: userv1.Role_ROLE_EDITOR,
+	model.RoleAdmin:  userv1.Role_ROLE_ADMIN,
+}
+
+func fromProtoRole(role userv1.Role, admin bool) model.Role {
+	for r, p := range protoRoles {
+		if p == role {
+			return r
+		}
+	}
+	return model.RoleFromAdmin(admin)
+}
+
 func toProtoUserFromFullUser(user model.FullUser) *userv1.User {
 	var agentUserGroup *userv1.AgentUserGroup
 	if user.AgentUID.Valid || user.AgentGID.Valid {
@@ -29,6 +44,7 @@ func toProtoUserFromFullUser(user model.FullUser) *userv1.User {
 		Admin:          user.Admin,
 		Active:         user.Active,
 		AgentUserGroup: agentUserGroup,
+		Role:           protoRoles[user.Role],
 	}
 }
 
@@ -53,6 +69,7 @@ func getUser(d *db.PgDB, username string) (*userv1.User, error) {
 		Admin:          user.Admin,
 		Active:         user.Active,
 		AgentUserGroup: protoAug,
+		Role:           protoRoles[user.Role],
 	}, err
 }
 
@@ -95,9 +112,9 @@ func (a *apiServer) PostUser(
 	}
 	user := &model.User{
 		Username: req.User.Username,
-		Admin:    req.User.Admin,
 		Active:   req.User.Active,
 	}
+	user.SetRole(fromProtoRole(req.User.Role, req.User.Admin))
 	if err = user.UpdatePasswordHash(req.Password); err != nil {
 		return nil, err
 	}
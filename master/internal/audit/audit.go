@@ -0,0 +1,178 @@
+// Package audit records who made the requests that change the cluster, what they acted on and
+// how the requests turned out.
+package audit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const (
+	maxStringLength = 256
+	maxListLength   = 32
+)
+
+// isSecretKey returns whether a request field, in either camelCase or snake_case, may hold a
+// secret and so is never recorded, e.g. a password, an API token or the access key, account key,
+// credential or connection string of checkpoint storage. IDs of secrets, like "tokenId", are kept.
+func isSecretKey(key string) bool {
+	key = snakeCase(key)
+	switch {
+	case key == "key" || strings.HasSuffix(key, "_key"),
+		strings.Contains(key, "secret"),
+		strings.Contains(key, "password"),
+		strings.HasPrefix(key, "credential"),
+		key == "connection_string":
+		return true
+	case strings.Contains(key, "token"):
+		return !strings.HasSuffix(key, "_id")
+	default:
+		return false
+	}
+}
+
+// Summarize returns a copy of a decoded JSON request without secrets and with long strings and
+// lists, such as the contents of model definitions, cut short.
+func Summarize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		summary := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if isSecretKey(key) {
+				summary[key] = "<redacted>"
+			} else {
+				summary[key] = Summarize(value)
+			}
+		}
+		return summary
+	case []interface{}:
+		n := len(v)
+		if n > maxListLength {
+			n = maxListLength
+		}
+		summary := make([]interface{}, 0, n+1)
+		for _, value := range v[:n] {
+			summary = append(summary, Summarize(value))
+		}
+		if len(v) > n {
+			summary = append(summary, fmt.Sprintf("<%d more items>", len(v)-n))
+		}
+		return summary
+	case string:
+		if len(v) > maxStringLength {
+			return fmt.Sprintf("<%d characters>", len(v))
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// ResourceFromMethod returns the type of the resource that an API method acts on, e.g.
+// "model_version" for PostModelVersion.
+func ResourceFromMethod(method string) string {
+	for i, r := range method {
+		if i > 0 && unicode.IsUpper(r) {
+			return snakeCase(method[i:])
+		}
+	}
+	return snakeCase(method)
+}
+
+// ResourceFromRoute returns the type of the resource that an HTTP route acts on, e.g.
+// "experiment" for /experiments/:experiment_id/kill.
+func ResourceFromRoute(route string) string {
+	first := strings.SplitN(strings.Trim(route, "/*"), "/", 2)[0]
+	return strings.TrimSuffix(strings.ReplaceAll(first, "-", "_"), "s")
+}
+
+// ResourceID returns the ID of the resource of the given type that a summarized request acts on:
+// the first of its ID field, e.g. "experimentId", its "id" field, its name field, e.g.
+// "templateName", or the ID of the resource that it holds, e.g. "experiment": {"id": 1} or
+// "checkpoint": {"uuid": "..."} for checkpoint metadata.
+// Otherwise, it falls back to any other ID or name field.
+func ResourceID(resource string, request map[string]interface{}) string {
+	camel := camelCase(resource)
+	for _, key := range []string{camel + "Id", "id", camel + "Name", "username", "name"} {
+		if id, ok := scalar(request[key]); ok {
+			return id
+		}
+	}
+	keys := make([]string, 0, len(request))
+	for key := range request {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		nested, ok := request[key].(map[string]interface{})
+		if !ok || !strings.HasPrefix(camel, key) {
+			continue
+		}
+		for _, nestedKey := range []string{"id", "name", "uuid"} {
+			if id, ok := scalar(nested[nestedKey]); ok {
+				return id
+			}
+		}
+	}
+	for _, key := range keys {
+		if strings.HasSuffix(key, "Id") || strings.HasSuffix(key, "Name") {
+			if id, ok := scalar(request[key]); ok {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// Record stores an audit event. Failing to record an event is logged rather than failing the
+// request, which has already been served.
+func Record(d *db.PgDB, event *model.AuditEvent) {
+	if err := d.AddAuditEvent(event); err != nil {
+		log.WithError(err).Error("failed to record audit event")
+	}
+}
+
+func scalar(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, v != ""
+	case float64:
+		return fmt.Sprint(v), v != 0
+	default:
+		return "", false
+	}
+}
+
+// snakeCase converts a CamelCase name, which may contain acronyms like "API", to snake_case.
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func camelCase(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	request := map[string]interface{}{
+		"username": "alice",
+		"password": "hunter2",
+		"config":   strings.Repeat("x", maxStringLength+1),
+		"files":    make([]interface{}, maxListLength+2),
+	}
+	summary := Summarize(request).(map[string]interface{})
+	assert.Equal(t, summary["username"], "alice")
+	assert.Equal(t, summary["password"], "<redacted>")
+	assert.Equal(t, summary["config"], "<257 characters>")
+	files := summary["files"].([]interface{})
+	assert.Equal(t, len(files), maxListLength+1)
+	assert.Equal(t, files[maxListLength], "<2 more items>")
+	assert.Equal(t, request["password"], "hunter2")
+}
+
+func TestSummarizeCheckpointStorage(t *testing.T) {
+	request := map[string]interface{}{
+		"config": map[string]interface{}{
+			"checkpoint_storage": map[string]interface{}{
+				"type":       "s3",
+				"bucket":     "checkpoints",
+				"access_key": "AKIA",
+				"secret_key": "s3-secret",
+			},
+			"data": map[string]interface{}{
+				"container":         "data",
+				"connection_string": "AccountKey=azure-secret",
+				"credential":        "azure-credential",
+				"accountKey":        "azure-key",
+				"Password":          "hunter2",
+			},
+		},
+		"tokenId":  3.0,
+		"apiToken": "abc",
+	}
+	summary := Summarize(request).(map[string]interface{})
+	config := summary["config"].(map[string]interface{})
+	assert.DeepEqual(t, config["checkpoint_storage"], map[string]interface{}{
+		"type":       "s3",
+		"bucket":     "checkpoints",
+		"access_key": "<redacted>",
+		"secret_key": "<redacted>",
+	})
+	assert.DeepEqual(t, config["data"], map[string]interface{}{
+		"container":         "data",
+		"connection_string": "<redacted>",
+		"credential":        "<redacted>",
+		"accountKey":        "<redacted>",
+		"Password":          "<redacted>",
+	})
+	assert.Equal(t, summary["tokenId"], 3.0)
+	assert.Equal(t, summary["apiToken"], "<redacted>")
+}
+
+func TestResources(t *testing.T) {
+	assert.Equal(t, ResourceFromMethod("KillExperiment"), "experiment")
+	assert.Equal(t, ResourceFromMethod("PostModelVersion"), "model_version")
+	assert.Equal(t, ResourceFromMethod("DeleteAPIToken"), "api_token")
+	assert.Equal(t, ResourceFromRoute("/experiments/:experiment_id/kill"), "experiment")
+	assert.Equal(t, ResourceFromRoute("/tensorboard*"), "tensorboard")
+
+	cases := []struct {
+		resource string
+		request  map[string]interface{}
+		id       string
+	}{
+		{"experiment", map[string]interface{}{"id": 4.0}, "4"},
+		{"agent", map[string]interface{}{"agentId": "agent-1"}, "agent-1"},
+		{"experiment", map[string]interface{}{"experiment": map[string]interface{}{"id": 7.0}}, "7"},
+		{"checkpoint_metadata", map[string]interface{}{
+			"checkpoint": map[string]interface{}{"uuid": "abc"},
+		}, "abc"},
+		{"model_version", map[string]interface{}{"modelName": "mnist"}, "mnist"},
+		{"user_password", map[string]interface{}{"username": "bob"}, "bob"},
+		{"project", map[string]interface{}{}, ""},
+	}
+	for _, c := range cases {
+		assert.Equal(t, ResourceID(c.resource, c.request), c.id, "%s %v", c.resource, c.request)
+	}
+}
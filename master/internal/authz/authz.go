@@ -0,0 +1,129 @@
+// Package authz decides what users are allowed to do. Viewers can read everything, editors can
+// also create experiments, commands and templates and modify the ones that they own, and admins
+// can modify anything and administer the cluster. Objects in a project can only be created by
+// its members.
+package authz
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/internal/command"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// ErrPermissionDenied is returned when a user is not allowed to do something.
+var ErrPermissionDenied = errors.New("user does not have permission")
+
+// Authorizer checks the permissions of users on the objects of the cluster.
+type Authorizer struct {
+	db     *db.PgDB
+	system *actor.System
+}
+
+// New returns an authorizer that looks up the owners of objects in the database and the actor
+// system.
+func New(db *db.PgDB, system *actor.System) *Authorizer {
+	return &Authorizer{db: db, system: system}
+}
+
+// RequireRole checks that the user has at least the given role.
+func RequireRole(user model.User, role model.Role) error {
+	if !user.Role.Includes(role) {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// canModify checks that the user can modify an object with the given owner. Objects that do not
+// exist pass the check, so that the handler of the request can report that they are missing.
+func canModify(user model.User, owner *model.UserID, err error) error {
+	switch {
+	case errors.Cause(err) == db.ErrNotFound:
+		return RequireRole(user, model.RoleEditor)
+	case err != nil:
+		return err
+	case !user.CanModify(owner):
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// CanModifyExperiment checks that the user can modify the experiment.
+func (a *Authorizer) CanModifyExperiment(user model.User, id int) error {
+	owner, err := a.db.ExperimentOwnerID(id)
+	return canModify(user, owner, err)
+}
+
+// CanModifyTrial checks that the user can modify the experiment of the trial.
+func (a *Authorizer) CanModifyTrial(user model.User, id int) error {
+	owner, err := a.db.TrialOwnerID(id)
+	return canModify(user, owner, err)
+}
+
+// CanModifyCheckpoint checks that the user can modify the experiment of the checkpoint.
+func (a *Authorizer) CanModifyCheckpoint(user model.User, uuid string) error {
+	owner, err := a.db.CheckpointOwnerID(uuid)
+	return canModify(user, owner, err)
+}
+
+// CanModifyTemplate checks that the user can create or modify the template.
+func (a *Authorizer) CanModifyTemplate(user model.User, name string) error {
+	owner, err := a.db.TemplateOwnerID(name)
+	return canModify(user, owner, err)
+}
+
+// CanModifyCommand checks that the user can modify the command, notebook, shell or tensorboard
+// with the given actor address.
+func (a *Authorizer) CanModifyCommand(user model.User, addr actor.Address) error {
+	ref := a.system.Get(addr)
+	if ref == nil {
+		return canModify(user, nil, db.ErrNotFound)
+	}
+	resp := a.system.Ask(ref, command.GetOwner{})
+	if resp.Empty() {
+		return canModify(user, nil, db.ErrNotFound)
+	}
+	owner := resp.Get().(model.UserID)
+	return canModify(user, &owner, nil)
+}
+
+// CanModifyProject checks that the user can change the project and its members.
+func (a *Authorizer) CanModifyProject(user model.User, id int) error {
+	owner, err := a.db.ProjectOwnerID(id)
+	return canModify(user, owner, err)
+}
+
+// CanUseProject checks that the user can create experiments, models and templates in the project,
+// which requires being an editor and a member of the project. A nil project stands for objects
+// outside of any project, which all editors can create.
+func (a *Authorizer) CanUseProject(user model.User, id *int) error {
+	if err := RequireRole(user, model.RoleEditor); err != nil || id == nil {
+		return err
+	}
+	if user.Role.Includes(model.RoleAdmin) {
+		return nil
+	}
+	switch member, err := a.db.IsProjectMember(*id, user.ID); {
+	case err == db.ErrNotFound:
+		return nil
+	case err != nil:
+		return err
+	case !member:
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// CanModifyModel checks that the user can change the model and add versions to it.
+func (a *Authorizer) CanModifyModel(user model.User, name string) error {
+	switch projectID, err := a.db.ModelProjectID(name); {
+	case err == db.ErrNotFound:
+		return RequireRole(user, model.RoleEditor)
+	case err != nil:
+		return err
+	default:
+		return a.CanUseProject(user, projectID)
+	}
+}
@@ -0,0 +1,320 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/determined-ai/determined/master/pkg/archive"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/protoutils"
+	"github.com/determined-ai/determined/proto/pkg/checkpointv1"
+)
+
+// checkpointBundleFormatVersion is the version of the format of the checkpoint bundles that this
+// master exports and imports.
+const checkpointBundleFormatVersion = 1
+
+// importedCheckpoint is the experiment, trial, step, validation and checkpoint that register a
+// checkpoint from a bundle. The validation is nil if the bundle has no validation metrics.
+type importedCheckpoint struct {
+	experiment *model.Experiment
+	trial      *model.Trial
+	step       *model.Step
+	validation *model.Validation
+	checkpoint *model.Checkpoint
+}
+
+// parseCheckpointBundle converts a checkpoint bundle into the records that register it on this
+// master. If storage is not nil, it replaces the checkpoint storage of the bundled experiment
+// configuration.
+func (m *Master) parseCheckpointBundle(
+	bundle *checkpointv1.CheckpointBundle, storage *structpb.Struct, projectID *int,
+) (*importedCheckpoint, error) {
+	if bundle.GetFormatVersion() != checkpointBundleFormatVersion {
+		return nil, errors.Errorf(
+			"unsupported checkpoint bundle format version %d", bundle.GetFormatVersion())
+	}
+	ckpt := bundle.GetCheckpoint()
+	if ckpt == nil {
+		return nil, errors.New("checkpoint bundle has no checkpoint")
+	}
+	if ckpt.State != checkpointv1.State_STATE_COMPLETED {
+		return nil, errors.Errorf("checkpoint %s is in state %s", ckpt.Uuid, ckpt.State)
+	}
+
+	config, err := bundleExperimentConfig(ckpt.ExperimentConfig, storage)
+	if err != nil {
+		return nil, err
+	}
+	modelDef := archive.Archive{}
+	if len(bundle.ModelDefinition) > 0 {
+		if modelDef, err = archive.FromTarGz(bundle.ModelDefinition); err != nil {
+			return nil, errors.Wrap(err, "invalid model definition")
+		}
+	}
+	return m.importCheckpointRecords(ckpt, config, modelDef, projectID)
+}
+
+// parseExternalCheckpoint converts the metadata of a checkpoint whose files are at storageURI into
+// the records that register it on this master. If the metadata has no experiment configuration,
+// the checkpoint is registered under a placeholder configuration.
+func (m *Master) parseExternalCheckpoint(
+	storageURI string, ckpt *checkpointv1.Checkpoint, projectID *int,
+) (*importedCheckpoint, error) {
+	if ckpt == nil {
+		return nil, errors.New("no checkpoint metadata")
+	}
+	if ckpt.State != checkpointv1.State_STATE_UNSPECIFIED &&
+		ckpt.State != checkpointv1.State_STATE_COMPLETED {
+		return nil, errors.Errorf("checkpoint %s is in state %s", ckpt.Uuid, ckpt.State)
+	}
+	storage, err := storageFromURI(storageURI, ckpt.Uuid)
+	if err != nil {
+		return nil, err
+	}
+	experimentConfig := ckpt.ExperimentConfig
+	if experimentConfig == nil {
+		if experimentConfig, err = placeholderExperimentConfig(ckpt); err != nil {
+			return nil, err
+		}
+	}
+	config, err := bundleExperimentConfig(experimentConfig, protoutils.ToStruct(storage))
+	if err != nil {
+		return nil, err
+	}
+	return m.importCheckpointRecords(ckpt, config, archive.Archive{}, projectID)
+}
+
+// importCheckpointRecords returns the records that register a checkpoint from elsewhere as the
+// only checkpoint of a new completed experiment with the given configuration and model definition.
+func (m *Master) importCheckpointRecords(
+	ckpt *checkpointv1.Checkpoint, config string, modelDef archive.Archive, projectID *int,
+) (*importedCheckpoint, error) {
+	exp, _, err := m.parseCreateExperiment(&CreateExperimentParams{
+		ConfigBytes: config,
+		ModelDef:    modelDef,
+		ProjectID:   projectID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	imported, err := checkpointRecords(ckpt)
+	if err != nil {
+		return nil, err
+	}
+	exp.State = model.CompletedState
+	exp.StartTime = imported.trial.StartTime
+	exp.EndTime = imported.trial.EndTime
+	imported.experiment = exp
+	return imported, nil
+}
+
+// storageFromURI returns the checkpoint storage configuration that holds the checkpoint with the
+// given UUID at the URI of its directory, e.g. s3://bucket/<uuid>.
+func storageFromURI(storageURI, checkpointUUID string) (map[string]interface{}, error) {
+	u, err := url.Parse(storageURI)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid storage URI")
+	}
+	dir, base := path.Split(strings.TrimSuffix(u.Path, "/"))
+	if base != checkpointUUID {
+		return nil, errors.Errorf(
+			"storage URI %s does not end with the checkpoint UUID %s", storageURI, checkpointUUID)
+	}
+	dir = path.Clean(dir)
+
+	switch u.Scheme {
+	case "s3", "gs":
+		if u.Host == "" || dir != "/" {
+			return nil, errors.Errorf(
+				"checkpoints in %s must be at the top level of a bucket", u.Scheme)
+		}
+		storageType := map[string]string{"s3": "s3", "gs": "gcs"}[u.Scheme]
+		return map[string]interface{}{"type": storageType, "bucket": u.Host}, nil
+	case "hdfs":
+		return map[string]interface{}{
+			"type":      "hdfs",
+			"hdfs_url":  fmt.Sprintf("hdfs://%s", u.Host),
+			"hdfs_path": dir,
+		}, nil
+	case "file":
+		if u.Host != "" {
+			return nil, errors.Errorf("file URI %s must not have a host", storageURI)
+		}
+		return map[string]interface{}{"type": "shared_fs", "host_path": dir}, nil
+	default:
+		return nil, errors.Errorf("unsupported storage URI scheme %q", u.Scheme)
+	}
+}
+
+// placeholderExperimentConfig returns the configuration of an experiment that only records a
+// checkpoint whose original configuration is unknown. The experiment has no model definition, so
+// its entrypoint is a placeholder; its hyperparameters are those of the checkpoint, with a global
+// batch size of 1 if the checkpoint has none, and its searcher metric is the first of the
+// validation metrics of the checkpoint.
+func placeholderExperimentConfig(ckpt *checkpointv1.Checkpoint) (*structpb.Struct, error) {
+	hparams, err := structToJSONObj(ckpt.Hparams)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid hyperparameters")
+	}
+	hyperparameters := map[string]interface{}{}
+	for name, val := range hparams {
+		hyperparameters[name] = map[string]interface{}{"type": "const", "val": val}
+	}
+	if _, ok := hyperparameters["global_batch_size"]; !ok {
+		hyperparameters["global_batch_size"] = map[string]interface{}{"type": "const", "val": 1}
+	}
+
+	metric := "validation_loss"
+	if metrics := ckpt.GetMetrics().GetValidationMetrics(); len(metrics.GetFields()) > 0 {
+		names := make([]string, 0, len(metrics.Fields))
+		for name := range metrics.Fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		metric = names[0]
+	}
+	batches := ckpt.BatchNumber
+	if batches < 1 {
+		batches = 1
+	}
+
+	return protoutils.ToStruct(map[string]interface{}{
+		"description":     fmt.Sprintf("Imported checkpoint %s", ckpt.Uuid),
+		"entrypoint":      "imported",
+		"hyperparameters": hyperparameters,
+		"searcher": map[string]interface{}{
+			"name":       "single",
+			"metric":     metric,
+			"max_length": map[string]interface{}{"batches": batches},
+		},
+	}), nil
+}
+
+// bundleExperimentConfig returns the experiment configuration of a bundled checkpoint as JSON, with
+// its checkpoint storage replaced by storage if storage is not nil.
+func bundleExperimentConfig(config, storage *structpb.Struct) (string, error) {
+	if config == nil {
+		return "", errors.New("checkpoint has no experiment configuration")
+	}
+	parsed, err := structToJSONObj(config)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid experiment configuration")
+	}
+	if storage != nil {
+		if parsed["checkpoint_storage"], err = structToJSONObj(storage); err != nil {
+			return "", errors.Wrap(err, "invalid checkpoint storage")
+		}
+	}
+	bytes, err := json.Marshal(parsed)
+	return string(bytes), err
+}
+
+// checkpointRecords converts a completed checkpoint from elsewhere into the trial, step,
+// validation and checkpoint that record it. The checkpoint is external, since its files are
+// owned elsewhere. The IDs of the records are left unset.
+func checkpointRecords(ckpt *checkpointv1.Checkpoint) (*importedCheckpoint, error) {
+	if _, err := uuid.Parse(ckpt.Uuid); err != nil {
+		return nil, errors.Wrap(err, "invalid checkpoint UUID")
+	}
+	startTime, endTime := time.Now().UTC(), time.Now().UTC()
+	if ckpt.StartTime != nil {
+		t, err := ptypes.Timestamp(ckpt.StartTime)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid start time")
+		}
+		startTime = t
+	}
+	if ckpt.EndTime != nil {
+		t, err := ptypes.Timestamp(ckpt.EndTime)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid end time")
+		}
+		endTime = t
+	}
+
+	hparams, err := structToJSONObj(ckpt.Hparams)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid hyperparameters")
+	}
+	metadata, err := structToJSONObj(ckpt.Metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid metadata")
+	}
+	resources := model.JSONObj{}
+	var size int64
+	for path, bytes := range ckpt.Resources {
+		resources[path] = bytes
+		size += bytes
+	}
+
+	imported := &importedCheckpoint{
+		trial: &model.Trial{
+			State:     model.CompletedState,
+			StartTime: startTime,
+			EndTime:   &endTime,
+			HParams:   hparams,
+		},
+		step: &model.Step{
+			ID:         1,
+			State:      model.CompletedState,
+			StartTime:  startTime,
+			EndTime:    &endTime,
+			NumBatches: int(ckpt.BatchNumber),
+		},
+		checkpoint: &model.Checkpoint{
+			External:          true,
+			State:             model.CompletedState,
+			StartTime:         startTime,
+			EndTime:           &endTime,
+			UUID:              &ckpt.Uuid,
+			Resources:         resources,
+			Metadata:          metadata,
+			Framework:         ckpt.Framework,
+			Format:            ckpt.Format,
+			DeterminedVersion: ckpt.DeterminedVersion,
+			Size:              size,
+		},
+	}
+	if ckpt.Metrics != nil {
+		metrics, err := structToJSONObj(ckpt.Metrics.ValidationMetrics)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid validation metrics")
+		}
+		imported.validation = &model.Validation{
+			State:     model.CompletedState,
+			StartTime: startTime,
+			EndTime:   &endTime,
+			Metrics: model.JSONObj{
+				"num_inputs":         ckpt.Metrics.NumInputs,
+				"validation_metrics": metrics,
+			},
+		}
+	}
+	return imported, nil
+}
+
+// structToJSONObj converts a protobuf struct to a JSON object; a nil struct is an empty object.
+func structToJSONObj(s *structpb.Struct) (model.JSONObj, error) {
+	obj := model.JSONObj{}
+	if s == nil {
+		return obj, nil
+	}
+	bytes, err := protojson.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return obj, json.Unmarshal(bytes, &obj)
+}
@@ -0,0 +1,141 @@
+package internal
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/protoutils"
+	"github.com/determined-ai/determined/proto/pkg/checkpointv1"
+)
+
+func TestBundleExperimentConfig(t *testing.T) {
+	config := protoutils.ToStruct(map[string]interface{}{
+		"description":        "source",
+		"checkpoint_storage": map[string]interface{}{"type": "s3", "bucket": "old"},
+	})
+
+	parse := func(s string) map[string]interface{} {
+		var parsed map[string]interface{}
+		assert.NilError(t, json.Unmarshal([]byte(s), &parsed))
+		return parsed
+	}
+
+	kept, err := bundleExperimentConfig(config, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, parse(kept)["checkpoint_storage"],
+		map[string]interface{}{"type": "s3", "bucket": "old"})
+
+	storage := protoutils.ToStruct(map[string]interface{}{"type": "gcs", "bucket": "new"})
+	replaced, err := bundleExperimentConfig(config, storage)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, parse(replaced), map[string]interface{}{
+		"description":        "source",
+		"checkpoint_storage": map[string]interface{}{"type": "gcs", "bucket": "new"},
+	})
+
+	_, err = bundleExperimentConfig(nil, storage)
+	assert.ErrorContains(t, err, "no experiment configuration")
+}
+
+func TestCheckpointRecords(t *testing.T) {
+	end := time.Date(2021, 2, 16, 12, 0, 0, 0, time.UTC)
+	ckpt := &checkpointv1.Checkpoint{
+		Uuid:              "6b4ac2d4-8e0c-4b8c-9f1e-2c1c4a6f0d3e",
+		Hparams:           protoutils.ToStruct(map[string]interface{}{"lr": 0.1}),
+		BatchNumber:       400,
+		StartTime:         protoutils.ToTimestamp(end.Add(-time.Minute)),
+		EndTime:           protoutils.ToTimestamp(end),
+		Resources:         map[string]int64{"model.pt": 100, "state.json": 20},
+		Framework:         "torch",
+		DeterminedVersion: "0.14.0",
+		Metrics: &checkpointv1.Metrics{
+			NumInputs:         8,
+			ValidationMetrics: protoutils.ToStruct(map[string]interface{}{"loss": 0.5}),
+		},
+		State: checkpointv1.State_STATE_COMPLETED,
+	}
+
+	imported, err := checkpointRecords(ckpt)
+	assert.NilError(t, err)
+	assert.Equal(t, imported.trial.State, model.CompletedState)
+	assert.DeepEqual(t, imported.trial.HParams, model.JSONObj{"lr": 0.1})
+	assert.Equal(t, imported.step.NumBatches, 400)
+	assert.Equal(t, *imported.checkpoint.EndTime, end)
+	assert.Equal(t, *imported.checkpoint.UUID, ckpt.Uuid)
+	assert.Equal(t, imported.checkpoint.Size, int64(120))
+	assert.Equal(t, imported.checkpoint.Framework, "torch")
+	assert.Assert(t, imported.checkpoint.External)
+	assert.DeepEqual(t, imported.validation.Metrics, model.JSONObj{
+		"num_inputs":         int32(8),
+		"validation_metrics": model.JSONObj{"loss": 0.5},
+	})
+
+	ckpt.Metrics = nil
+	imported, err = checkpointRecords(ckpt)
+	assert.NilError(t, err)
+	assert.Assert(t, imported.validation == nil)
+
+	ckpt.Uuid = "not-a-uuid"
+	_, err = checkpointRecords(ckpt)
+	assert.ErrorContains(t, err, "invalid checkpoint UUID")
+}
+
+func TestStorageFromURI(t *testing.T) {
+	const id = "6b4ac2d4-8e0c-4b8c-9f1e-2c1c4a6f0d3e"
+	cases := []struct {
+		uri     string
+		storage map[string]interface{}
+		err     string
+	}{
+		{"s3://bucket/" + id, map[string]interface{}{"type": "s3", "bucket": "bucket"}, ""},
+		{"gs://bucket/" + id + "/", map[string]interface{}{"type": "gcs", "bucket": "bucket"}, ""},
+		{"hdfs://namenode:8020/ckpts/" + id, map[string]interface{}{
+			"type": "hdfs", "hdfs_url": "hdfs://namenode:8020", "hdfs_path": "/ckpts",
+		}, ""},
+		{"file:///mnt/ckpts/" + id, map[string]interface{}{
+			"type": "shared_fs", "host_path": "/mnt/ckpts",
+		}, ""},
+		{"s3://bucket/prefix/" + id, nil, "top level of a bucket"},
+		{"s3://bucket/other", nil, "does not end with the checkpoint UUID"},
+		{"file://host/mnt/" + id, nil, "must not have a host"},
+		{"ftp://host/" + id, nil, "unsupported storage URI scheme"},
+	}
+	for _, c := range cases {
+		storage, err := storageFromURI(c.uri, id)
+		if c.err != "" {
+			assert.ErrorContains(t, err, c.err, c.uri)
+			continue
+		}
+		assert.NilError(t, err, c.uri)
+		assert.DeepEqual(t, storage, c.storage)
+	}
+}
+
+func TestPlaceholderExperimentConfig(t *testing.T) {
+	config, err := placeholderExperimentConfig(&checkpointv1.Checkpoint{
+		Uuid:    "6b4ac2d4-8e0c-4b8c-9f1e-2c1c4a6f0d3e",
+		Hparams: protoutils.ToStruct(map[string]interface{}{"lr": 0.1}),
+		Metrics: &checkpointv1.Metrics{
+			ValidationMetrics: protoutils.ToStruct(map[string]interface{}{
+				"loss": 0.5, "accuracy": 0.9,
+			}),
+		},
+	})
+	assert.NilError(t, err)
+	configJSON, err := bundleExperimentConfig(config, protoutils.ToStruct(
+		map[string]interface{}{"type": "s3", "bucket": "bucket"}))
+	assert.NilError(t, err)
+
+	parsed := model.DefaultExperimentConfig(nil)
+	assert.NilError(t, json.Unmarshal([]byte(configJSON), &parsed))
+	assert.NilError(t, check.Validate(parsed))
+	assert.Equal(t, parsed.Searcher.Metric, "accuracy")
+	assert.Equal(t, parsed.Searcher.SingleConfig.MaxLength, model.NewLengthInBatches(1))
+	assert.Equal(t, parsed.Hyperparameters["lr"].ConstHyperparameter.Val, 0.1)
+	assert.Equal(t, parsed.CheckpointStorage.S3Config.Bucket, "bucket")
+}
@@ -15,6 +15,9 @@ type checkpointGCTask struct {
 	rm         *actor.Ref
 	db         *db.PgDB
 	experiment *model.Experiment
+	// toDelete, if set, holds the checkpoints to delete instead of the ones that the checkpoint
+	// storage config of the experiment selects. They are already marked as deleted.
+	toDelete []byte
 
 	agentUserGroup *model.AgentUserGroup
 	taskSpec       *tasks.TaskSpec
@@ -36,12 +39,15 @@ func (t *checkpointGCTask) Receive(ctx *actor.Context) error {
 		})
 
 	case sproto.ResourcesAllocated:
-		config := t.experiment.Config.CheckpointStorage
-
-		checkpoints, err := t.db.ExperimentCheckpointsToGCRaw(t.experiment.ID,
-			&config.SaveExperimentBest, &config.SaveTrialBest, &config.SaveTrialLatest, true)
-		if err != nil {
-			return err
+		checkpoints := t.toDelete
+		if checkpoints == nil {
+			config := t.experiment.Config.CheckpointStorage
+			var err error
+			if checkpoints, err = t.db.ExperimentCheckpointsToGCRaw(t.experiment.ID,
+				&config.SaveExperimentBest, &config.SaveTrialBest, &config.SaveTrialLatest, true,
+			); err != nil {
+				return err
+			}
 		}
 
 		ctx.Log().Info("starting checkpoint garbage collection")
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/check"
+)
+
+// CheckpointQuotaConfig configures the maximum total size of the checkpoints of the experiments of
+// each user. When a user goes over their quota, their lowest-ranked checkpoints are deleted.
+type CheckpointQuotaConfig struct {
+	// DefaultUserBytes applies to users that are not listed in UserBytes; if it is not set, those
+	// users are not limited.
+	DefaultUserBytes *int64           `json:"default_user_bytes"`
+	UserBytes        map[string]int64 `json:"user_bytes"`
+}
+
+// Validate implements the check.Validatable interface.
+func (q CheckpointQuotaConfig) Validate() []error {
+	errs := []error{
+		check.GreaterThanOrEqualTo(q.DefaultUserBytes, int64(0), "default_user_bytes must be >= 0"),
+	}
+	for user, bytes := range q.UserBytes {
+		errs = append(errs, check.GreaterThanOrEqualTo(
+			bytes, int64(0), "the checkpoint quota of user %s must be >= 0", user))
+	}
+	return errs
+}
+
+// userQuota returns the checkpoint quota of the user in bytes, if there is one.
+func (q CheckpointQuotaConfig) userQuota(user string) (int64, bool) {
+	if bytes, ok := q.UserBytes[user]; ok {
+		return bytes, true
+	}
+	if q.DefaultUserBytes != nil {
+		return *q.DefaultUserBytes, true
+	}
+	return 0, false
+}
+
+// enforceCheckpointQuotas deletes the lowest-ranked checkpoints of the experiment, and of the other
+// experiments of its owner, that go over the quotas of the experiment and of its owner.
+func (e *experiment) enforceCheckpointQuotas(ctx *actor.Context) {
+	if maxBytes := e.Config.CheckpointStorage.MaxStorageBytes; maxBytes != nil {
+		e.gcCheckpointsOverQuota(ctx, &e.ID, nil, *maxBytes)
+	}
+	if maxBytes, ok := e.checkpointQuotas.userQuota(e.owner); ok && e.OwnerID != nil {
+		ownerID := int(*e.OwnerID)
+		e.gcCheckpointsOverQuota(ctx, nil, &ownerID, maxBytes)
+	}
+}
+
+func (e *experiment) gcCheckpointsOverQuota(
+	ctx *actor.Context, experimentID, ownerID *int, maxBytes int64,
+) {
+	toDelete, err := e.db.CheckpointsOverQuota(experimentID, ownerID, maxBytes)
+	if err != nil {
+		ctx.Log().WithError(err).Error("failed to find checkpoints over quota")
+		return
+	}
+	for id, checkpoints := range toDelete {
+		exp := e.Experiment
+		if id != e.ID {
+			if exp, err = e.db.ExperimentByID(id); err != nil {
+				ctx.Log().WithError(err).Errorf("failed to load experiment %d to delete checkpoints", id)
+				continue
+			}
+		}
+		ctx.Log().Infof("deleting checkpoints of experiment %d that are over quota", id)
+		addr := actor.Addr(fmt.Sprintf("quota-checkpoint-gc-%s", uuid.New().String()))
+		ctx.Self().System().ActorOf(addr, &checkpointGCTask{
+			agentUserGroup: e.agentUserGroup,
+			taskSpec:       e.taskSpec,
+			rm:             e.rm,
+			db:             e.db,
+			experiment:     exp,
+			toDelete:       checkpoints,
+		})
+	}
+}
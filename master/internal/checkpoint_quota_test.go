@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+)
+
+func TestCheckpointQuotaConfig(t *testing.T) {
+	raw := `
+default_user_bytes: 1000
+user_bytes:
+  alice: 5000
+`
+	var config CheckpointQuotaConfig
+	assert.NilError(t, yaml.Unmarshal([]byte(raw), &config))
+	assert.NilError(t, check.Validate(config))
+
+	bytes, ok := config.userQuota("alice")
+	assert.Assert(t, ok)
+	assert.Equal(t, bytes, int64(5000))
+	bytes, ok = config.userQuota("bob")
+	assert.Assert(t, ok)
+	assert.Equal(t, bytes, int64(1000))
+
+	_, ok = CheckpointQuotaConfig{}.userQuota("bob")
+	assert.Assert(t, !ok)
+
+	config.UserBytes["bob"] = -1
+	assert.ErrorContains(t, check.Validate(config), "quota of user bob")
+}
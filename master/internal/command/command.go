@@ -39,6 +39,9 @@ type readinessCheck func(sproto.ContainerLog) bool
 // should stop and garbage collect its state.
 type terminateForGC struct{}
 
+// GetOwner is a message asking a command for the ID of the user that owns it.
+type GetOwner struct{}
+
 // commandOwner describes the owner of a command.
 type commandOwner struct {
 	ID       model.UserID `json:"id"`
@@ -102,6 +105,10 @@ func (c *command) Receive(ctx *actor.Context) error {
 		// Schedule the command with the cluster.
 		c.proxy = ctx.Self().System().Get(actor.Addr("proxy"))
 
+		var expectedRuntime time.Duration
+		if runtime := c.config.Resources.ExpectedRuntime; runtime != nil {
+			expectedRuntime = time.Duration(*runtime)
+		}
 		c.task = &sproto.AllocateRequest{
 			ID:             c.taskID,
 			Name:           c.config.Description,
@@ -112,7 +119,9 @@ func (c *command) Receive(ctx *actor.Context) error {
 			FittingRequirements: sproto.FittingRequirements{
 				SingleAgent: true,
 			},
-			TaskActor: ctx.Self(),
+			TaskActor:       ctx.Self(),
+			User:            c.owner.Username,
+			ExpectedRuntime: expectedRuntime,
 		}
 		if err := ctx.Ask(sproto.GetRM(ctx.Self().System()), *c.task).Error(); err != nil {
 			return err
@@ -129,6 +138,9 @@ func (c *command) Receive(ctx *actor.Context) error {
 	case sproto.ResourcesAllocated:
 		return c.receiveSchedulerMsg(ctx)
 
+	case GetOwner:
+		ctx.Respond(c.owner.ID)
+
 	case getSummary:
 		if msg.userFilter == "" || c.owner.Username == msg.userFilter {
 			ctx.Respond(newSummary(c))
@@ -285,6 +285,15 @@ func (t *tensorboardManager) newTensorBoard(
 		case c.GCSConfig != nil:
 			logBasePath = "gs://" + c.GCSConfig.Bucket
 
+		case c.AzureConfig != nil:
+			// TensorBoard cannot read from Azure Blob Storage, so the TensorBoard entrypoint copies
+			// the logs under az:// paths to local disk with these credentials.
+			for key, value := range azureEnvVars(*c.AzureConfig) {
+				uniqEnvVars[key] = value
+			}
+
+			logBasePath = "az://" + c.AzureConfig.Container
+
 		case c.HDFSConfig != nil:
 			logBasePath = "hdfs://" + c.HDFSConfig.Path
 
@@ -438,6 +447,22 @@ func getMounts(m map[model.BindMount]bool) []model.BindMount {
 	return bindMounts
 }
 
+// azureEnvVars returns the environment variables that give a TensorBoard access to the storage
+// account of an Azure container.
+func azureEnvVars(c model.AzureConfig) map[string]string {
+	envVars := map[string]string{}
+	if c.ConnectionString != nil {
+		envVars["AZURE_STORAGE_CONNECTION_STRING"] = *c.ConnectionString
+	}
+	if c.AccountURL != nil {
+		envVars["DET_AZURE_ACCOUNT_URL"] = *c.AccountURL
+	}
+	if c.Credential != nil {
+		envVars["DET_AZURE_CREDENTIAL"] = *c.Credential
+	}
+	return envVars
+}
+
 func getEnvVars(m map[string]string) []string {
 	var envVars []string
 
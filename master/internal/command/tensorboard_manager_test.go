@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 func TestRefineArgs(t *testing.T) {
@@ -34,3 +36,17 @@ func TestRefineArgs(t *testing.T) {
 
 	assert.DeepEqual(t, args, expected)
 }
+
+func TestAzureEnvVars(t *testing.T) {
+	connectionString := "DefaultEndpointsProtocol=http;AccountName=devstoreaccount1"
+	assert.DeepEqual(t,
+		azureEnvVars(model.AzureConfig{Container: "c", ConnectionString: &connectionString}),
+		map[string]string{"AZURE_STORAGE_CONNECTION_STRING": connectionString})
+
+	accountURL, credential := "https://myaccount.blob.core.windows.net", "key"
+	assert.DeepEqual(t,
+		azureEnvVars(model.AzureConfig{
+			Container: "c", AccountURL: &accountURL, Credential: &credential,
+		}),
+		map[string]string{"DET_AZURE_ACCOUNT_URL": accountURL, "DET_AZURE_CREDENTIAL": credential})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/hpimportance"
 	"github.com/determined-ai/determined/master/internal/resourcemanagers"
+	"github.com/determined-ai/determined/master/internal/user"
 	"github.com/determined-ai/determined/master/pkg/check"
 	"github.com/determined-ai/determined/master/pkg/logger"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -91,6 +92,7 @@ type Config struct {
 	ClusterName           string                            `json:"cluster_name"`
 	Logging               model.LoggingConfig               `json:"logging"`
 	HPImportance          hpimportance.HPImportanceConfig   `json:"hyperparameter_importance"`
+	CheckpointQuotas      CheckpointQuotaConfig             `json:"checkpoint_quotas"`
 
 	*resourcemanagers.ResourceConfig
 }
@@ -190,6 +192,15 @@ func (c *CheckpointStorageConfig) printable() ([]byte, error) {
 		csm.S3Config.AccessKey = &hiddenValue
 		csm.S3Config.SecretKey = &hiddenValue
 		return csm.MarshalJSON()
+	case csm.AzureConfig != nil:
+		// Keep unset secrets unset so that the printed config still shows the kind of auth in use.
+		if csm.AzureConfig.ConnectionString != nil {
+			csm.AzureConfig.ConnectionString = &hiddenValue
+		}
+		if csm.AzureConfig.Credential != nil {
+			csm.AzureConfig.Credential = &hiddenValue
+		}
+		return csm.MarshalJSON()
 	default:
 		return csm.MarshalJSON()
 	}
@@ -253,6 +264,8 @@ func (c *CheckpointStorageConfig) UnmarshalJSON(data []byte) error {
 type SecurityConfig struct {
 	DefaultTask model.AgentUserGroup `json:"default_task"`
 	TLS         TLSConfig            `json:"tls"`
+	// OIDC lets users log in with an OpenID Connect identity provider in addition to passwords.
+	OIDC *user.OIDCConfig `json:"oidc"`
 }
 
 // TLSConfig is the configuration for setting up serving over TLS.
@@ -13,6 +13,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/provisioner"
 	"github.com/determined-ai/determined/master/internal/resourcemanagers"
 	"github.com/determined-ai/determined/master/pkg/logger"
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 func TestUnmarshalConfigWithAgentResourceManager(t *testing.T) {
@@ -66,8 +67,8 @@ resource_pools:
 						AgentDockerRuntime:     "runc",
 						AgentDockerNetwork:     "default",
 						AgentFluentImage:       "fluent/fluent-bit:1.6",
-						MaxIdleAgentPeriod:     provisioner.Duration(30 * time.Second),
-						MaxAgentStartingPeriod: provisioner.Duration(30 * time.Second),
+						MaxIdleAgentPeriod:     model.Duration(30 * time.Second),
+						MaxAgentStartingPeriod: model.Duration(30 * time.Second),
 						MaxInstances:           5,
 					},
 					MaxCPUContainersPerAgent: 100,
@@ -156,6 +157,7 @@ checkpoint_storage:
 {
   "access_key": "my_key",
   "bucket": "my_bucket",
+  "max_storage_bytes": null,
   "save_experiment_best": 0,
   "save_trial_best": 0,
   "save_trial_latest": 0,
@@ -169,3 +171,25 @@ checkpoint_storage:
 	assert.NilError(t, err)
 	assert.DeepEqual(t, unmarshaled, expected)
 }
+
+func TestPrintableConfigHidesAzureSecrets(t *testing.T) {
+	raw := `
+checkpoint_storage:
+  type: azure
+  container: my_container
+  account_url: https://myaccount.blob.core.windows.net
+  credential: my_account_key
+`
+	config := DefaultConfig()
+	assert.NilError(t, yaml.Unmarshal([]byte(raw), config, yaml.DisallowUnknownFields))
+	assert.NilError(t, config.Resolve())
+
+	printable, err := config.Printable()
+	assert.NilError(t, err)
+	assert.Assert(t, !strings.Contains(string(printable), "my_account_key"))
+	assert.Assert(t, strings.Contains(string(printable), "myaccount.blob.core.windows.net"))
+
+	storage, err := config.CheckpointStorage.ToModel()
+	assert.NilError(t, err)
+	assert.Equal(t, *storage.AzureConfig.Credential, "my_account_key")
+}
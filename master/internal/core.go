@@ -28,6 +28,7 @@ import (
 	"github.com/soheilhy/cmux"
 
 	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/authz"
 	"github.com/determined-ai/determined/master/internal/command"
 	detContext "github.com/determined-ai/determined/master/internal/context"
 	"github.com/determined-ai/determined/master/internal/db"
@@ -188,7 +189,7 @@ func (m *Master) startServers(ctx context.Context, cert *tls.Certificate) error
 		}()
 	}
 	start("gRPC server", func() error {
-		srv := grpc.NewGRPCServer(m.db, &apiServer{m: m})
+		srv := grpc.NewGRPCServer(m.db, authz.New(m.db, m.system), &apiServer{m: m})
 		// We should defer srv.Stop() here, but cmux does not unblock accept calls when underlying
 		// listeners close and grpc-go depends on cmux unblocking and closing, Stop() blocks
 		// indefinitely when using cmux.
@@ -379,11 +380,21 @@ func (m *Master) Run(ctx context.Context) error {
 	}
 	m.trialLogger, _ = m.system.ActorOf(actor.Addr("trialLogger"), newTrialLogger(m.trialLogBackend))
 
-	userService, err := user.New(m.db, m.system)
+	var providers []user.Provider
+	if m.config.Security.OIDC != nil {
+		oidc, oErr := user.NewOIDCProvider(context.Background(), *m.config.Security.OIDC)
+		if oErr != nil {
+			return errors.Wrap(oErr, "cannot initialize OIDC provider")
+		}
+		providers = append(providers, oidc)
+	}
+	userService, err := user.New(m.db, m.system, authz.New(m.db, m.system), providers...)
 	if err != nil {
 		return errors.Wrap(err, "cannot initialize user manager")
 	}
-	authFuncs := []echo.MiddlewareFunc{userService.ProcessAuthentication}
+	authFuncs := []echo.MiddlewareFunc{
+		userService.RecordAuditEvents, userService.ProcessAuthentication,
+	}
 
 	m.proxy, _ = m.system.ActorOf(actor.Addr("proxy"), &proxy.Proxy{})
 
@@ -532,10 +543,12 @@ func (m *Master) Run(ctx context.Context) error {
 	experimentsGroup.PATCH("/:experiment_id", api.Route(m.patchExperiment))
 	experimentsGroup.POST("", api.Route(m.postExperiment))
 	experimentsGroup.POST("/:experiment_id/kill", api.Route(m.postExperimentKill))
+	experimentsGroup.POST("/:experiment_id/continue", api.Route(m.postExperimentContinue))
 	experimentsGroup.DELETE("/:experiment_id", api.Route(m.deleteExperiment))
 
 	searcherGroup := m.echo.Group("/searcher", authFuncs...)
 	searcherGroup.POST("/preview", api.Route(m.getSearcherPreview))
+	searcherGroup.POST("/replay", api.Route(m.postSearcherReplay))
 
 	trialsGroup := m.echo.Group("/trials", authFuncs...)
 	trialsGroup.GET("/:trial_id", api.Route(m.getTrial))
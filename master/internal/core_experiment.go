@@ -18,10 +18,12 @@ import (
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/context"
 	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/internal/telemetry"
 	"github.com/determined-ai/determined/master/pkg/actor"
 	"github.com/determined-ai/determined/master/pkg/archive"
 	"github.com/determined-ai/determined/master/pkg/check"
 	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/searcher"
 )
 
 // ExperimentRequestQuery contains values for the experiments request queries with defaults already
@@ -325,6 +327,10 @@ func (m *Master) patchExperiment(c echo.Context) (interface{}, error) {
 	}
 
 	if patch.CheckpointStorage != nil {
+		if _, ok := model.TerminalStates[dbExp.State]; !ok {
+			m.system.TellAt(actor.Addr("experiments", args.ExperimentID),
+				dbExp.Config.CheckpointStorage)
+		}
 		m.system.ActorOf(actor.Addr(fmt.Sprintf("patch-checkpoint-gc-%s", uuid.New().String())),
 			&checkpointGCTask{
 				agentUserGroup: agentUserGroup,
@@ -350,6 +356,7 @@ type CreateExperimentParams struct {
 	GitCommitter  *string         `json:"git_committer"`
 	GitCommitDate *time.Time      `json:"git_commit_date"`
 	ValidateOnly  bool            `json:"validate_only"`
+	ProjectID     *int            `json:"project_id"`
 }
 
 func (m *Master) parseCreateExperiment(params *CreateExperimentParams) (
@@ -364,6 +371,16 @@ func (m *Master) parseCreateExperiment(params *CreateExperimentParams) (
 
 	config.CheckpointStorage = *checkpointStorage
 
+	if params.ProjectID != nil {
+		project, perr := m.db.ProjectByID(*params.ProjectID)
+		if perr != nil {
+			return nil, false, errors.Wrapf(perr, "unable to find project %d", *params.ProjectID)
+		}
+		if perr = applyProjectDefaults(&config, project); perr != nil {
+			return nil, false, perr
+		}
+	}
+
 	if params.Template != nil {
 		template, terr := m.db.TemplateByName(*params.Template)
 		if terr != nil {
@@ -412,7 +429,11 @@ func (m *Master) parseCreateExperiment(params *CreateExperimentParams) (
 	dbExp, err := model.NewExperiment(
 		config, modelBytes, params.ParentID, params.Archived,
 		params.GitRemote, params.GitCommit, params.GitCommitter, params.GitCommitDate)
-	return dbExp, params.ValidateOnly, err
+	if err != nil {
+		return nil, false, err
+	}
+	dbExp.ProjectID = params.ProjectID
+	return dbExp, params.ValidateOnly, nil
 }
 
 func (m *Master) postExperiment(c echo.Context) (interface{}, error) {
@@ -457,6 +478,207 @@ func (m *Master) postExperiment(c echo.Context) (interface{}, error) {
 	return c.JSON(http.StatusCreated, response), nil
 }
 
+// continuedSearcherConfig returns the searcher config of an experiment with a larger budget. Only
+// random, async_halving and adaptive_asha searches can be continued. The brackets of an
+// adaptive_asha search are pinned so that the larger budget does not change them.
+func continuedSearcherConfig(
+	config model.SearcherConfig, maxTrials, maxLength *int,
+) (model.SearcherConfig, error) {
+	if maxTrials == nil && maxLength == nil {
+		return config, errors.New("either max_trials or max_length must be set")
+	}
+	switch {
+	case config.RandomConfig != nil:
+		c := *config.RandomConfig
+		if maxTrials != nil {
+			c.MaxTrials = *maxTrials
+		}
+		if maxLength != nil {
+			c.MaxLength.Units = *maxLength
+		}
+		config.RandomConfig = &c
+	case config.AsyncHalvingConfig != nil:
+		c := *config.AsyncHalvingConfig
+		if maxTrials != nil {
+			c.MaxTrials = *maxTrials
+		}
+		if maxLength != nil {
+			c.MaxLength.Units = *maxLength
+		}
+		config.AsyncHalvingConfig = &c
+	case config.AdaptiveASHAConfig != nil:
+		c := *config.AdaptiveASHAConfig
+		if len(c.BracketRungs) == 0 {
+			c.BracketRungs = searcher.AdaptiveASHABrackets(c)
+		}
+		if maxTrials != nil {
+			c.MaxTrials = *maxTrials
+		}
+		if maxLength != nil {
+			c.MaxLength.Units = *maxLength
+		}
+		config.AdaptiveASHAConfig = &c
+	default:
+		return config, errors.New(
+			"only random, async_halving and adaptive_asha searches can be continued")
+	}
+	if err := check.Validate(config); err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
+// setCheckpointRetention changes which checkpoints of an experiment are kept and starts a
+// checkpoint GC task to delete the ones that are no longer kept. It returns the UUIDs of those
+// checkpoints.
+func (m *Master) setCheckpointRetention(
+	expModel *model.Experiment, config model.CheckpointStorageConfig,
+) ([]string, error) {
+	// The config is saved before checkpoints are marked as deleted, so that checkpoints are never
+	// marked as deleted without being garbage collected. If marking them fails, they are collected
+	// under the new retention when the experiment is garbage collected next.
+	expModel.Config.CheckpointStorage = config
+	if err := m.db.SaveExperimentConfig(expModel); err != nil {
+		return nil, errors.Wrapf(err, "patching experiment %d", expModel.ID)
+	}
+
+	toDelete, err := m.db.ExperimentCheckpointsToGCRaw(expModel.ID,
+		&config.SaveExperimentBest, &config.SaveTrialBest, &config.SaveTrialLatest, true)
+	if err != nil {
+		return nil, err
+	}
+	uuids, err := checkpointUUIDs(toDelete)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := model.TerminalStates[expModel.State]; !ok {
+		// Running experiments garbage collect their checkpoints again when they finish.
+		m.system.TellAt(actor.Addr("experiments", expModel.ID), config)
+	}
+
+	agentUserGroup, err := m.db.AgentUserGroup(*expModel.OwnerID)
+	if err != nil {
+		return nil, errors.Errorf("cannot find user and group for experiment %v", expModel.ID)
+	}
+	if agentUserGroup == nil {
+		agentUserGroup = &m.config.Security.DefaultTask
+	}
+	m.system.ActorOf(actor.Addr(fmt.Sprintf("retention-checkpoint-gc-%s", uuid.New().String())),
+		&checkpointGCTask{
+			agentUserGroup: agentUserGroup,
+			taskSpec:       m.taskSpec,
+			rm:             m.rm,
+			db:             m.db,
+			experiment:     expModel,
+			toDelete:       toDelete,
+		})
+	return uuids, nil
+}
+
+// checkpointUUIDs returns the UUIDs of checkpoints in the format that checkpoint GC takes.
+func checkpointUUIDs(toDelete []byte) ([]string, error) {
+	var parsed struct {
+		Checkpoints []struct {
+			UUID string `json:"uuid"`
+		} `json:"checkpoints"`
+	}
+	if err := json.Unmarshal(toDelete, &parsed); err != nil {
+		return nil, errors.Wrap(err, "error parsing checkpoints to delete")
+	}
+	uuids := make([]string, 0, len(parsed.Checkpoints))
+	for _, c := range parsed.Checkpoints {
+		uuids = append(uuids, c.UUID)
+	}
+	return uuids, nil
+}
+
+// continueExperiment reopens a completed experiment with the given searcher config. The searcher is
+// restored from the last snapshot of the experiment and resumes issuing operations for new trials,
+// while the trials of the completed experiment are kept as they are.
+func (m *Master) continueExperiment(
+	expModel *model.Experiment, config model.SearcherConfig,
+) error {
+	switch {
+	case expModel.State != model.CompletedState:
+		return errors.Errorf(
+			"cannot continue experiment %d in state %v", expModel.ID, expModel.State)
+	case expModel.Archived:
+		return errors.Errorf("cannot continue archived experiment %d", expModel.ID)
+	case m.system.Get(actor.Addr("experiments", expModel.ID)) != nil:
+		return errors.Errorf("experiment %d is already running", expModel.ID)
+	}
+
+	snapshot, err := m.retrieveExperimentSnapshot(expModel)
+	if err != nil {
+		return errors.Wrapf(err, "failed to continue experiment %d", expModel.ID)
+	} else if snapshot == nil {
+		return errors.Errorf("experiment %d has no searcher snapshot to continue from", expModel.ID)
+	}
+	e, err := newExperiment(m, expModel)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create experiment %d from model", expModel.ID)
+	}
+	if err = e.Restore(snapshot); err != nil {
+		return errors.Wrap(err, "failed to restore experiment")
+	}
+	if _, err = e.searcher.Continue(config); err != nil {
+		return errors.Wrapf(err, "failed to continue experiment %d", expModel.ID)
+	}
+
+	e.Config.Searcher = config
+	e.State = model.ActiveState
+	e.EndTime = nil
+	if snapshot, err = e.Snapshot(); err != nil {
+		return errors.Wrap(err, "failed to snapshot experiment")
+	}
+	if err = m.db.ContinueExperiment(e.Experiment, experimentSnapshotVersion, snapshot); err != nil {
+		return errors.Wrapf(err, "failed to save continued experiment %d", expModel.ID)
+	}
+	telemetry.ReportExperimentStateChanged(m.system, m.db, *e.Experiment)
+
+	// The new trials are created from the searcher operations, like trials that were never
+	// allocated are on restore.
+	e.restored = true
+	m.system.ActorOf(actor.Addr("experiments", e.ID), e)
+	return nil
+}
+
+func (m *Master) postExperimentContinue(c echo.Context) (interface{}, error) {
+	args := struct {
+		ExperimentID int `path:"experiment_id"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	params := struct {
+		MaxTrials *int `json:"max_trials"`
+		MaxLength *int `json:"max_length"`
+	}{}
+	if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
+		return nil, echo.NewHTTPError(
+			http.StatusBadRequest, errors.Wrap(err, "invalid continue params"))
+	}
+
+	dbExp, err := m.db.ExperimentByID(args.ExperimentID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading experiment %v", args.ExperimentID)
+	}
+	config, err := continuedSearcherConfig(dbExp.Config.Searcher, params.MaxTrials, params.MaxLength)
+	if err != nil {
+		return nil, echo.NewHTTPError(
+			http.StatusBadRequest, errors.Wrap(err, "invalid searcher budget"))
+	}
+	if err = m.continueExperiment(dbExp, config); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return model.ExperimentDescriptor{
+		ID:       dbExp.ID,
+		Archived: dbExp.Archived,
+		Config:   dbExp.Config,
+		Labels:   make([]string, 0),
+	}, nil
+}
+
 func (m *Master) deleteExperiment(c echo.Context) (interface{}, error) {
 	args := struct {
 		ExperimentID int `path:"experiment_id"`
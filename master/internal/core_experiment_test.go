@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestContinuedSearcherConfig(t *testing.T) {
+	maxTrials, maxLength := 16, 2000
+
+	random := model.SearcherConfig{
+		Metric: "loss", SmallerIsBetter: true,
+		RandomConfig: &model.RandomConfig{
+			MaxTrials: 4, MaxLength: model.NewLengthInBatches(1000),
+		},
+	}
+	config, err := continuedSearcherConfig(random, &maxTrials, &maxLength)
+	assert.NilError(t, err)
+	assert.Equal(t, config.RandomConfig.MaxTrials, 16)
+	assert.Equal(t, config.RandomConfig.MaxLength, model.NewLengthInBatches(2000))
+	assert.Equal(t, random.RandomConfig.MaxTrials, 4)
+
+	_, err = continuedSearcherConfig(random, nil, nil)
+	assert.ErrorContains(t, err, "either max_trials or max_length must be set")
+
+	adaptive := model.SearcherConfig{
+		Metric: "loss", SmallerIsBetter: true,
+		AdaptiveASHAConfig: &model.AdaptiveASHAConfig{
+			Metric: "loss", SmallerIsBetter: true,
+			MaxTrials: 4, MaxLength: model.NewLengthInBatches(800),
+			Divisor: 2, MaxRungs: 5, Mode: model.StandardMode,
+		},
+	}
+	config, err = continuedSearcherConfig(adaptive, &maxTrials, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, config.AdaptiveASHAConfig.MaxTrials, 16)
+	assert.DeepEqual(t, config.AdaptiveASHAConfig.BracketRungs, []int{3, 2})
+
+	grid := model.SearcherConfig{
+		Metric: "loss", SmallerIsBetter: true,
+		GridConfig: &model.GridConfig{MaxLength: model.NewLengthInBatches(1000)},
+	}
+	_, err = continuedSearcherConfig(grid, &maxTrials, nil)
+	assert.ErrorContains(t, err, "can be continued")
+}
+
+func TestCheckpointUUIDs(t *testing.T) {
+	uuids, err := checkpointUUIDs([]byte(`{"checkpoints": [
+		{"uuid": "a", "resources": {"x": 1}, "framework": "", "format": ""},
+		{"uuid": "b", "resources": {}, "framework": "", "format": ""}
+	]}`))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, uuids, []string{"a", "b"})
+
+	uuids, err = checkpointUUIDs([]byte(`{"checkpoints": null}`))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, uuids, []string{})
+}
@@ -1,10 +1,13 @@
 package internal
 
 import (
+	"encoding/json"
 	"io/ioutil"
+	"net/http"
 
 	"github.com/ghodss/yaml"
 	"github.com/labstack/echo"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/determined-ai/determined/master/pkg/check"
@@ -30,6 +33,63 @@ func (m *Master) getSearcherPreview(c echo.Context) (interface{}, error) {
 	return searcher.Simulate(s, nil, searcher.RandomValidation, true, config.Searcher.Metric)
 }
 
+func (m *Master) postSearcherReplay(c echo.Context) (interface{}, error) {
+	params := struct {
+		Config         json.RawMessage          `json:"config"`
+		Seed           int64                    `json:"seed"`
+		LearningCurves []searcher.LearningCurve `json:"learning_curves"`
+		ExperimentID   int                      `json:"experiment_id"`
+	}{}
+	if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
+		return nil, echo.NewHTTPError(
+			http.StatusBadRequest, errors.Wrap(err, "invalid replay params"))
+	}
+	config := model.DefaultExperimentConfig(&m.config.TaskContainerDefaults)
+	if err := json.Unmarshal(params.Config, &config); err != nil {
+		return nil, echo.NewHTTPError(
+			http.StatusBadRequest, errors.Wrap(err, "invalid experiment config"))
+	}
+	if err := check.Validate(config.Searcher); err != nil {
+		return nil, echo.NewHTTPError(
+			http.StatusBadRequest, errors.Wrap(err, "invalid experiment config"))
+	}
+	replay, err := m.replaySearch(config, params.LearningCurves, params.ExperimentID, params.Seed)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return replay, nil
+}
+
+// replaySearch replays the searcher of an experiment config against either the given learning
+// curves or the validation history of an existing experiment.
+func (m *Master) replaySearch(
+	config model.ExperimentConfig, curves []searcher.LearningCurve, experimentID int, seed int64,
+) (searcher.Replay, error) {
+	if experimentID != 0 {
+		if len(curves) != 0 {
+			return searcher.Replay{}, errors.New(
+				"either learning curves or an experiment can be replayed, not both")
+		}
+		if unit := config.Searcher.Unit(); unit != model.Batches {
+			return searcher.Replay{}, errors.Errorf(
+				"replaying an experiment requires a max_length in batches, not %s", unit)
+		}
+		var err error
+		if curves, err = m.db.ExperimentLearningCurves(experimentID); err != nil {
+			return searcher.Replay{}, err
+		}
+		if len(curves) == 0 {
+			return searcher.Replay{}, errors.Errorf(
+				"experiment %d has no completed validations to replay", experimentID)
+		}
+	}
+
+	sm := searcher.NewSearchMethod(config.Searcher)
+	s := searcher.NewSearcher(uint32(seed), sm, config.Hyperparameters)
+	return searcher.ReplayLearningCurves(
+		s, curves, seed, config.Searcher.Metric, config.Searcher.SmallerIsBetter)
+}
+
 // cleanUpExperimentSnapshots deletes all snapshots for terminal state experiments from
 // the database.
 func (m *Master) cleanUpExperimentSnapshots() {
@@ -118,6 +118,20 @@ func (db *PgDB) namedExecOne(query string, arg interface{}) error {
 	return nil
 }
 
+// execOne executes a statement that must change exactly one row, returning ErrNotFound otherwise.
+func (db *PgDB) execOne(query string, args ...interface{}) error {
+	res, err := db.sql.Exec(query, args...)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if num, err := res.RowsAffected(); err != nil {
+		return errors.WithStack(err)
+	} else if num != 1 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 func queryBinds(fields []string) []string {
 	binds := make([]string, 0, len(fields))
 	for _, field := range fields {
@@ -654,9 +668,9 @@ func (db *PgDB) AddExperiment(experiment *model.Experiment) error {
 	err := db.namedGet(&experiment.ID, `
 INSERT INTO experiments
 (state, config, model_definition, start_time, end_time, archived,
- git_remote, git_commit, git_committer, git_commit_date, owner_id)
+ git_remote, git_commit, git_committer, git_commit_date, owner_id, project_id)
 VALUES (:state, :config, :model_definition, :start_time, :end_time, :archived,
-        :git_remote, :git_commit, :git_committer, :git_commit_date, :owner_id)
+        :git_remote, :git_commit, :git_committer, :git_commit_date, :owner_id, :project_id)
 RETURNING id`, experiment)
 	if err != nil {
 		return errors.Wrapf(err, "error inserting experiment %v", *experiment)
@@ -670,7 +684,7 @@ func (db *PgDB) ExperimentByID(id int) (*model.Experiment, error) {
 
 	if err := db.query(`
 SELECT id, state, config, model_definition, start_time, end_time, archived,
-       git_remote, git_commit, git_committer, git_commit_date, owner_id
+       git_remote, git_commit, git_committer, git_commit_date, owner_id, project_id
 FROM experiments
 WHERE id = $1`, &experiment, id); err != nil {
 		return nil, err
@@ -691,7 +705,7 @@ func (db *PgDB) ExperimentWithoutBackwardsIncompatibleFieldsByID(
 SELECT id, state,
   config #- '{searcher}' #- '{min_validation_period}' #- '{min_checkpoint_period}' AS config,
   model_definition, start_time, end_time, archived,
-  git_remote, git_commit, git_committer, git_commit_date, owner_id
+  git_remote, git_commit, git_committer, git_commit_date, owner_id, project_id
 FROM experiments
 WHERE id = $1`, &experiment, id); err != nil {
 		return nil, err
@@ -708,7 +722,7 @@ func (db *PgDB) ExperimentWithoutConfigByID(id int) (*model.Experiment, error) {
 
 	if err := db.query(`
 SELECT id, state, model_definition, start_time, end_time, archived,
-       git_remote, git_commit, git_committer, git_commit_date, owner_id
+       git_remote, git_commit, git_committer, git_commit_date, owner_id, project_id
 FROM experiments
 WHERE id = $1`, &experiment, id); err != nil {
 		return nil, err
@@ -743,7 +757,7 @@ FROM experiments e, trials t  WHERE t.id = $1 AND e.id = t.experiment_id`,
 func (db *PgDB) NonTerminalExperiments() ([]*model.Experiment, error) {
 	rows, err := db.sql.Queryx(`
 SELECT id, state, config, model_definition, start_time, end_time, archived,
-       git_remote, git_commit, git_committer, git_commit_date, owner_id
+       git_remote, git_commit, git_committer, git_commit_date, owner_id, project_id
 FROM experiments
 WHERE state IN ('ACTIVE', 'PAUSED', 'STOPPING_CANCELED', 'STOPPING_COMPLETED', 'STOPPING_ERROR')`)
 	if err == sql.ErrNoRows {
@@ -848,6 +862,28 @@ WHERE id = :id`
 	return db.namedExecOne(query, experiment)
 }
 
+// ContinueExperiment saves the state, end time, and config of a completed experiment that is being
+// continued, along with the snapshot of its continued searcher.
+func (db *PgDB) ContinueExperiment(
+	experiment *model.Experiment, version int, experimentSnapshot []byte,
+) error {
+	return db.withTransaction("continue experiment", func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`
+UPDATE experiments
+SET state = $2, end_time = $3, config = $4
+WHERE id = $1`, experiment.ID, experiment.State, experiment.EndTime, experiment.Config); err != nil {
+			return errors.Wrap(err, "failed to update experiment")
+		}
+		if _, err := tx.Exec(`
+UPDATE experiment_snapshots
+SET updated_at = now(), content = $2, version = $3
+WHERE experiment_id = $1`, experiment.ID, experimentSnapshot, version); err != nil {
+			return errors.Wrap(err, "failed to update experiment snapshot")
+		}
+		return nil
+	})
+}
+
 // SaveExperimentArchiveStatus saves the current experiment archive status to the database.
 func (db *PgDB) SaveExperimentArchiveStatus(experiment *model.Experiment) error {
 	if !model.TerminalStates[experiment.State] {
@@ -1009,7 +1045,7 @@ WHERE id = $1`, id)
 
 // ExperimentCheckpointsToGCRaw returns a JSON string describing checkpoints that should be GCed
 // according to the given GC policy parameters. If the delete parameter is true, the returned
-// checkpoints are also marked as deleted in the database.
+// checkpoints are also marked as deleted in the database. External checkpoints are never GCed.
 func (db *PgDB) ExperimentCheckpointsToGCRaw(
 	id int,
 	experimentBest, trialBest, trialLatest *int,
@@ -1082,7 +1118,8 @@ WITH const AS (
                    -- removed.)
                    '[]'::jsonb AS warm_start_trials
             FROM checkpoints c, trials t, const
-            WHERE c.state = 'COMPLETED' AND c.trial_id = t.id AND t.experiment_id = $1
+            WHERE c.state = 'COMPLETED' AND NOT c.external
+                  AND c.trial_id = t.id AND t.experiment_id = $1
         ) _, const
     ) c, const
     WHERE (const.experiment_best IS NOT NULL
@@ -1719,7 +1756,8 @@ func (db *PgDB) UpdateCheckpoint(
 				trialID, stepID)
 		}
 		checkpoint.Resources = newCheckpoint.Resources
-		toUpdate = append(toUpdate, "resources")
+		checkpoint.Size = newCheckpoint.Size
+		toUpdate = append(toUpdate, "resources", "size")
 	}
 	if len(newCheckpoint.Metadata) != 0 {
 		if len(checkpoint.Metadata) == 0 {
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// APITokenPrefix starts the secrets of API tokens, which tells them apart from session tokens.
+const APITokenPrefix = "det_"
+
+const apiTokenBytes = 32
+
+func hashAPIToken(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// AddAPIToken creates an API token, sets its ID and creation time and returns its secret.
+func (db *PgDB) AddAPIToken(token *model.APIToken) (string, error) {
+	buf := make([]byte, apiTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.Wrap(err, "failed to generate API token")
+	}
+	secret := APITokenPrefix + hex.EncodeToString(buf)
+
+	if err := db.sql.QueryRow(`
+INSERT INTO api_tokens (user_id, token_hash, description, read_only, expiry)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, creation_time`,
+		token.UserID, hashAPIToken(secret), token.Description, token.ReadOnly, token.Expiry,
+	).Scan(&token.ID, &token.CreationTime); err != nil {
+		return "", errors.Wrap(err, "error creating API token")
+	}
+	return secret, nil
+}
+
+// APITokenByID looks up an API token by its ID.
+func (db *PgDB) APITokenByID(id int) (*model.APIToken, error) {
+	var token model.APIToken
+	if err := db.query(`
+SELECT id, user_id, description, read_only, expiry, creation_time, last_used
+FROM api_tokens
+WHERE id = $1`, &token, id); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// DeleteAPIToken revokes the API token with the given ID.
+func (db *PgDB) DeleteAPIToken(id int) error {
+	res, err := db.sql.Exec(`DELETE FROM api_tokens WHERE id = $1`, id)
+	if err != nil {
+		return errors.Wrapf(err, "error revoking API token %d", id)
+	}
+	if num, err := res.RowsAffected(); err != nil {
+		return errors.Wrapf(err, "error revoking API token %d", id)
+	} else if num != 1 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// userByAPIToken returns the user that an unexpired API token authenticates as, along with a
+// session that describes the token, and records that the token was used.
+func (db *PgDB) userByAPIToken(secret string) (*model.User, *model.UserSession, error) {
+	var token model.APIToken
+	if err := db.query(`
+UPDATE api_tokens SET last_used = now()
+WHERE token_hash = $1 AND (expiry IS NULL OR expiry > now())
+RETURNING id, user_id, description, read_only, expiry, creation_time, last_used`,
+		&token, hashAPIToken(secret)); errors.Cause(err) == ErrNotFound {
+		return nil, nil, ErrNotFound
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	var user model.User
+	if err := db.query(
+		`SELECT * FROM users WHERE id = $1`, &user, token.UserID,
+	); errors.Cause(err) == ErrNotFound {
+		return nil, nil, ErrNotFound
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	session := &model.UserSession{
+		UserID:     user.ID,
+		APITokenID: &token.ID,
+		ReadOnly:   token.ReadOnly,
+	}
+	if token.Expiry != nil {
+		session.Expiry = *token.Expiry
+	}
+	return &user, session, nil
+}
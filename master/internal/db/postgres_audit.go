@@ -0,0 +1,20 @@
+package db
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// AddAuditEvent records an audit event.
+func (db *PgDB) AddAuditEvent(event *model.AuditEvent) error {
+	if err := db.namedGet(&event.ID, `
+INSERT INTO audit_events
+(user_id, username, method, resource_type, resource_id, request, success, error)
+VALUES
+(:user_id, :username, :method, :resource_type, :resource_id, :request, :success, :error)
+RETURNING id`, *event); err != nil {
+		return errors.Wrapf(err, "error recording audit event for %s", event.Method)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// ErrCheckpointExists is returned when importing a checkpoint whose UUID is already registered.
+var ErrCheckpointExists = errors.New("checkpoint already exists")
+
+// ImportCheckpoint registers a checkpoint that was created elsewhere, along with the experiment,
+// trial, step and validation it belongs to, and sets their IDs. The validation may be nil.
+func (db *PgDB) ImportCheckpoint(
+	experiment *model.Experiment, trial *model.Trial, step *model.Step,
+	validation *model.Validation, checkpoint *model.Checkpoint,
+) error {
+	return db.withTransaction("import checkpoint", func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(`
+SELECT EXISTS(SELECT 1 FROM checkpoints WHERE uuid = $1)`, checkpoint.UUID,
+		).Scan(&exists); err != nil {
+			return errors.Wrap(err, "error checking for existing checkpoint")
+		}
+		if exists {
+			return ErrCheckpointExists
+		}
+
+		if err := tx.QueryRow(`
+INSERT INTO experiments
+(state, config, model_definition, start_time, end_time, archived, owner_id, project_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id`,
+			experiment.State, experiment.Config, experiment.ModelDefinitionBytes,
+			experiment.StartTime, experiment.EndTime, experiment.Archived, experiment.OwnerID,
+			experiment.ProjectID,
+		).Scan(&experiment.ID); err != nil {
+			return errors.Wrap(err, "error inserting experiment")
+		}
+
+		trial.ExperimentID = experiment.ID
+		if err := tx.QueryRow(`
+INSERT INTO trials (experiment_id, state, start_time, end_time, hparams, seed)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id`,
+			trial.ExperimentID, trial.State, trial.StartTime, trial.EndTime, trial.HParams,
+			trial.Seed,
+		).Scan(&trial.ID); err != nil {
+			return errors.Wrap(err, "error inserting trial")
+		}
+
+		step.TrialID = trial.ID
+		if _, err := tx.Exec(`
+INSERT INTO steps
+(trial_id, id, state, start_time, end_time, num_batches, prior_batches_processed)
+VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			step.TrialID, step.ID, step.State, step.StartTime, step.EndTime, step.NumBatches,
+			step.PriorBatchesProcessed,
+		); err != nil {
+			return errors.Wrap(err, "error inserting step")
+		}
+
+		if validation != nil {
+			validation.TrialID, validation.StepID = trial.ID, step.ID
+			if err := tx.QueryRow(`
+INSERT INTO validations (trial_id, step_id, state, start_time, end_time, metrics)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id`,
+				validation.TrialID, validation.StepID, validation.State, validation.StartTime,
+				validation.EndTime, validation.Metrics,
+			).Scan(&validation.ID); err != nil {
+				return errors.Wrap(err, "error inserting validation")
+			}
+			if _, err := tx.Exec(`
+UPDATE trials SET best_validation_id = $2 WHERE id = $1`, trial.ID, validation.ID,
+			); err != nil {
+				return errors.Wrap(err, "error setting best validation")
+			}
+		}
+
+		checkpoint.TrialID, checkpoint.StepID = trial.ID, step.ID
+		if err := tx.QueryRow(`
+INSERT INTO checkpoints
+(trial_id, step_id, state, start_time, end_time, uuid, resources, metadata, framework, format,
+ determined_version, size, external)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+RETURNING id`,
+			checkpoint.TrialID, checkpoint.StepID, checkpoint.State, checkpoint.StartTime,
+			checkpoint.EndTime, checkpoint.UUID, checkpoint.Resources, checkpoint.Metadata,
+			checkpoint.Framework, checkpoint.Format, checkpoint.DeterminedVersion, checkpoint.Size,
+			checkpoint.External,
+		).Scan(&checkpoint.ID); err != nil {
+			return errors.Wrap(err, "error inserting checkpoint")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"github.com/pkg/errors"
+)
+
+// CheckpointsOverQuota marks the lowest-ranked completed checkpoints of an experiment, or of all
+// the experiments of a user, as deleted until their total size is at most maxBytes. It returns the
+// deleted checkpoints by experiment, in the format that checkpoint GC takes. Checkpoints are ranked
+// within their experiment by the searcher metric of their validation, and checkpoints without one
+// rank lowest. The latest checkpoint of each trial, checkpoints that trials warm start from and
+// checkpoints registered as model versions are never deleted. External checkpoints neither count
+// toward the quota nor are deleted.
+func (db *PgDB) CheckpointsOverQuota(
+	experimentID, ownerID *int, maxBytes int64,
+) (map[int][]byte, error) {
+	rows, err := db.sql.Queryx(`
+WITH scoped AS (
+    SELECT c.id, c.uuid, c.trial_id, c.step_id, c.size, c.resources, c.framework, c.format,
+           e.id AS experiment_id,
+           (CASE
+                WHEN coalesce((e.config->'searcher'->>'smaller_is_better')::boolean, true)
+                THEN 1
+                ELSE -1
+            END) * (v.metrics->'validation_metrics'->>(e.config->'searcher'->>'metric'))::float8
+               AS signed_metric
+    FROM checkpoints c
+    JOIN trials t ON c.trial_id = t.id
+    JOIN experiments e ON t.experiment_id = e.id
+    LEFT JOIN validations v
+        ON v.trial_id = c.trial_id AND v.step_id = c.step_id AND v.state = 'COMPLETED'
+    WHERE c.state = 'COMPLETED' AND NOT c.external
+          AND ($1::int IS NULL OR e.id = $1)
+          AND ($2::int IS NULL OR e.owner_id = $2)
+), ranked AS (
+    SELECT *,
+           -- Ranks are normalized within each experiment so that the checkpoints of experiments
+           -- with different metrics can be compared; the worst checkpoint has a rank of 1.
+           percent_rank() OVER (
+               PARTITION BY experiment_id ORDER BY signed_metric ASC NULLS LAST, id ASC
+           ) AS badness,
+           rank() OVER (PARTITION BY trial_id ORDER BY step_id DESC) AS trial_order_rank
+    FROM scoped
+), candidates AS (
+    SELECT *, sum(size) OVER (ORDER BY badness DESC, id ASC) AS freed
+    FROM ranked r
+    WHERE r.trial_order_rank > 1
+          AND NOT EXISTS (SELECT 1 FROM trials t WHERE t.warm_start_checkpoint_id = r.id)
+          AND NOT EXISTS (SELECT 1 FROM model_versions mv WHERE mv.checkpoint_uuid = r.uuid)
+), selected AS (
+    SELECT *
+    FROM candidates
+    WHERE (SELECT coalesce(sum(size), 0) FROM scoped) - (freed - size) > $3
+), do_delete AS (
+    UPDATE checkpoints
+    SET state = 'DELETED'
+    FROM selected
+    WHERE checkpoints.id = selected.id
+)
+SELECT experiment_id,
+       jsonb_build_object('checkpoints', jsonb_agg(jsonb_build_object(
+           'uuid', uuid,
+           'resources', resources,
+           'framework', framework,
+           'format', format
+       ) ORDER BY id)) AS to_delete
+FROM selected
+GROUP BY experiment_id`, experimentID, ownerID, maxBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "error selecting checkpoints over quota")
+	}
+	defer rows.Close()
+
+	toDelete := map[int][]byte{}
+	for rows.Next() {
+		var id int
+		var checkpoints []byte
+		if err := rows.Scan(&id, &checkpoints); err != nil {
+			return nil, errors.Wrap(err, "error reading checkpoints over quota")
+		}
+		toDelete[id] = checkpoints
+	}
+	return toDelete, errors.Wrap(rows.Err(), "error reading checkpoints over quota")
+}
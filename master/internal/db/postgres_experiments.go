@@ -11,6 +11,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/lttb"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/protoutils"
+	"github.com/determined-ai/determined/master/pkg/searcher"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 )
 
@@ -299,6 +300,57 @@ SELECT t.id FROM (
 	return trials, err
 }
 
+// ExperimentCompletedValidations returns the completed validations of all trials of an experiment,
+// in the order that they ended.
+func (db *PgDB) ExperimentCompletedValidations(experimentID int) ([]*model.Validation, error) {
+	var validations []*model.Validation
+	if err := db.queryRows(`
+SELECT v.id, v.trial_id, v.step_id, v.state, v.start_time, v.end_time, v.metrics
+FROM validations v
+INNER JOIN trials t ON v.trial_id = t.id
+WHERE t.experiment_id = $1
+  AND v.state = 'COMPLETED'
+ORDER BY v.end_time ASC, v.id ASC`, &validations, experimentID); err != nil {
+		return nil, errors.Wrapf(err,
+			"error querying completed validations for experiment %d", experimentID)
+	}
+	return validations, nil
+}
+
+// ExperimentLearningCurves returns the validation history of each trial of an experiment that has
+// completed a validation, with the lengths of the curves in batches.
+func (db *PgDB) ExperimentLearningCurves(experimentID int) ([]searcher.LearningCurve, error) {
+	var rows []struct {
+		TrialID int           `db:"trial_id"`
+		Batches int           `db:"batches"`
+		Metrics model.JSONObj `db:"metrics"`
+	}
+	if err := db.queryRows(`
+SELECT v.trial_id, (s.prior_batches_processed + s.num_batches) AS batches,
+  v.metrics->'validation_metrics' AS metrics
+FROM validations v
+INNER JOIN steps s ON v.step_id = s.id AND v.trial_id = s.trial_id
+INNER JOIN trials t ON v.trial_id = t.id
+WHERE t.experiment_id = $1
+  AND v.state = 'COMPLETED'
+ORDER BY v.trial_id ASC, batches ASC`, &rows, experimentID); err != nil {
+		return nil, errors.Wrapf(err,
+			"error querying learning curves for experiment %d", experimentID)
+	}
+
+	var curves []searcher.LearningCurve
+	for i, row := range rows {
+		if i == 0 || row.TrialID != rows[i-1].TrialID {
+			curves = append(curves, nil)
+		}
+		curves[len(curves)-1] = append(curves[len(curves)-1], searcher.LearningCurvePoint{
+			Length:  row.Batches,
+			Metrics: row.Metrics,
+		})
+	}
+	return curves, nil
+}
+
 // TopTrialsByTrainingLength chooses the subset of trials that has been training for the highest
 // number of batches, using the specified metric as a tie breaker.
 func (db *PgDB) TopTrialsByTrainingLength(experimentID int, maxTrials int, metric string,
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// UpdateModelVersion sets the stage, notes and tags of a model version.
+func (db *PgDB) UpdateModelVersion(
+	modelName string, version int, stage model.ModelVersionStage, notes string,
+	tags model.StringList,
+) error {
+	return db.execOne(`
+UPDATE model_versions
+SET stage = $3, notes = $4, tags = $5, last_updated_time = now()
+WHERE model_name = $1 AND version = $2`, modelName, version, stage, notes, tags)
+}
+
+// DeleteModelVersion deletes a model version along with the aliases that point to it.
+func (db *PgDB) DeleteModelVersion(modelName string, version int) error {
+	return db.execOne(
+		`DELETE FROM model_versions WHERE model_name = $1 AND version = $2`, modelName, version)
+}
+
+// ModelAliasVersion returns the version that an alias of a model points to.
+func (db *PgDB) ModelAliasVersion(modelName, alias string) (int, error) {
+	var version int
+	switch err := db.sql.QueryRow(`
+SELECT version FROM model_aliases WHERE model_name = $1 AND alias = $2`, modelName, alias,
+	).Scan(&version); {
+	case err == sql.ErrNoRows:
+		return 0, ErrNotFound
+	case err != nil:
+		return 0, errors.WithStack(err)
+	}
+	return version, nil
+}
+
+// PutModelAlias points an alias of a model to a version, moving it if it already exists.
+func (db *PgDB) PutModelAlias(modelName, alias string, version int) error {
+	_, err := db.sql.Exec(`
+INSERT INTO model_aliases (model_name, alias, version) VALUES ($1, $2, $3)
+ON CONFLICT (model_name, alias) DO UPDATE SET version = EXCLUDED.version`,
+		modelName, alias, version)
+	return errors.Wrapf(err, "error setting alias %s of model %s", alias, modelName)
+}
+
+// DeleteModelAlias deletes an alias of a model.
+func (db *PgDB) DeleteModelAlias(modelName, alias string) error {
+	return db.execOne(
+		`DELETE FROM model_aliases WHERE model_name = $1 AND alias = $2`, modelName, alias)
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func (db *PgDB) ownerID(query string, arg interface{}) (*model.UserID, error) {
+	var ownerID *model.UserID
+	switch err := db.sql.Get(&ownerID, query, arg); {
+	case err == sql.ErrNoRows:
+		return nil, ErrNotFound
+	case err != nil:
+		return nil, errors.Wrap(err, "querying for owner")
+	}
+	return ownerID, nil
+}
+
+// ExperimentOwnerID returns the ID of the user that owns the experiment.
+func (db *PgDB) ExperimentOwnerID(id int) (*model.UserID, error) {
+	return db.ownerID(`SELECT owner_id FROM experiments WHERE id = $1`, id)
+}
+
+// TrialOwnerID returns the ID of the user that owns the experiment of the trial.
+func (db *PgDB) TrialOwnerID(id int) (*model.UserID, error) {
+	return db.ownerID(`
+SELECT e.owner_id FROM experiments e
+JOIN trials t ON e.id = t.experiment_id
+WHERE t.id = $1`, id)
+}
+
+// CheckpointOwnerID returns the ID of the user that owns the experiment of the checkpoint.
+func (db *PgDB) CheckpointOwnerID(uuid string) (*model.UserID, error) {
+	return db.ownerID(`
+SELECT e.owner_id FROM experiments e
+JOIN trials t ON e.id = t.experiment_id
+JOIN checkpoints c ON t.id = c.trial_id
+WHERE c.uuid = $1`, uuid)
+}
+
+// TemplateOwnerID returns the ID of the user that owns the template, if it has an owner.
+func (db *PgDB) TemplateOwnerID(name string) (*model.UserID, error) {
+	return db.ownerID(`SELECT owner_id FROM templates WHERE name = $1`, name)
+}
+
+// ProjectOwnerID returns the ID of the user that owns the project.
+func (db *PgDB) ProjectOwnerID(id int) (*model.UserID, error) {
+	return db.ownerID(`SELECT owner_id FROM projects WHERE id = $1`, id)
+}
+
+// ModelProjectID returns the ID of the project of the model, if it belongs to one.
+func (db *PgDB) ModelProjectID(name string) (*int, error) {
+	var projectID *int
+	switch err := db.sql.Get(&projectID, `SELECT project_id FROM models WHERE name = $1`, name); {
+	case err == sql.ErrNoRows:
+		return nil, ErrNotFound
+	case err != nil:
+		return nil, errors.Wrap(err, "querying for project")
+	}
+	return projectID, nil
+}
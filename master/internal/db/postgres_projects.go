@@ -0,0 +1,129 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/jackc/pgconn"
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// ErrProjectNotEmpty is returned when deleting a project that still owns experiments, models or
+// templates.
+var ErrProjectNotEmpty = errors.New("project is not empty")
+
+func projectError(err error) error {
+	if pgerr, ok := errors.Cause(err).(*pgconn.PgError); ok && pgerr.Code == uniqueViolation {
+		return ErrDuplicateRecord
+	}
+	return err
+}
+
+// AddProject creates a project, sets its ID and makes its owner its first member.
+func (db *PgDB) AddProject(project *model.Project) error {
+	return db.withTransaction("add project", func(tx *sql.Tx) error {
+		if err := tx.QueryRow(`
+INSERT INTO projects (name, description, owner_id, checkpoint_storage, resource_pool)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, creation_time`,
+			project.Name, project.Description, project.OwnerID, project.CheckpointStorage,
+			project.ResourcePool,
+		).Scan(&project.ID, &project.CreationTime); err != nil {
+			return projectError(err)
+		}
+		_, err := tx.Exec(`
+INSERT INTO project_members (project_id, user_id) VALUES ($1, $2)`, project.ID, project.OwnerID)
+		return err
+	})
+}
+
+// ProjectByID looks up a project by its ID.
+func (db *PgDB) ProjectByID(id int) (*model.Project, error) {
+	var project model.Project
+	if err := db.query(`
+SELECT id, name, description, owner_id, checkpoint_storage, resource_pool, creation_time
+FROM projects
+WHERE id = $1`, &project, id); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// UpdateProject updates the name, description and defaults of a project.
+func (db *PgDB) UpdateProject(project *model.Project) error {
+	res, err := db.sql.Exec(`
+UPDATE projects
+SET name = $2, description = $3, checkpoint_storage = $4, resource_pool = $5
+WHERE id = $1`,
+		project.ID, project.Name, project.Description, project.CheckpointStorage,
+		project.ResourcePool)
+	if err != nil {
+		return errors.Wrapf(projectError(err), "error updating project %d", project.ID)
+	}
+	if num, err := res.RowsAffected(); err != nil {
+		return errors.Wrapf(err, "error updating project %d", project.ID)
+	} else if num != 1 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteProject deletes a project that does not own any experiments, models or templates.
+func (db *PgDB) DeleteProject(id int) error {
+	return db.withTransaction("delete project", func(tx *sql.Tx) error {
+		// Lock the project so that nothing is added to it between the check and the delete.
+		var locked int
+		switch err := tx.QueryRow(
+			`SELECT id FROM projects WHERE id = $1 FOR UPDATE`, id).Scan(&locked); {
+		case err == sql.ErrNoRows:
+			return ErrNotFound
+		case err != nil:
+			return errors.Wrapf(err, "error locking project %d", id)
+		}
+
+		var empty bool
+		if err := tx.QueryRow(`
+SELECT NOT EXISTS(SELECT 1 FROM experiments WHERE project_id = $1)
+   AND NOT EXISTS(SELECT 1 FROM models WHERE project_id = $1)
+   AND NOT EXISTS(SELECT 1 FROM templates WHERE project_id = $1)`, id).Scan(&empty); err != nil {
+			return errors.Wrapf(err, "error checking the contents of project %d", id)
+		}
+		if !empty {
+			return ErrProjectNotEmpty
+		}
+		_, err := tx.Exec(`DELETE FROM projects WHERE id = $1`, id)
+		return errors.Wrapf(err, "error deleting project %d", id)
+	})
+}
+
+// AddProjectMember makes the user a member of the project.
+func (db *PgDB) AddProjectMember(projectID int, userID model.UserID) error {
+	_, err := db.sql.Exec(`
+INSERT INTO project_members (project_id, user_id) VALUES ($1, $2)
+ON CONFLICT DO NOTHING`, projectID, userID)
+	return errors.Wrapf(err, "error adding user %d to project %d", userID, projectID)
+}
+
+// RemoveProjectMember removes the user from the members of the project.
+func (db *PgDB) RemoveProjectMember(projectID int, userID model.UserID) error {
+	_, err := db.sql.Exec(`
+DELETE FROM project_members WHERE project_id = $1 AND user_id = $2`, projectID, userID)
+	return errors.Wrapf(err, "error removing user %d from project %d", userID, projectID)
+}
+
+// IsProjectMember returns whether the user is a member of the project. It returns ErrNotFound if
+// the project does not exist.
+func (db *PgDB) IsProjectMember(projectID int, userID model.UserID) (bool, error) {
+	var member bool
+	switch err := db.sql.Get(&member, `
+SELECT EXISTS(SELECT 1 FROM project_members WHERE project_id = $1 AND user_id = $2)
+FROM projects
+WHERE id = $1`, projectID, userID); {
+	case err == sql.ErrNoRows:
+		return false, ErrNotFound
+	case err != nil:
+		return false, errors.Wrapf(err, "error checking the members of project %d", projectID)
+	}
+	return member, nil
+}
@@ -83,8 +83,20 @@ WHERE experiment_id = $1`, experimentID); err != nil {
 	}
 }
 
+// DeleteTrialSnapshotsForExperiment deletes the trial snapshots for the given experiment, keeping
+// the experiment snapshot so that the search can be continued.
+func (db *PgDB) DeleteTrialSnapshotsForExperiment(experimentID int) error {
+	if _, err := db.sql.Exec(`
+DELETE FROM trial_snapshots
+WHERE experiment_id = $1`, experimentID); err != nil {
+		return errors.Wrap(err, "failed to delete trial snapshots")
+	}
+	return nil
+}
+
 // DeleteSnapshotsForTerminalExperiments deletes all snapshots for
-// terminal state experiments from the database.
+// terminal state experiments from the database. The experiment snapshots of
+// completed experiments are kept so that their searches can be continued.
 func (db *PgDB) DeleteSnapshotsForTerminalExperiments() error {
 	return db.withTransaction("delete snapshots", func(tx *sql.Tx) error {
 		if _, err := tx.Exec(`
@@ -92,7 +104,7 @@ DELETE FROM experiment_snapshots
 WHERE experiment_id IN (
 	SELECT id
 	FROM experiments
-	WHERE state IN ('COMPLETED', 'CANCELED', 'ERROR'))`); err != nil {
+	WHERE state IN ('CANCELED', 'ERROR'))`); err != nil {
 			return errors.Wrap(err, "failed to delete experiment snapshots")
 		}
 		if _, err := tx.Exec(`
@@ -24,8 +24,8 @@ func (db *PgDB) UpsertTemplate(tpl *model.Template) error {
 		return errors.New("error setting a template: empty name")
 	}
 	err := db.namedExecOne(`
-INSERT INTO templates (name, config)
-VALUES (:name, :config)
+INSERT INTO templates (name, config, owner_id)
+VALUES (:name, :config, :owner_id)
 ON CONFLICT (name)
 DO
 UPDATE SET config=:config`, tpl)
@@ -38,8 +38,13 @@ func (db *PgDB) StartUserSession(user *model.User) (string, error) {
 	return token, nil
 }
 
-// UserByToken returns a user session given an authentication token.
+// UserByToken returns a user session given an authentication token, which is either a session
+// token or the secret of an API token.
 func (db *PgDB) UserByToken(token string) (*model.User, *model.UserSession, error) {
+	if strings.HasPrefix(token, APITokenPrefix) {
+		return db.userByAPIToken(token)
+	}
+
 	v2 := paseto.NewV2()
 
 	var session model.UserSession
@@ -92,10 +97,13 @@ func (db *PgDB) UserByUsername(username string) (*model.User, error) {
 }
 
 func addUser(tx *sqlx.Tx, user *model.User) (model.UserID, error) {
+	if user.Role == "" {
+		user.SetRole(model.RoleFromAdmin(user.Admin))
+	}
 	stmt, err := tx.PrepareNamed(`
 INSERT INTO users
-(username, admin, active)
-VALUES (:username, :admin, :active)
+(username, admin, active, role, login_provider)
+VALUES (:username, :admin, :active, :role, :login_provider)
 RETURNING id`)
 	if err != nil {
 		return 0, errors.WithStack(err)
@@ -259,7 +267,7 @@ func (db *PgDB) UserByID(userID model.UserID) (*model.FullUser, error) {
 	var fu model.FullUser
 	if err := db.query(`
 SELECT
-	u.id, u.username, u.admin, u.active,
+	u.id, u.username, u.admin, u.active, u.role,
 	h.uid AS agent_uid, h.gid AS agent_gid, h.user_ AS agent_user, h.group_ AS agent_group
 FROM users u
 LEFT OUTER JOIN agent_user_groups h ON (u.id = h.user_id)
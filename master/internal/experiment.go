@@ -91,6 +91,9 @@ type (
 
 		agentUserGroup *model.AgentUserGroup
 		taskSpec       *tasks.TaskSpec
+		// owner is the username of the owner of the experiment, which resource pool quotas apply to.
+		owner            string
+		checkpointQuotas CheckpointQuotaConfig
 
 		faultToleranceEnabled bool
 		restored              bool
@@ -151,6 +154,11 @@ func newExperiment(master *Master, expModel *model.Experiment) (*experiment, err
 		agentUserGroup = &master.config.Security.DefaultTask
 	}
 
+	owner, err := master.db.UserByID(*expModel.OwnerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve the owner of the experiment")
+	}
+
 	return &experiment{
 		Experiment:          expModel,
 		modelDefinition:     modelDefinition,
@@ -163,6 +171,9 @@ func newExperiment(master *Master, expModel *model.Experiment) (*experiment, err
 
 		agentUserGroup: agentUserGroup,
 		taskSpec:       master.taskSpec,
+		owner:          owner.Username,
+
+		checkpointQuotas: master.config.CheckpointQuotas,
 
 		faultToleranceEnabled: true,
 	}, nil
@@ -200,6 +211,9 @@ func (e *experiment) Receive(ctx *actor.Context) error {
 		e.processOperations(ctx, ops, err)
 	case trialCompletedWorkload:
 		e.searcher.WorkloadCompleted(msg.requestID, msg.unitsCompleted)
+		if msg.completedMessage.CheckpointMetrics != nil {
+			e.enforceCheckpointQuotas(ctx)
+		}
 		for _, op := range msg.completedOps {
 			ops, err := e.searcher.OperationCompleted(msg.trialID, op.op, op.metrics)
 			e.processOperations(ctx, ops, err)
@@ -213,6 +227,8 @@ func (e *experiment) Receive(ctx *actor.Context) error {
 			ctx.Log().WithError(err).Error("failed to save experiment progress")
 		}
 		ctx.Tell(e.hpImportance, hpimportance.ExperimentProgress{ID: e.ID, Progress: progress})
+	case trialSnapshot:
+		// Only the snapshot is saved, which snapshotAndSave does.
 	case trialValidation:
 		if msg.validationMetrics != nil {
 			ctx.Respond(e.isBestValidation(*msg.validationMetrics))
@@ -250,6 +266,8 @@ func (e *experiment) Receive(ctx *actor.Context) error {
 		e.Config.Resources.Weight = msg.Weight
 		msg.Handler = ctx.Self()
 		ctx.Tell(e.rm, msg)
+	case model.CheckpointStorageConfig:
+		e.Config.CheckpointStorage = msg
 
 	case killExperiment:
 		if _, running := model.RunningStates[e.State]; running {
@@ -289,7 +307,13 @@ func (e *experiment) Receive(ctx *actor.Context) error {
 
 		ctx.Tell(e.hpImportance, hpimportance.ExperimentCompleted{ID: e.ID})
 
-		if err := e.db.DeleteSnapshotsForExperiment(e.Experiment.ID); err != nil {
+		// The searcher snapshot of a completed experiment is kept so the search can be continued.
+		if e.State == model.CompletedState {
+			if err := e.db.DeleteTrialSnapshotsForExperiment(e.Experiment.ID); err != nil {
+				ctx.Log().WithError(err).Errorf(
+					"failure to delete trial snapshots for experiment: %d", e.Experiment.ID)
+			}
+		} else if err := e.db.DeleteSnapshotsForExperiment(e.Experiment.ID); err != nil {
 			ctx.Log().WithError(err).Errorf(
 				"failure to delete snapshots for experiment: %d", e.Experiment.ID)
 		}
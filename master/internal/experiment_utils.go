@@ -42,11 +42,13 @@ func checkpointFromTrialIDOrUUID(
 }
 
 // checkpointFromCheckpointMetrics converts a workload.CheckpointMetrics into a model.Checkpoint
-// with the UUID, and Resources fields filled out.
+// with the UUID, Resources and Size fields filled out.
 func checkpointFromCheckpointMetrics(metrics workload.CheckpointMetrics) model.Checkpoint {
 	resources := model.JSONObj{}
+	var size int64
 	for key, value := range metrics.Resources {
 		resources[key] = value
+		size += int64(value)
 	}
 
 	id := metrics.UUID.String()
@@ -55,6 +57,7 @@ func checkpointFromCheckpointMetrics(metrics workload.CheckpointMetrics) model.C
 		Resources: resources,
 		Framework: metrics.Framework,
 		Format:    metrics.Format,
+		Size:      size,
 	}
 }
 
@@ -18,6 +18,7 @@ import (
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/status"
 
+	"github.com/determined-ai/determined/master/internal/authz"
 	"github.com/determined-ai/determined/master/internal/db"
 	proto "github.com/determined-ai/determined/proto/pkg/apiv1"
 )
@@ -25,7 +26,9 @@ import (
 const jsonPretty = "application/json+pretty"
 
 // NewGRPCServer creates a Determined gRPC service.
-func NewGRPCServer(db *db.PgDB, srv proto.DeterminedServer) *grpc.Server {
+func NewGRPCServer(
+	db *db.PgDB, a *authz.Authorizer, srv proto.DeterminedServer,
+) *grpc.Server {
 	logger := logrus.NewEntry(logrus.StandardLogger())
 	opts := []grpclogrus.Option{
 		grpclogrus.WithLevels(grpcCodeToLogrusLevel),
@@ -35,7 +38,7 @@ func NewGRPCServer(db *db.PgDB, srv proto.DeterminedServer) *grpc.Server {
 		grpc.StreamInterceptor(grpcmiddleware.ChainStreamServer(
 			grpclogrus.StreamServerInterceptor(logger, opts...),
 			grpcrecovery.StreamServerInterceptor(),
-			streamAuthInterceptor(db),
+			streamAuthInterceptor(db, a),
 		)),
 		grpc.UnaryInterceptor(grpcmiddleware.ChainUnaryServer(
 			grpclogrus.UnaryServerInterceptor(logger, opts...),
@@ -45,7 +48,8 @@ func NewGRPCServer(db *db.PgDB, srv proto.DeterminedServer) *grpc.Server {
 					return status.Errorf(codes.Internal, "%s", p)
 				},
 			)),
-			unaryAuthInterceptor(db),
+			unaryAuditInterceptor(db),
+			unaryAuthInterceptor(db, a),
 		)),
 	)
 	proto.RegisterDeterminedServer(grpcS, srv)
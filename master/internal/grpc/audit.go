@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/determined-ai/determined/master/internal/audit"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// isReadMethod returns whether an API method only reads from the cluster.
+func isReadMethod(method string) bool {
+	return strings.HasPrefix(method, "Get") || readMethods[method]
+}
+
+// auditEvent describes a call to an API method that changes the cluster.
+func auditEvent(
+	user *model.User, fullMethod string, req interface{}, err error,
+) *model.AuditEvent {
+	method := strings.TrimPrefix(fullMethod, apiPrefix)
+	event := &model.AuditEvent{
+		Method:       method,
+		ResourceType: audit.ResourceFromMethod(method),
+		Success:      err == nil,
+	}
+	if user != nil {
+		event.UserID = &user.ID
+		event.Username = user.Username
+	}
+	if err != nil {
+		event.Error = status.Convert(err).Message()
+		if event.Error == "" {
+			event.Error = status.Code(err).String()
+		}
+	}
+	if msg, ok := req.(protoreflect.ProtoMessage); ok {
+		var request map[string]interface{}
+		if raw, mErr := protojson.Marshal(msg); mErr == nil && json.Unmarshal(raw, &request) == nil {
+			event.Request = audit.Summarize(request).(map[string]interface{})
+			event.ResourceID = audit.ResourceID(event.ResourceType, request)
+		}
+	}
+	return event
+}
+
+// unaryAuditInterceptor records who called the API methods that change the cluster and how the
+// calls turned out, including the calls that were not authenticated or not allowed.
+func unaryAuditInterceptor(db *db.PgDB) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		method := strings.TrimPrefix(info.FullMethod, apiPrefix)
+		if isReadMethod(method) || unauthenticatedMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		resp, err := handler(ctx, req)
+		user, _, _ := GetUser(ctx, db)
+		audit.Record(db, auditEvent(user, info.FullMethod, req, err))
+		return resp, err
+	}
+}
@@ -0,0 +1,45 @@
+package grpc
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestAuditEvent(t *testing.T) {
+	user := &model.User{ID: 3, Username: "alice"}
+
+	event := auditEvent(
+		user, apiPrefix+"ArchiveExperiment", &apiv1.ArchiveExperimentRequest{Id: 9}, nil)
+	assert.Equal(t, *event.UserID, model.UserID(3))
+	assert.Equal(t, event.Username, "alice")
+	assert.Equal(t, event.Method, "ArchiveExperiment")
+	assert.Equal(t, event.ResourceType, "experiment")
+	assert.Equal(t, event.ResourceID, "9")
+	assert.Equal(t, event.Success, true)
+
+	event = auditEvent(nil, apiPrefix+"DisableAgent",
+		&apiv1.DisableAgentRequest{AgentId: "agent-1"}, status.Error(codes.PermissionDenied, "denied"))
+	assert.Assert(t, event.UserID == nil)
+	assert.Equal(t, event.ResourceType, "agent")
+	assert.Equal(t, event.ResourceID, "agent-1")
+	assert.Equal(t, event.Success, false)
+	assert.Equal(t, event.Error, "denied")
+
+	event = auditEvent(user, apiPrefix+"SetUserPassword",
+		&apiv1.SetUserPasswordRequest{Username: "alice", Password: "hunter2"}, nil)
+	assert.Equal(t, event.Request["password"], "<redacted>")
+}
+
+func TestIsReadMethod(t *testing.T) {
+	assert.Assert(t, isReadMethod("GetExperiments"))
+	assert.Assert(t, isReadMethod("TrialLogs"))
+	// Logging out ends a session, so it is audited.
+	assert.Assert(t, !isReadMethod("Logout"))
+	assert.Assert(t, !isReadMethod("SetUserPassword"))
+}
@@ -14,6 +14,7 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
+	"github.com/determined-ai/determined/master/internal/authz"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
@@ -74,23 +75,31 @@ func GetUser(ctx context.Context, d *db.PgDB) (*model.User, *model.UserSession,
 	}
 }
 
-func streamAuthInterceptor(db *db.PgDB) grpc.StreamServerInterceptor {
+func streamAuthInterceptor(db *db.PgDB, a *authz.Authorizer) grpc.StreamServerInterceptor {
 	return func(
 		srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler,
 	) error {
-		if _, _, err := GetUser(ss.Context(), db); err != nil {
+		user, session, err := GetUser(ss.Context(), db)
+		if err != nil {
+			return err
+		}
+		if err := authorize(a, *user, *session, info.FullMethod, nil); err != nil {
 			return err
 		}
 		return handler(srv, ss)
 	}
 }
 
-func unaryAuthInterceptor(db *db.PgDB) grpc.UnaryServerInterceptor {
+func unaryAuthInterceptor(db *db.PgDB, a *authz.Authorizer) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
 	) (resp interface{}, err error) {
 		if !unauthenticatedMethods[info.FullMethod] {
-			if _, _, err := GetUser(ctx, db); err != nil {
+			user, session, err := GetUser(ctx, db)
+			if err != nil {
+				return nil, err
+			}
+			if err := authorize(a, *user, *session, info.FullMethod, req); err != nil {
 				return nil, err
 			}
 		}
@@ -0,0 +1,180 @@
+package grpc
+
+import (
+	"strings"
+
+	"github.com/determined-ai/determined/master/internal/authz"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+const apiPrefix = "/determined.api.v1.Determined/"
+
+// permission checks whether a user may call an API method with the given request.
+type permission func(a *authz.Authorizer, user model.User, req interface{}) error
+
+func requireRole(role model.Role) permission {
+	return func(_ *authz.Authorizer, user model.User, _ interface{}) error {
+		return authz.RequireRole(user, role)
+	}
+}
+
+// experimentID is implemented by the requests that act on an experiment by its ID.
+type experimentID interface{ GetId() int32 }
+
+func modifyExperiment(a *authz.Authorizer, user model.User, req interface{}) error {
+	return a.CanModifyExperiment(user, int(req.(experimentID).GetId()))
+}
+
+// projectID is implemented by the requests that act on a project by its ID.
+type projectID interface{ GetProjectId() int32 }
+
+func modifyProject(a *authz.Authorizer, user model.User, req interface{}) error {
+	return a.CanModifyProject(user, int(req.(projectID).GetProjectId()))
+}
+
+// useProject checks that the user can create objects in the project with the given ID, where 0
+// means no project.
+func useProject(a *authz.Authorizer, user model.User, id int32) error {
+	if id == 0 {
+		return a.CanUseProject(user, nil)
+	}
+	project := int(id)
+	return a.CanUseProject(user, &project)
+}
+
+func modifyCommand(kind string, id func(req interface{}) string) permission {
+	return func(a *authz.Authorizer, user model.User, req interface{}) error {
+		return a.CanModifyCommand(user, actor.Addr(kind, id(req)))
+	}
+}
+
+// readMethods are the methods that do not start with "Get" but only read from the cluster.
+var readMethods = map[string]bool{
+	"CurrentUser":      true,
+	"MasterLogs":       true,
+	"PreviewHPSearch":  true,
+	"ReplayHPSearch":   true,
+	"TrialLogs":        true,
+	"TrialLogsFields":  true,
+	"NotebookLogs":     true,
+	"MetricNames":      true,
+	"MetricBatches":    true,
+	"TrialsSnapshot":   true,
+	"TrialsSample":     true,
+	"ExportCheckpoint": true,
+}
+
+// methodPermissions holds what users need to call the methods that change the cluster. Methods
+// that are neither listed here nor read methods can only be called by admins.
+var methodPermissions = map[string]permission{
+	"SetUserPassword": requireRole(model.RoleViewer),
+	"Logout":          requireRole(model.RoleViewer),
+	"PostAPIToken":    requireRole(model.RoleViewer),
+	"DeleteAPIToken":  requireRole(model.RoleViewer),
+	"CreateExperiment": func(a *authz.Authorizer, user model.User, req interface{}) error {
+		return useProject(a, user, req.(*apiv1.CreateExperimentRequest).ProjectId)
+	},
+	"ActivateExperiment":     modifyExperiment,
+	"PauseExperiment":        modifyExperiment,
+	"CancelExperiment":       modifyExperiment,
+	"KillExperiment":         modifyExperiment,
+	"ContinueExperiment":     modifyExperiment,
+	"ArchiveExperiment":      modifyExperiment,
+	"UnarchiveExperiment":    modifyExperiment,
+	"SetCheckpointRetention": modifyExperiment,
+	"ComputeHPImportance":    requireRole(model.RoleEditor),
+	"LaunchNotebook":         requireRole(model.RoleEditor),
+	"LaunchShell":            requireRole(model.RoleEditor),
+	"LaunchCommand":          requireRole(model.RoleEditor),
+	"LaunchTensorboard":      requireRole(model.RoleEditor),
+	"PostModel": func(a *authz.Authorizer, user model.User, req interface{}) error {
+		return useProject(a, user, req.(*apiv1.PostModelRequest).GetModel().GetProjectId())
+	},
+	"PatchModel": func(a *authz.Authorizer, user model.User, req interface{}) error {
+		return a.CanModifyModel(user, req.(*apiv1.PatchModelRequest).GetModel().GetName())
+	},
+	"PostModelVersion": func(a *authz.Authorizer, user model.User, req interface{}) error {
+		return a.CanModifyModel(user, req.(*apiv1.PostModelVersionRequest).ModelName)
+	},
+	"PatchModelVersion": func(a *authz.Authorizer, user model.User, req interface{}) error {
+		return a.CanModifyModel(user, req.(*apiv1.PatchModelVersionRequest).ModelName)
+	},
+	"DeleteModelVersion": func(a *authz.Authorizer, user model.User, req interface{}) error {
+		return a.CanModifyModel(user, req.(*apiv1.DeleteModelVersionRequest).ModelName)
+	},
+	"PutModelAlias": func(a *authz.Authorizer, user model.User, req interface{}) error {
+		return a.CanModifyModel(user, req.(*apiv1.PutModelAliasRequest).ModelName)
+	},
+	"DeleteModelAlias": func(a *authz.Authorizer, user model.User, req interface{}) error {
+		return a.CanModifyModel(user, req.(*apiv1.DeleteModelAliasRequest).ModelName)
+	},
+	"PostProject":         requireRole(model.RoleEditor),
+	"DeleteProject":       modifyProject,
+	"PostProjectMember":   modifyProject,
+	"DeleteProjectMember": modifyProject,
+	"PatchProject": func(a *authz.Authorizer, user model.User, req interface{}) error {
+		return a.CanModifyProject(user, int(req.(*apiv1.PatchProjectRequest).GetProject().GetId()))
+	},
+	"PatchExperiment": func(a *authz.Authorizer, user model.User, req interface{}) error {
+		return a.CanModifyExperiment(
+			user, int(req.(*apiv1.PatchExperimentRequest).GetExperiment().GetId()))
+	},
+	"KillTrial": func(a *authz.Authorizer, user model.User, req interface{}) error {
+		return a.CanModifyTrial(user, int(req.(*apiv1.KillTrialRequest).Id))
+	},
+	"PostCheckpointMetadata": func(a *authz.Authorizer, user model.User, req interface{}) error {
+		return a.CanModifyCheckpoint(
+			user, req.(*apiv1.PostCheckpointMetadataRequest).GetCheckpoint().GetUuid())
+	},
+	"ImportCheckpoint": func(a *authz.Authorizer, user model.User, req interface{}) error {
+		return useProject(a, user, req.(*apiv1.ImportCheckpointRequest).ProjectId)
+	},
+	"PutTemplate": func(a *authz.Authorizer, user model.User, req interface{}) error {
+		template := req.(*apiv1.PutTemplateRequest).GetTemplate()
+		if err := a.CanModifyTemplate(user, template.GetName()); err != nil {
+			return err
+		}
+		return useProject(a, user, template.GetProjectId())
+	},
+	"DeleteTemplate": func(a *authz.Authorizer, user model.User, req interface{}) error {
+		return a.CanModifyTemplate(user, req.(*apiv1.DeleteTemplateRequest).TemplateName)
+	},
+	"KillNotebook": modifyCommand("notebooks", func(req interface{}) string {
+		return req.(*apiv1.KillNotebookRequest).NotebookId
+	}),
+	"KillShell": modifyCommand("shells", func(req interface{}) string {
+		return req.(*apiv1.KillShellRequest).ShellId
+	}),
+	"KillCommand": modifyCommand("commands", func(req interface{}) string {
+		return req.(*apiv1.KillCommandRequest).CommandId
+	}),
+	"KillTensorboard": modifyCommand("tensorboard", func(req interface{}) string {
+		return req.(*apiv1.KillTensorboardRequest).TensorboardId
+	}),
+}
+
+// authorize checks that the user may call the API method with the given request; streaming
+// methods are checked without their request. Read-only API tokens may only call read methods.
+func authorize(
+	a *authz.Authorizer, user model.User, session model.UserSession, fullMethod string,
+	req interface{},
+) error {
+	method := strings.TrimPrefix(fullMethod, apiPrefix)
+	var err error
+	switch check, ok := methodPermissions[method]; {
+	case isReadMethod(method):
+		err = authz.RequireRole(user, model.RoleViewer)
+	case session.ReadOnly:
+		err = authz.ErrPermissionDenied
+	case ok && req != nil:
+		err = check(a, user, req)
+	default:
+		err = authz.RequireRole(user, model.RoleAdmin)
+	}
+	if err == authz.ErrPermissionDenied {
+		return ErrPermissionDenied
+	}
+	return err
+}
@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestAuthorize(t *testing.T) {
+	user := func(role model.Role) model.User {
+		u := model.User{ID: 1}
+		u.SetRole(role)
+		return u
+	}
+	viewer, editor, admin := user(model.RoleViewer), user(model.RoleEditor), user(model.RoleAdmin)
+
+	cases := []struct {
+		user    model.User
+		method  string
+		req     interface{}
+		allowed bool
+	}{
+		{viewer, "GetExperiments", &apiv1.GetExperimentsRequest{}, true},
+		{viewer, "TrialLogs", nil, true},
+		{viewer, "Logout", &apiv1.LogoutRequest{}, true},
+		{viewer, "CreateExperiment", &apiv1.CreateExperimentRequest{}, false},
+		{editor, "CreateExperiment", &apiv1.CreateExperimentRequest{}, true},
+		{editor, "LaunchNotebook", &apiv1.LaunchNotebookRequest{}, true},
+		{editor, "PostUser", &apiv1.PostUserRequest{}, false},
+		{editor, "EnableAgent", &apiv1.EnableAgentRequest{}, false},
+		{admin, "EnableAgent", &apiv1.EnableAgentRequest{}, true},
+		{editor, "UnknownMethod", nil, false},
+		{viewer, "GetProjects", &apiv1.GetProjectsRequest{}, true},
+		{viewer, "PostProject", &apiv1.PostProjectRequest{}, false},
+		{editor, "PostProject", &apiv1.PostProjectRequest{}, true},
+		{editor, "PostModel", &apiv1.PostModelRequest{}, true},
+		{viewer, "ExportCheckpoint", &apiv1.ExportCheckpointRequest{}, true},
+		{viewer, "ImportCheckpoint", &apiv1.ImportCheckpointRequest{}, false},
+		{editor, "ImportCheckpoint", &apiv1.ImportCheckpointRequest{}, true},
+	}
+	for _, c := range cases {
+		err := authorize(nil, c.user, model.UserSession{}, apiPrefix+c.method, c.req)
+		if c.allowed {
+			assert.NilError(t, err, "%s calling %s", c.user.Role, c.method)
+		} else {
+			assert.Equal(t, err, ErrPermissionDenied, "%s calling %s", c.user.Role, c.method)
+		}
+	}
+}
+
+func TestAuthorizeReadOnlyToken(t *testing.T) {
+	admin := model.User{ID: 1}
+	admin.SetRole(model.RoleAdmin)
+	session := model.UserSession{UserID: admin.ID, ReadOnly: true}
+
+	cases := []struct {
+		method  string
+		req     interface{}
+		allowed bool
+	}{
+		{"GetExperiments", &apiv1.GetExperimentsRequest{}, true},
+		{"TrialLogs", nil, true},
+		{"CreateExperiment", &apiv1.CreateExperimentRequest{}, false},
+		{"SetUserPassword", &apiv1.SetUserPasswordRequest{}, false},
+		{"PostAPIToken", &apiv1.PostAPITokenRequest{}, false},
+		{"Logout", &apiv1.LogoutRequest{}, false},
+		{"EnableAgent", &apiv1.EnableAgentRequest{}, false},
+		{"GetModelAlias", &apiv1.GetModelAliasRequest{}, true},
+		{"PutModelAlias", &apiv1.PutModelAliasRequest{}, false},
+		{"DeleteModelVersion", &apiv1.DeleteModelVersionRequest{}, false},
+		{"SetCheckpointRetention", &apiv1.SetCheckpointRetentionRequest{}, false},
+	}
+	for _, c := range cases {
+		err := authorize(nil, admin, session, apiPrefix+c.method, c.req)
+		if c.allowed {
+			assert.NilError(t, err, "read-only token calling %s", c.method)
+		} else {
+			assert.Equal(t, err, ErrPermissionDenied, "read-only token calling %s", c.method)
+		}
+	}
+}
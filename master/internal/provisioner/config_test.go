@@ -11,6 +11,7 @@ import (
 	"gotest.tools/assert"
 
 	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 func TestProvisionerConfigMissingFields(t *testing.T) {
@@ -20,8 +21,8 @@ func TestProvisionerConfigMissingFields(t *testing.T) {
 	err = check.Validate(&config)
 	assert.ErrorContains(t, err, "must configure aws or gcp cluster")
 	expected := Config{
-		MaxIdleAgentPeriod:     Duration(20 * time.Minute),
-		MaxAgentStartingPeriod: Duration(20 * time.Minute),
+		MaxIdleAgentPeriod:     model.Duration(20 * time.Minute),
+		MaxAgentStartingPeriod: model.Duration(20 * time.Minute),
 		MaxInstances:           5,
 		AgentDockerRuntime:     "runc",
 		AgentDockerNetwork:     "default",
@@ -60,8 +61,8 @@ func TestUnmarshalProvisionerConfigMasterURL(t *testing.T) {
 		AgentDockerRuntime:     "runc",
 		AgentDockerNetwork:     "default",
 		AWS:                    &awsConfig,
-		MaxIdleAgentPeriod:     Duration(30 * time.Second),
-		MaxAgentStartingPeriod: Duration(30 * time.Second),
+		MaxIdleAgentPeriod:     model.Duration(30 * time.Second),
+		MaxAgentStartingPeriod: model.Duration(30 * time.Second),
 		MaxInstances:           5,
 	}
 	assert.DeepEqual(t, config, unmarshaled)
@@ -111,8 +112,8 @@ func TestUnmarshalProvisionerConfigWithAWS(t *testing.T) {
 		AgentFluentImage:       "fluent/fluent-bit:1.6",
 		AgentDockerRuntime:     "runc",
 		AgentDockerNetwork:     "default",
-		MaxIdleAgentPeriod:     Duration(30 * time.Second),
-		MaxAgentStartingPeriod: Duration(30 * time.Second),
+		MaxIdleAgentPeriod:     model.Duration(30 * time.Second),
+		MaxAgentStartingPeriod: model.Duration(30 * time.Second),
 		MaxInstances:           5,
 	}
 	assert.DeepEqual(t, config, unmarshaled)
@@ -145,8 +146,8 @@ func TestUnmarshalProvisionerConfigWithGCP(t *testing.T) {
 		AgentFluentImage:       "fluent/fluent-bit:1.6",
 		AgentDockerRuntime:     "runc",
 		AgentDockerNetwork:     "default",
-		MaxIdleAgentPeriod:     Duration(20 * time.Minute),
-		MaxAgentStartingPeriod: Duration(20 * time.Minute),
+		MaxIdleAgentPeriod:     model.Duration(20 * time.Minute),
+		MaxAgentStartingPeriod: model.Duration(20 * time.Minute),
 		MaxInstances:           5,
 	}
 	assert.DeepEqual(t, config, unmarshaled)
@@ -205,8 +206,8 @@ boot_disk_source_image: test-source_image3
 		AgentFluentImage:       "fluent/fluent-bit:1.6",
 		AgentDockerRuntime:     "runc",
 		AgentDockerNetwork:     "default",
-		MaxIdleAgentPeriod:     Duration(20 * time.Minute),
-		MaxAgentStartingPeriod: Duration(20 * time.Minute),
+		MaxIdleAgentPeriod:     model.Duration(20 * time.Minute),
+		MaxAgentStartingPeriod: model.Duration(20 * time.Minute),
 		MaxInstances:           5,
 	}
 	assert.DeepEqual(t, expected, unmarshaled)
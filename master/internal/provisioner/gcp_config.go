@@ -12,6 +12,7 @@ import (
 
 	"github.com/determined-ai/determined/master/pkg"
 	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 // MaxNamePrefixLen is the max length of the instance name prefix. The full name of an instance
@@ -41,7 +42,7 @@ type GCPClusterConfig struct {
 
 	InstanceType gceInstanceType `json:"instance_type"`
 
-	OperationTimeoutPeriod Duration `json:"operation_timeout_period"`
+	OperationTimeoutPeriod model.Duration `json:"operation_timeout_period"`
 }
 
 // DefaultGCPClusterConfig returns the default configuration of the gcp cluster.
@@ -55,7 +56,7 @@ func DefaultGCPClusterConfig() *GCPClusterConfig {
 			GPUType:     "nvidia-tesla-v100",
 			GPUNum:      4,
 		},
-		OperationTimeoutPeriod: Duration(5 * time.Minute),
+		OperationTimeoutPeriod: model.Duration(5 * time.Minute),
 	}
 }
 
@@ -9,6 +9,7 @@ import (
 
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 type TestInstanceType struct {
@@ -198,7 +199,7 @@ func TestProvisionerScaleDown(t *testing.T) {
 			Slots: 4,
 		},
 		Config: &Config{
-			MaxIdleAgentPeriod: Duration(50 * time.Millisecond),
+			MaxIdleAgentPeriod: model.Duration(50 * time.Millisecond),
 			MaxInstances:       100,
 		},
 		initInstances: []*Instance{
@@ -248,8 +249,8 @@ func TestProvisionerNotProvisionExtraInstances(t *testing.T) {
 			Slots: 4,
 		},
 		Config: &Config{
-			MaxAgentStartingPeriod: Duration(100 * time.Millisecond),
-			MaxIdleAgentPeriod:     Duration(100 * time.Millisecond),
+			MaxAgentStartingPeriod: model.Duration(100 * time.Millisecond),
+			MaxIdleAgentPeriod:     model.Duration(100 * time.Millisecond),
 			MaxInstances:           100,
 		},
 		initInstances: []*Instance{
@@ -306,8 +307,8 @@ func TestProvisionerTerminateDisconnectedInstances(t *testing.T) {
 			Slots: 4,
 		},
 		Config: &Config{
-			MaxAgentStartingPeriod: Duration(3 * time.Minute),
-			MaxIdleAgentPeriod:     Duration(50 * time.Millisecond),
+			MaxAgentStartingPeriod: model.Duration(3 * time.Minute),
+			MaxIdleAgentPeriod:     model.Duration(50 * time.Millisecond),
 			MaxInstances:           100,
 		},
 		initInstances: []*Instance{
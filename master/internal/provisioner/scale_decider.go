@@ -40,6 +40,7 @@ type scaleDecider struct {
 	pending          map[string]bool
 	recentlyLaunched map[string]bool
 	stopped          map[string]bool
+	draining         map[string]bool
 	disconnected     map[string]time.Time
 	idle             map[string]time.Time
 	longDisconnected map[string]bool
@@ -65,6 +66,7 @@ func newScaleDecider(
 		pending:                make(map[string]bool),
 		recentlyLaunched:       make(map[string]bool),
 		stopped:                make(map[string]bool),
+		draining:               make(map[string]bool),
 		disconnected:           make(map[string]time.Time),
 		idle:                   make(map[string]time.Time),
 		longDisconnected:       make(map[string]bool),
@@ -114,6 +116,7 @@ func (s *scaleDecider) calculateInstanceStates() {
 	s.pending = make(map[string]bool)
 	s.recentlyLaunched = make(map[string]bool)
 	s.stopped = make(map[string]bool)
+	s.draining = make(map[string]bool)
 	s.disconnected = make(map[string]time.Time)
 	s.idle = make(map[string]time.Time)
 	s.longDisconnected = make(map[string]bool)
@@ -129,7 +132,10 @@ func (s *scaleDecider) calculateInstanceStates() {
 			s.instances[inst.ID] = inst
 
 			// Connected agent instances.
-			if _, connected := s.connectedAgentSnapshot[inst.AgentName]; connected {
+			if agent, connected := s.connectedAgentSnapshot[inst.AgentName]; connected {
+				if agent.IsDraining {
+					s.draining[inst.ID] = true
+				}
 				if _, ok := s.idleAgentSnapshot[inst.AgentName]; ok {
 					// Connected idle agent instances.
 					if t, ok := pastIdle[inst.ID]; ok {
@@ -180,8 +186,12 @@ func (s *scaleDecider) findInstancesToTerminate() sproto.TerminateDecision {
 		delete(s.disconnected, id)
 	}
 
-	// Terminate instances that are idle for a long time.
+	// Terminate instances that are idle for a long time, drained ones first.
+	longIdle := make([]string, 0, len(s.longIdle))
 	for id := range s.longIdle {
+		longIdle = append(longIdle, id)
+	}
+	for _, id := range s.drainingFirst(longIdle) {
 		if len(s.instances)-len(toTerminate) > s.minInstanceNum {
 			toTerminate[id] = sproto.TerminateLongIdleInstances
 			delete(s.idle, id)
@@ -191,8 +201,20 @@ func (s *scaleDecider) findInstancesToTerminate() sproto.TerminateDecision {
 	}
 
 	// Terminate instances to keep the number of instances less than than the desired size.
-	// We start by terminating unfulfilled spot requests, then idle instances, then
-	// disconnected instances, then the most recently provisioned instances
+	// We start by terminating drained instances, then unfulfilled spot requests, then idle
+	// instances, then disconnected instances, then draining instances, then the most recently
+	// provisioned instances
+	for id := range s.idle {
+		if !s.draining[id] {
+			continue
+		}
+		if len(s.instances)-len(toTerminate) > s.maxInstanceNum {
+			toTerminate[id] = sproto.InstanceNumberExceedsMaximum
+			delete(s.idle, id)
+		} else {
+			break
+		}
+	}
 	for id := range s.pending {
 		if len(s.instances)-len(toTerminate) > s.maxInstanceNum {
 			toTerminate[id] = sproto.InstanceNumberExceedsMaximum
@@ -222,6 +244,9 @@ func (s *scaleDecider) findInstancesToTerminate() sproto.TerminateDecision {
 		instances = append(instances, inst)
 	}
 	sort.Slice(instances, func(i, j int) bool {
+		if s.draining[instances[i].ID] != s.draining[instances[j].ID] {
+			return s.draining[instances[i].ID]
+		}
 		return instances[i].LaunchTime.After(instances[j].LaunchTime)
 	})
 	for i := 0; i < len(instances) && len(instances)-len(toTerminate) > s.maxInstanceNum; i++ {
@@ -237,13 +262,26 @@ func (s *scaleDecider) findInstancesToTerminate() sproto.TerminateDecision {
 	return res
 }
 
+// calculateNumInstancesToLaunch returns the number of instances to launch. Instances whose agents
+// are draining do not take tasks, so they do not count toward the minimum number of instances.
 func (s *scaleDecider) calculateNumInstancesToLaunch() int {
 	desiredNum := s.desiredNewInstances - len(s.recentlyLaunched)
 	desiredNum = min(desiredNum, s.maxInstanceNum-len(s.instances))
-	desiredNum = max(desiredNum, s.minInstanceNum-len(s.instances))
+	desiredNum = max(desiredNum, s.minInstanceNum-(len(s.instances)-len(s.draining)))
 	return max(0, desiredNum)
 }
 
+// drainingFirst sorts instance IDs so that the instances whose agents are draining come first.
+func (s *scaleDecider) drainingFirst(ids []string) []string {
+	sort.Slice(ids, func(i, j int) bool {
+		if s.draining[ids[i]] != s.draining[ids[j]] {
+			return s.draining[ids[i]]
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
@@ -41,6 +41,7 @@ func TestCalculateInstanceStates(t *testing.T) {
 		longDisconnected map[string]bool
 		longIdle         map[string]bool
 		stopped          map[string]bool
+		draining         map[string]bool
 		recentlyLaunched map[string]bool
 	}
 	var tcs = []testcase{
@@ -112,6 +113,12 @@ func TestCalculateInstanceStates(t *testing.T) {
 						AgentName:  "occupied",
 						State:      Running,
 					},
+					"draining": {
+						ID:         "draining",
+						LaunchTime: time.Now().Add(-time.Hour),
+						AgentName:  "draining",
+						State:      Running,
+					},
 				},
 				connectedAgentSnapshot: map[string]sproto.AgentSummary{
 					"past disconnected": {Name: "past disconnected"},
@@ -119,6 +126,7 @@ func TestCalculateInstanceStates(t *testing.T) {
 					"new idle":          {Name: "new idle", IsIdle: true},
 					"long idle":         {Name: "long idle", IsIdle: true},
 					"occupied":          {Name: "occupied", IsIdle: true},
+					"draining":          {Name: "draining", IsDraining: true},
 				},
 				idleAgentSnapshot: map[string]sproto.AgentSummary{
 					"new idle":  {Name: "new idle", IsIdle: true},
@@ -155,6 +163,9 @@ func TestCalculateInstanceStates(t *testing.T) {
 			stopped: map[string]bool{
 				"stopped": true,
 			},
+			draining: map[string]bool{
+				"draining": true,
+			},
 			recentlyLaunched: map[string]bool{
 				"unconnected starting": true,
 				"unconnected running":  true,
@@ -170,6 +181,7 @@ func TestCalculateInstanceStates(t *testing.T) {
 			assert.DeepEqual(t, tc.scaleDecider.longDisconnected, tc.longDisconnected)
 			assert.DeepEqual(t, tc.scaleDecider.longIdle, tc.longIdle)
 			assert.DeepEqual(t, tc.scaleDecider.stopped, tc.stopped)
+			assert.DeepEqual(t, tc.scaleDecider.draining, tc.draining)
 			assert.DeepEqual(t, tc.scaleDecider.recentlyLaunched, tc.recentlyLaunched)
 		})
 	}
@@ -290,6 +302,50 @@ func TestFindInstancesToTerminate(t *testing.T) {
 				"long disconnected",
 			},
 		},
+		{
+			name: "terminate drained instances first when above the maximum",
+			scaleDecider: scaleDecider{
+				instances: map[string]*Instance{
+					"drained":  {ID: "drained", LaunchTime: time.Now().Add(-time.Hour)},
+					"idle":     {ID: "idle", LaunchTime: time.Now().Add(-time.Hour)},
+					"occupied": {ID: "occupied", LaunchTime: time.Now().Add(-time.Hour)},
+				},
+				idle: map[string]time.Time{
+					"drained": time.Now(),
+					"idle":    time.Now(),
+				},
+				draining:       map[string]bool{"drained": true},
+				maxInstanceNum: 2,
+			},
+			toTerminate: []string{"drained"},
+		},
+		{
+			name: "terminate long idle drained instances first",
+			scaleDecider: scaleDecider{
+				instances: map[string]*Instance{
+					"drained":   {},
+					"long idle": {},
+					"occupied":  {},
+				},
+				longIdle:       map[string]bool{"drained": true, "long idle": true},
+				draining:       map[string]bool{"drained": true},
+				maxInstanceNum: 10,
+				minInstanceNum: 2,
+			},
+			toTerminate: []string{"drained"},
+		},
+		{
+			name: "terminate draining instances before more recent ones",
+			scaleDecider: scaleDecider{
+				instances: map[string]*Instance{
+					"draining":    {ID: "draining", LaunchTime: time.Now().Add(-time.Hour)},
+					"most recent": {ID: "most recent", LaunchTime: time.Now()},
+				},
+				draining:       map[string]bool{"draining": true},
+				maxInstanceNum: 1,
+			},
+			toTerminate: []string{"draining"},
+		},
 	}
 	for idx := range tcs {
 		tc := tcs[idx]
@@ -387,6 +443,20 @@ func TestCalculateNumInstancesToLaunch(t *testing.T) {
 			},
 			numToLaunch: 0,
 		},
+		{
+			name: "replace draining instances to keep above min instance num",
+			scaleDecider: scaleDecider{
+				maxStartingPeriod: time.Minute,
+				minInstanceNum:    2,
+				maxInstanceNum:    10,
+				instances: map[string]*Instance{
+					"draining": {ID: "draining", State: Running},
+					"running":  {ID: "running", State: Running},
+				},
+				draining: map[string]bool{"draining": true},
+			},
+			numToLaunch: 1,
+		},
 	}
 
 	for idx := range tcs {
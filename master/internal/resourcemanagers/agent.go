@@ -14,23 +14,41 @@ type agentState struct {
 	handler *actor.Ref
 	devices map[device.Device]*cproto.ID
 	label   string
+	// draining is set while the agent is being drained; no tasks are scheduled on it meanwhile.
+	draining bool
+	// quarantined is set while the agent is quarantined after repeated task failures; no tasks
+	// are scheduled on it meanwhile, but its running tasks are left alone.
+	quarantined bool
 
 	// Since we only model GPUs as devices/slots and assume each slot can be allocated with
 	// one container, we add one additional field to keep track of zero-slot containers.
 	// We need this field to know if the agent is idle.
 	zeroSlotContainers    map[cproto.ID]bool
 	maxZeroSlotContainers int
+
+	// reattachable holds the containers that the agent ran for a previous master until a restored
+	// task reattaches to them or they exit.
+	reattachable map[cproto.ID]cproto.Container
 }
 
 // newAgentState returns a new agent empty agent state backed by the handler.
 func newAgentState(msg sproto.AddAgent, maxZeroSlotContainers int) *agentState {
-	return &agentState{
+	state := &agentState{
 		handler:               msg.Agent,
 		label:                 msg.Label,
 		devices:               make(map[device.Device]*cproto.ID),
 		zeroSlotContainers:    make(map[cproto.ID]bool),
 		maxZeroSlotContainers: maxZeroSlotContainers,
+		reattachable:          make(map[cproto.ID]cproto.Container),
+	}
+	for _, c := range msg.Containers {
+		state.reattachable[c.ID] = c
+		// The devices of the other containers are added as they are in use by the slots.
+		if len(c.Devices) == 0 {
+			state.zeroSlotContainers[c.ID] = true
+		}
 	}
+	return state
 }
 
 func (a *agentState) numSlots() int {
@@ -66,21 +84,16 @@ func (a *agentState) allocateFreeDevices(slots int, id cproto.ID) []device.Devic
 		return nil
 	}
 	cid := id
-	devices := make([]device.Device, 0, slots)
-	for d, dcid := range a.devices {
-		if dcid == nil {
-			a.devices[d] = &cid
-			devices = append(devices, d)
-		}
-		if len(devices) == slots {
-			break
-		}
-	}
+	devices, _ := a.localFreeDevices(slots)
 	check.Panic(check.True(len(devices) == slots, "not enough devices"))
+	for _, d := range devices {
+		a.devices[d] = &cid
+	}
 	return devices
 }
 
 func (a *agentState) deallocateDevice(t device.Type, id cproto.ID, d device.Device) {
+	delete(a.reattachable, id)
 	if t == device.ZeroSlot {
 		delete(a.zeroSlotContainers, id)
 		return
@@ -91,10 +104,30 @@ func (a *agentState) deallocateDevice(t device.Type, id cproto.ID, d device.Devi
 	a.devices[d] = nil
 }
 
+// reattachableDevices returns the devices of the container that the agent ran for a previous
+// master, once the agent has added all of them, and whether it did.
+func (a *agentState) reattachableDevices(id cproto.ID) ([]device.Device, bool) {
+	c, ok := a.reattachable[id]
+	switch {
+	case !ok:
+		return nil, false
+	case len(c.Devices) == 0:
+		return nil, a.zeroSlotContainers[id]
+	}
+	for _, d := range c.Devices {
+		if cid := a.devices[d]; cid == nil || *cid != id {
+			return nil, false
+		}
+	}
+	return c.Devices, true
+}
+
 func (a *agentState) deepCopy() *agentState {
 	copiedAgent := &agentState{
 		handler:               a.handler,
 		label:                 a.label,
+		draining:              a.draining,
+		quarantined:           a.quarantined,
 		devices:               make(map[device.Device]*cproto.ID),
 		zeroSlotContainers:    make(map[cproto.ID]bool),
 		maxZeroSlotContainers: a.maxZeroSlotContainers,
@@ -102,10 +135,11 @@ func (a *agentState) deepCopy() *agentState {
 
 	for originalDevice, id := range a.devices {
 		copiedDevice := device.Device{
-			ID:    originalDevice.ID,
-			Brand: originalDevice.Brand,
-			UUID:  originalDevice.UUID,
-			Type:  originalDevice.Type,
+			ID:       originalDevice.ID,
+			Brand:    originalDevice.Brand,
+			UUID:     originalDevice.UUID,
+			Type:     originalDevice.Type,
+			Topology: originalDevice.Topology,
 		}
 		copiedAgent.devices[copiedDevice] = id
 	}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/actor"
@@ -79,6 +81,14 @@ func (a *agentResourceManager) Receive(ctx *actor.Context) error {
 	case sproto.GetDefaultCPUResourcePoolRequest:
 		ctx.Respond(sproto.GetDefaultCPUResourcePoolResponse{PoolName: a.config.DefaultCPUResourcePool})
 
+	case *apiv1.GetResourcePoolQueueRequest:
+		if a.pools[msg.ResourcePool] == nil {
+			ctx.Respond(status.Errorf(codes.NotFound,
+				"cannot find resource pool %s", msg.ResourcePool))
+			return nil
+		}
+		a.forwardToPool(ctx, msg.ResourcePool, msg)
+
 	case *apiv1.GetResourcePoolsRequest:
 		summaries := make([]*resourcepoolv1.ResourcePool, 0, len(a.poolsConfig))
 		for _, pool := range a.poolsConfig {
@@ -121,6 +131,8 @@ func (a *agentResourceManager) createResourcePool(
 		MakeScheduler(config.Scheduler),
 		MakeFitFunction(config.Scheduler.FittingPolicy),
 	)
+	// Restored tasks wait for their containers as long as agents keep them after a restart.
+	rp.reattachWait = a.config.agentReconnectWait()
 	ref, ok := ctx.ActorOf(config.PoolName, rp)
 	if !ok {
 		ctx.Log().Errorf("cannot create resource pool actor: %s", config.PoolName)
@@ -252,6 +264,9 @@ func (a *agentResourceManager) createResourcePoolSummary(
 	if pool.Scheduler.RoundRobin != nil {
 		schedulerType = resourcepoolv1.SchedulerType_SCHEDULER_TYPE_ROUND_ROBIN
 	}
+	if pool.Scheduler.Backfill != nil {
+		schedulerType = resourcepoolv1.SchedulerType_SCHEDULER_TYPE_BACKFILL
+	}
 
 	resp := &resourcepoolv1.ResourcePool{
 		Name:                         pool.PoolName,
@@ -289,8 +304,11 @@ func (a *agentResourceManager) createResourcePoolSummary(
 		if pool.Scheduler.FittingPolicy == worst {
 			resp.SchedulerFittingPolicy = resourcepoolv1.FittingPolicy_FITTING_POLICY_WORST
 		}
+		if pool.Scheduler.FittingPolicy == topology {
+			resp.SchedulerFittingPolicy = resourcepoolv1.FittingPolicy_FITTING_POLICY_TOPOLOGY
+		}
 
-		if pool.Scheduler.FittingPolicy != best && pool.Scheduler.FittingPolicy != worst {
+		if resp.SchedulerFittingPolicy == resourcepoolv1.FittingPolicy_FITTING_POLICY_UNSPECIFIED {
 			ctx.Log().Errorf("unrecognized scheduler fitting policy")
 			return &resourcepoolv1.ResourcePool{}, err
 		}
@@ -361,6 +379,8 @@ func (a *agentResourceManager) createResourcePoolSummary(
 	resp.SlotsUsed = int32(resourceSummary.numActiveSlots)
 	resp.CpuContainerCapacity = int32(resourceSummary.maxNumCPUContainers)
 	resp.CpuContainersRunning = int32(resourceSummary.numActiveCPUContainers)
+	resp.UserSlotQuotas, resp.DefaultUserSlotQuota, resp.LabelSlotQuotas = quotaSummaries(
+		pool.Quotas, resourceSummary.userSlots, resourceSummary.labelSlots)
 
 	return resp, nil
 }
@@ -0,0 +1,265 @@
+package resourcemanagers
+
+import (
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	cproto "github.com/determined-ai/determined/master/pkg/container"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+type backfillScheduler struct {
+	// started records when the scheduler first saw each task holding resources, which is used to
+	// estimate when running tasks will release them.
+	started map[*actor.Ref]time.Time
+	now     func() time.Time
+}
+
+// NewBackfillScheduler creates a new scheduler that schedules tasks in the order they were
+// submitted. When the oldest pending task does not fit, it is given a reservation at the time that
+// enough resources are expected to be free for it, based on the expected runtimes of the running
+// tasks. Later tasks are only started in the meantime if doing so does not delay that reservation.
+func NewBackfillScheduler() Scheduler {
+	return &backfillScheduler{
+		started: make(map[*actor.Ref]time.Time),
+		now:     time.Now,
+	}
+}
+
+// backfillUsage is the set of devices that a task holds and the time at which it is expected to
+// release them; end is nil if the task does not have an expected runtime.
+type backfillUsage struct {
+	end     *time.Time
+	devices map[*actor.Ref][]device.Device
+}
+
+// release frees the devices of the usage on the given agents.
+func (u backfillUsage) release(agents map[*actor.Ref]*agentState) {
+	for handler, devices := range u.devices {
+		agent, ok := agents[handler]
+		if !ok {
+			continue
+		}
+		for _, allocated := range devices {
+			for d, id := range agent.devices {
+				if d.ID == allocated.ID && id != nil {
+					agent.devices[d] = nil
+				}
+			}
+		}
+	}
+}
+
+// backfillReservation is the time at which the first blocked task is expected to fit; end is nil
+// if that time cannot be estimated because some of the tasks it waits on have no expected runtime.
+type backfillReservation struct {
+	req *sproto.AllocateRequest
+	end *time.Time
+}
+
+func (b *backfillScheduler) Schedule(rp *ResourcePool) ([]*sproto.AllocateRequest, []*actor.Ref) {
+	return b.backfillSchedule(
+		rp.quotas.filter(rp.taskList, nil), rp.agents, rp.fittingMethod), nil
+}
+
+func (b *backfillScheduler) backfillSchedule(
+	taskList *taskList,
+	agents map[*actor.Ref]*agentState,
+	fittingMethod SoftConstraint,
+) []*sproto.AllocateRequest {
+	now := b.now()
+	b.updateStarted(taskList, now)
+
+	toAllocate := make([]*sproto.AllocateRequest, 0)
+	// Since labels are a hard scheduling constraint, process every label independently.
+	for label, agentsWithLabel := range splitAgentsByLabel(agents) {
+		toAllocate = append(toAllocate,
+			b.backfillScheduleByLabel(taskList, agentsWithLabel, fittingMethod, label, now)...)
+	}
+	return toAllocate
+}
+
+// updateStarted records the start time of newly allocated tasks and forgets tasks that no longer
+// hold resources.
+func (b *backfillScheduler) updateStarted(taskList *taskList, now time.Time) {
+	allocated := make(map[*actor.Ref]bool)
+	for it := taskList.iterator(); it.next(); {
+		req := it.value()
+		if assigned := taskList.GetAllocations(req.TaskActor); assigned == nil {
+			continue
+		}
+		allocated[req.TaskActor] = true
+		if _, ok := b.started[req.TaskActor]; !ok {
+			b.started[req.TaskActor] = now
+		}
+	}
+	for handler := range b.started {
+		if !allocated[handler] {
+			delete(b.started, handler)
+		}
+	}
+}
+
+func (b *backfillScheduler) backfillScheduleByLabel(
+	taskList *taskList,
+	agents map[*actor.Ref]*agentState,
+	fittingMethod SoftConstraint,
+	label string,
+	now time.Time,
+) []*sproto.AllocateRequest {
+	toAllocate := make([]*sproto.AllocateRequest, 0)
+
+	// Make a local copy of the agent state that we will modify.
+	localAgentsState := deepCopyAgents(agents)
+
+	var usages []backfillUsage
+	var pending []*sproto.AllocateRequest
+	for it := taskList.iterator(); it.next(); {
+		req := it.value()
+		if req.Label != label {
+			continue
+		}
+		assigned := taskList.GetAllocations(req.TaskActor)
+		switch {
+		case assigned == nil || len(assigned.Allocations) == 0:
+			pending = append(pending, req)
+		case req.SlotsNeeded > 0:
+			usages = append(usages,
+				allocatedUsage(assigned, expectedEnd(b.started[req.TaskActor], req, now)))
+		}
+	}
+
+	var reservation *backfillReservation
+	for _, req := range pending {
+		// Zero-slot tasks do not use any slots that could be reserved, so they are started
+		// whenever they fit.
+		if req.SlotsNeeded == 0 {
+			if fits := findFits(req, localAgentsState, fittingMethod); len(fits) != 0 {
+				addTaskToAgents(fits)
+				toAllocate = append(toAllocate, req)
+			}
+			continue
+		}
+
+		candidateAgentsState := deepCopyAgents(localAgentsState)
+		fits := findFits(req, candidateAgentsState, fittingMethod)
+		if len(fits) == 0 {
+			if reservation == nil {
+				reservation = reserve(req, localAgentsState, usages, fittingMethod)
+			}
+			continue
+		}
+		usage := newUsage(fits, expectedEnd(now, req, now))
+
+		if reservation != nil &&
+			!reservation.allows(usage, candidateAgentsState, usages, fittingMethod) {
+			continue
+		}
+
+		if reservation != nil {
+			log.Debugf("backfilled task %s ahead of task %s", req.Name, reservation.req.Name)
+		}
+		localAgentsState = candidateAgentsState
+		usages = append(usages, usage)
+		toAllocate = append(toAllocate, req)
+	}
+
+	return toAllocate
+}
+
+// expectedEnd returns when a task that started at the given time is expected to finish, or nil if
+// it has no expected runtime. Tasks that have overrun their expected runtime are expected to
+// finish now.
+func expectedEnd(start time.Time, req *sproto.AllocateRequest, now time.Time) *time.Time {
+	if req.ExpectedRuntime <= 0 {
+		return nil
+	}
+	end := start.Add(req.ExpectedRuntime)
+	if end.Before(now) {
+		end = now
+	}
+	return &end
+}
+
+// allocatedUsage returns the devices that have been allocated to a running task.
+func allocatedUsage(assigned *sproto.ResourcesAllocated, end *time.Time) backfillUsage {
+	usage := backfillUsage{end: end, devices: make(map[*actor.Ref][]device.Device)}
+	for _, allocation := range assigned.Allocations {
+		allocation, ok := allocation.(*containerAllocation)
+		if !ok {
+			continue
+		}
+		handler := allocation.agent.handler
+		usage.devices[handler] = append(usage.devices[handler], allocation.devices...)
+	}
+	return usage
+}
+
+// newUsage allocates devices for a task on the agents of its fits.
+func newUsage(fits []*fittingState, end *time.Time) backfillUsage {
+	usage := backfillUsage{end: end, devices: make(map[*actor.Ref][]device.Device)}
+	for _, fit := range fits {
+		usage.devices[fit.Agent.handler] = fit.Agent.allocateFreeDevices(
+			fit.Slots, cproto.NewID())
+	}
+	return usage
+}
+
+// reserve finds the earliest time at which the task is expected to fit by releasing the running
+// tasks in the order in which they are expected to finish. If it only fits once the tasks with no
+// expected runtime finish as well, the reservation has no end. If it does not fit even on the idle
+// agents, there is nothing to reserve.
+func reserve(
+	req *sproto.AllocateRequest,
+	agents map[*actor.Ref]*agentState,
+	usages []backfillUsage,
+	fittingMethod SoftConstraint,
+) *backfillReservation {
+	sorted := append([]backfillUsage(nil), usages...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		switch first, second := sorted[i].end, sorted[j].end; {
+		case first == nil:
+			return false
+		case second == nil:
+			return true
+		default:
+			return first.Before(*second)
+		}
+	})
+
+	shadowAgentsState := deepCopyAgents(agents)
+	for _, usage := range sorted {
+		usage.release(shadowAgentsState)
+		if len(findFits(req, shadowAgentsState, fittingMethod)) == 0 {
+			continue
+		}
+		log.Debugf("reserved resources for task %s", req.Name)
+		return &backfillReservation{req: req, end: usage.end}
+	}
+	return nil
+}
+
+// allows returns whether a task with the given usage can start without delaying the reservation:
+// either it is expected to finish before the reservation, or the reserved task still fits at the
+// time of the reservation with the task running.
+func (r *backfillReservation) allows(
+	usage backfillUsage,
+	agents map[*actor.Ref]*agentState,
+	usages []backfillUsage,
+	fittingMethod SoftConstraint,
+) bool {
+	if r.end != nil && usage.end != nil && !usage.end.After(*r.end) {
+		return true
+	}
+	shadowAgentsState := deepCopyAgents(agents)
+	for _, running := range usages {
+		if r.end == nil || (running.end != nil && !running.end.After(*r.end)) {
+			running.release(shadowAgentsState)
+		}
+	}
+	return len(findFits(r.req, shadowAgentsState, fittingMethod)) != 0
+}
@@ -0,0 +1,165 @@
+package resourcemanagers
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/actor"
+)
+
+func newTestBackfillScheduler() *backfillScheduler {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	scheduler := NewBackfillScheduler().(*backfillScheduler)
+	scheduler.now = func() time.Time { return now }
+	return scheduler
+}
+
+func TestBackfillSchedulerFIFO(t *testing.T) {
+	agents := []*mockAgent{
+		{id: "agent1", slots: 4, maxZeroSlotContainers: 100},
+	}
+	tasks := []*mockTask{
+		{id: "task1", slotsNeeded: 2},
+		{id: "task2", slotsNeeded: 1},
+		{id: "task3", slotsNeeded: 0},
+		{id: "task4", slotsNeeded: 1},
+		{id: "task5", slotsNeeded: 1},
+	}
+	expectedToAllocate := []*mockTask{tasks[0], tasks[1], tasks[2], tasks[3]}
+
+	system := actor.NewSystem(t.Name())
+	taskList, _, agentMap := setupSchedulerStates(t, system, tasks, nil, agents)
+	toAllocate := newTestBackfillScheduler().backfillSchedule(taskList, agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+}
+
+func TestBackfillSchedulerShortTasks(t *testing.T) {
+	agents := []*mockAgent{
+		{id: "agent1", slots: 4, maxZeroSlotContainers: 100},
+		{id: "agent2", slots: 4, maxZeroSlotContainers: 100},
+	}
+	tasks := []*mockTask{
+		{
+			id: "running1", slotsNeeded: 4, expectedRuntime: time.Hour,
+			allocatedAgent: agents[0], containerStarted: true,
+		},
+		{
+			id: "running2", slotsNeeded: 2, expectedRuntime: 2 * time.Hour,
+			allocatedAgent: agents[1], containerStarted: true,
+		},
+		{id: "distributed", slotsNeeded: 8, expectedRuntime: time.Hour},
+		{id: "short", slotsNeeded: 1, expectedRuntime: time.Hour},
+		{id: "long", slotsNeeded: 1, expectedRuntime: 3 * time.Hour},
+		{id: "unknown", slotsNeeded: 1},
+		{id: "zero-slot", slotsNeeded: 0},
+	}
+	expectedToAllocate := []*mockTask{tasks[3], tasks[6]}
+
+	system := actor.NewSystem(t.Name())
+	taskList, _, agentMap := setupSchedulerStates(t, system, tasks, nil, agents)
+	toAllocate := newTestBackfillScheduler().backfillSchedule(taskList, agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+}
+
+func TestBackfillSchedulerOutsideReservation(t *testing.T) {
+	agents := []*mockAgent{
+		{id: "agent1", slots: 4, maxZeroSlotContainers: 100},
+		{id: "agent2", slots: 4, maxZeroSlotContainers: 100},
+		{id: "agent3", slots: 4, maxZeroSlotContainers: 100},
+	}
+	tasks := []*mockTask{
+		{
+			id: "running1", slotsNeeded: 4, expectedRuntime: time.Hour,
+			allocatedAgent: agents[0], containerStarted: true,
+		},
+		{
+			id: "running2", slotsNeeded: 2,
+			allocatedAgent: agents[1], containerStarted: true,
+		},
+		{id: "distributed", slotsNeeded: 8},
+		// The reservation is on agent1 and agent3, so this task can only run on agent2.
+		{id: "unknown", slotsNeeded: 2},
+		{id: "after-unknown", slotsNeeded: 2},
+	}
+	expectedToAllocate := []*mockTask{tasks[3]}
+
+	system := actor.NewSystem(t.Name())
+	taskList, _, agentMap := setupSchedulerStates(t, system, tasks, nil, agents)
+	toAllocate := newTestBackfillScheduler().backfillSchedule(taskList, agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+}
+
+func TestBackfillSchedulerNoExpectedRuntimes(t *testing.T) {
+	agents := []*mockAgent{
+		{id: "agent1", slots: 4, maxZeroSlotContainers: 100},
+		{id: "agent2", slots: 4, maxZeroSlotContainers: 100},
+	}
+	tasks := []*mockTask{
+		{
+			id: "running1", slotsNeeded: 2,
+			allocatedAgent: agents[0], containerStarted: true,
+		},
+		{id: "distributed", slotsNeeded: 8},
+		// Without expected runtimes, the whole cluster is reserved for the distributed task.
+		{id: "small", slotsNeeded: 1, expectedRuntime: time.Minute},
+	}
+	expectedToAllocate := []*mockTask{}
+
+	system := actor.NewSystem(t.Name())
+	taskList, _, agentMap := setupSchedulerStates(t, system, tasks, nil, agents)
+	toAllocate := newTestBackfillScheduler().backfillSchedule(taskList, agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+}
+
+func TestBackfillSchedulerTooLarge(t *testing.T) {
+	agents := []*mockAgent{
+		{id: "agent1", slots: 4, maxZeroSlotContainers: 100},
+	}
+	tasks := []*mockTask{
+		{id: "too-large", slotsNeeded: 8},
+		{id: "task1", slotsNeeded: 2},
+	}
+	expectedToAllocate := []*mockTask{tasks[1]}
+
+	system := actor.NewSystem(t.Name())
+	taskList, _, agentMap := setupSchedulerStates(t, system, tasks, nil, agents)
+	toAllocate := newTestBackfillScheduler().backfillSchedule(taskList, agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+}
+
+func TestBackfillSchedulerStartTimes(t *testing.T) {
+	agents := []*mockAgent{
+		{id: "agent1", slots: 4, maxZeroSlotContainers: 100},
+		{id: "agent2", slots: 4, maxZeroSlotContainers: 100},
+	}
+	tasks := []*mockTask{
+		{
+			id: "running1", slotsNeeded: 4, expectedRuntime: time.Hour,
+			allocatedAgent: agents[0], containerStarted: true,
+		},
+		{
+			id: "running2", slotsNeeded: 4, expectedRuntime: 2 * time.Hour,
+			allocatedAgent: agents[1], containerStarted: true,
+		},
+	}
+
+	system := actor.NewSystem(t.Name())
+	taskList, _, agentMap := setupSchedulerStates(t, system, tasks, nil, agents)
+	scheduler := newTestBackfillScheduler()
+	start := scheduler.now()
+	toAllocate := scheduler.backfillSchedule(taskList, agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, nil)
+	assert.Equal(t, len(scheduler.started), 2)
+
+	// Start times are kept across passes and dropped once tasks release their resources.
+	scheduler.now = func() time.Time { return start.Add(time.Minute) }
+	running1 := system.Get(actor.Addr("running1"))
+	taskList.RemoveTaskByHandler(running1)
+	scheduler.backfillSchedule(taskList, agentMap, BestFit)
+	assert.Equal(t, len(scheduler.started), 1)
+	for _, started := range scheduler.started {
+		assert.Equal(t, started, start)
+	}
+}
@@ -9,13 +9,16 @@ import (
 	"github.com/determined-ai/determined/master/pkg/check"
 )
 
-type fairShare struct{}
+type fairShare struct {
+	preemption preemptionPolicy
+}
 
 // NewFairShareScheduler creates a new scheduler that schedules tasks according to the max-min
 // fairness of groups. For groups that are above their fair share, the scheduler requests
 // them to terminate their idle tasks until they have achieved their fair share.
-func NewFairShareScheduler() Scheduler {
-	return &fairShare{}
+func NewFairShareScheduler(config *SchedulerConfig) Scheduler {
+	return &fairShare{preemption: newPreemptionPolicy(
+		config.FairShare.MinRunTime, config.FairShare.PreemptionCooldown)}
 }
 
 type groupState struct {
@@ -48,7 +51,8 @@ func (g groupState) String() string {
 }
 
 func (f *fairShare) Schedule(rp *ResourcePool) ([]*sproto.AllocateRequest, []*actor.Ref) {
-	return fairshareSchedule(rp.taskList, rp.groups, rp.agents, rp.fittingMethod)
+	taskList := f.preemption.apply(rp, rp.quotas.filter(rp.taskList, nil))
+	return fairshareSchedule(taskList, rp.groups, rp.agents, rp.fittingMethod)
 }
 
 func fairshareSchedule(
@@ -98,6 +102,9 @@ func capacityByAgentLabel(agents map[*actor.Ref]*agentState) map[string]int {
 	agentCap := map[string]int{}
 
 	for _, agent := range agents {
+		if agent.draining || agent.quarantined {
+			continue
+		}
 		agentCap[agent.label] += agent.numSlots()
 	}
 
@@ -103,7 +103,9 @@ func findDedicatedAgentFits(
 	// 2) Multi-agent tasks will receive all the slots on every agent they are scheduled on.
 	agentsByNumSlots := make(map[int][]*agentState)
 	for _, agent := range agentStates {
-		constraints := []HardConstraint{labelSatisfied, agentSlotUnusedSatisfied}
+		constraints := []HardConstraint{
+			labelSatisfied, agentSlotUnusedSatisfied, notDrainingSatisfied, notQuarantinedSatisfied,
+		}
 		if isViable(req, agent, constraints...) {
 			agentsByNumSlots[agent.numEmptySlots()] = append(agentsByNumSlots[agent.numEmptySlots()], agent)
 		}
@@ -170,7 +172,8 @@ func findSharedAgentFit(
 ) *fittingState {
 	var candidates candidateList
 	for _, agent := range agents {
-		if !isViable(req, agent, slotsSatisfied, maxZeroSlotContainersSatisfied, labelSatisfied) {
+		if !isViable(req, agent, slotsSatisfied, maxZeroSlotContainersSatisfied, labelSatisfied,
+			notDrainingSatisfied, notQuarantinedSatisfied) {
 			continue
 		}
 
@@ -26,6 +26,14 @@ func maxZeroSlotContainersSatisfied(req *sproto.AllocateRequest, agent *agentSta
 	return true
 }
 
+func notDrainingSatisfied(_ *sproto.AllocateRequest, agent *agentState) bool {
+	return !agent.draining
+}
+
+func notQuarantinedSatisfied(_ *sproto.AllocateRequest, agent *agentState) bool {
+	return !agent.quarantined
+}
+
 func agentSlotUnusedSatisfied(_ *sproto.AllocateRequest, agent *agentState) bool {
 	return agent.numUsedSlots() == 0
 }
@@ -62,6 +70,19 @@ func WorstFit(req *sproto.AllocateRequest, agent *agentState) float64 {
 	}
 }
 
+// TopologyFit returns a float affinity score between 0 and 1 for the affinity between the task and
+// the agent. This method prefers agents on which the task can use devices in a single NVLink
+// island, then agents on which it can use devices in a single NUMA node, and otherwise falls back
+// to BestFit. This method should be used when the cluster runs multi-GPU tasks whose devices
+// communicate heavily with each other.
+func TopologyFit(req *sproto.AllocateRequest, agent *agentState) float64 {
+	level := topologyNVLinkIsland
+	if req.SlotsNeeded > 1 && req.SlotsNeeded <= agent.numEmptySlots() {
+		_, level = agent.localFreeDevices(req.SlotsNeeded)
+	}
+	return (float64(level) + BestFit(req, agent)) / float64(topologyNVLinkIsland+1)
+}
+
 // MakeFitFunction returns the corresponding fitting function.
 func MakeFitFunction(fittingPolicy string) func(*sproto.AllocateRequest, *agentState) float64 {
 	switch fittingPolicy {
@@ -69,6 +90,8 @@ func MakeFitFunction(fittingPolicy string) func(*sproto.AllocateRequest, *agentS
 		return WorstFit
 	case best:
 		return BestFit
+	case topology:
+		return TopologyFit
 	default:
 		panic(fmt.Sprintf("invalid scheduler fit: %s", fittingPolicy))
 	}
@@ -2,6 +2,8 @@ package resourcemanagers
 
 import (
 	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/actor"
@@ -83,6 +85,11 @@ func (k *kubernetesResourceManager) Receive(ctx *actor.Context) error {
 		reschedule = false
 		ctx.Respond(getTaskSummaries(k.reqList, k.groups, kubernetesScheduler))
 
+	case *apiv1.GetResourcePoolQueueRequest:
+		reschedule = false
+		ctx.Respond(status.Error(codes.Unimplemented,
+			"the queue is managed by the Kubernetes scheduler"))
+
 	case *apiv1.GetResourcePoolsRequest:
 		resourcePoolSummary := k.summarizeDummyResourcePool(ctx)
 		resp := &apiv1.GetResourcePoolsResponse{
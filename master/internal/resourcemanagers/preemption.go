@@ -0,0 +1,70 @@
+package resourcemanagers
+
+import (
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// preemptionPolicy protects running tasks from being preempted right after they start or too
+// soon after they were last preempted, so that tasks are not preempted over and over under churn.
+type preemptionPolicy struct {
+	// minRunTime is how long a task runs before it can be preempted.
+	minRunTime time.Duration
+	// cooldown is how long after a task was preempted it can be preempted again.
+	cooldown time.Duration
+	now      func() time.Time
+}
+
+func newPreemptionPolicy(minRunTime, cooldown *model.Duration) preemptionPolicy {
+	p := preemptionPolicy{now: time.Now}
+	if minRunTime != nil {
+		p.minRunTime = time.Duration(*minRunTime)
+	}
+	if cooldown != nil {
+		p.cooldown = time.Duration(*cooldown)
+	}
+	return p
+}
+
+// protects returns whether the running task cannot be preempted at the given time.
+func (p preemptionPolicy) protects(
+	taskList *taskList, req *sproto.AllocateRequest, now time.Time,
+) bool {
+	if runtime, ok := taskList.runtime(req.TaskActor, now); ok && runtime < p.minRunTime {
+		return true
+	}
+	if since, ok := taskList.sincePreempted(req.TaskActor, now); ok && since < p.cooldown {
+		return true
+	}
+	return false
+}
+
+// apply returns a copy of the task list in which the running tasks that the policy protects are
+// non-preemptible. It also records on the resource pool whether any task was protected, so that
+// the pool schedules again once the protection expires.
+func (p preemptionPolicy) apply(rp *ResourcePool, taskList *taskList) *taskList {
+	rp.protecting = false
+	now := p.now()
+	rp.taskList.forgetPreemptionsBefore(now.Add(-p.cooldown))
+	if p.minRunTime == 0 && p.cooldown == 0 {
+		return taskList
+	}
+
+	protected := newTaskList()
+	for it := taskList.iterator(); it.next(); {
+		req := it.value()
+		assigned := taskList.GetAllocations(req.TaskActor)
+		if assigned == nil || len(assigned.Allocations) == 0 || req.NonPreemptible ||
+			!p.protects(taskList, req, now) {
+			protected.addTaskFrom(taskList, req)
+			continue
+		}
+		copied := *req
+		copied.NonPreemptible = true
+		protected.addTaskFrom(taskList, &copied)
+		rp.protecting = true
+	}
+	return protected
+}
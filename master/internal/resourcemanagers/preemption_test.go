@@ -0,0 +1,144 @@
+package resourcemanagers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestPreemptionPolicyConfig(t *testing.T) {
+	var config SchedulerConfig
+	assert.NilError(t, json.Unmarshal([]byte(`{
+		"type": "priority",
+		"preemption": true,
+		"min_run_time": "10m",
+		"preemption_cooldown": "1h"
+	}`), &config))
+	assert.Equal(t, *config.Priority.MinRunTime, model.Duration(10*time.Minute))
+	assert.Equal(t, *config.Priority.PreemptionCooldown, model.Duration(time.Hour))
+
+	config = SchedulerConfig{}
+	assert.NilError(t, json.Unmarshal([]byte(`{
+		"type": "fair_share",
+		"min_run_time": "-1s"
+	}`), &config))
+	assert.ErrorContains(t, check.Validate(config), "min_run_time must be >= 0")
+}
+
+func TestPreemptionPolicyFairShareMinRunTime(t *testing.T) {
+	agents := []*mockAgent{
+		{id: "agent", slots: 2, maxZeroSlotContainers: 100},
+	}
+	groups := []*mockGroup{
+		{id: "group1", weight: 1},
+		{id: "group2", weight: 1},
+	}
+	tasks := []*mockTask{
+		{id: "restored", slotsNeeded: 1, group: groups[0], allocatedAgent: agents[0]},
+		{id: "long-running", slotsNeeded: 1, group: groups[0], allocatedAgent: agents[0]},
+		{id: "pending", slotsNeeded: 1, group: groups[1]},
+	}
+
+	system := actor.NewSystem(t.Name())
+	minRunTime := model.Duration(10 * time.Minute)
+	config := &SchedulerConfig{
+		FairShare: &FairShareSchedulerConfig{MinRunTime: &minRunTime}, FittingPolicy: best,
+	}
+	rp := newTestQueueResourcePool(t, system, config, tasks, groups, agents)
+	now := time.Now()
+	scheduler := rp.scheduler.(*fairShare)
+	scheduler.preemption.now = func() time.Time { return now }
+
+	// The task that only just started is kept, so the other task of its group is released.
+	rp.taskList.startTimes[system.Get(actor.Addr("restored"))] = now.Add(-time.Minute)
+	rp.taskList.startTimes[system.Get(actor.Addr("long-running"))] = now.Add(-time.Hour)
+	_, toRelease := scheduler.Schedule(rp)
+	assertEqualToRelease(t, rp.taskList, toRelease, []*mockTask{tasks[1]})
+	assert.Assert(t, rp.protecting)
+
+	// Once both tasks have run for long enough, fair share releases the first of them.
+	rp.taskList.startTimes[system.Get(actor.Addr("restored"))] = now.Add(-time.Hour)
+	_, toRelease = scheduler.Schedule(rp)
+	assertEqualToRelease(t, rp.taskList, toRelease, []*mockTask{tasks[0]})
+	assert.Assert(t, !rp.protecting)
+}
+
+func TestPreemptionPolicyPriorityCooldown(t *testing.T) {
+	lowerPriority := 50
+	higherPriority := 40
+
+	agents := []*mockAgent{
+		{id: "agent1", slots: 4, maxZeroSlotContainers: 100},
+	}
+	groups := []*mockGroup{
+		{id: "group1", priority: &lowerPriority},
+		{id: "group2", priority: &higherPriority},
+	}
+	tasks := []*mockTask{
+		{
+			id: "task1", slotsNeeded: 4, group: groups[0],
+			allocatedAgent: agents[0], containerStarted: true,
+		},
+		{id: "task2", slotsNeeded: 4, group: groups[1]},
+	}
+
+	system := actor.NewSystem(t.Name())
+	cooldown := model.Duration(time.Hour)
+	config := &SchedulerConfig{
+		Priority: &PrioritySchedulerConfig{
+			Preemption: true, DefaultPriority: &lowerPriority, PreemptionCooldown: &cooldown,
+		},
+		FittingPolicy: best,
+	}
+	rp := newTestQueueResourcePool(t, system, config, tasks, groups, agents)
+	now := time.Now()
+	scheduler := rp.scheduler.(*priorityScheduler)
+	scheduler.preemption.now = func() time.Time { return now }
+
+	task1 := system.Get(actor.Addr("task1"))
+	rp.taskList.setPreempted(task1, now.Add(-time.Minute))
+	_, toRelease := scheduler.Schedule(rp)
+	assertEqualToRelease(t, rp.taskList, toRelease, nil)
+	assert.Assert(t, rp.protecting)
+
+	// Preemptions older than the cooldown are forgotten.
+	rp.taskList.setPreempted(task1, now.Add(-2*time.Hour))
+	_, toRelease = scheduler.Schedule(rp)
+	assertEqualToRelease(t, rp.taskList, toRelease, []*mockTask{tasks[0]})
+	assert.Equal(t, len(rp.taskList.preemptTimes), 0)
+}
+
+func TestPreemptionsForgotten(t *testing.T) {
+	agents := []*mockAgent{{id: "agent", slots: 2}}
+	tasks := []*mockTask{
+		{id: "task1", slotsNeeded: 1, allocatedAgent: agents[0]},
+		{id: "task2", slotsNeeded: 1, allocatedAgent: agents[0]},
+	}
+	system := actor.NewSystem(t.Name())
+	rp, ref := setupResourcePool(t, system, nil, tasks, nil, agents)
+	task1 := system.Get(actor.Addr("task1"))
+	task2 := system.Get(actor.Addr("task2"))
+	system.Ask(task1, SendRequestResourcesToResourceManager{}).Get()
+	system.Ask(ref, actor.Ping{}).Get()
+
+	// The preemption of a task is forgotten once its actor stops.
+	rp.taskList.setPreempted(task1, time.Now())
+	assert.NilError(t, task1.StopAndAwaitTermination())
+	for _, n := range rp.notifications {
+		<-n
+	}
+	system.Ask(ref, actor.Ping{}).Get()
+	_, ok := rp.taskList.sincePreempted(task1, time.Now())
+	assert.Assert(t, !ok)
+
+	// Without a preemption policy, preemptions are not kept at all.
+	rp.taskList.setPreempted(task2, time.Now().Add(-time.Second))
+	rp.scheduler.Schedule(rp)
+	assert.Equal(t, len(rp.taskList.preemptTimes), 0)
+}
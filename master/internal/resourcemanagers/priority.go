@@ -14,15 +14,31 @@ import (
 
 type priorityScheduler struct {
 	preemptionEnabled bool
+	preemption        preemptionPolicy
 }
 
 // NewPriorityScheduler creates a new scheduler that schedules tasks via priority.
 func NewPriorityScheduler(config *SchedulerConfig) Scheduler {
-	return &priorityScheduler{preemptionEnabled: config.Priority.Preemption}
+	return &priorityScheduler{
+		preemptionEnabled: config.Priority.Preemption,
+		preemption: newPreemptionPolicy(
+			config.Priority.MinRunTime, config.Priority.PreemptionCooldown),
+	}
 }
 
 func (p *priorityScheduler) Schedule(rp *ResourcePool) ([]*sproto.AllocateRequest, []*actor.Ref) {
-	return p.prioritySchedule(rp.taskList, rp.groups, rp.agents, rp.fittingMethod)
+	// Tasks are admitted to their quotas in the order of their priority.
+	taskList := rp.quotas.filter(rp.taskList, byGroupPriority(rp.groups))
+	taskList = p.preemption.apply(rp, taskList)
+	return p.prioritySchedule(taskList, rp.groups, rp.agents, rp.fittingMethod)
+}
+
+// byGroupPriority orders tasks by the priority of their groups.
+func byGroupPriority(groups map[*actor.Ref]*group) func(a, b *sproto.AllocateRequest) bool {
+	return func(a, b *sproto.AllocateRequest) bool {
+		first, second := groups[a.Group].priority, groups[b.Group].priority
+		return first != nil && second != nil && *first < *second
+	}
 }
 
 func (p *priorityScheduler) prioritySchedule(
@@ -0,0 +1,121 @@
+package resourcemanagers
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	cproto "github.com/determined-ai/determined/master/pkg/container"
+	"github.com/determined-ai/determined/proto/pkg/resourcepoolv1"
+)
+
+// queue returns the pending tasks of the resource pool in the order that the scheduler considers
+// them, along with why each of them is not running yet.
+func (rp *ResourcePool) queue() []*resourcepoolv1.QueuedTask {
+	var pending []*sproto.AllocateRequest
+	groupSlots := make(map[*actor.Ref]int)
+	for it := rp.taskList.iterator(); it.next(); {
+		req := it.value()
+		assigned := rp.taskList.GetAllocations(req.TaskActor)
+		if assigned == nil || len(assigned.Allocations) == 0 {
+			pending = append(pending, req)
+			continue
+		}
+		groupSlots[req.Group] += req.SlotsNeeded
+	}
+	if rp.config.Scheduler.Priority != nil {
+		sort.SliceStable(pending, func(i, j int) bool {
+			return byGroupPriority(rp.groups)(pending[i], pending[j])
+		})
+	}
+
+	// The agents as they will be once the tasks that have been asked to release their resources
+	// have done so.
+	preemptedAgents := deepCopyAgents(rp.agents)
+	for handler := range rp.preempting {
+		if assigned := rp.taskList.GetAllocations(handler); assigned != nil {
+			removeTaskFromAgents(preemptedAgents, assigned)
+		}
+	}
+
+	queue := make([]*resourcepoolv1.QueuedTask, 0, len(pending))
+	for _, req := range pending {
+		task := &resourcepoolv1.QueuedTask{
+			TaskId:        string(req.ID),
+			Name:          req.Name,
+			SlotsNeeded:   int32(req.SlotsNeeded),
+			AgentLabel:    req.Label,
+			FittingAgents: fittingAgents(req, rp.agents, rp.fittingMethod),
+		}
+		group := rp.groups[req.Group]
+		switch {
+		case rp.quotas.reason(req.TaskActor) != "":
+			task.BlockingReason = resourcepoolv1.QueueBlockingReason_QUEUE_BLOCKING_REASON_OVER_QUOTA
+			task.Details = rp.quotas.reason(req.TaskActor)
+		case len(task.FittingAgents) == 0:
+			task.BlockingReason =
+				resourcepoolv1.QueueBlockingReason_QUEUE_BLOCKING_REASON_NO_FITTING_AGENT
+			task.Details = fmt.Sprintf(
+				"no agent with label %q can host a task with %d slots", req.Label, req.SlotsNeeded)
+		case group != nil && group.maxSlots != nil &&
+			groupSlots[req.Group]+req.SlotsNeeded > *group.maxSlots:
+			task.BlockingReason =
+				resourcepoolv1.QueueBlockingReason_QUEUE_BLOCKING_REASON_OVER_MAX_SLOTS
+			task.Details = fmt.Sprintf("%d of the max_slots of %d are in use",
+				groupSlots[req.Group], *group.maxSlots)
+		case len(findFits(req, rp.agents, rp.fittingMethod)) != 0:
+			task.BlockingReason =
+				resourcepoolv1.QueueBlockingReason_QUEUE_BLOCKING_REASON_WAITING_FOR_SCHEDULER
+			task.Details = "enough slots are free; the task is waiting for the scheduler to start it"
+		case len(rp.preempting) != 0 && len(findFits(req, preemptedAgents, rp.fittingMethod)) != 0:
+			task.BlockingReason =
+				resourcepoolv1.QueueBlockingReason_QUEUE_BLOCKING_REASON_WAITING_ON_PREEMPTION
+			task.Details = fmt.Sprintf(
+				"waiting for %d preempted tasks to release their slots", len(rp.preempting))
+		default:
+			task.BlockingReason =
+				resourcepoolv1.QueueBlockingReason_QUEUE_BLOCKING_REASON_SLOTS_UNAVAILABLE
+			task.Details = "the agents that could host the task do not have enough free slots"
+		}
+		queue = append(queue, task)
+	}
+	return queue
+}
+
+// fittingAgents returns the names of the agents that could host the task if they were idle.
+func fittingAgents(
+	req *sproto.AllocateRequest, agents map[*actor.Ref]*agentState, fittingMethod SoftConstraint,
+) []string {
+	idleAgents := make(map[*actor.Ref]*agentState, len(agents))
+	for handler, agent := range agents {
+		if agent.draining || agent.quarantined {
+			continue
+		}
+		idle := agent.deepCopy()
+		for d := range idle.devices {
+			idle.devices[d] = nil
+		}
+		idle.zeroSlotContainers = make(map[cproto.ID]bool)
+		idleAgents[handler] = idle
+	}
+
+	names := make([]string, 0)
+	for _, agent := range idleAgents {
+		if isViable(req, agent, slotsSatisfied, maxZeroSlotContainersSatisfied, labelSatisfied) {
+			names = append(names, agent.handler.Address().Local())
+		}
+	}
+	if len(names) == 0 && !req.FittingRequirements.SingleAgent && req.SlotsNeeded > 1 {
+		// Multi-agent tasks can be hosted by any agent of the size that the dedicated fit uses.
+		if fits := findDedicatedAgentFits(req, idleAgents, fittingMethod); len(fits) != 0 {
+			for _, agent := range idleAgents {
+				if labelSatisfied(req, agent) && agent.numSlots() == fits[0].Slots {
+					names = append(names, agent.handler.Address().Local())
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
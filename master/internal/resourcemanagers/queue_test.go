@@ -0,0 +1,133 @@
+package resourcemanagers
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/proto/pkg/resourcepoolv1"
+)
+
+func newTestQueueResourcePool(
+	t *testing.T,
+	system *actor.System,
+	config *SchedulerConfig,
+	tasks []*mockTask,
+	groups []*mockGroup,
+	agents []*mockAgent,
+) *ResourcePool {
+	rp := NewResourcePool(
+		&ResourcePoolConfig{PoolName: "pool", Scheduler: config},
+		nil, MakeScheduler(config), MakeFitFunction(config.FittingPolicy))
+	rp.taskList, rp.groups, rp.agents = setupSchedulerStates(t, system, tasks, groups, agents)
+	return rp
+}
+
+func TestResourcePoolQueueReasons(t *testing.T) {
+	agents := []*mockAgent{
+		{id: "agent1", slots: 4, maxZeroSlotContainers: 100},
+		{id: "agent2", slots: 4, maxZeroSlotContainers: 100},
+	}
+	groups := []*mockGroup{
+		{id: "group1", maxSlots: newMaxSlot(6), weight: 1},
+	}
+	tasks := []*mockTask{
+		{
+			id: "running1", slotsNeeded: 4, group: groups[0],
+			allocatedAgent: agents[0], containerStarted: true,
+		},
+		{
+			id: "running2", slotsNeeded: 2,
+			allocatedAgent: agents[1], containerStarted: true,
+		},
+		{id: "over-quota", slotsNeeded: 1, user: "bob"},
+		{id: "over-max-slots", slotsNeeded: 4, group: groups[0]},
+		{id: "wrong-label", slotsNeeded: 1, label: "gpu"},
+		{id: "too-large", slotsNeeded: 16},
+		{id: "fits", slotsNeeded: 2},
+		{id: "preemption", slotsNeeded: 4},
+		{id: "unavailable", slotsNeeded: 8},
+	}
+
+	system := actor.NewSystem(t.Name())
+	rp := newTestQueueResourcePool(t, system, DefaultSchedulerConfig(), tasks, groups, agents)
+	rp.quotas = newQuotaState(&QuotaConfig{UserSlots: map[string]int{"bob": 0}}, rp.taskList)
+	rp.quotas.filter(rp.taskList, nil)
+	rp.preempting[system.Get(actor.Addr("running2"))] = true
+
+	queue := rp.queue()
+	expected := []struct {
+		id     string
+		reason resourcepoolv1.QueueBlockingReason
+		agents []string
+	}{
+		{
+			"over-quota", resourcepoolv1.QueueBlockingReason_QUEUE_BLOCKING_REASON_OVER_QUOTA,
+			[]string{"agent1", "agent2"},
+		},
+		{
+			"over-max-slots", resourcepoolv1.QueueBlockingReason_QUEUE_BLOCKING_REASON_OVER_MAX_SLOTS,
+			[]string{"agent1", "agent2"},
+		},
+		{
+			"wrong-label", resourcepoolv1.QueueBlockingReason_QUEUE_BLOCKING_REASON_NO_FITTING_AGENT,
+			[]string{},
+		},
+		{
+			"too-large", resourcepoolv1.QueueBlockingReason_QUEUE_BLOCKING_REASON_NO_FITTING_AGENT,
+			[]string{},
+		},
+		{
+			"fits", resourcepoolv1.QueueBlockingReason_QUEUE_BLOCKING_REASON_WAITING_FOR_SCHEDULER,
+			[]string{"agent1", "agent2"},
+		},
+		{
+			"preemption",
+			resourcepoolv1.QueueBlockingReason_QUEUE_BLOCKING_REASON_WAITING_ON_PREEMPTION,
+			[]string{"agent1", "agent2"},
+		},
+		{
+			"unavailable", resourcepoolv1.QueueBlockingReason_QUEUE_BLOCKING_REASON_SLOTS_UNAVAILABLE,
+			[]string{"agent1", "agent2"},
+		},
+	}
+	assert.Equal(t, len(queue), len(expected))
+	for i, task := range queue {
+		assert.Equal(t, task.TaskId, expected[i].id)
+		assert.Equal(t, task.BlockingReason, expected[i].reason, task.TaskId)
+		assert.DeepEqual(t, task.FittingAgents, expected[i].agents)
+		assert.Assert(t, task.Details != "")
+	}
+}
+
+func TestResourcePoolQueuePriorityOrder(t *testing.T) {
+	lowerPriority := 50
+	higherPriority := 40
+
+	agents := []*mockAgent{
+		{id: "agent1", slots: 4, maxZeroSlotContainers: 100},
+	}
+	groups := []*mockGroup{
+		{id: "group1", priority: &lowerPriority},
+		{id: "group2", priority: &higherPriority},
+	}
+	tasks := []*mockTask{
+		{id: "task1", slotsNeeded: 8, group: groups[0]},
+		{id: "task2", slotsNeeded: 8, group: groups[1]},
+		{id: "task3", slotsNeeded: 8, group: groups[0]},
+	}
+	config := &SchedulerConfig{
+		Priority:      &PrioritySchedulerConfig{DefaultPriority: &lowerPriority},
+		FittingPolicy: best,
+	}
+
+	system := actor.NewSystem(t.Name())
+	rp := newTestQueueResourcePool(t, system, config, tasks, groups, agents)
+
+	var ids []string
+	for _, task := range rp.queue() {
+		ids = append(ids, task.TaskId)
+	}
+	assert.DeepEqual(t, ids, []string{"task2", "task1", "task3"})
+}
@@ -0,0 +1,199 @@
+package resourcemanagers
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/proto/pkg/resourcepoolv1"
+)
+
+// QuotaConfig configures the maximum number of slots that the tasks of a user or of an experiment
+// label can hold at the same time in a resource pool.
+type QuotaConfig struct {
+	// DefaultUserSlots applies to users that are not listed in UserSlots; if it is not set, those
+	// users are not limited.
+	DefaultUserSlots *int           `json:"default_user_slots"`
+	UserSlots        map[string]int `json:"user_slots"`
+	LabelSlots       map[string]int `json:"label_slots"`
+}
+
+// Validate implements the check.Validatable interface.
+func (q QuotaConfig) Validate() []error {
+	errs := []error{
+		check.GreaterThanOrEqualTo(q.DefaultUserSlots, 0, "default_user_slots must be >= 0"),
+	}
+	for user, slots := range q.UserSlots {
+		errs = append(errs, check.GreaterThanOrEqualTo(
+			slots, 0, "the slot quota of user %s must be >= 0", user))
+	}
+	for label, slots := range q.LabelSlots {
+		errs = append(errs, check.GreaterThanOrEqualTo(
+			slots, 0, "the slot quota of label %s must be >= 0", label))
+	}
+	return errs
+}
+
+// userQuota returns the slot quota of the user, if there is one.
+func (q QuotaConfig) userQuota(user string) (int, bool) {
+	if slots, ok := q.UserSlots[user]; ok {
+		return slots, true
+	}
+	if q.DefaultUserSlots != nil {
+		return *q.DefaultUserSlots, true
+	}
+	return 0, false
+}
+
+// quotaState tracks the slots that each user and experiment label holds in a resource pool during
+// a scheduling pass.
+type quotaState struct {
+	config     QuotaConfig
+	userSlots  map[string]int
+	labelSlots map[string]int
+	// reasons holds why each pending task that is over quota was held back.
+	reasons map[*actor.Ref]string
+}
+
+// newQuotaState returns the quota state of the tasks that hold resources in the task list, or nil
+// if no quotas are configured.
+func newQuotaState(config *QuotaConfig, taskList *taskList) *quotaState {
+	if config == nil {
+		return nil
+	}
+	q := &quotaState{
+		config:     *config,
+		userSlots:  make(map[string]int),
+		labelSlots: make(map[string]int),
+		reasons:    make(map[*actor.Ref]string),
+	}
+	for it := taskList.iterator(); it.next(); {
+		req := it.value()
+		if assigned := taskList.GetAllocations(req.TaskActor); assigned != nil &&
+			len(assigned.Allocations) > 0 {
+			q.charge(req)
+		}
+	}
+	return q
+}
+
+func (q *quotaState) charge(req *sproto.AllocateRequest) {
+	q.userSlots[req.User] += req.SlotsNeeded
+	for _, label := range req.ExperimentLabels {
+		q.labelSlots[label] += req.SlotsNeeded
+	}
+}
+
+// admit charges the slots of the task to its quotas if they fit and otherwise returns the reason
+// that they do not.
+func (q *quotaState) admit(req *sproto.AllocateRequest) string {
+	if req.SlotsNeeded == 0 {
+		return ""
+	}
+	if quota, ok := q.config.userQuota(req.User); ok &&
+		q.userSlots[req.User]+req.SlotsNeeded > quota {
+		return fmt.Sprintf("over the slot quota of user %s: %d of %d slots in use, task needs %d",
+			req.User, q.userSlots[req.User], quota, req.SlotsNeeded)
+	}
+	for _, label := range req.ExperimentLabels {
+		if quota, ok := q.config.LabelSlots[label]; ok &&
+			q.labelSlots[label]+req.SlotsNeeded > quota {
+			return fmt.Sprintf("over the slot quota of label %s: %d of %d slots in use, task needs %d",
+				label, q.labelSlots[label], quota, req.SlotsNeeded)
+		}
+	}
+	q.charge(req)
+	return ""
+}
+
+// filter returns a copy of the task list without the pending tasks that would exceed their
+// quotas. Pending tasks are admitted in the given order, so that tasks earlier in that order
+// are not held back by later ones.
+func (q *quotaState) filter(
+	taskList *taskList, less func(a, b *sproto.AllocateRequest) bool,
+) *taskList {
+	if q == nil {
+		return taskList
+	}
+
+	var pending []*sproto.AllocateRequest
+	filtered := newTaskList()
+	for it := taskList.iterator(); it.next(); {
+		req := it.value()
+		assigned := taskList.GetAllocations(req.TaskActor)
+		if assigned == nil || len(assigned.Allocations) == 0 {
+			pending = append(pending, req)
+			continue
+		}
+		filtered.addTaskFrom(taskList, req)
+	}
+	if less != nil {
+		sort.SliceStable(pending, func(i, j int) bool { return less(pending[i], pending[j]) })
+	}
+	for _, req := range pending {
+		if reason := q.admit(req); reason != "" {
+			q.reasons[req.TaskActor] = reason
+			continue
+		}
+		filtered.addTaskFrom(taskList, req)
+	}
+	return filtered
+}
+
+// reason returns why the task is being held back by its quotas, if it is.
+func (q *quotaState) reason(handler *actor.Ref) string {
+	if q == nil {
+		return ""
+	}
+	return q.reasons[handler]
+}
+
+// getQuotaUsage returns the slots held by the tasks of each user and experiment label.
+func getQuotaUsage(taskList *taskList) (map[string]int, map[string]int) {
+	q := newQuotaState(&QuotaConfig{}, taskList)
+	return q.userSlots, q.labelSlots
+}
+
+// quotaSummaries returns the quotas of a resource pool along with the slots that are held under
+// each of them.
+func quotaSummaries(
+	config *QuotaConfig, userSlots map[string]int, labelSlots map[string]int,
+) ([]*resourcepoolv1.ResourcePoolQuota, *wrappers.Int32Value, []*resourcepoolv1.ResourcePoolQuota) {
+	userQuotas := make([]*resourcepoolv1.ResourcePoolQuota, 0)
+	labelQuotas := make([]*resourcepoolv1.ResourcePoolQuota, 0)
+	if config == nil {
+		return userQuotas, nil, labelQuotas
+	}
+
+	var defaultUserQuota *wrappers.Int32Value
+	if config.DefaultUserSlots != nil {
+		defaultUserQuota = &wrappers.Int32Value{Value: int32(*config.DefaultUserSlots)}
+	}
+	users := make(map[string]bool)
+	for user := range config.UserSlots {
+		users[user] = true
+	}
+	for user, slots := range userSlots {
+		if config.DefaultUserSlots != nil && slots > 0 {
+			users[user] = true
+		}
+	}
+	for user := range users {
+		quota, _ := config.userQuota(user)
+		userQuotas = append(userQuotas, &resourcepoolv1.ResourcePoolQuota{
+			Name: user, Slots: int32(quota), SlotsUsed: int32(userSlots[user]),
+		})
+	}
+	for label, quota := range config.LabelSlots {
+		labelQuotas = append(labelQuotas, &resourcepoolv1.ResourcePoolQuota{
+			Name: label, Slots: int32(quota), SlotsUsed: int32(labelSlots[label]),
+		})
+	}
+	sort.Slice(userQuotas, func(i, j int) bool { return userQuotas[i].Name < userQuotas[j].Name })
+	sort.Slice(labelQuotas, func(i, j int) bool { return labelQuotas[i].Name < labelQuotas[j].Name })
+	return userQuotas, defaultUserQuota, labelQuotas
+}
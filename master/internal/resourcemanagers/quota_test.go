@@ -0,0 +1,122 @@
+package resourcemanagers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/check"
+)
+
+func TestQuotaConfig(t *testing.T) {
+	var config ResourcePoolConfig
+	assert.NilError(t, json.Unmarshal([]byte(`{
+		"pool_name": "default",
+		"quotas": {
+			"default_user_slots": 4,
+			"user_slots": {"alice": 8},
+			"label_slots": {"research": -1}
+		}
+	}`), &config))
+	assert.Equal(t, *config.Quotas.DefaultUserSlots, 4)
+	assert.Equal(t, config.Quotas.UserSlots["alice"], 8)
+	assert.ErrorContains(t, check.Validate(config), "the slot quota of label research must be >= 0")
+
+	quota, ok := config.Quotas.userQuota("alice")
+	assert.Assert(t, ok)
+	assert.Equal(t, quota, 8)
+	quota, ok = config.Quotas.userQuota("bob")
+	assert.Assert(t, ok)
+	assert.Equal(t, quota, 4)
+}
+
+func TestQuotaFairShare(t *testing.T) {
+	agents := []*mockAgent{
+		{id: "agent1", slots: 4, maxZeroSlotContainers: 100},
+		{id: "agent2", slots: 4, maxZeroSlotContainers: 100},
+	}
+	tasks := []*mockTask{
+		{
+			id: "running", slotsNeeded: 2, user: "alice",
+			allocatedAgent: agents[0], containerStarted: true,
+		},
+		{id: "alice1", slotsNeeded: 2, user: "alice"},
+		{id: "alice2", slotsNeeded: 2, user: "alice"},
+		{id: "alice-cpu", slotsNeeded: 0, user: "alice"},
+		{id: "bob1", slotsNeeded: 2, user: "bob"},
+	}
+	expectedToAllocate := []*mockTask{tasks[1], tasks[3], tasks[4]}
+
+	system := actor.NewSystem(t.Name())
+	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, nil, agents)
+	quotas := newQuotaState(&QuotaConfig{UserSlots: map[string]int{"alice": 4}}, taskList)
+	toAllocate, _ := fairshareSchedule(quotas.filter(taskList, nil), groupMap, agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+
+	alice2 := system.Get(actor.Addr("alice2"))
+	assert.Equal(t, quotas.reason(alice2),
+		"over the slot quota of user alice: 4 of 4 slots in use, task needs 2")
+	assert.Equal(t, len(quotas.reasons), 1)
+}
+
+func TestQuotaPriority(t *testing.T) {
+	lowerPriority := 50
+	higherPriority := 40
+
+	agents := []*mockAgent{
+		{id: "agent1", slots: 4, maxZeroSlotContainers: 100},
+		{id: "agent2", slots: 4, maxZeroSlotContainers: 100},
+	}
+	groups := []*mockGroup{
+		{id: "group1", priority: &lowerPriority},
+		{id: "group2", priority: &higherPriority},
+	}
+	tasks := []*mockTask{
+		{id: "task1", slotsNeeded: 2, group: groups[0], experimentLabels: []string{"research"}},
+		{id: "task2", slotsNeeded: 2, group: groups[1], experimentLabels: []string{"research"}},
+		{id: "task3", slotsNeeded: 2, group: groups[1], experimentLabels: []string{"prod"}},
+	}
+	expectedToAllocate := []*mockTask{tasks[1], tasks[2]}
+
+	system := actor.NewSystem(t.Name())
+	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
+	quotas := newQuotaState(&QuotaConfig{LabelSlots: map[string]int{"research": 2}}, taskList)
+
+	p := &priorityScheduler{}
+	toAllocate, _ := p.prioritySchedule(
+		quotas.filter(taskList, byGroupPriority(groupMap)), groupMap, agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+
+	task1 := system.Get(actor.Addr("task1"))
+	assert.Equal(t, quotas.reason(task1),
+		"over the slot quota of label research: 2 of 2 slots in use, task needs 2")
+}
+
+func TestQuotaSummaries(t *testing.T) {
+	defaultSlots := 2
+	config := &QuotaConfig{
+		DefaultUserSlots: &defaultSlots,
+		UserSlots:        map[string]int{"alice": 8},
+		LabelSlots:       map[string]int{"research": 4},
+	}
+	users, defaultUser, labels := quotaSummaries(config,
+		map[string]int{"alice": 2, "bob": 1, "carol": 0}, map[string]int{"research": 3})
+
+	assert.Equal(t, defaultUser.Value, int32(2))
+	assert.Equal(t, len(users), 2)
+	assert.Equal(t, users[0].Name, "alice")
+	assert.Equal(t, users[0].Slots, int32(8))
+	assert.Equal(t, users[0].SlotsUsed, int32(2))
+	assert.Equal(t, users[1].Name, "bob")
+	assert.Equal(t, users[1].Slots, int32(2))
+	assert.Equal(t, users[1].SlotsUsed, int32(1))
+	assert.Equal(t, len(labels), 1)
+	assert.Equal(t, labels[0].SlotsUsed, int32(3))
+
+	users, defaultUser, labels = quotaSummaries(nil, nil, nil)
+	assert.Assert(t, defaultUser == nil)
+	assert.Equal(t, len(users), 0)
+	assert.Equal(t, len(labels), 0)
+}
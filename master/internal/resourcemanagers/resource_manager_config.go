@@ -2,12 +2,17 @@ package resourcemanagers
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/union"
 )
 
-const defaultResourcePoolName = "default"
+const (
+	defaultResourcePoolName   = "default"
+	defaultAgentReconnectWait = 5 * time.Minute
+)
 
 // ResourceManagerConfig hosts configuration fields for the resource manager.
 type ResourceManagerConfig struct {
@@ -49,6 +54,32 @@ type AgentResourceManagerConfig struct {
 	Scheduler              *SchedulerConfig `json:"scheduler"`
 	DefaultCPUResourcePool string           `json:"default_cpu_resource_pool"`
 	DefaultGPUResourcePool string           `json:"default_gpu_resource_pool"`
+	// AgentReconnectWait is how long the containers of a disconnected agent are kept before they
+	// are considered lost; zero disables agent reconnection.
+	AgentReconnectWait *model.Duration `json:"agent_reconnect_wait"`
+	// AgentQuarantineFailures is how many distinct tasks must fail in a row on an agent before it
+	// is quarantined; zero disables quarantining.
+	AgentQuarantineFailures int `json:"agent_quarantine_failures"`
+	// AgentQuarantineTaskFailures is how many times the containers of a single task must fail in
+	// a row on an agent before it is quarantined; zero disables counting them.
+	AgentQuarantineTaskFailures *int `json:"agent_quarantine_task_failures"`
+}
+
+// agentReconnectWait returns how long to wait for a disconnected agent to reconnect.
+func (a AgentResourceManagerConfig) agentReconnectWait() time.Duration {
+	if a.AgentReconnectWait == nil {
+		return defaultAgentReconnectWait
+	}
+	return time.Duration(*a.AgentReconnectWait)
+}
+
+// agentQuarantineTaskFailures returns how many times a single task must fail on an agent to
+// quarantine it, which defaults to twice the number of distinct tasks.
+func (a AgentResourceManagerConfig) agentQuarantineTaskFailures() int {
+	if a.AgentQuarantineTaskFailures == nil {
+		return 2 * a.AgentQuarantineFailures
+	}
+	return *a.AgentQuarantineTaskFailures
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
@@ -72,6 +103,12 @@ func (a AgentResourceManagerConfig) Validate() []error {
 	return []error{
 		check.NotEmpty(a.DefaultCPUResourcePool, "default_cpu_resource_pool should be non-empty"),
 		check.NotEmpty(a.DefaultGPUResourcePool, "default_gpu_resource_pool should be non-empty"),
+		check.GreaterThanOrEqualTo(int64(a.agentReconnectWait()), int64(0),
+			"agent_reconnect_wait must be >= 0"),
+		check.GreaterThanOrEqualTo(a.AgentQuarantineFailures, 0,
+			"agent_quarantine_failures must be >= 0"),
+		check.GreaterThanOrEqualTo(a.agentQuarantineTaskFailures(), 0,
+			"agent_quarantine_task_failures must be >= 0"),
 	}
 }
 
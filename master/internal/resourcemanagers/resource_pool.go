@@ -2,6 +2,7 @@ package resourcemanagers
 
 import (
 	"crypto/tls"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
@@ -16,6 +17,7 @@ import (
 	cproto "github.com/determined-ai/determined/master/pkg/container"
 	"github.com/determined-ai/determined/master/pkg/device"
 	image "github.com/determined-ai/determined/master/pkg/tasks"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
 )
 
 // ResourcePool manages the agent and task lifecycles.
@@ -32,6 +34,20 @@ type ResourcePool struct {
 	taskList    *taskList
 	groups      map[*actor.Ref]*group
 	scalingInfo *sproto.ScalingInfo
+	// quotas holds the quota state of the last scheduling pass.
+	quotas *quotaState
+	// preempting holds the tasks that have been asked to release their resources.
+	preempting map[*actor.Ref]bool
+	// reattaching holds the requests of restored tasks that wait for the agents to announce the
+	// containers that the tasks ran before a master restart. They wait until reattachDeadline,
+	// which is reattachWait after the pool started; afterwards they are scheduled like any other.
+	reattaching      map[*actor.Ref]*sproto.AllocateRequest
+	reattachWait     time.Duration
+	reattachDeadline time.Time
+	// protecting is set when the last scheduling pass kept running tasks from being preempted
+	// because of the preemption policy of the scheduler; the pool then schedules on every tick
+	// until the protection expires.
+	protecting bool
 
 	reschedule bool
 
@@ -44,6 +60,11 @@ type ResourcePool struct {
 // resource pool (agents, slots, cpu containers).
 type GetResourceSummary struct{}
 
+// taskActorStopped notifies the resource pool that the actor of a task stopped.
+type taskActorStopped struct {
+	Ref *actor.Ref
+}
+
 // NewResourcePool initializes a new empty default resource provider.
 func NewResourcePool(
 	config *ResourcePoolConfig,
@@ -62,6 +83,8 @@ func NewResourcePool(
 		taskList:    newTaskList(),
 		groups:      make(map[*actor.Ref]*group),
 		scalingInfo: &sproto.ScalingInfo{},
+		preempting:  make(map[*actor.Ref]bool),
+		reattaching: make(map[*actor.Ref]*sproto.AllocateRequest),
 
 		reschedule: false,
 	}
@@ -83,7 +106,7 @@ func (rp *ResourcePool) setupProvisioner(ctx *actor.Context) error {
 }
 
 func (rp *ResourcePool) addTask(ctx *actor.Context, msg sproto.AllocateRequest) {
-	rp.notifyOnStop(ctx, msg.TaskActor, sproto.ResourcesReleased{TaskActor: msg.TaskActor})
+	rp.notifyOnStop(ctx, msg.TaskActor, taskActorStopped{Ref: msg.TaskActor})
 
 	if len(msg.ID) == 0 {
 		msg.ID = sproto.TaskID(uuid.New().String())
@@ -100,9 +123,74 @@ func (rp *ResourcePool) addTask(ctx *actor.Context, msg sproto.AllocateRequest)
 		"resources are requested by %s (Task ID: %s)",
 		msg.TaskActor.Address(), msg.ID,
 	)
+	if len(msg.ReattachContainers) > 0 {
+		rp.reattaching[msg.TaskActor] = &msg
+		rp.reattach(ctx)
+		return
+	}
 	rp.taskList.AddTask(&msg)
 }
 
+// reattach allocates the containers that restored tasks ran before a master restart to the tasks
+// once their agents announce them. The tasks that are still waiting for their containers at the
+// reattach deadline are scheduled like any other.
+func (rp *ResourcePool) reattach(ctx *actor.Context) {
+	for handler, req := range rp.reattaching {
+		if rp.reattachResources(ctx, req) {
+			delete(rp.reattaching, handler)
+			continue
+		}
+		if time.Now().Before(rp.reattachDeadline) {
+			continue
+		}
+		ctx.Log().Infof("containers of %s did not reattach, scheduling it", handler.Address())
+		delete(rp.reattaching, handler)
+		req.ReattachContainers = nil
+		rp.taskList.AddTask(req)
+	}
+}
+
+// reattachResources allocates the containers that the task asks to reattach to if the agents
+// announced all of them. It returns true if it is successfully allocated.
+func (rp *ResourcePool) reattachResources(
+	ctx *actor.Context, req *sproto.AllocateRequest,
+) bool {
+	allocations := make([]sproto.Allocation, 0, len(req.ReattachContainers))
+	agents := make([]*agentState, 0, len(req.ReattachContainers))
+	for _, id := range req.ReattachContainers {
+		var allocation *containerAllocation
+		for _, agent := range rp.agents {
+			if devices, ok := agent.reattachableDevices(id); ok {
+				allocation = &containerAllocation{
+					req:        req,
+					agent:      agent,
+					container:  &container{req: req, id: id, slots: len(devices), agent: agent},
+					devices:    devices,
+					reattached: true,
+				}
+				break
+			}
+		}
+		if allocation == nil {
+			return false
+		}
+		allocations = append(allocations, allocation)
+		agents = append(agents, allocation.agent)
+	}
+
+	for i, id := range req.ReattachContainers {
+		delete(agents[i].reattachable, id)
+	}
+	allocated := sproto.ResourcesAllocated{
+		ID: req.ID, ResourcePool: rp.config.PoolName, Allocations: allocations, Reattached: true,
+	}
+	rp.taskList.AddTask(req)
+	rp.taskList.SetAllocations(req.TaskActor, &allocated)
+	req.TaskActor.System().Tell(req.TaskActor, allocated)
+	ctx.Log().Infof("reattached containers to %s", req.TaskActor.Address())
+	return true
+}
+
 func (rp *ResourcePool) receiveSetTaskName(ctx *actor.Context, msg sproto.SetTaskName) {
 	if task, found := rp.taskList.GetTaskByHandler(msg.TaskHandler); found {
 		task.Name = msg.Name
@@ -141,12 +229,16 @@ func (rp *ResourcePool) allocateResources(ctx *actor.Context, req *sproto.Alloca
 
 func (rp *ResourcePool) releaseResource(ctx *actor.Context, handler *actor.Ref) {
 	ctx.Log().Infof("releasing resources taken by %s", handler.Address())
+	rp.preempting[handler] = true
+	rp.taskList.setPreempted(handler, time.Now())
 	handler.System().Tell(handler, sproto.ReleaseResources{ResourcePool: rp.config.PoolName})
 }
 
 func (rp *ResourcePool) resourcesReleased(ctx *actor.Context, handler *actor.Ref) {
 	ctx.Log().Infof("resources are released for %s", handler.Address())
 	rp.taskList.RemoveTaskByHandler(handler)
+	delete(rp.preempting, handler)
+	delete(rp.reattaching, handler)
 }
 
 func (rp *ResourcePool) getOrCreateGroup(
@@ -211,6 +303,7 @@ func (rp *ResourcePool) Receive(ctx *actor.Context) error {
 
 	switch msg := ctx.Message().(type) {
 	case actor.PreStart:
+		rp.reattachDeadline = time.Now().Add(rp.reattachWait)
 		reportResourcePoolCreated(ctx.Self().System(), rp.config)
 		err := rp.setupProvisioner(ctx)
 		actors.NotifyAfter(ctx, actionCoolDown, schedulerTick{})
@@ -221,11 +314,14 @@ func (rp *ResourcePool) Receive(ctx *actor.Context) error {
 		sproto.AddDevice,
 		sproto.FreeDevice,
 		sproto.RemoveDevice,
-		sproto.RemoveAgent:
+		sproto.RemoveAgent,
+		sproto.SetAgentDraining,
+		sproto.SetAgentQuarantined:
 		return rp.receiveAgentMsg(ctx)
 
 	case
 		groupActorStopped,
+		taskActorStopped,
 		sproto.SetGroupMaxSlots,
 		sproto.SetGroupWeight,
 		sproto.SetGroupPriority,
@@ -238,20 +334,50 @@ func (rp *ResourcePool) Receive(ctx *actor.Context) error {
 		reschedule = false
 		if resp := getTaskSummary(
 			rp.taskList, *msg.ID, rp.groups, rp.config.Scheduler.GetType()); resp != nil {
+			if req, ok := rp.taskList.GetTaskByID(resp.ID); ok {
+				resp.QueueReason = rp.quotas.reason(req.TaskActor)
+			}
 			ctx.Respond(*resp)
 		}
 
 	case sproto.GetTaskSummaries:
 		reschedule = false
-		ctx.Respond(getTaskSummaries(rp.taskList, rp.groups, rp.config.Scheduler.GetType()))
+		summaries := getTaskSummaries(rp.taskList, rp.groups, rp.config.Scheduler.GetType())
+		for id, summary := range summaries {
+			if req, ok := rp.taskList.GetTaskByID(id); ok {
+				summary.QueueReason = rp.quotas.reason(req.TaskActor)
+				summaries[id] = summary
+			}
+		}
+		ctx.Respond(summaries)
+
+	case sproto.GetAgentDrainStatus:
+		reschedule = false
+		if state, ok := rp.agents[msg.Agent]; ok {
+			ctx.Respond(rp.drainStatus(state))
+		} else {
+			ctx.Respond(errors.Errorf("agent not found: %s", msg.Agent.Address().Local()))
+		}
+
+	case *apiv1.GetResourcePoolQueueRequest:
+		reschedule = false
+		ctx.Respond(&apiv1.GetResourcePoolQueueResponse{Tasks: rp.queue()})
 
 	case GetResourceSummary:
 		reschedule = false
-		ctx.Respond(getResourceSummary(rp.agents))
+		summary := getResourceSummary(rp.agents)
+		summary.userSlots, summary.labelSlots = getQuotaUsage(rp.taskList)
+		ctx.Respond(summary)
 
 	case schedulerTick:
+		if len(rp.reattaching) > 0 {
+			rp.reattach(ctx)
+		}
 		if rp.reschedule {
+			previous := rp.quotas
+			rp.quotas = newQuotaState(rp.config.Quotas, rp.taskList)
 			toAllocate, toRelease := rp.scheduler.Schedule(rp)
+			rp.logQuotaReasons(ctx, previous)
 			for _, req := range toAllocate {
 				rp.allocateResources(ctx, req)
 			}
@@ -260,7 +386,7 @@ func (rp *ResourcePool) Receive(ctx *actor.Context) error {
 			}
 			rp.sendScalingInfo(ctx)
 		}
-		rp.reschedule = false
+		rp.reschedule = rp.protecting
 		reschedule = false
 		actors.NotifyAfter(ctx, actionCoolDown, schedulerTick{})
 
@@ -271,6 +397,19 @@ func (rp *ResourcePool) Receive(ctx *actor.Context) error {
 	return nil
 }
 
+// logQuotaReasons logs the tasks that have started being held back by their quotas since the
+// previous scheduling pass.
+func (rp *ResourcePool) logQuotaReasons(ctx *actor.Context, previous *quotaState) {
+	if rp.quotas == nil {
+		return
+	}
+	for handler, reason := range rp.quotas.reasons {
+		if previous.reason(handler) != reason {
+			ctx.Log().Infof("task %s remains queued: %s", handler.Address(), reason)
+		}
+	}
+}
+
 func (rp *ResourcePool) receiveAgentMsg(ctx *actor.Context) error {
 	switch msg := ctx.Message().(type) {
 	case sproto.AddAgent:
@@ -300,12 +439,77 @@ func (rp *ResourcePool) receiveAgentMsg(ctx *actor.Context) error {
 		ctx.Log().Infof("removing agent: %s", msg.Agent.Address().Local())
 		delete(rp.agents, msg.Agent)
 
+	case sproto.SetAgentDraining:
+		state, ok := rp.agents[msg.Agent]
+		if !ok {
+			ctx.Log().Warnf("cannot drain agent, agent not found: %s", msg.Agent.Address().Local())
+			break
+		}
+		state.draining = msg.Draining
+		if !msg.Draining {
+			ctx.Log().Infof("stopped draining agent: %s", msg.Agent.Address().Local())
+			break
+		}
+		ctx.Log().Infof("draining agent: %s", msg.Agent.Address().Local())
+		for _, req := range rp.tasksOnAgent(state) {
+			// Like the schedulers, draining never releases non-preemptible tasks, which are
+			// killed when asked to release their resources. They block the drain until they exit.
+			if !req.NonPreemptible && !rp.preempting[req.TaskActor] {
+				rp.releaseResource(ctx, req.TaskActor)
+			}
+		}
+
+	case sproto.SetAgentQuarantined:
+		state, ok := rp.agents[msg.Agent]
+		if !ok {
+			ctx.Log().Warnf("cannot quarantine agent, agent not found: %s",
+				msg.Agent.Address().Local())
+			break
+		}
+		state.quarantined = msg.Quarantined
+
 	default:
 		return actor.ErrUnexpectedMessage(ctx)
 	}
 	return nil
 }
 
+// tasksOnAgent returns the tasks that hold resources on the agent.
+func (rp *ResourcePool) tasksOnAgent(agent *agentState) []*sproto.AllocateRequest {
+	var reqs []*sproto.AllocateRequest
+	for it := rp.taskList.iterator(); it.next(); {
+		req := it.value()
+		assigned := rp.taskList.GetAllocations(req.TaskActor)
+		if assigned == nil {
+			continue
+		}
+		for _, allocation := range assigned.Allocations {
+			if c, ok := allocation.(*containerAllocation); ok && c.agent.handler == agent.handler {
+				reqs = append(reqs, req)
+				break
+			}
+		}
+	}
+	return reqs
+}
+
+// drainStatus reports the tasks that still hold resources on the agent, including the
+// non-preemptible tasks that draining waits for. A draining agent is drained once no tasks hold
+// resources on it and all of its containers are gone.
+func (rp *ResourcePool) drainStatus(agent *agentState) *apiv1.GetAgentDrainStatusResponse {
+	resp := &apiv1.GetAgentDrainStatusResponse{Draining: agent.draining}
+	for _, req := range rp.tasksOnAgent(agent) {
+		resp.Tasks = append(resp.Tasks, &apiv1.DrainingTask{
+			TaskId:         string(req.ID),
+			Name:           req.Name,
+			Releasing:      rp.preempting[req.TaskActor],
+			NonPreemptible: req.NonPreemptible,
+		})
+	}
+	resp.Drained = agent.draining && len(resp.Tasks) == 0 && agent.idle()
+	return resp
+}
+
 func (rp *ResourcePool) receiveRequestMsg(ctx *actor.Context) error {
 	switch msg := ctx.Message().(type) {
 	case groupActorStopped:
@@ -337,6 +541,10 @@ func (rp *ResourcePool) receiveRequestMsg(ctx *actor.Context) error {
 	case sproto.ResourcesReleased:
 		rp.resourcesReleased(ctx, msg.TaskActor)
 
+	case taskActorStopped:
+		rp.resourcesReleased(ctx, msg.Ref)
+		rp.taskList.forgetPreemption(msg.Ref)
+
 	default:
 		return actor.ErrUnexpectedMessage(ctx)
 	}
@@ -349,6 +557,8 @@ type containerAllocation struct {
 	container *container
 	agent     *agentState
 	devices   []device.Device
+	// reattached is set for the running containers that restored tasks reattach to.
+	reattached bool
 }
 
 // Summary summarizes a container allocation.
@@ -360,9 +570,16 @@ func (c containerAllocation) Summary() sproto.ContainerSummary {
 	}
 }
 
-// StartContainer notifies the agent to start a container.
+// StartContainer notifies the agent to start a container. The running containers that tasks
+// reattach to are handed over to the tasks instead.
 func (c containerAllocation) Start(ctx *actor.Context, spec image.TaskSpec) {
 	handler := c.agent.handler
+	if c.reattached {
+		ctx.Tell(handler, sproto.ReattachTaskContainer{
+			TaskActor: c.req.TaskActor, ContainerID: c.container.id,
+		})
+		return
+	}
 	spec.ContainerID = string(c.container.id)
 	spec.TaskID = string(c.req.ID)
 	spec.Devices = c.devices
@@ -21,6 +21,7 @@ type ResourcePoolConfig struct {
 	Provider                 *provisioner.Config `json:"provider"`
 	Scheduler                *SchedulerConfig    `json:"scheduler,omitempty"`
 	MaxCPUContainersPerAgent int                 `json:"max_cpu_containers_per_agent"`
+	Quotas                   *QuotaConfig        `json:"quotas,omitempty"`
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
@@ -2,6 +2,7 @@ package resourcemanagers
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -10,6 +11,8 @@ import (
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/actor"
 	cproto "github.com/determined-ai/determined/master/pkg/container"
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
 )
 
 func TestCleanUpTaskWhenTaskActorStopsWithError(t *testing.T) {
@@ -205,3 +208,186 @@ func TestSettingGroupPriority(t *testing.T) {
 	assert.Equal(t, *rp.groups[groupRefOne].priority, updatedPriority)
 	assert.Equal(t, *rp.groups[groupRefTwo].priority, defaultPriority)
 }
+
+func TestDrainAgent(t *testing.T) {
+	system := actor.NewSystem(t.Name())
+	agents := []*mockAgent{
+		{id: "agent1", slots: 1},
+		{id: "agent2", slots: 1},
+	}
+	tasks := []*mockTask{
+		{id: "task1", slotsNeeded: 1, allocatedAgent: agents[0]},
+		{id: "task2", slotsNeeded: 1, allocatedAgent: agents[1]},
+	}
+	rp, ref := setupResourcePool(t, system, nil, tasks, nil, agents)
+	agent1 := system.Get(actor.Addr("agent1"))
+	agent2 := system.Get(actor.Addr("agent2"))
+	task1 := system.Get(actor.Addr("task1"))
+
+	drainStatus := func(agent *actor.Ref) *apiv1.GetAgentDrainStatusResponse {
+		resp := system.Ask(ref, sproto.GetAgentDrainStatus{Agent: agent}).Get()
+		return resp.(*apiv1.GetAgentDrainStatusResponse)
+	}
+
+	status := drainStatus(agent1)
+	assert.Equal(t, status.Draining, false)
+	assert.Equal(t, status.Drained, false)
+	assert.Equal(t, len(status.Tasks), 1)
+	assert.Equal(t, status.Tasks[0].TaskId, "task1")
+
+	system.Tell(ref, sproto.SetAgentDraining{Agent: agent1, Draining: true})
+	system.Ask(ref, actor.Ping{}).Get()
+	assert.Assert(t, rp.agents[agent1].draining)
+	assert.Assert(t, !notDrainingSatisfied(&sproto.AllocateRequest{}, rp.agents[agent1]))
+	assert.Assert(t, notDrainingSatisfied(&sproto.AllocateRequest{}, rp.agents[agent2]))
+
+	// Wait for the task to release its resources back to the resource pool.
+	system.Ask(task1, actor.Ping{}).Get()
+	status = drainStatus(agent1)
+	assert.Equal(t, status.Draining, true)
+	assert.Equal(t, status.Drained, true)
+	assert.Equal(t, len(status.Tasks), 0)
+
+	status = drainStatus(agent2)
+	assert.Equal(t, status.Draining, false)
+	assert.Equal(t, len(status.Tasks), 1)
+
+	system.Tell(ref, sproto.SetAgentDraining{Agent: agent1, Draining: false})
+	system.Ask(ref, actor.Ping{}).Get()
+	assert.Assert(t, !rp.agents[agent1].draining)
+}
+
+func TestDrainAgentWaitsForNonPreemptibleTasks(t *testing.T) {
+	system := actor.NewSystem(t.Name())
+	agents := []*mockAgent{{id: "agent1", slots: 2}}
+	tasks := []*mockTask{
+		{id: "task1", slotsNeeded: 1, allocatedAgent: agents[0]},
+		{id: "task2", slotsNeeded: 1, allocatedAgent: agents[0], nonPreemptible: true},
+	}
+	_, ref := setupResourcePool(t, system, nil, tasks, nil, agents)
+	agent1 := system.Get(actor.Addr("agent1"))
+	task1 := system.Get(actor.Addr("task1"))
+	task2 := system.Get(actor.Addr("task2"))
+
+	drainStatus := func() *apiv1.GetAgentDrainStatusResponse {
+		resp := system.Ask(ref, sproto.GetAgentDrainStatus{Agent: agent1}).Get()
+		return resp.(*apiv1.GetAgentDrainStatusResponse)
+	}
+
+	system.Tell(ref, sproto.SetAgentDraining{Agent: agent1, Draining: true})
+	system.Ask(ref, actor.Ping{}).Get()
+	system.Ask(task1, actor.Ping{}).Get()
+	system.Ask(task2, actor.Ping{}).Get()
+
+	// Only the preemptible task is asked to release its resources.
+	status := drainStatus()
+	assert.Equal(t, status.Drained, false)
+	assert.Equal(t, len(status.Tasks), 1)
+	assert.Equal(t, status.Tasks[0].TaskId, "task2")
+	assert.Equal(t, status.Tasks[0].Releasing, false)
+	assert.Equal(t, status.Tasks[0].NonPreemptible, true)
+
+	// The drain completes once the non-preemptible task exits on its own.
+	system.Ask(task2, SendResourcesReleasedToResourceManager{}).Get()
+	status = drainStatus()
+	assert.Equal(t, len(status.Tasks), 0)
+	assert.Equal(t, status.Drained, true)
+}
+
+func TestQuarantineAgent(t *testing.T) {
+	system := actor.NewSystem(t.Name())
+	agents := []*mockAgent{
+		{id: "agent1", slots: 2},
+		{id: "agent2", slots: 1},
+	}
+	tasks := []*mockTask{
+		{id: "task1", slotsNeeded: 1, allocatedAgent: agents[0]},
+	}
+	rp, ref := setupResourcePool(t, system, nil, tasks, nil, agents)
+	agent1 := system.Get(actor.Addr("agent1"))
+	agent2 := system.Get(actor.Addr("agent2"))
+	task1 := system.Get(actor.Addr("task1"))
+
+	system.Tell(ref, sproto.SetAgentQuarantined{Agent: agent1, Quarantined: true})
+	system.Ask(ref, actor.Ping{}).Get()
+	system.Ask(task1, actor.Ping{}).Get()
+	assert.Assert(t, rp.agents[agent1].quarantined)
+	assert.Assert(t, !notQuarantinedSatisfied(&sproto.AllocateRequest{}, rp.agents[agent1]))
+	assert.Assert(t, notQuarantinedSatisfied(&sproto.AllocateRequest{}, rp.agents[agent2]))
+	assert.DeepEqual(t, capacityByAgentLabel(rp.agents), map[string]int{"": 1})
+
+	// Unlike draining, quarantining leaves the tasks on the agent running.
+	assert.Assert(t, rp.taskList.GetAllocations(task1) != nil)
+	assert.Equal(t, len(rp.drainStatus(rp.agents[agent1]).Tasks), 1)
+
+	system.Tell(ref, sproto.SetAgentQuarantined{Agent: agent1, Quarantined: false})
+	system.Ask(ref, actor.Ping{}).Get()
+	assert.Assert(t, !rp.agents[agent1].quarantined)
+}
+
+// setupReattachPool starts a resource pool whose restored tasks wait for their containers for the
+// given time.
+func setupReattachPool(
+	t *testing.T, system *actor.System, reattachWait time.Duration, mockTasks []*mockTask,
+) (*ResourcePool, *actor.Ref) {
+	config := &ResourcePoolConfig{
+		PoolName:  "pool",
+		Scheduler: &SchedulerConfig{FairShare: &FairShareSchedulerConfig{}, FittingPolicy: best},
+	}
+	rp := NewResourcePool(
+		config, nil, MakeScheduler(config.Scheduler), MakeFitFunction(config.Scheduler.FittingPolicy))
+	rp.taskList, rp.groups, rp.agents = setupSchedulerStates(t, system, mockTasks, nil, nil)
+	rp.reattachWait = reattachWait
+	ref, created := system.ActorOf(actor.Addr(rp.config.PoolName), rp)
+	assert.Assert(t, created)
+	for _, task := range mockTasks {
+		task.rmRef = ref
+	}
+	return rp, ref
+}
+
+func TestReattachContainers(t *testing.T) {
+	system := actor.NewSystem(t.Name())
+	c := cproto.Container{
+		ID: cproto.NewID(), State: cproto.Running,
+		Devices: []device.Device{{ID: 0, Type: device.GPU}},
+	}
+	tasks := []*mockTask{{id: "task", slotsNeeded: 1, reattachContainers: []cproto.ID{c.ID}}}
+	rp, ref := setupReattachPool(t, system, time.Hour, tasks)
+	agent, _ := system.ActorOf(actor.Addr("agent"), &mockAgent{id: "agent", slots: 1})
+	task := system.Get(actor.Addr("task"))
+
+	system.Tell(ref, sproto.AddAgent{Agent: agent, Containers: []cproto.Container{c}})
+	system.Ask(task, SendRequestResourcesToResourceManager{}).Get()
+	system.Ask(ref, actor.Ping{}).Get()
+	// The container is only reattached once the agent adds its device.
+	assert.Assert(t, rp.taskList.GetAllocations(task) == nil)
+
+	system.Tell(ref, sproto.AddDevice{
+		DeviceID: sproto.DeviceID{Agent: agent, Device: c.Devices[0]}, ContainerID: &c.ID,
+	})
+	system.Tell(ref, schedulerTick{})
+	system.Ask(ref, actor.Ping{}).Get()
+	allocated := rp.taskList.GetAllocations(task)
+	assert.Assert(t, allocated != nil)
+	assert.Assert(t, allocated.Reattached)
+	assert.Equal(t, len(allocated.Allocations), 1)
+	assert.Equal(t, allocated.Allocations[0].Summary().ID, c.ID)
+	assert.Equal(t, len(rp.reattaching), 0)
+	assert.Equal(t, len(rp.agents[agent].reattachable), 0)
+}
+
+func TestReattachContainersTimeout(t *testing.T) {
+	system := actor.NewSystem(t.Name())
+	tasks := []*mockTask{{id: "task", slotsNeeded: 1, reattachContainers: []cproto.ID{"gone"}}}
+	rp, ref := setupReattachPool(t, system, 0, tasks)
+	task := system.Get(actor.Addr("task"))
+
+	// Past the deadline, the task is scheduled like any other.
+	system.Ask(task, SendRequestResourcesToResourceManager{}).Get()
+	system.Ask(ref, actor.Ping{}).Get()
+	assert.Equal(t, len(rp.reattaching), 0)
+	req, ok := rp.taskList.GetTaskByHandler(task)
+	assert.Assert(t, ok)
+	assert.Equal(t, len(req.ReattachContainers), 0)
+}
@@ -16,7 +16,8 @@ func NewRoundRobinScheduler() Scheduler {
 }
 
 func (p *roundRobinScheduler) Schedule(rp *ResourcePool) ([]*sproto.AllocateRequest, []*actor.Ref) {
-	return roundRobinSchedule(rp.taskList, rp.groups, rp.agents, rp.fittingMethod)
+	return roundRobinSchedule(
+		rp.quotas.filter(rp.taskList, nil), rp.groups, rp.agents, rp.fittingMethod)
 }
 
 func roundRobinSchedule(
@@ -19,9 +19,11 @@ func MakeScheduler(config *SchedulerConfig) Scheduler {
 	case priorityScheduling:
 		return NewPriorityScheduler(config)
 	case fairShareScheduling:
-		return NewFairShareScheduler()
+		return NewFairShareScheduler(config)
 	case roundRobinScheduling:
 		return NewRoundRobinScheduler()
+	case backfillScheduling:
+		return NewBackfillScheduler()
 	default:
 		panic(fmt.Sprintf("invalid scheduler: %s", config.GetType()))
 	}
@@ -14,9 +14,11 @@ const (
 	fairShareScheduling  = "fair_share"
 	priorityScheduling   = "priority"
 	roundRobinScheduling = "round_robin"
+	backfillScheduling   = "backfill"
 
 	best             = "best"
 	worst            = "worst"
+	topology         = "topology"
 	defaultFitPolicy = best
 )
 
@@ -33,6 +35,7 @@ type SchedulerConfig struct {
 	FairShare     *FairShareSchedulerConfig  `union:"type,fair_share" json:"-"`
 	Priority      *PrioritySchedulerConfig   `union:"type,priority" json:"-"`
 	RoundRobin    *RoundRobinSchedulerConfig `union:"type,round_robin" json:"-"`
+	Backfill      *BackfillSchedulerConfig   `union:"type,backfill" json:"-"`
 	FittingPolicy string                     `json:"fitting_policy"`
 }
 
@@ -53,7 +56,7 @@ func (s *SchedulerConfig) UnmarshalJSON(data []byte) error {
 	}
 
 	// Fill in the default
-	if s.FairShare == nil && s.Priority == nil && s.RoundRobin == nil {
+	if s.FairShare == nil && s.Priority == nil && s.RoundRobin == nil && s.Backfill == nil {
 		s.FairShare = &FairShareSchedulerConfig{}
 	}
 	if s.Priority != nil && s.Priority.DefaultPriority == nil {
@@ -71,7 +74,7 @@ func (s *SchedulerConfig) UnmarshalJSON(data []byte) error {
 func (s SchedulerConfig) Validate() []error {
 	return []error{
 		check.Contains(
-			s.FittingPolicy, []interface{}{best, worst}, "invalid fitting policy",
+			s.FittingPolicy, []interface{}{best, worst, topology}, "invalid fitting policy",
 		),
 	}
 }
@@ -85,24 +88,57 @@ func (s *SchedulerConfig) GetType() string {
 		return priorityScheduling
 	case s.RoundRobin != nil:
 		return roundRobinScheduling
+	case s.Backfill != nil:
+		return backfillScheduling
 	default:
 		panic("neither scheduler type configured")
 	}
 }
 
 // FairShareSchedulerConfig holds configurations for the fair share scheduler.
-type FairShareSchedulerConfig struct{}
+type FairShareSchedulerConfig struct {
+	// MinRunTime is how long a task runs before it can be preempted.
+	MinRunTime *model.Duration `json:"min_run_time"`
+	// PreemptionCooldown is how long after a task was preempted it can be preempted again.
+	PreemptionCooldown *model.Duration `json:"preemption_cooldown"`
+}
+
+// Validate implements the check.Validatable interface.
+func (f FairShareSchedulerConfig) Validate() []error {
+	return validatePreemptionPolicy(f.MinRunTime, f.PreemptionCooldown)
+}
 
 // PrioritySchedulerConfig holds the configurations for the priority scheduler.
 type PrioritySchedulerConfig struct {
 	Preemption      bool `json:"preemption"`
 	DefaultPriority *int `json:"default_priority"`
+	// MinRunTime is how long a task runs before it can be preempted.
+	MinRunTime *model.Duration `json:"min_run_time"`
+	// PreemptionCooldown is how long after a task was preempted it can be preempted again.
+	PreemptionCooldown *model.Duration `json:"preemption_cooldown"`
 }
 
 // RoundRobinSchedulerConfig holds the configurations for the round robing scheduler.
 type RoundRobinSchedulerConfig struct{}
 
+// BackfillSchedulerConfig holds the configurations for the backfill scheduler.
+type BackfillSchedulerConfig struct{}
+
 // Validate implements the check.Validatable interface.
 func (p PrioritySchedulerConfig) Validate() []error {
-	return model.ValidatePrioritySetting(p.DefaultPriority)
+	return append(model.ValidatePrioritySetting(p.DefaultPriority),
+		validatePreemptionPolicy(p.MinRunTime, p.PreemptionCooldown)...)
+}
+
+func validatePreemptionPolicy(minRunTime, cooldown *model.Duration) []error {
+	var errs []error
+	if minRunTime != nil {
+		errs = append(errs, check.GreaterThanOrEqualTo(
+			int64(*minRunTime), int64(0), "min_run_time must be >= 0"))
+	}
+	if cooldown != nil {
+		errs = append(errs, check.GreaterThanOrEqualTo(
+			int64(*cooldown), int64(0), "preemption_cooldown must be >= 0"))
+	}
+	return errs
 }
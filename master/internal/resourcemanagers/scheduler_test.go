@@ -2,6 +2,7 @@ package resourcemanagers
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -56,6 +57,11 @@ type mockTask struct {
 	resourcePool     string
 	allocatedAgent   *mockAgent
 	containerStarted bool
+	expectedRuntime  time.Duration
+	user             string
+	experimentLabels []string
+	// reattachContainers are the containers that the task ran before a master restart.
+	reattachContainers []cproto.ID
 }
 
 func (t *mockTask) Receive(ctx *actor.Context) error {
@@ -71,6 +77,8 @@ func (t *mockTask) Receive(ctx *actor.Context) error {
 			Label:          t.label,
 			ResourcePool:   t.resourcePool,
 			TaskActor:      ctx.Self(),
+
+			ReattachContainers: t.reattachContainers,
 		}
 		if t.group == nil {
 			task.Group = ctx.Self()
@@ -116,6 +124,8 @@ type mockAgent struct {
 	slotsUsed             int
 	maxZeroSlotContainers int
 	zeroSlotContainers    int
+	// topology holds the topology of each device of the agent, if it is known.
+	topology []device.Topology
 }
 
 func newMockAgent(
@@ -142,6 +152,7 @@ func (m *mockAgent) Receive(ctx *actor.Context) error {
 	case actor.PostStop:
 	case sproto.StartTaskContainer:
 	case sproto.KillTaskContainer:
+	case sproto.ReattachTaskContainer:
 	default:
 		return actor.ErrUnexpectedMessage(ctx)
 	}
@@ -314,7 +325,11 @@ func setupSchedulerStates(
 			maxZeroSlotContainers: mockAgent.maxZeroSlotContainers,
 		}
 		for i := 0; i < mockAgent.slots; i++ {
-			agent.devices[device.Device{ID: i}] = nil
+			d := device.Device{ID: i}
+			if mockAgent.topology != nil {
+				d.Topology = mockAgent.topology[i]
+			}
+			agent.devices[d] = nil
 		}
 		agents[ref] = agent
 	}
@@ -343,11 +358,14 @@ func setupSchedulerStates(
 		groups[ref] = &group{handler: ref}
 
 		req := &sproto.AllocateRequest{
-			ID:             mockTask.id,
-			SlotsNeeded:    mockTask.slotsNeeded,
-			Label:          mockTask.label,
-			TaskActor:      ref,
-			NonPreemptible: mockTask.nonPreemptible,
+			ID:               mockTask.id,
+			SlotsNeeded:      mockTask.slotsNeeded,
+			Label:            mockTask.label,
+			TaskActor:        ref,
+			NonPreemptible:   mockTask.nonPreemptible,
+			ExpectedRuntime:  mockTask.expectedRuntime,
+			User:             mockTask.user,
+			ExperimentLabels: mockTask.experimentLabels,
 		}
 		if mockTask.group == nil {
 			req.Group = ref
@@ -94,7 +94,10 @@ func setupAgentResourceManager(
 	system.Ask(ref, actor.Ping{}).Get()
 
 	logrus.Infof("initializing endpoints for agents")
-	agent.Initialize(system, echo, opts)
+	agentRM := config.ResourceManager.AgentRM
+	agent.Initialize(
+		system, echo, opts, agentRM.agentReconnectWait(), agentRM.AgentQuarantineFailures,
+		agentRM.agentQuarantineTaskFailures())
 	return ref
 }
 
@@ -18,6 +18,8 @@ type TaskSummary struct {
 	Containers     []sproto.ContainerSummary `json:"containers"`
 	SchedulerType  string                    `json:"scheduler_type"`
 	Priority       *int                      `json:"priority"`
+	// QueueReason is why the task is being held back from scheduling, if it is known.
+	QueueReason string `json:"queue_reason,omitempty"`
 }
 
 func newTaskSummary(
@@ -52,8 +54,9 @@ func newTaskSummary(
 // newAgentSummary returns a new immutable view of the agent.
 func newAgentSummary(state *agentState) sproto.AgentSummary {
 	return sproto.AgentSummary{
-		Name:   state.handler.Address().Local(),
-		IsIdle: state.idle(),
+		Name:       state.handler.Address().Local(),
+		IsIdle:     state.idle(),
+		IsDraining: state.draining,
 	}
 }
 
@@ -90,6 +93,9 @@ type ResourceSummary struct {
 	numActiveSlots         int
 	maxNumCPUContainers    int
 	numActiveCPUContainers int
+	// userSlots and labelSlots are the slots held by the tasks of each user and experiment label.
+	userSlots  map[string]int
+	labelSlots map[string]int
 }
 
 func getResourceSummary(
@@ -2,6 +2,7 @@ package resourcemanagers
 
 import (
 	"strings"
+	"time"
 
 	"github.com/emirpasic/gods/sets/treeset"
 
@@ -15,6 +16,11 @@ type taskList struct {
 	taskByHandler map[*actor.Ref]*sproto.AllocateRequest
 	taskByID      map[sproto.TaskID]*sproto.AllocateRequest
 	allocations   map[*actor.Ref]*sproto.ResourcesAllocated
+	// startTimes holds when each task was allocated its resources. preemptTimes holds when each
+	// task was last asked to release them; it outlives the task so that a task that is preempted
+	// and queued again keeps its history, until the actor of the task stops.
+	startTimes   map[*actor.Ref]time.Time
+	preemptTimes map[*actor.Ref]time.Time
 }
 
 func newTaskList() *taskList {
@@ -23,6 +29,8 @@ func newTaskList() *taskList {
 		taskByHandler: make(map[*actor.Ref]*sproto.AllocateRequest),
 		taskByID:      make(map[sproto.TaskID]*sproto.AllocateRequest),
 		allocations:   make(map[*actor.Ref]*sproto.ResourcesAllocated),
+		startTimes:    make(map[*actor.Ref]time.Time),
+		preemptTimes:  make(map[*actor.Ref]time.Time),
 	}
 }
 
@@ -55,6 +63,20 @@ func (l *taskList) AddTask(req *sproto.AllocateRequest) bool {
 	return true
 }
 
+// addTaskFrom adds the task to the list along with its allocations and times in the other list.
+func (l *taskList) addTaskFrom(other *taskList, req *sproto.AllocateRequest) {
+	l.AddTask(req)
+	if assigned := other.GetAllocations(req.TaskActor); assigned != nil {
+		l.allocations[req.TaskActor] = assigned
+	}
+	if start, ok := other.startTimes[req.TaskActor]; ok {
+		l.startTimes[req.TaskActor] = start
+	}
+	if preempted, ok := other.preemptTimes[req.TaskActor]; ok {
+		l.preemptTimes[req.TaskActor] = preempted
+	}
+}
+
 func (l *taskList) RemoveTaskByHandler(handler *actor.Ref) *sproto.AllocateRequest {
 	req, ok := l.GetTaskByHandler(handler)
 	if !ok {
@@ -65,6 +87,7 @@ func (l *taskList) RemoveTaskByHandler(handler *actor.Ref) *sproto.AllocateReque
 	delete(l.taskByHandler, handler)
 	delete(l.taskByID, req.ID)
 	delete(l.allocations, handler)
+	delete(l.startTimes, handler)
 	return req
 }
 
@@ -74,10 +97,50 @@ func (l *taskList) GetAllocations(handler *actor.Ref) *sproto.ResourcesAllocated
 
 func (l *taskList) SetAllocations(handler *actor.Ref, assigned *sproto.ResourcesAllocated) {
 	l.allocations[handler] = assigned
+	l.startTimes[handler] = time.Now()
 }
 
 func (l *taskList) RemoveAllocations(handler *actor.Ref) {
 	delete(l.allocations, handler)
+	delete(l.startTimes, handler)
+}
+
+// runtime returns how long the task has held its resources, if it holds any.
+func (l *taskList) runtime(handler *actor.Ref, now time.Time) (time.Duration, bool) {
+	start, ok := l.startTimes[handler]
+	if !ok {
+		return 0, false
+	}
+	return now.Sub(start), true
+}
+
+// setPreempted records that the task was asked to release its resources.
+func (l *taskList) setPreempted(handler *actor.Ref, now time.Time) {
+	l.preemptTimes[handler] = now
+}
+
+// sincePreempted returns how long ago the task was last asked to release its resources, if it
+// ever was.
+func (l *taskList) sincePreempted(handler *actor.Ref, now time.Time) (time.Duration, bool) {
+	preempted, ok := l.preemptTimes[handler]
+	if !ok {
+		return 0, false
+	}
+	return now.Sub(preempted), true
+}
+
+// forgetPreemption drops the preemption of the task, once its actor stopped.
+func (l *taskList) forgetPreemption(handler *actor.Ref) {
+	delete(l.preemptTimes, handler)
+}
+
+// forgetPreemptionsBefore drops the preemptions that happened before the given time.
+func (l *taskList) forgetPreemptionsBefore(before time.Time) {
+	for handler, preempted := range l.preemptTimes {
+		if preempted.Before(before) {
+			delete(l.preemptTimes, handler)
+		}
+	}
 }
 
 type taskIterator struct{ it treeset.Iterator }
@@ -0,0 +1,70 @@
+package resourcemanagers
+
+import (
+	"sort"
+
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+// topologyLevel is how close to each other a set of devices on an agent is.
+type topologyLevel int
+
+const (
+	topologySpread topologyLevel = iota
+	topologyNUMANode
+	topologyNVLinkIsland
+)
+
+// localFreeDevices returns the given number of free devices of the agent, chosen to be as close
+// to each other as possible, along with how close they are. Of the NVLink islands or NUMA nodes
+// that can host the devices, the one with the fewest free devices is used so that larger ones are
+// kept for larger tasks. Devices are otherwise taken in the order of their IDs.
+func (a *agentState) localFreeDevices(slots int) ([]device.Device, topologyLevel) {
+	free := make([]device.Device, 0, len(a.devices))
+	for d, id := range a.devices {
+		if id == nil {
+			free = append(free, d)
+		}
+	}
+	sort.Slice(free, func(i, j int) bool { return free[i].ID < free[j].ID })
+	if len(free) < slots {
+		return nil, topologySpread
+	}
+
+	levels := []struct {
+		level topologyLevel
+		key   func(device.Topology) (int, bool)
+	}{
+		{topologyNVLinkIsland, func(t device.Topology) (int, bool) {
+			return t.NVLinkIsland, t.HasNVLinkIsland
+		}},
+		{topologyNUMANode, func(t device.Topology) (int, bool) {
+			return t.NUMANode, t.HasNUMANode
+		}},
+	}
+	for _, l := range levels {
+		groups := make(map[int][]device.Device)
+		var keys []int
+		for _, d := range free {
+			key, ok := l.key(d.Topology)
+			if !ok {
+				continue
+			}
+			if _, ok := groups[key]; !ok {
+				keys = append(keys, key)
+			}
+			groups[key] = append(groups[key], d)
+		}
+
+		var best []device.Device
+		for _, key := range keys {
+			if group := groups[key]; len(group) >= slots && (best == nil || len(group) < len(best)) {
+				best = group
+			}
+		}
+		if best != nil {
+			return best[:slots], l.level
+		}
+	}
+	return free[:slots], topologySpread
+}
@@ -0,0 +1,142 @@
+package resourcemanagers
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	cproto "github.com/determined-ai/determined/master/pkg/container"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+// dgxTopology is the topology of an agent with 8 GPUs in two NVLink islands of 4, one per NUMA
+// node.
+var dgxTopology = []device.Topology{
+	nvlinkTopology(0, 0), nvlinkTopology(0, 0), nvlinkTopology(0, 0), nvlinkTopology(0, 0),
+	nvlinkTopology(1, 4), nvlinkTopology(1, 4), nvlinkTopology(1, 4), nvlinkTopology(1, 4),
+}
+
+// numaTopology is the topology of a device on the NUMA node without NVLink connections.
+func numaTopology(node int) device.Topology {
+	return device.Topology{NUMANode: node, HasNUMANode: true}
+}
+
+// nvlinkTopology is the topology of a device on the NUMA node in the NVLink island.
+func nvlinkTopology(node, island int) device.Topology {
+	t := numaTopology(node)
+	t.NVLinkIsland, t.HasNVLinkIsland = island, true
+	return t
+}
+
+// useDevices marks the devices of the agent with the given IDs as used.
+func useDevices(agent *agentState, ids ...int) {
+	used := cproto.NewID()
+	for d := range agent.devices {
+		for _, id := range ids {
+			if d.ID == id {
+				agent.devices[d] = &used
+			}
+		}
+	}
+}
+
+func deviceIDs(devices []device.Device) []int {
+	ids := make([]int, 0, len(devices))
+	for _, d := range devices {
+		ids = append(ids, d.ID)
+	}
+	return ids
+}
+
+func TestLocalFreeDevices(t *testing.T) {
+	pcieTopology := []device.Topology{
+		numaTopology(0), numaTopology(1), numaTopology(0), numaTopology(1),
+	}
+	agents := []*mockAgent{
+		{id: "dgx", slots: 8, topology: dgxTopology},
+		{id: "pcie", slots: 4, topology: pcieTopology},
+	}
+
+	system := actor.NewSystem(t.Name())
+	_, _, agentMap := setupSchedulerStates(t, system, nil, nil, agents)
+	dgx := agentMap[system.Get(actor.Addr("dgx"))]
+	pcie := agentMap[system.Get(actor.Addr("pcie"))]
+
+	// The island with the fewest free devices that can host the task is used.
+	useDevices(dgx, 0)
+	devices, level := dgx.localFreeDevices(2)
+	assert.DeepEqual(t, deviceIDs(devices), []int{1, 2})
+	assert.Equal(t, level, topologyNVLinkIsland)
+	devices, level = dgx.localFreeDevices(4)
+	assert.DeepEqual(t, deviceIDs(devices), []int{4, 5, 6, 7})
+	assert.Equal(t, level, topologyNVLinkIsland)
+	devices, level = dgx.localFreeDevices(6)
+	assert.DeepEqual(t, deviceIDs(devices), []int{1, 2, 3, 4, 5, 6})
+	assert.Equal(t, level, topologySpread)
+
+	// Without NVLink, devices on the same NUMA node are preferred.
+	devices, level = pcie.localFreeDevices(2)
+	assert.DeepEqual(t, deviceIDs(devices), []int{0, 2})
+	assert.Equal(t, level, topologyNUMANode)
+	useDevices(pcie, 0)
+	devices, level = pcie.localFreeDevices(2)
+	assert.DeepEqual(t, deviceIDs(devices), []int{1, 3})
+	assert.Equal(t, level, topologyNUMANode)
+	devices, level = pcie.localFreeDevices(3)
+	assert.DeepEqual(t, deviceIDs(devices), []int{1, 2, 3})
+	assert.Equal(t, level, topologySpread)
+}
+
+func TestTopologyFit(t *testing.T) {
+	agents := []*mockAgent{
+		{id: "fragmented", slots: 8, topology: dgxTopology},
+		{id: "island", slots: 8, topology: dgxTopology},
+	}
+
+	system := actor.NewSystem(t.Name())
+	_, _, agentMap := setupSchedulerStates(t, system, nil, nil, agents)
+	fragmented := agentMap[system.Get(actor.Addr("fragmented"))]
+	island := agentMap[system.Get(actor.Addr("island"))]
+	useDevices(fragmented, 0, 1, 4, 5)
+	useDevices(island, 0, 1, 2, 3)
+
+	req := &sproto.AllocateRequest{SlotsNeeded: 4}
+	assert.Equal(t, BestFit(req, fragmented), BestFit(req, island))
+	assert.Assert(t, TopologyFit(req, island) > TopologyFit(req, fragmented))
+	assert.Assert(t, TopologyFit(req, island) <= 1)
+
+	// Single-slot tasks are not steered by the topology.
+	single := &sproto.AllocateRequest{SlotsNeeded: 1}
+	assert.Equal(t, TopologyFit(single, fragmented), TopologyFit(single, island))
+
+	fits := findFits(req, agentMap, TopologyFit)
+	assert.Equal(t, len(fits), 1)
+	assert.Equal(t, fits[0].Agent, island)
+	devices := fits[0].Agent.allocateFreeDevices(fits[0].Slots, cproto.NewID())
+	assert.DeepEqual(t, deviceIDs(devices), []int{4, 5, 6, 7})
+}
+
+func TestTopologyFitUnknownTopology(t *testing.T) {
+	// Devices of agents that do not report their topology are not treated as co-located.
+	agents := []*mockAgent{
+		{id: "unknown", slots: 8},
+		{id: "dgx", slots: 8, topology: dgxTopology},
+	}
+
+	system := actor.NewSystem(t.Name())
+	_, _, agentMap := setupSchedulerStates(t, system, nil, nil, agents)
+	unknown := agentMap[system.Get(actor.Addr("unknown"))]
+	dgx := agentMap[system.Get(actor.Addr("dgx"))]
+
+	devices, level := unknown.localFreeDevices(4)
+	assert.DeepEqual(t, deviceIDs(devices), []int{0, 1, 2, 3})
+	assert.Equal(t, level, topologySpread)
+
+	req := &sproto.AllocateRequest{SlotsNeeded: 4}
+	assert.Assert(t, TopologyFit(req, dgx) > TopologyFit(req, unknown))
+	fits := findFits(req, agentMap, TopologyFit)
+	assert.Equal(t, len(fits), 1)
+	assert.Equal(t, fits[0].Agent, dgx)
+}
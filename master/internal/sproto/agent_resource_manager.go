@@ -18,10 +18,12 @@ type DeviceID struct {
 
 // Message protocol from an agent actor to the default resource manager.
 type (
-	// AddAgent adds the agent to the cluster.
+	// AddAgent adds the agent to the cluster. Containers are the containers that the agent ran
+	// for a previous master, which the tasks restored by this master may reattach to.
 	AddAgent struct {
-		Agent *actor.Ref
-		Label string
+		Agent      *actor.Ref
+		Label      string
+		Containers []cproto.Container
 	}
 	// AddDevice makes the device immediately available for scheduling.
 	AddDevice struct {
@@ -41,6 +43,23 @@ type (
 	RemoveAgent struct {
 		Agent *actor.Ref
 	}
+	// SetAgentDraining starts or stops draining the agent. No tasks are scheduled on a draining
+	// agent, and the tasks that run on it are asked to release their resources.
+	SetAgentDraining struct {
+		Agent    *actor.Ref
+		Draining bool
+	}
+	// SetAgentQuarantined starts or stops quarantining the agent. No tasks are scheduled on a
+	// quarantined agent, but the tasks that run on it keep running.
+	SetAgentQuarantined struct {
+		Agent       *actor.Ref
+		Quarantined bool
+	}
+	// GetAgentDrainStatus returns the drain progress of the agent as an
+	// *apiv1.GetAgentDrainStatusResponse.
+	GetAgentDrainStatus struct {
+		Agent *actor.Ref
+	}
 )
 
 // Message protocol from the default resource manager to an agent actor.
@@ -54,12 +73,19 @@ type (
 	KillTaskContainer struct {
 		ContainerID cproto.ID
 	}
+	// ReattachTaskContainer notifies the agent to hand the container that it ran for a previous
+	// master over to the task.
+	ReattachTaskContainer struct {
+		TaskActor   *actor.Ref
+		ContainerID cproto.ID
+	}
 )
 
 // AgentSummary contains information about an agent for external display.
 type AgentSummary struct {
-	Name   string
-	IsIdle bool
+	Name       string
+	IsIdle     bool
+	IsDraining bool
 }
 
 // ScalingInfo describes the information that is needed for scaling.
@@ -1,9 +1,12 @@
 package sproto
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 
 	"github.com/determined-ai/determined/master/pkg/actor"
+	cproto "github.com/determined-ai/determined/master/pkg/container"
 	"github.com/determined-ai/determined/master/pkg/tasks"
 )
 
@@ -20,6 +23,17 @@ type (
 		ResourcePool        string
 		FittingRequirements FittingRequirements
 		TaskActor           *actor.Ref
+		// User and ExperimentLabels are the owner and the experiment labels of the task, which the
+		// resource pool quotas apply to.
+		User             string
+		ExperimentLabels []string
+		// ExpectedRuntime is how long the task is expected to hold its resources once they are
+		// allocated; zero means that it is unknown.
+		ExpectedRuntime time.Duration
+		// ReattachContainers are the containers, in the order of their ranks, that a task that was
+		// restored after a master restart ran before the restart. The task is allocated these
+		// containers if their agents announce them soon enough after the restart.
+		ReattachContainers []cproto.ID
 	}
 	// ResourcesReleased notifies resource providers to return resources from a task.
 	ResourcesReleased struct {
@@ -43,6 +57,9 @@ type (
 		ID           TaskID
 		ResourcePool string
 		Allocations  []Allocation
+		// Reattached is set when the allocations are the running containers that the task asked
+		// to reattach to.
+		Reattached bool
 	}
 	// ReleaseResources notifies the task actor to release resources.
 	ReleaseResources struct {
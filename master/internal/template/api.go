@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/context"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/pkg/model"
 )
@@ -47,8 +48,9 @@ func (m *manager) put(c echo.Context) (interface{}, error) {
 	if err := yaml.Unmarshal(body, make(map[interface{}]interface{})); err != nil {
 		return nil, errors.Wrap(err, "invalid YAML for template")
 	}
+	owner := c.(*context.DetContext).MustGetUser().ID
 	return nil, errors.Wrapf(
-		m.db.UpsertTemplate(&model.Template{Name: name, Config: body}),
+		m.db.UpsertTemplate(&model.Template{Name: name, Config: body, OwnerID: &owner}),
 		"error putting template %q", name)
 }
 
@@ -179,6 +179,11 @@ type (
 		TerminationSent            bool `json:"termination_sent"`
 		CancelUnready              bool `json:"cancel_unready"`
 		Killed                     bool `json:"killed"`
+
+		// RendezvousContainers are the containers of the current run in rank order, once they
+		// have gone through the rendezvous. A trial restored by a restarted master reattaches to
+		// them instead of starting over from its latest checkpoint.
+		RendezvousContainers []cproto.ID `json:"rendezvous_containers"`
 	}
 
 	// trial is an actor which is responsible for handling:
@@ -224,9 +229,12 @@ type (
 		terminatedContainers       map[cproto.ID]terminatedContainerWithState
 		// tracks if allReady check has passed successfully.
 		allReadySucceeded bool
+		// a completed workload resent by a reattached container before all containers connected.
+		resentWorkload *workload.CompletedMessage
 
 		agentUserGroup *model.AgentUserGroup
 		taskSpec       *tasks.TaskSpec
+		owner          string
 		privateKey     []byte
 		publicKey      []byte
 	}
@@ -266,6 +274,7 @@ func newTrial(
 
 		agentUserGroup: exp.agentUserGroup,
 		taskSpec:       exp.taskSpec,
+		owner:          exp.owner,
 	}
 }
 
@@ -340,6 +349,15 @@ func (t *trial) Receive(ctx *actor.Context) error {
 			slotsNeeded := t.experiment.Config.Resources.SlotsPerTrial
 			label := t.experiment.Config.Resources.AgentLabel
 			resourcePool := t.experiment.Config.Resources.ResourcePool
+			var expectedRuntime time.Duration
+			if runtime := t.experiment.Config.Resources.ExpectedRuntime; runtime != nil {
+				expectedRuntime = time.Duration(*runtime)
+			}
+			var labels []string
+			for label := range t.experiment.Config.Labels {
+				labels = append(labels, label)
+			}
+			sort.Strings(labels)
 			var name string
 			if t.idSet {
 				name = fmt.Sprintf("Trial %d (Experiment %d)", t.id, t.experiment.ID)
@@ -358,7 +376,12 @@ func (t *trial) Receive(ctx *actor.Context) error {
 				FittingRequirements: sproto.FittingRequirements{
 					SingleAgent: false,
 				},
-				TaskActor: ctx.Self(),
+				TaskActor:        ctx.Self(),
+				User:             t.owner,
+				ExperimentLabels: labels,
+				ExpectedRuntime:  expectedRuntime,
+
+				ReattachContainers: t.RendezvousContainers,
 			}
 			if err := ctx.Ask(t.rm, *t.task).Error(); err != nil {
 				ctx.Log().Error(err)
@@ -384,6 +407,9 @@ func (t *trial) runningReceive(ctx *actor.Context) error {
 		return t.processAPIMsg(ctx)
 
 	case workload.CompletedMessage:
+		if msg.Type == workload.ResentCompletedMessageType {
+			return t.processResentWorkload(ctx, msg)
+		}
 		if err := t.processCompletedWorkload(ctx, msg); err != nil {
 			return err
 		}
@@ -526,6 +552,23 @@ func (t *trial) processAllocated(
 
 	t.allocations = msg.Allocations
 
+	if msg.Reattached {
+		ctx.Log().Info("reattaching to the trial containers of the previous master")
+		for rank, id := range t.RendezvousContainers {
+			t.containerRanks[id] = rank
+		}
+		for _, a := range msg.Allocations {
+			a.Start(ctx, *t.taskSpec)
+		}
+		return nil
+	} else if len(t.RendezvousContainers) > 0 {
+		ctx.Log().Info("the trial containers of the previous master are gone, restarting trial")
+		t.RendezvousContainers = nil
+		if err := t.reset(); err != nil {
+			return errors.Wrap(err, "failed to reset trial")
+		}
+	}
+
 	if len(t.privateKey) == 0 {
 		generatedKeys, err := ssh.GenerateKey(nil)
 		if err != nil {
@@ -711,6 +754,25 @@ func (t *trial) processCompletedWorkload(ctx *actor.Context, msg workload.Comple
 	}
 }
 
+// processResentWorkload handles a completed workload that a reattached container resends because
+// the previous master may have missed it.
+func (t *trial) processResentWorkload(ctx *actor.Context, msg workload.CompletedMessage) error {
+	if !t.allReady(ctx) {
+		t.resentWorkload = &msg
+		return nil
+	}
+
+	w, err := t.sequencer.Workload()
+	if err == nil && w == msg.Workload {
+		return t.processCompletedWorkload(ctx, msg)
+	}
+	if p := t.sequencer.PrecloseCheckpointWorkload(); p != nil && *p == msg.Workload {
+		return t.processCompletedWorkload(ctx, msg)
+	}
+	ctx.Log().Infof("ignoring resent workload that was already completed: %v", msg.Workload)
+	return t.sendNextWorkload(ctx)
+}
+
 func (t *trial) sendNextWorkload(ctx *actor.Context) error {
 	terminateNow := false
 	var w workload.Workload
@@ -842,6 +904,16 @@ func (t *trial) pushRendezvous(ctx *actor.Context) error {
 	}
 	ctx.Log().Info("found all containers are connected successfully")
 
+	// The containers of a run only go through the rendezvous once, even if they reconnect
+	// later on, e.g., to a restarted master.
+	if len(t.RendezvousContainers) > 0 {
+		if msg := t.resentWorkload; msg != nil {
+			t.resentWorkload = nil
+			return t.processResentWorkload(ctx, *msg)
+		}
+		return nil
+	}
+
 	type CAddress struct {
 		Container cproto.Container
 		Addresses []cproto.Address
@@ -928,6 +1000,15 @@ func (t *trial) pushRendezvous(ctx *actor.Context) error {
 		}
 	}
 
+	t.RendezvousContainers = make([]cproto.ID, len(t.allocations))
+	for id, rank := range t.containerRanks {
+		t.RendezvousContainers[rank] = id
+	}
+	if err := t.tellWithSnapshot(ctx, ctx.Self().Parent(), func(s trialSnapshot) interface{} {
+		return s
+	}); err != nil {
+		return errors.Wrap(err, "failed to snapshot trial after rendezvous")
+	}
 	return nil
 }
 
@@ -1091,8 +1172,17 @@ func (t *trial) terminated(ctx *actor.Context) {
 	t.allocations = nil
 	t.containerRanks = make(map[cproto.ID]int)
 	ctx.Tell(t.rm, sproto.ResourcesReleased{TaskActor: ctx.Self()})
+	if len(t.RendezvousContainers) > 0 {
+		t.RendezvousContainers = nil
+		if err := t.tellWithSnapshot(ctx, ctx.Self().Parent(), func(s trialSnapshot) interface{} {
+			return s
+		}); err != nil {
+			ctx.Log().WithError(err).Error("failed to snapshot trial after termination")
+		}
+	}
 
 	t.allReadySucceeded = false
+	t.resentWorkload = nil
 	t.PendingGracefulTermination = false
 	t.TerminationSent = false
 	t.terminatedContainers = make(map[cproto.ID]terminatedContainerWithState)
@@ -1183,6 +1273,11 @@ func (t *trial) Restore(snapshot json.RawMessage) error {
 	if err := t.sequencer.Restore(t.TrialWorkloadSequencerState); err != nil {
 		return errors.Wrap(err, "failed to restore trial workload sequencer state")
 	}
+	// A trial whose containers went through the rendezvous before the master restarted tries to
+	// reattach to them first; it is reset if they are gone.
+	if len(t.RendezvousContainers) > 0 {
+		return nil
+	}
 	if err := t.reset(); err != nil {
 		return errors.Wrap(err, "failed to reset trial")
 	}
@@ -85,7 +85,7 @@ func TestRendezvousInfo(t *testing.T) {
 		resourcemanagers.NewResourcePool(
 			&resourcemanagers.ResourcePoolConfig{PoolName: "default"},
 			nil,
-			resourcemanagers.NewFairShareScheduler(),
+			resourcemanagers.NewFairShareScheduler(resourcemanagers.DefaultSchedulerConfig()),
 			resourcemanagers.WorstFit,
 		))
 	if !created {
@@ -111,6 +111,7 @@ func TestRendezvousInfo(t *testing.T) {
 		containerAddresses:   make(map[cproto.ID][]cproto.Address),
 		containerSockets:     make(map[cproto.ID]*actor.Ref),
 		taskSpec:             defaultTaskSpec,
+		sequencer:            &trialWorkloadSequencer{},
 	}
 	trialRef, created := system.ActorOf(actor.Addr("trial"), trial)
 	if !created {
@@ -207,3 +208,53 @@ func TestRendezvousInfo(t *testing.T) {
 		}
 	})
 }
+
+func TestReattachSkipsRendezvous(t *testing.T) {
+	system := actor.NewSystem(t.Name())
+	rendezvous := []cproto.ID{"leader", "worker"}
+
+	// This is the minimal trial that was restored after its containers went through the rendezvous.
+	trial := &trial{
+		trialState:           trialState{RendezvousContainers: rendezvous},
+		experiment:           &model.Experiment{},
+		task:                 &sproto.AllocateRequest{ID: sproto.NewTaskID()},
+		experimentState:      model.ActiveState,
+		startedContainers:    make(map[cproto.ID]bool),
+		terminatedContainers: make(map[cproto.ID]terminatedContainerWithState),
+		containers:           make(map[cproto.ID]cproto.Container),
+		containerRanks:       make(map[cproto.ID]int),
+		containerAddresses:   make(map[cproto.ID][]cproto.Address),
+		containerSockets:     make(map[cproto.ID]*actor.Ref),
+		taskSpec:             &tasks.TaskSpec{},
+		sequencer:            &trialWorkloadSequencer{},
+	}
+	trialRef, created := system.ActorOf(actor.Addr("trial"), trial)
+	assert.Assert(t, created)
+
+	system.Ask(trialRef, sproto.ResourcesAllocated{
+		ID:          trial.task.ID,
+		Allocations: []sproto.Allocation{mockAllocation{}, mockAllocation{}},
+		Reattached:  true,
+	}).Get()
+	assert.DeepEqual(t, trial.containerRanks, map[cproto.ID]int{"leader": 0, "worker": 1})
+
+	sockets := make(map[cproto.ID]*mockActor)
+	for _, id := range rendezvous {
+		sockets[id] = &mockActor{}
+		ref, created := system.ActorOf(actor.Addr("socket-"+string(id)), sockets[id])
+		assert.Assert(t, created)
+		trial.containerSockets[id] = ref
+
+		// Reattached containers are replayed as running without their addresses.
+		system.Ask(trialRef, sproto.TaskContainerStateChanged{
+			Container:        cproto.Container{ID: id, State: cproto.Running},
+			ContainerStarted: &sproto.TaskContainerStarted{},
+		}).Get()
+	}
+
+	assert.Assert(t, trial.allReadySucceeded)
+	for id, socket := range sockets {
+		assert.Equal(t, len(socket.Messages), 0, "rendezvous pushed to %s", id)
+	}
+	assert.DeepEqual(t, trial.RendezvousContainers, rendezvous)
+}
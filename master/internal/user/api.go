@@ -16,4 +16,9 @@ func RegisterAPIHandler(echo *echo.Echo, m *Service, middleware ...echo.Middlewa
 	usersGroup.GET("/me", api.Route(m.getMe))
 	usersGroup.PATCH("/:username", api.Route(m.patchUser))
 	usersGroup.PATCH("/:username/username", api.Route(m.patchUsername))
+
+	for _, provider := range m.providers {
+		echo.GET("/auth/"+provider.Name()+"/login", m.getProviderLogin(provider))
+		echo.GET("/auth/"+provider.Name()+"/callback", m.getProviderCallback(provider))
+	}
 }
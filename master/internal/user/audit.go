@@ -0,0 +1,72 @@
+package user
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/determined-ai/determined/master/internal/audit"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// isReadRoute returns whether requests with the given method to a route only read from the cluster.
+func isReadRoute(method, route string) bool {
+	return method == http.MethodGet || method == http.MethodHead || readRoutes[method+" "+route]
+}
+
+// RecordAuditEvents is a middleware that records who made the requests that change the cluster and
+// how the requests turned out. It must run before ProcessAuthentication so that requests that are
+// not authenticated or not allowed are recorded too.
+func (s *Service) RecordAuditEvents(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		method := c.Request().Method
+		if isReadRoute(method, c.Path()) {
+			return next(c)
+		}
+
+		body, err := ioutil.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+		c.Request().Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		err = next(c)
+
+		resource := audit.ResourceFromRoute(c.Path())
+		event := &model.AuditEvent{
+			Method:       method + " " + c.Path(),
+			ResourceType: resource,
+			Success:      err == nil && c.Response().Status < http.StatusBadRequest,
+		}
+		if user, ok := c.Get("user").(model.User); ok {
+			event.UserID = &user.ID
+			event.Username = user.Username
+		}
+		if values := c.ParamValues(); len(values) > 0 {
+			event.ResourceID = values[0]
+		}
+		switch e := err.(type) {
+		case nil:
+		case *echo.HTTPError:
+			event.Error = fmt.Sprintf("%d %v", e.Code, e.Message)
+		default:
+			event.Error = e.Error()
+		}
+
+		var request interface{}
+		if len(body) > 0 && json.Unmarshal(body, &request) == nil {
+			if fields, ok := audit.Summarize(request).(map[string]interface{}); ok {
+				event.Request = fields
+				if event.ResourceID == "" {
+					event.ResourceID = audit.ResourceID(resource, fields)
+				}
+			}
+		}
+		audit.Record(s.db, event)
+		return err
+	}
+}
@@ -0,0 +1,133 @@
+package user
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo"
+
+	"github.com/determined-ai/determined/master/internal/authz"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// routePermission checks whether a user may make a request to a route.
+type routePermission func(a *authz.Authorizer, user model.User, c echo.Context) error
+
+func requireRole(role model.Role) routePermission {
+	return func(_ *authz.Authorizer, user model.User, _ echo.Context) error {
+		return authz.RequireRole(user, role)
+	}
+}
+
+// modifyByID checks that the user can modify the object with the ID in the given path parameter.
+// Malformed IDs are left for the handler to reject.
+func modifyByID(
+	param string, check func(*authz.Authorizer, model.User, int) error,
+) routePermission {
+	return func(a *authz.Authorizer, user model.User, c echo.Context) error {
+		id, err := strconv.Atoi(c.Param(param))
+		if err != nil {
+			return authz.RequireRole(user, model.RoleEditor)
+		}
+		return check(a, user, id)
+	}
+}
+
+// modifyCommand checks that the user can modify the command, notebook, shell or tensorboard that
+// the request is addressed to, e.g. /notebooks/<id>.
+func modifyCommand(a *authz.Authorizer, user model.User, c echo.Context) error {
+	parts := strings.Split(strings.Trim(c.Request().URL.Path, "/"), "/")
+	if len(parts) != 2 {
+		return authz.RequireRole(user, model.RoleAdmin)
+	}
+	return a.CanModifyCommand(user, actor.Addr(parts[0], parts[1]))
+}
+
+var (
+	modifyExperiment = modifyByID("experiment_id", (*authz.Authorizer).CanModifyExperiment)
+	modifyTrial      = modifyByID("trial_id", (*authz.Authorizer).CanModifyTrial)
+)
+
+// createExperiment checks that the user can create experiments in the project that the request
+// names, if any. The body of the request is left for the handler to read.
+func createExperiment(a *authz.Authorizer, user model.User, c echo.Context) error {
+	body, err := ioutil.ReadAll(c.Request().Body)
+	if err != nil {
+		return err
+	}
+	c.Request().Body = ioutil.NopCloser(bytes.NewReader(body))
+	var params struct {
+		ProjectID *int `json:"project_id"`
+	}
+	if err := json.Unmarshal(body, &params); err != nil {
+		return authz.RequireRole(user, model.RoleEditor)
+	}
+	return a.CanUseProject(user, params.ProjectID)
+}
+
+func modifyCheckpoint(a *authz.Authorizer, user model.User, c echo.Context) error {
+	return a.CanModifyCheckpoint(user, c.Param("checkpoint_uuid"))
+}
+
+func modifyTemplate(a *authz.Authorizer, user model.User, c echo.Context) error {
+	return a.CanModifyTemplate(user, c.Param("template_name"))
+}
+
+// readRoutes are the routes that are not requested with GET or HEAD but only read from the
+// cluster.
+var readRoutes = map[string]bool{
+	"POST /logout":           true,
+	"POST /searcher/preview": true,
+	"POST /searcher/replay":  true,
+}
+
+// routePermissions holds what users need to make the requests that change the cluster, keyed by
+// method and route. All users may make GET and HEAD requests; other requests to routes that are
+// not listed here can only be made by admins.
+var routePermissions = map[string]routePermission{
+	"PATCH /users/:username":                        requireRole(model.RoleViewer),
+	"POST /experiments":                             createExperiment,
+	"PATCH /experiments/:experiment_id":             modifyExperiment,
+	"DELETE /experiments/:experiment_id":            modifyExperiment,
+	"POST /experiments/:experiment_id/kill":         modifyExperiment,
+	"POST /experiments/:experiment_id/continue":     modifyExperiment,
+	"POST /trials/:trial_id/kill":                   modifyTrial,
+	"POST /checkpoints/:checkpoint_uuid/metadata":   modifyCheckpoint,
+	"DELETE /checkpoints/:checkpoint_uuid/metadata": modifyCheckpoint,
+	"PUT /templates/:template_name":                 modifyTemplate,
+	"DELETE /templates/:template_name":              modifyTemplate,
+	"POST /commands*":                               requireRole(model.RoleEditor),
+	"POST /notebooks*":                              requireRole(model.RoleEditor),
+	"POST /shells*":                                 requireRole(model.RoleEditor),
+	"POST /tensorboard*":                            requireRole(model.RoleEditor),
+	"DELETE /commands*":                             modifyCommand,
+	"DELETE /notebooks*":                            modifyCommand,
+	"DELETE /shells*":                               modifyCommand,
+	"DELETE /tensorboard*":                          modifyCommand,
+}
+
+// authorize checks that the authenticated user may make the request. Read-only API tokens may only
+// be used for requests that read from the cluster.
+func (s *Service) authorize(c echo.Context, user model.User, session model.UserSession) error {
+	method := c.Request().Method
+	var err error
+	switch check, ok := routePermissions[method+" "+c.Path()]; {
+	case isReadRoute(method, c.Path()):
+		err = authz.RequireRole(user, model.RoleViewer)
+	case session.ReadOnly:
+		err = authz.ErrPermissionDenied
+	case ok:
+		err = check(s.authorizer, user, c)
+	default:
+		err = authz.RequireRole(user, model.RoleAdmin)
+	}
+	if err == authz.ErrPermissionDenied {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,298 @@
+package user
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+const (
+	defaultUsernameClaim = "email"
+	defaultGroupsClaim   = "groups"
+	oidcTimeout          = 30 * time.Second
+)
+
+// OIDCConfig configures logging in with an OpenID Connect identity provider.
+type OIDCConfig struct {
+	// IssuerURL is the identifier of the identity provider, where its discovery document is served
+	// from under /.well-known/openid-configuration.
+	IssuerURL    string `json:"issuer_url"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	// RedirectURL is the callback of the master that is registered with the identity provider,
+	// e.g. https://master.example.com:8443/auth/oidc/callback.
+	RedirectURL string   `json:"redirect_url"`
+	Scopes      []string `json:"scopes"`
+	// UsernameClaim is the claim of the ID token that holds the username.
+	UsernameClaim string `json:"username_claim"`
+	// GroupsClaim is the claim of the ID token that holds the groups of the user; members of
+	// AdminGroups are made admins and other users are not, unless AdminGroups is empty.
+	GroupsClaim string   `json:"groups_claim"`
+	AdminGroups []string `json:"admin_groups"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c OIDCConfig) Validate() []error {
+	var errs []error
+	if c.IssuerURL == "" {
+		errs = append(errs, errors.New("OIDC issuer_url must be set"))
+	}
+	if c.ClientID == "" {
+		errs = append(errs, errors.New("OIDC client_id must be set"))
+	}
+	if c.RedirectURL == "" {
+		errs = append(errs, errors.New("OIDC redirect_url must be set"))
+	}
+	return errs
+}
+
+// oidcDiscovery is the part of the discovery document of an identity provider that is used.
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// OIDCProvider logs users in with the authorization code flow of OpenID Connect.
+type OIDCProvider struct {
+	config    OIDCConfig
+	discovery oidcDiscovery
+	oauth     oauth2.Config
+	client    *http.Client
+
+	mu   sync.Mutex
+	keys map[string]*rsa.PublicKey
+}
+
+// NewOIDCProvider creates an OIDC provider from the discovery document of the identity provider.
+func NewOIDCProvider(ctx context.Context, config OIDCConfig) (*OIDCProvider, error) {
+	if config.UsernameClaim == "" {
+		config.UsernameClaim = defaultUsernameClaim
+	}
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = defaultGroupsClaim
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "profile", "email"}
+	}
+
+	p := &OIDCProvider{config: config, client: &http.Client{Timeout: oidcTimeout}}
+	issuer := strings.TrimSuffix(config.IssuerURL, "/")
+	if err := p.getJSON(ctx, issuer+"/.well-known/openid-configuration", &p.discovery); err != nil {
+		return nil, errors.Wrap(err, "error fetching OIDC discovery document")
+	}
+	if strings.TrimSuffix(p.discovery.Issuer, "/") != issuer {
+		return nil, errors.Errorf(
+			"OIDC issuer %s does not match the configured issuer %s", p.discovery.Issuer, issuer)
+	}
+	p.oauth = oauth2.Config{
+		ClientID:     config.ClientID,
+		ClientSecret: config.ClientSecret,
+		RedirectURL:  config.RedirectURL,
+		Scopes:       config.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  p.discovery.AuthorizationEndpoint,
+			TokenURL: p.discovery.TokenEndpoint,
+		},
+	}
+	return p, nil
+}
+
+// Name implements the Provider interface.
+func (p *OIDCProvider) Name() string {
+	return "oidc"
+}
+
+// LoginURL implements the Provider interface.
+func (p *OIDCProvider) LoginURL(state, nonce string) string {
+	return p.oauth.AuthCodeURL(state, oauth2.SetAuthURLParam("nonce", nonce))
+}
+
+// Authenticate implements the Provider interface by exchanging the code for an ID token and
+// verifying it.
+func (p *OIDCProvider) Authenticate(ctx context.Context, code, nonce string) (*Identity, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+	token, err := p.oauth.Exchange(ctx, code)
+	if err != nil {
+		return nil, errors.Wrap(err, "error exchanging OIDC code")
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("OIDC token response has no ID token")
+	}
+	claims, err := p.verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims["nonce"] != nonce {
+		return nil, errors.New("OIDC ID token has an invalid nonce")
+	}
+
+	// Anyone can put an address they do not own in their email claim until it is verified.
+	if p.config.UsernameClaim == "email" && !isTrue(claims["email_verified"]) {
+		return nil, errors.New("OIDC ID token has an unverified email")
+	}
+	username, _ := claims[p.config.UsernameClaim].(string)
+	identity := &Identity{Username: username}
+	if len(p.config.AdminGroups) > 0 {
+		admin := false
+		groups, _ := claims[p.config.GroupsClaim].([]interface{})
+		for _, group := range groups {
+			for _, adminGroup := range p.config.AdminGroups {
+				if group == adminGroup {
+					admin = true
+				}
+			}
+		}
+		identity.Admin = &admin
+	}
+	return identity, nil
+}
+
+// verify checks the signature, issuer, audience and expiry of an ID token and returns its claims.
+func (p *OIDCProvider) verify(
+	ctx context.Context, rawIDToken string,
+) (map[string]interface{}, error) {
+	parts := strings.Split(rawIDToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed OIDC ID token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, errors.Wrap(err, "malformed OIDC ID token header")
+	}
+	if header.Alg != "RS256" {
+		return nil, errors.Errorf("unsupported OIDC ID token algorithm %s", header.Alg)
+	}
+	key, err := p.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed OIDC ID token signature")
+	}
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err = rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature); err != nil {
+		return nil, errors.Wrap(err, "invalid OIDC ID token signature")
+	}
+
+	var claims map[string]interface{}
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return nil, errors.Wrap(err, "malformed OIDC ID token claims")
+	}
+	if claims["iss"] != p.discovery.Issuer {
+		return nil, errors.Errorf("OIDC ID token has an invalid issuer %v", claims["iss"])
+	}
+	if !hasAudience(claims["aud"], p.config.ClientID) {
+		return nil, errors.New("OIDC ID token has an invalid audience")
+	}
+	if exp, ok := claims["exp"].(float64); !ok || time.Unix(int64(exp), 0).Before(time.Now()) {
+		return nil, errors.New("OIDC ID token has expired")
+	}
+	return claims, nil
+}
+
+// key returns the signing key with the given ID, fetching the keys of the identity provider again
+// if it is unknown, e.g. because the keys were rotated.
+func (p *OIDCProvider) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.getJSON(ctx, p.discovery.JWKSURI, &jwks); err != nil {
+		return nil, errors.Wrap(err, "error fetching OIDC signing keys")
+	}
+	p.keys = map[string]*rsa.PublicKey{}
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, nErr := base64.RawURLEncoding.DecodeString(k.N)
+		e, eErr := base64.RawURLEncoding.DecodeString(k.E)
+		if nErr != nil || eErr != nil {
+			continue
+		}
+		p.keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	key, ok := p.keys[kid]
+	if !ok {
+		return nil, errors.Errorf("unknown OIDC signing key %s", kid)
+	}
+	return key, nil
+}
+
+func (p *OIDCProvider) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status %s from %s", resp.Status, url)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// hasAudience returns whether the aud claim, which is either a string or a list of strings,
+// includes the client ID.
+func hasAudience(aud interface{}, clientID string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == clientID
+	case []interface{}:
+		for _, a := range aud {
+			if a == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isTrue returns whether a boolean claim is set. Some identity providers send booleans as strings.
+func isTrue(claim interface{}) bool {
+	return claim == true || claim == "true"
+}
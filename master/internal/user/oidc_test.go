@@ -0,0 +1,143 @@
+package user
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+// mockIdentityProvider is an OIDC identity provider that issues ID tokens with the given claims for
+// every code.
+type mockIdentityProvider struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	claims map[string]interface{}
+}
+
+func newMockIdentityProvider(t *testing.T) *mockIdentityProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NilError(t, err)
+	m := &mockIdentityProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(oidcDiscovery{
+			Issuer:                m.URL,
+			AuthorizationEndpoint: m.URL + "/authorize",
+			TokenEndpoint:         m.URL + "/token",
+			JWKSURI:               m.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []jsonWebKey{{
+			Kid: "test",
+			Kty: "RSA",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"id_token":     m.sign(t, m.claims),
+		})
+	})
+	m.Server = httptest.NewServer(mux)
+	return m
+}
+
+func (m *mockIdentityProvider) sign(t *testing.T, claims map[string]interface{}) string {
+	encode := func(v interface{}) string {
+		raw, err := json.Marshal(v)
+		assert.NilError(t, err)
+		return base64.RawURLEncoding.EncodeToString(raw)
+	}
+	payload := encode(map[string]string{"alg": "RS256", "kid": "test"}) + "." + encode(claims)
+	hash := sha256.Sum256([]byte(payload))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, m.key, crypto.SHA256, hash[:])
+	assert.NilError(t, err)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestOIDCProvider(t *testing.T) {
+	idp := newMockIdentityProvider(t)
+	defer idp.Close()
+
+	ctx := context.Background()
+	p, err := NewOIDCProvider(ctx, OIDCConfig{
+		IssuerURL:   idp.URL,
+		ClientID:    "determined",
+		RedirectURL: "http://master/auth/oidc/callback",
+		AdminGroups: []string{"ml-admins"},
+	})
+	assert.NilError(t, err)
+
+	loginURL, err := url.Parse(p.LoginURL("state", "nonce"))
+	assert.NilError(t, err)
+	assert.Equal(t, loginURL.Path, "/authorize")
+	assert.Equal(t, loginURL.Query().Get("state"), "state")
+	assert.Equal(t, loginURL.Query().Get("nonce"), "nonce")
+	assert.Equal(t, loginURL.Query().Get("client_id"), "determined")
+
+	validClaims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":    idp.URL,
+			"aud":    "determined",
+			"exp":    time.Now().Add(time.Hour).Unix(),
+			"nonce":  "nonce",
+			"email":  "alice@example.com",
+			"groups": []string{"ml-admins"},
+
+			"email_verified": true,
+		}
+	}
+
+	idp.claims = validClaims()
+	identity, err := p.Authenticate(ctx, "code", "nonce")
+	assert.NilError(t, err)
+	assert.Equal(t, identity.Username, "alice@example.com")
+	assert.Equal(t, *identity.Admin, true)
+
+	idp.claims["groups"] = []string{"ml-users"}
+	identity, err = p.Authenticate(ctx, "code", "nonce")
+	assert.NilError(t, err)
+	assert.Equal(t, *identity.Admin, false)
+
+	invalid := map[string]func(map[string]interface{}){
+		"audience": func(c map[string]interface{}) { c["aud"] = "other" },
+		"issuer":   func(c map[string]interface{}) { c["iss"] = "https://other" },
+		"expiry":   func(c map[string]interface{}) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"nonce":    func(c map[string]interface{}) { c["nonce"] = "replayed" },
+		"email":    func(c map[string]interface{}) { c["email_verified"] = false },
+		"no email": func(c map[string]interface{}) { delete(c, "email_verified") },
+	}
+	for name, invalidate := range invalid {
+		idp.claims = validClaims()
+		invalidate(idp.claims)
+		_, err = p.Authenticate(ctx, "code", "nonce")
+		assert.Assert(t, err != nil, "ID token with invalid %s was accepted", name)
+	}
+}
+
+func TestOIDCConfigValidate(t *testing.T) {
+	assert.Equal(t, len(OIDCConfig{}.Validate()), 3)
+	assert.Equal(t, len(OIDCConfig{
+		IssuerURL:   "https://idp.example.com",
+		ClientID:    "determined",
+		RedirectURL: "http://master/auth/oidc/callback",
+	}.Validate()), 0)
+}
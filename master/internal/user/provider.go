@@ -0,0 +1,201 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/guregu/null.v3"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/telemetry"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// Identity is a user as asserted by an external identity provider.
+type Identity struct {
+	Username string
+	// Admin is set when the identity provider makes the user an admin; it is nil when the provider
+	// does not decide who is an admin.
+	Admin *bool
+}
+
+// Provider authenticates users with an external identity provider through the browser: users are
+// sent to the provider to log in, and the provider sends them back with a code that proves who they
+// are.
+type Provider interface {
+	// Name is the name of the provider in the routes of the master, e.g. /auth/<name>/login.
+	Name() string
+	// LoginURL returns where to send users to log in. The state and nonce must be passed to
+	// Authenticate when they come back.
+	LoginURL(state, nonce string) string
+	// Authenticate returns the identity that the code that the provider sent back proves.
+	Authenticate(ctx context.Context, code, nonce string) (*Identity, error)
+}
+
+const (
+	loginStateCookie   = "login_state"
+	loginStateDuration = 10 * time.Minute
+	loginRedirect      = "/det/"
+)
+
+func randomString() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.Wrap(err, "failed to generate random string")
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// getProviderLogin sends the user to the identity provider, remembering the state and nonce of the
+// login in a short-lived cookie.
+func (s *Service) getProviderLogin(provider Provider) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		state, err := randomString()
+		if err != nil {
+			return err
+		}
+		nonce, err := randomString()
+		if err != nil {
+			return err
+		}
+		c.SetCookie(&http.Cookie{
+			Name:     loginStateCookie,
+			Value:    state + ":" + nonce,
+			Path:     "/auth/" + provider.Name(),
+			Expires:  time.Now().Add(loginStateDuration),
+			HttpOnly: true,
+		})
+		return c.Redirect(http.StatusFound, provider.LoginURL(state, nonce))
+	}
+}
+
+// getProviderCallback completes a login with the identity provider: it provisions the user that
+// the provider identified, starts a session for them and sends them to the WebUI.
+func (s *Service) getProviderCallback(provider Provider) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		cookie, err := c.Cookie(loginStateCookie)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "no login in progress")
+		}
+		parts := strings.SplitN(cookie.Value, ":", 2)
+		if len(parts) != 2 || parts[0] != c.QueryParam("state") {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid login state")
+		}
+		if msg := c.QueryParam("error"); msg != "" {
+			return echo.NewHTTPError(http.StatusUnauthorized,
+				fmt.Sprintf("login failed: %s %s", msg, c.QueryParam("error_description")))
+		}
+
+		identity, err := provider.Authenticate(c.Request().Context(), c.QueryParam("code"), parts[1])
+		if err != nil {
+			log.WithError(err).Warnf("%s login failed", provider.Name())
+			return echo.NewHTTPError(http.StatusUnauthorized, "invalid credentials")
+		}
+		user, err := s.provisionUser(provider.Name(), identity)
+		if err != nil {
+			return err
+		}
+		if !user.Active {
+			return echo.NewHTTPError(http.StatusForbidden, "invalid credentials")
+		}
+		token, err := s.db.StartUserSession(user)
+		if err != nil {
+			return err
+		}
+
+		c.SetCookie(&http.Cookie{
+			Name:    loginStateCookie,
+			Path:    "/auth/" + provider.Name(),
+			Expires: time.Unix(0, 0),
+		})
+		c.SetCookie(&http.Cookie{
+			Name:    "auth",
+			Value:   token,
+			Path:    "/",
+			Expires: time.Now().Add(db.SessionDuration),
+		})
+		return c.Redirect(http.StatusFound, loginRedirect)
+	}
+}
+
+// provisionUser returns the user with the given identity, creating it on its first login, and
+// updates whether it is an admin if the identity provider decides that. Users that were not
+// created by the provider cannot log in through it, so that identities cannot take over local
+// users like admin that happen to have the same name.
+func (s *Service) provisionUser(provider string, identity *Identity) (*model.User, error) {
+	if identity.Username == "" {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "identity has no username")
+	}
+	user, err := s.db.UserByUsername(identity.Username)
+	switch {
+	case err == db.ErrNotFound:
+		return s.addProvidedUser(provider, identity)
+	case err != nil:
+		return nil, err
+	}
+	if err = checkProvidedUser(provider, user); err != nil {
+		return nil, err
+	}
+
+	if identity.Admin == nil || *identity.Admin == user.Admin {
+		return user, nil
+	}
+	if *identity.Admin {
+		user.SetRole(model.RoleAdmin)
+	} else {
+		user.SetRole(model.RoleEditor)
+	}
+	if err = s.db.UpdateUser(user, []string{"admin", "role"}, nil); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// checkProvidedUser returns an error unless the existing user was created by the provider.
+func checkProvidedUser(provider string, user *model.User) error {
+	if !user.LoginProvider.Valid || user.LoginProvider.String != provider {
+		log.Warnf("refusing %s login of user %s, which was not created by %s",
+			provider, user.Username, provider)
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid credentials")
+	}
+	return nil
+}
+
+// addProvidedUser creates a user for an identity. The user gets a random password so that it can
+// only log in through the identity provider.
+func (s *Service) addProvidedUser(provider string, identity *Identity) (*model.User, error) {
+	user := &model.User{
+		Username:      strings.ToLower(identity.Username),
+		Active:        true,
+		LoginProvider: null.StringFrom(provider),
+	}
+	user.SetRole(model.RoleFromAdmin(identity.Admin != nil && *identity.Admin))
+	if err := s.db.AddUser(user, nil); err != nil {
+		return nil, err
+	}
+	telemetry.ReportUserCreated(s.system, user.Admin, user.Active)
+
+	created, err := s.db.UserByUsername(user.Username)
+	if err != nil {
+		return nil, err
+	}
+	password, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	if err = created.UpdatePasswordHash(password); err != nil {
+		return nil, err
+	}
+	if err = s.db.UpdateUser(created, []string{"password_hash"}, nil); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
@@ -0,0 +1,59 @@
+package user
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"gopkg.in/guregu/null.v3"
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestCheckProvidedUser(t *testing.T) {
+	local := &model.User{Username: "alice", Active: true}
+	assert.ErrorContains(t, checkProvidedUser("oidc", local), "invalid credentials")
+
+	other := &model.User{Username: "alice", Active: true, LoginProvider: null.StringFrom("saml")}
+	assert.ErrorContains(t, checkProvidedUser("oidc", other), "invalid credentials")
+
+	provided := &model.User{Username: "alice", Active: true, LoginProvider: null.StringFrom("oidc")}
+	assert.NilError(t, checkProvidedUser("oidc", provided))
+}
+
+func TestProviderCannotTakeOverAdmin(t *testing.T) {
+	idp := newMockIdentityProvider(t)
+	defer idp.Close()
+
+	ctx := context.Background()
+	p, err := NewOIDCProvider(ctx, OIDCConfig{
+		IssuerURL:     idp.URL,
+		ClientID:      "determined",
+		RedirectURL:   "http://master/auth/oidc/callback",
+		UsernameClaim: "preferred_username",
+		AdminGroups:   []string{"ml-admins"},
+	})
+	assert.NilError(t, err)
+
+	// Users of the identity provider may be able to choose their own preferred username.
+	idp.claims = map[string]interface{}{
+		"iss":                idp.URL,
+		"aud":                "determined",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"nonce":              "nonce",
+		"preferred_username": "admin",
+		"groups":             []string{"ml-admins"},
+	}
+	identity, err := p.Authenticate(ctx, "code", "nonce")
+	assert.NilError(t, err)
+	assert.Equal(t, identity.Username, "admin")
+
+	admin := &model.User{Username: "admin", Admin: true, Active: true, Role: model.RoleAdmin}
+	err = checkProvidedUser(p.Name(), admin)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.Assert(t, ok, "expected an HTTP error but got: %v", err)
+	assert.Equal(t, httpErr.Code, http.StatusUnauthorized)
+}
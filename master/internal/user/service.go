@@ -12,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/authz"
 	"github.com/determined-ai/determined/master/internal/context"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/telemetry"
@@ -48,13 +49,18 @@ func (h *agentUserGroup) Validate() (*model.AgentUserGroup, error) {
 
 // Service describes a user manager.
 type Service struct {
-	db     *db.PgDB
-	system *actor.System
+	db         *db.PgDB
+	system     *actor.System
+	authorizer *authz.Authorizer
+	providers  []Provider
 }
 
-// New creates a new user service.
-func New(db *db.PgDB, system *actor.System) (*Service, error) {
-	return &Service{db, system}, nil
+// New creates a new user service. Users can log in with the given identity providers as well as
+// with their passwords.
+func New(
+	db *db.PgDB, system *actor.System, authorizer *authz.Authorizer, providers ...Provider,
+) (*Service, error) {
+	return &Service{db, system, authorizer, providers}, nil
 }
 
 // ProcessAuthentication is a middleware processing function that attempts
@@ -91,6 +97,9 @@ func (s *Service) ProcessAuthentication(next echo.HandlerFunc) echo.HandlerFunc
 			// event handlers.
 			c.(*context.DetContext).SetUser(*user)
 			c.(*context.DetContext).SetUserSession(*userSession)
+			if err := s.authorize(c, *user, *userSession); err != nil {
+				return err
+			}
 			return next(c)
 		case db.ErrNotFound:
 			return echo.NewHTTPError(http.StatusUnauthorized)
@@ -199,6 +208,7 @@ func (s *Service) patchUser(c echo.Context) (interface{}, error) {
 			Password *string `json:"password,omitempty"`
 			Active   *bool   `json:"active,omitempty"`
 			Admin    *bool   `json:"admin,omitempty"`
+			Role     *string `json:"role,omitempty"`
 
 			AgentUserGroup *agentUserGroup `json:"agent_user_group,omitempty"`
 		}
@@ -265,8 +275,23 @@ func (s *Service) patchUser(c echo.Context) (interface{}, error) {
 		if !user.AdminCanBeModifiedBy(authenticatedUser) {
 			return nil, forbiddenError
 		}
-		user.Admin = *params.Admin
-		toUpdate = append(toUpdate, "admin")
+		user.SetRole(model.RoleFromAdmin(*params.Admin))
+		toUpdate = append(toUpdate, "admin", "role")
+	}
+
+	if params.Role != nil {
+		if !user.AdminCanBeModifiedBy(authenticatedUser) {
+			return nil, forbiddenError
+		}
+		role := model.Role(*params.Role)
+		if !role.Valid() {
+			return nil, echo.NewHTTPError(
+				http.StatusBadRequest, fmt.Sprintf("invalid role: %s", *params.Role))
+		}
+		user.SetRole(role)
+		if params.Admin == nil {
+			toUpdate = append(toUpdate, "admin", "role")
+		}
 	}
 
 	var ug *model.AgentUserGroup
@@ -358,6 +383,7 @@ func (s *Service) postUser(c echo.Context) (interface{}, error) {
 			Username string `json:"username"`
 			Admin    bool   `json:"admin"`
 			Active   bool   `json:"active"`
+			Role     string `json:"role"`
 
 			AgentUserGroup *agentUserGroup `json:"agent_user_group,omitempty"`
 		}
@@ -394,12 +420,18 @@ func (s *Service) postUser(c echo.Context) (interface{}, error) {
 		ug = u
 	}
 
+	role := model.RoleFromAdmin(params.Admin)
+	if params.Role != "" {
+		if role = model.Role(params.Role); !role.Valid() {
+			return nil, echo.NewHTTPError(
+				http.StatusBadRequest, fmt.Sprintf("invalid role: %s", params.Role))
+		}
+	}
+
 	params.Username = strings.ToLower(params.Username)
-	err = s.db.AddUser(&model.User{
-		Username: params.Username,
-		Admin:    params.Admin,
-		Active:   params.Active,
-	}, ug)
+	user := model.User{Username: params.Username, Active: params.Active}
+	user.SetRole(role)
+	err = s.db.AddUser(&user, ug)
 
 	switch {
 	case err == db.ErrDuplicateRecord:
@@ -408,7 +440,7 @@ func (s *Service) postUser(c echo.Context) (interface{}, error) {
 		return nil, err
 	}
 
-	telemetry.ReportUserCreated(s.system, params.Admin, params.Active)
+	telemetry.ReportUserCreated(s.system, user.Admin, params.Active)
 
 	return response{
 		message: fmt.Sprintf("successfully created user: %s", params.Username),
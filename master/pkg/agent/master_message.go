@@ -34,13 +34,30 @@ type MasterMessage struct {
 	AgentStarted          *AgentStarted
 	ContainerStateChanged *ContainerStateChanged
 	ContainerLog          *ContainerLog
+	DevicesHealthChanged  *DevicesHealthChanged
 }
 
-// AgentStarted notifies the master that the agent has started up.
+// AgentStarted notifies the master that the agent has started up or has reconnected to the master.
 type AgentStarted struct {
 	Version string
 	Label   string
 	Devices []device.Device
+	// Containers are the containers that are still running on the agent when it reconnects.
+	Containers []container.Container
+}
+
+// DevicesHealthChanged notifies the master that the health of some devices of the agent changed.
+// It reports the health of every device that the agent probes.
+type DevicesHealthChanged struct {
+	Devices []DeviceHealth
+}
+
+// DeviceHealth is the result of probing the health of a device.
+type DeviceHealth struct {
+	Device  device.Device
+	Healthy bool
+	// Reason describes why the device is unhealthy.
+	Reason string
 }
 
 // ContainerStateChanged notifies the master that the agent transitioned the container state.
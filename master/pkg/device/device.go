@@ -32,12 +32,27 @@ func (t Type) Proto() devicev1.Type {
 	}
 }
 
+// Topology describes where a device sits in the interconnect of its agent. Devices that share an
+// NVLink island or a NUMA node communicate with each other faster than devices that do not. The
+// zero value describes a device whose interconnect is not known, like the devices of agents that
+// do not report their topology.
+type Topology struct {
+	// NUMANode is the NUMA node that the device is attached to if HasNUMANode is set.
+	NUMANode    int  `json:"numa_node"`
+	HasNUMANode bool `json:"has_numa_node"`
+	// NVLinkIsland identifies the set of devices that are connected to each other over NVLink if
+	// HasNVLinkIsland is set. Devices without NVLink connections are in no island.
+	NVLinkIsland    int  `json:"nvlink_island"`
+	HasNVLinkIsland bool `json:"has_nvlink_island"`
+}
+
 // Device represents a single computational device on an agent.
 type Device struct {
-	ID    int    `json:"id"`
-	Brand string `json:"brand"`
-	UUID  string `json:"uuid"`
-	Type  Type   `json:"type"`
+	ID       int      `json:"id"`
+	Brand    string   `json:"brand"`
+	UUID     string   `json:"uuid"`
+	Type     Type     `json:"type"`
+	Topology Topology `json:"topology"`
 }
 
 func (d *Device) String() string {
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+)
+
+// APIToken represents a row from the `api_tokens` table. API tokens authenticate as their user
+// without logging in, e.g. for automation; only a hash of their secret is stored.
+type APIToken struct {
+	ID           int        `db:"id" json:"id"`
+	UserID       UserID     `db:"user_id" json:"user_id"`
+	Description  string     `db:"description" json:"description"`
+	ReadOnly     bool       `db:"read_only" json:"read_only"`
+	Expiry       *time.Time `db:"expiry" json:"expiry"`
+	CreationTime time.Time  `db:"creation_time" json:"creation_time"`
+	LastUsed     *time.Time `db:"last_used" json:"last_used"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// AuditEvent represents a row from the `audit_events` table, which records who made each request
+// that changed or tried to change the cluster and how it turned out.
+type AuditEvent struct {
+	ID     int64     `db:"id" json:"id"`
+	Time   time.Time `db:"time" json:"time"`
+	UserID *UserID   `db:"user_id" json:"user_id"`
+	// Username is kept along with the user ID so that events outlive the users that caused them.
+	Username     string  `db:"username" json:"username"`
+	Method       string  `db:"method" json:"method"`
+	ResourceType string  `db:"resource_type" json:"resource_type"`
+	ResourceID   string  `db:"resource_id" json:"resource_id"`
+	Request      JSONObj `db:"request" json:"request"`
+	Success      bool    `db:"success" json:"success"`
+	Error        string  `db:"error" json:"error"`
+}
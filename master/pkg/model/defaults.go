@@ -83,6 +83,13 @@ func DefaultExperimentConfig(taskContainerDefaults *TaskContainerDefaultsConfig)
 			PBTConfig: &PBTConfig{
 				SmallerIsBetter: true,
 			},
+			BayesianConfig: &BayesianConfig{
+				SmallerIsBetter:     true,
+				MaxConcurrentTrials: 0,
+				NumStartupTrials:    10,
+				NumCandidates:       24,
+				Gamma:               0.25,
+			},
 		},
 		Resources: ResourcesConfig{
 			SlotsPerTrial:  1,
@@ -200,6 +200,7 @@ type Experiment struct {
 	GitCommitter         *string    `db:"git_committer"`
 	GitCommitDate        *time.Time `db:"git_commit_date"`
 	OwnerID              *UserID    `db:"owner_id"`
+	ProjectID            *int       `db:"project_id"`
 }
 
 // ExperimentDescriptor is a minimal description of an experiment.
@@ -375,6 +376,11 @@ type Checkpoint struct {
 	Framework         string     `db:"framework" json:"framework"`
 	Format            string     `db:"format" json:"format"`
 	DeterminedVersion string     `db:"determined_version" json:"determined_version"`
+	// Size is the total size of the resources of the checkpoint in bytes.
+	Size int64 `db:"size" json:"size"`
+	// External is true for checkpoints imported from elsewhere, whose files this master does not
+	// own and so never deletes.
+	External bool `db:"external" json:"external"`
 }
 
 // NewCheckpoint creates a new checkpoint in the active state.
@@ -52,9 +52,11 @@ func (e ExperimentConfig) Validate() []error {
 	// hyperparameter config, we have to do it at this level.
 	// - Check that counts are specified for all parameters.
 	// - Compute the total number of trials that would be created and check that it is not too large.
+	// - Hyperparameters whose conditions are not met do not multiply the number of trials.
 	gridTrials := 1
 	noCountParams := make([]string, 0)
 	if e.Searcher.GridConfig != nil {
+		counts := make(map[string]int)
 		e.Hyperparameters.Each(func(name string, param Hyperparameter) {
 			mult := 1
 			switch {
@@ -87,8 +89,9 @@ func (e ExperimentConfig) Validate() []error {
 				p := param.CategoricalHyperparameter
 				mult = len(p.Vals)
 			}
-			gridTrials *= mult
+			counts[name] = mult
 		})
+		gridTrials = e.Hyperparameters.GridSize(counts, MaxAllowedTrials)
 	}
 
 	errs := []error{}
@@ -195,6 +198,10 @@ type ResourcesConfig struct {
 	AgentLabel     string  `json:"agent_label"`
 	ResourcePool   string  `json:"resource_pool"`
 	Priority       *int    `json:"priority,omitempty"`
+
+	// ExpectedRuntime is how long the task is expected to run for once it has been scheduled,
+	// which lets the backfill scheduler reserve resources for tasks that are waiting.
+	ExpectedRuntime *Duration `json:"expected_runtime,omitempty"`
 }
 
 // ValidatePrioritySetting checks that priority if set is within a valid range.
@@ -221,6 +228,10 @@ func (r ResourcesConfig) Validate() []error {
 			r.MaxSlots, r.SlotsPerTrial, "max_slots must be >= slots_per_trial"),
 		check.GreaterThanOrEqualTo(r.ShmSize, 0, "shm_size must be >= 0"),
 	}
+	if r.ExpectedRuntime != nil {
+		errs = append(errs, check.GreaterThan(
+			int64(*r.ExpectedRuntime), int64(0), "expected_runtime must be > 0"))
+	}
 	errs = append(errs, ValidatePrioritySetting(r.Priority)...)
 	return errs
 }
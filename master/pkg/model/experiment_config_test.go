@@ -209,6 +209,41 @@ func TestGridValidation(t *testing.T) {
 		config.Hyperparameters["log"].LogHyperparameter.Count = nil
 		assert.ErrorContains(t, check.Validate(config), "must specify counts for grid search: log")
 	}
+
+	// Check that hyperparameters whose conditions are not met are not counted.
+	{
+		config := validConditionalGridSearchConfig(MaxAllowedTrials / 2)
+		assert.NilError(t, check.Validate(config))
+	}
+	{
+		config := validConditionalGridSearchConfig(MaxAllowedTrials/2 + 1)
+		assert.ErrorContains(t, check.Validate(config), "number of trials")
+	}
+}
+
+// validConditionalGridSearchConfig returns a grid search config with one hyperparameter per
+// optimizer, each of which is only active with its optimizer and takes count values.
+func validConditionalGridSearchConfig(count int) ExperimentConfig {
+	config := validGridSearchConfig()
+	config.Hyperparameters = map[string]Hyperparameter{
+		GlobalBatchSize: {ConstHyperparameter: &ConstHyperparameter{Val: 64}},
+		"optimizer": {
+			CategoricalHyperparameter: &CategoricalHyperparameter{Vals: []interface{}{"sgd", "adam"}},
+		},
+		"sgd": {NestedHyperparameter: &map[string]Hyperparameter{
+			"momentum": {
+				DoubleHyperparameter: &DoubleHyperparameter{Minval: 0, Maxval: 1, Count: intP(count)},
+				Conditions:           HyperparameterConditions{"optimizer": {"sgd"}},
+			},
+		}},
+		"adam": {NestedHyperparameter: &map[string]Hyperparameter{
+			"beta": {
+				DoubleHyperparameter: &DoubleHyperparameter{Minval: 0, Maxval: 1, Count: intP(count)},
+				Conditions:           HyperparameterConditions{"optimizer": {"adam"}},
+			},
+		}},
+	}
+	return config
 }
 
 // TestResourcesValidation tests that invalid resources configurations produce validation errors and
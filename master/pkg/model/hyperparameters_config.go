@@ -2,6 +2,7 @@ package model
 
 import (
 	"encoding/json"
+	"reflect"
 	"sort"
 
 	"github.com/pkg/errors"
@@ -25,6 +26,14 @@ func (h Hyperparameters) Validate() []error {
 		}
 	}
 	switch {
+	case b.NestedHyperparameter != nil:
+		return []error{
+			errors.New("global_batch_size hyperparameter must be a numeric value"),
+		}
+	case len(b.Conditions) > 0:
+		return []error{
+			errors.New("global_batch_size hyperparameter must not have conditions"),
+		}
 	case b.ConstHyperparameter != nil:
 		if !isNumeric(b.ConstHyperparameter.Val) {
 			return []error{
@@ -40,6 +49,78 @@ func (h Hyperparameters) Validate() []error {
 			}
 		}
 	}
+	return h.validateConditions()
+}
+
+// validateConditions checks that every condition refers to a categorical or constant
+// hyperparameter by its path, only lists values that hyperparameter can take, and that conditions
+// do not depend on each other in a cycle.
+func (h Hyperparameters) validateConditions() []error {
+	flat := make(map[string]Hyperparameter)
+	if dup := h.flatten("", flat); dup != "" {
+		return []error{errors.Errorf("hyperparameter %s is defined more than once", dup)}
+	}
+
+	var errs []error
+	for _, path := range sortedPaths(flat) {
+		for _, dep := range sortedPaths(flat[path].Conditions) {
+			target, ok := flat[dep]
+			switch {
+			case !ok:
+				errs = append(errs, errors.Errorf(
+					"hyperparameter %s has a condition on undefined hyperparameter %s", path, dep))
+				continue
+			case target.ConstHyperparameter == nil && target.CategoricalHyperparameter == nil:
+				errs = append(errs, errors.Errorf(
+					"hyperparameter %s has a condition on %s, which is not categorical or const",
+					path, dep))
+				continue
+			}
+			if len(flat[path].Conditions[dep]) == 0 {
+				errs = append(errs, errors.Errorf(
+					"hyperparameter %s has a condition on %s without any values", path, dep))
+			}
+			for _, val := range flat[path].Conditions[dep] {
+				if !target.canTake(val) {
+					errs = append(errs, errors.Errorf(
+						"hyperparameter %s has a condition on %s being %v, which it cannot be",
+						path, dep, val))
+				}
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	// Depth-first search for cycles; visiting marks paths on the current stack.
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	var visit func(path string) error
+	visit = func(path string) error {
+		switch state[path] {
+		case visiting:
+			return errors.Errorf("hyperparameter conditions on %s form a cycle", path)
+		case visited:
+			return nil
+		}
+		state[path] = visiting
+		for _, dep := range sortedPaths(flat[path].Conditions) {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[path] = visited
+		return nil
+	}
+	for _, path := range sortedPaths(flat) {
+		if err := visit(path); err != nil {
+			return []error{err}
+		}
+	}
 	return nil
 }
 
@@ -49,30 +130,229 @@ func isNumeric(val interface{}) bool {
 	return iOk || fOk
 }
 
-// Each applies the function to each hyperparameter in string order of the name.
+// Each applies the function to each hyperparameter in string order of the name. Nested
+// hyperparameters are flattened and named by their dot-separated path, e.g. "optimizer.lr".
 func (h Hyperparameters) Each(f func(name string, param Hyperparameter)) {
-	keys := make([]string, 0, len(h))
-	for k := range h {
-		keys = append(keys, k)
+	flat := make(map[string]Hyperparameter)
+	h.flatten("", flat)
+	for _, path := range sortedPaths(flat) {
+		f(path, flat[path])
 	}
-	sort.Strings(keys)
-	for _, k := range keys {
-		f(k, h[k])
+}
+
+// Active returns whether the conditions of the hyperparameter at the given path are met by the
+// values of the other hyperparameters, which are keyed by their paths. A hyperparameter with a
+// condition on an inactive hyperparameter is inactive as well.
+func (h Hyperparameters) Active(path string, values map[string]interface{}) bool {
+	return h.ActivePaths(values)[path]
+}
+
+// ActivePaths returns the paths of all hyperparameters whose conditions are met by the values of
+// the other hyperparameters, which are keyed by their paths.
+func (h Hyperparameters) ActivePaths(values map[string]interface{}) map[string]bool {
+	flat := make(map[string]Hyperparameter)
+	h.flatten("", flat)
+	return activePaths(flat, values)
+}
+
+func activePaths(flat map[string]Hyperparameter, values map[string]interface{}) map[string]bool {
+	active := make(map[string]bool, len(flat))
+	done := make(map[string]bool, len(flat))
+	var visit func(path string) bool
+	visit = func(path string) bool {
+		if done[path] {
+			return active[path]
+		}
+		// Marking the path as done before visiting its dependencies stops the recursion on cyclic
+		// conditions, which are rejected by validation.
+		done[path] = true
+		active[path] = conditionsMet(flat[path].Conditions, values, visit)
+		return active[path]
+	}
+	for path := range flat {
+		visit(path)
 	}
+	return active
 }
 
-// Hyperparameter is a sum type for hyperparameters.
+func conditionsMet(
+	conditions HyperparameterConditions, values map[string]interface{}, active func(string) bool,
+) bool {
+	for dep, allowed := range conditions {
+		val, ok := values[dep]
+		if !ok || !active(dep) {
+			return false
+		}
+		found := false
+		for _, a := range allowed {
+			if reflect.DeepEqual(a, val) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// GridSize returns the number of distinct points of a grid over the hyperparameters, where each
+// hyperparameter takes as many values as given by counts, keyed by path. Points that only differ in
+// hyperparameters whose conditions are not met are the same point. Counting stops once the size
+// exceeds limit.
+func (h Hyperparameters) GridSize(counts map[string]int, limit int) int {
+	flat := make(map[string]Hyperparameter)
+	product := func() int {
+		size := 1
+		for path := range flat {
+			size *= counts[path]
+		}
+		return size
+	}
+	if h.flatten("", flat) != "" || len(h.validateConditions()) > 0 {
+		return product()
+	}
+
+	// Branch on the values of the hyperparameters that conditions refer to, dependencies first, so
+	// that each branch is a distinct assignment of the active ones. Conditions only refer to such
+	// hyperparameters, so they are the only ones visited.
+	var order []string
+	seen := make(map[string]bool)
+	var visit func(path string)
+	visit = func(path string) {
+		if seen[path] {
+			return
+		}
+		seen[path] = true
+		for _, dep := range sortedPaths(flat[path].Conditions) {
+			visit(dep)
+		}
+		order = append(order, path)
+	}
+	targets := make(map[string]bool)
+	for _, path := range sortedPaths(flat) {
+		for dep := range flat[path].Conditions {
+			targets[dep] = true
+		}
+	}
+	for _, path := range sortedPaths(targets) {
+		visit(path)
+	}
+
+	size := 0
+	values := make(map[string]interface{})
+	var branch func(i int)
+	branch = func(i int) {
+		if size > limit {
+			return
+		}
+		if i == len(order) {
+			active := activePaths(flat, values)
+			points := 1
+			for path := range flat {
+				if active[path] && !targets[path] {
+					points *= counts[path]
+				}
+			}
+			size += points
+			return
+		}
+		path := order[i]
+		if !conditionsMet(flat[path].Conditions, values, func(dep string) bool {
+			_, ok := values[dep]
+			return ok
+		}) {
+			branch(i + 1)
+			return
+		}
+		for _, val := range flat[path].gridValues() {
+			values[path] = val
+			branch(i + 1)
+		}
+		delete(values, path)
+	}
+	branch(0)
+	return size
+}
+
+// gridValues returns the values of a categorical or const hyperparameter.
+func (h Hyperparameter) gridValues() []interface{} {
+	switch {
+	case h.CategoricalHyperparameter != nil:
+		return h.CategoricalHyperparameter.Vals
+	case h.ConstHyperparameter != nil:
+		return []interface{}{h.ConstHyperparameter.Val}
+	default:
+		return nil
+	}
+}
+
+// flatten adds every non-nested hyperparameter to flat, keyed by its path. It returns the first
+// path that is defined more than once, if any.
+func (h Hyperparameters) flatten(prefix string, flat map[string]Hyperparameter) string {
+	var dup string
+	for name, param := range h {
+		path := prefix + name
+		if param.NestedHyperparameter != nil {
+			if d := Hyperparameters(*param.NestedHyperparameter).flatten(path+".", flat); d != "" {
+				dup = d
+			}
+			continue
+		}
+		if _, ok := flat[path]; ok {
+			dup = path
+		}
+		flat[path] = param
+	}
+	return dup
+}
+
+func sortedPaths(m interface{}) []string {
+	var keys []string
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Hyperparameter is a sum type for hyperparameters. A JSON object without a "type" is parsed as a
+// group of nested hyperparameters.
 type Hyperparameter struct {
 	ConstHyperparameter       *ConstHyperparameter       `union:"type,const" json:"-"`
 	IntHyperparameter         *IntHyperparameter         `union:"type,int" json:"-"`
 	DoubleHyperparameter      *DoubleHyperparameter      `union:"type,double" json:"-"`
 	LogHyperparameter         *LogHyperparameter         `union:"type,log" json:"-"`
 	CategoricalHyperparameter *CategoricalHyperparameter `union:"type,categorical" json:"-"`
+
+	// NestedHyperparameter is deliberately not a Hyperparameters so that its Validate method, which
+	// checks the whole search space, only runs on the top level.
+	NestedHyperparameter *map[string]Hyperparameter `json:"-"`
+
+	Conditions HyperparameterConditions `json:"conditions"`
 }
 
+// HyperparameterConditions maps the paths of categorical or const hyperparameters to the values
+// they must take for a hyperparameter to be sampled.
+type HyperparameterConditions map[string][]interface{}
+
 // MarshalJSON implements the json.Marshaler interface.
 func (h Hyperparameter) MarshalJSON() ([]byte, error) {
-	return union.Marshal(h)
+	if h.NestedHyperparameter != nil {
+		return json.Marshal(*h.NestedHyperparameter)
+	}
+	data, err := union.Marshal(h)
+	if err != nil || len(h.Conditions) > 0 {
+		return data, err
+	}
+	// Omit empty conditions so that hyperparameters without them are marshaled as before.
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, err
+	}
+	delete(parsed, "conditions")
+	return json.Marshal(parsed)
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
@@ -81,11 +361,41 @@ func (h *Hyperparameter) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &parsed); err != nil {
 		return err
 	}
-	if _, ok := parsed.(map[string]interface{}); ok {
-		return union.Unmarshal(data, h)
+	obj, ok := parsed.(map[string]interface{})
+	if !ok {
+		h.ConstHyperparameter = &ConstHyperparameter{Val: parsed}
+		return nil
+	}
+	if _, ok := obj["type"]; !ok {
+		// Conditions are only read from hyperparameters with a type; every key of a group,
+		// including one named "conditions", is a nested hyperparameter.
+		nested := make(map[string]Hyperparameter)
+		if err := json.Unmarshal(data, &nested); err != nil {
+			return err
+		}
+		h.NestedHyperparameter = &nested
+		return nil
+	}
+	if err := union.Unmarshal(data, h); err != nil {
+		return err
+	}
+	type DefaultParser *Hyperparameter
+	return errors.Wrap(json.Unmarshal(data, DefaultParser(h)), "failed to parse hyperparameter")
+}
+
+// canTake returns whether a categorical or const hyperparameter can take the value.
+func (h Hyperparameter) canTake(val interface{}) bool {
+	switch {
+	case h.ConstHyperparameter != nil:
+		return reflect.DeepEqual(h.ConstHyperparameter.Val, val)
+	case h.CategoricalHyperparameter != nil:
+		for _, v := range h.CategoricalHyperparameter.Vals {
+			if reflect.DeepEqual(v, val) {
+				return true
+			}
+		}
 	}
-	h.ConstHyperparameter = &ConstHyperparameter{Val: parsed}
-	return nil
+	return false
 }
 
 // ConstHyperparameter is a constant.
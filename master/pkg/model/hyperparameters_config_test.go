@@ -83,3 +83,131 @@ func TestValidateGlobalBatchSize(t *testing.T) {
 		runTestCase(t, tc)
 	}
 }
+
+func TestNestedHyperparameters(t *testing.T) {
+	config := `{
+		"global_batch_size": 32,
+		"optimizer": {"type": "categorical", "vals": ["sgd", "adam"]},
+		"sgd": {
+			"momentum": {
+				"type": "double",
+				"minval": 0,
+				"maxval": 1,
+				"conditions": {"optimizer": ["sgd"]}
+			}
+		}
+	}`
+	var h Hyperparameters
+	assert.NilError(t, json.Unmarshal([]byte(config), &h))
+	assert.NilError(t, check.Validate(h))
+
+	var names []string
+	h.Each(func(name string, param Hyperparameter) {
+		names = append(names, name)
+	})
+	assert.DeepEqual(t, names, []string{"global_batch_size", "optimizer", "sgd.momentum"})
+
+	assert.Assert(t, h.Active("sgd.momentum", map[string]interface{}{"optimizer": "sgd"}))
+	assert.Assert(t, !h.Active("sgd.momentum", map[string]interface{}{"optimizer": "adam"}))
+
+	// Hyperparameters without conditions marshal as they did before conditions existed.
+	data, err := json.Marshal(h["optimizer"])
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `{"type":"categorical","vals":["sgd","adam"]}`)
+
+	data, err = json.Marshal(h)
+	assert.NilError(t, err)
+	var roundTrip Hyperparameters
+	assert.NilError(t, json.Unmarshal(data, &roundTrip))
+	assert.DeepEqual(t, roundTrip, h)
+}
+
+func TestNestedHyperparameterNamedConditions(t *testing.T) {
+	config := `{
+		"global_batch_size": 32,
+		"optimizer": {"type": "categorical", "vals": ["sgd", "adam"]},
+		"group": {
+			"conditions": {"optimizer": ["sgd"]},
+			"lr": {"type": "const", "val": 1, "conditions": {"optimizer": ["sgd"]}}
+		}
+	}`
+	var h Hyperparameters
+	assert.NilError(t, json.Unmarshal([]byte(config), &h))
+	assert.NilError(t, check.Validate(h))
+
+	var names []string
+	h.Each(func(name string, param Hyperparameter) {
+		names = append(names, name)
+	})
+	assert.DeepEqual(t, names,
+		[]string{"global_batch_size", "group.conditions.optimizer", "group.lr", "optimizer"})
+
+	group := *h["group"].NestedHyperparameter
+	assert.Assert(t, h["group"].Conditions == nil)
+	assert.DeepEqual(t, group["conditions"].NestedHyperparameter, &map[string]Hyperparameter{
+		"optimizer": {ConstHyperparameter: &ConstHyperparameter{Val: []interface{}{"sgd"}}},
+	})
+	adam := map[string]interface{}{"optimizer": "adam"}
+	assert.Assert(t, h.Active("group.conditions.optimizer", adam))
+	assert.Assert(t, !h.Active("group.lr", adam))
+}
+
+func TestValidateHyperparameterConditions(t *testing.T) {
+	testCases := []struct {
+		name         string
+		config       string
+		errorMessage string
+	}{
+		{
+			"undefined condition",
+			`{"global_batch_size": 32,
+			  "lr": {"type": "const", "val": 1, "conditions": {"optimizer": ["sgd"]}}}`,
+			"condition on undefined hyperparameter optimizer",
+		},
+		{
+			"numeric condition",
+			`{"global_batch_size": 32,
+			  "x": {"type": "int", "minval": 0, "maxval": 4},
+			  "lr": {"type": "const", "val": 1, "conditions": {"x": [1]}}}`,
+			"which is not categorical or const",
+		},
+		{
+			"impossible value",
+			`{"global_batch_size": 32,
+			  "optimizer": {"type": "categorical", "vals": ["sgd"]},
+			  "lr": {"type": "const", "val": 1, "conditions": {"optimizer": ["adam"]}}}`,
+			"being adam, which it cannot be",
+		},
+		{
+			"empty values",
+			`{"global_batch_size": 32,
+			  "optimizer": {"type": "categorical", "vals": ["sgd"]},
+			  "lr": {"type": "const", "val": 1, "conditions": {"optimizer": []}}}`,
+			"without any values",
+		},
+		{
+			"cycle",
+			`{"global_batch_size": 32,
+			  "a": {"type": "categorical", "vals": [1, 2], "conditions": {"b": [1]}},
+			  "b": {"type": "categorical", "vals": [1, 2], "conditions": {"a": [1]}}}`,
+			"form a cycle",
+		},
+		{
+			"duplicate path",
+			`{"global_batch_size": 32, "a.b": 1, "a": {"b": 2}}`,
+			"hyperparameter a.b is defined more than once",
+		},
+		{
+			"nested global_batch_size",
+			`{"global_batch_size": {"size": 32}}`,
+			"global_batch_size hyperparameter must be a numeric value",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var h Hyperparameters
+			assert.NilError(t, json.Unmarshal([]byte(tc.config), &h))
+			assert.ErrorContains(t, check.Validate(h), tc.errorMessage)
+		})
+	}
+}
@@ -12,14 +12,40 @@ type Model struct {
 	Metadata        JSONObj   `db:"metadata" json:"metadata"`
 	CreationTime    time.Time `db:"creation_time" json:"creation_time"`
 	LastUpdatedTime time.Time `db:"last_updated_time" json:"last_updated_time"`
+	ProjectID       *int      `db:"project_id" json:"project_id"`
 }
 
+// ModelVersionStage is the stage of a model version in its lifecycle.
+type ModelVersionStage string
+
+const (
+	// StageNone is the stage of versions that have not been promoted.
+	StageNone ModelVersionStage = "NONE"
+	// StageStaging versions are being validated before they are deployed.
+	StageStaging ModelVersionStage = "STAGING"
+	// StageProduction versions are deployed.
+	StageProduction ModelVersionStage = "PRODUCTION"
+	// StageArchived versions are kept for reference but no longer used.
+	StageArchived ModelVersionStage = "ARCHIVED"
+)
+
 // ModelVersion represents a row from the `model_versions` table.
 type ModelVersion struct {
-	ID           int       `db:"id" json:"id"`
-	Version      int       `db:"version" json:"version"`
-	CheckpointID int       `db:"checkpoint_id" json:"checkpoint_id"`
-	CreationTime time.Time `db:"creation_time" json:"creation_time"`
-	ModelID      int       `db:"model_id" json:"model_id"`
-	Metadata     JSONObj   `db:"metadata" json:"metadata"`
+	Version         int               `db:"version" json:"version"`
+	ModelName       string            `db:"model_name" json:"model_name"`
+	CheckpointUUID  string            `db:"checkpoint_uuid" json:"checkpoint_uuid"`
+	CreationTime    time.Time         `db:"creation_time" json:"creation_time"`
+	LastUpdatedTime *time.Time        `db:"last_updated_time" json:"last_updated_time"`
+	Metadata        JSONObj           `db:"metadata" json:"metadata"`
+	Stage           ModelVersionStage `db:"stage" json:"stage"`
+	Notes           string            `db:"notes" json:"notes"`
+	Tags            StringList        `db:"tags" json:"tags"`
+}
+
+// ModelAlias represents a row from the `model_aliases` table: a name, such as "prod", that points
+// to a version of a model.
+type ModelAlias struct {
+	ModelName string `db:"model_name" json:"model_name"`
+	Alias     string `db:"alias" json:"alias"`
+	Version   int    `db:"version" json:"version"`
 }
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+)
+
+// Project represents a row from the `projects` table. Projects own experiments, models and
+// templates; only their members may create objects in them.
+type Project struct {
+	ID           int       `db:"id" json:"id"`
+	Name         string    `db:"name" json:"name"`
+	Description  string    `db:"description" json:"description"`
+	OwnerID      UserID    `db:"owner_id" json:"owner_id"`
+	CreationTime time.Time `db:"creation_time" json:"creation_time"`
+
+	// CheckpointStorage and ResourcePool are the defaults of the experiments that are created in
+	// the project; experiment configurations and templates take precedence over them.
+	CheckpointStorage JSONObj `db:"checkpoint_storage" json:"checkpoint_storage"`
+	ResourcePool      string  `db:"resource_pool" json:"resource_pool"`
+}
@@ -22,6 +22,8 @@ type SearcherConfig struct {
 	SourceTrialID        *int    `json:"source_trial_id"`
 	SourceCheckpointUUID *string `json:"source_checkpoint_uuid"`
 
+	AdditionalMetrics []SearcherMetric `json:"additional_metrics"`
+
 	SingleConfig         *SingleConfig         `union:"name,single" json:"-"`
 	RandomConfig         *RandomConfig         `union:"name,random" json:"-"`
 	GridConfig           *GridConfig           `union:"name,grid" json:"-"`
@@ -31,6 +33,7 @@ type SearcherConfig struct {
 	AdaptiveSimpleConfig *AdaptiveSimpleConfig `union:"name,adaptive_simple" json:"-"`
 	AdaptiveASHAConfig   *AdaptiveASHAConfig   `union:"name,adaptive_asha" json:"-"`
 	PBTConfig            *PBTConfig            `union:"name,pbt" json:"-"`
+	BayesianConfig       *BayesianConfig       `union:"name,bayesian" json:"-"`
 }
 
 // MarshalJSON implements the json.Marshaler interface.
@@ -47,6 +50,37 @@ func (s *SearcherConfig) UnmarshalJSON(data []byte) error {
 	return errors.Wrap(json.Unmarshal(data, DefaultParser(s)), "failed to parse searcher config")
 }
 
+// Validate implements the check.Validatable interface.
+func (s SearcherConfig) Validate() []error {
+	if len(s.AdditionalMetrics) == 0 {
+		return nil
+	}
+	switch {
+	case s.RandomConfig != nil, s.AsyncHalvingConfig != nil, s.AdaptiveASHAConfig != nil,
+		s.PBTConfig != nil:
+	default:
+		return []error{errors.New(
+			"additional_metrics is only supported by the random, async_halving, adaptive_asha, " +
+				"and pbt searchers")}
+	}
+	var errs []error
+	seen := map[string]bool{s.Metric: true}
+	for _, m := range s.AdditionalMetrics {
+		errs = append(errs, check.NotEmpty(m.Name, "additional_metrics name must be non-empty"))
+		if seen[m.Name] {
+			errs = append(errs, errors.Errorf("searcher metric %s is specified more than once", m.Name))
+		}
+		seen[m.Name] = true
+	}
+	return errs
+}
+
+// Metrics returns the searcher metric followed by any additional metrics.
+func (s SearcherConfig) Metrics() []SearcherMetric {
+	return append([]SearcherMetric{{Name: s.Metric, SmallerIsBetter: s.SmallerIsBetter}},
+		s.AdditionalMetrics...)
+}
+
 // Unit implements the model.InUnits interface.
 func (s SearcherConfig) Unit() Unit {
 	switch {
@@ -68,11 +102,28 @@ func (s SearcherConfig) Unit() Unit {
 		return s.AdaptiveASHAConfig.Unit()
 	case s.PBTConfig != nil:
 		return s.PBTConfig.Unit()
+	case s.BayesianConfig != nil:
+		return s.BayesianConfig.Unit()
 	default:
 		panic("no searcher type specified")
 	}
 }
 
+// SearcherMetric is a validation metric that a search optimizes in addition to the searcher metric.
+// Searches with additional metrics rank trials by Pareto dominance, using the searcher metric only
+// to break ties.
+type SearcherMetric struct {
+	Name            string `json:"name"`
+	SmallerIsBetter bool   `json:"smaller_is_better"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (m *SearcherMetric) UnmarshalJSON(data []byte) error {
+	m.SmallerIsBetter = true
+	type DefaultParser *SearcherMetric
+	return errors.Wrap(json.Unmarshal(data, DefaultParser(m)), "failed to parse searcher metric")
+}
+
 // SingleConfig configures a single trial.
 type SingleConfig struct {
 	MaxLength Length `json:"max_length"`
@@ -144,13 +195,14 @@ func (s SyncHalvingConfig) Unit() Unit {
 
 // AsyncHalvingConfig configures asynchronous successive halving.
 type AsyncHalvingConfig struct {
-	Metric              string  `json:"metric"`
-	SmallerIsBetter     bool    `json:"smaller_is_better"`
-	NumRungs            int     `json:"num_rungs"`
-	MaxLength           Length  `json:"max_length"`
-	MaxTrials           int     `json:"max_trials"`
-	Divisor             float64 `json:"divisor"`
-	MaxConcurrentTrials int     `json:"max_concurrent_trials"`
+	Metric              string           `json:"metric"`
+	SmallerIsBetter     bool             `json:"smaller_is_better"`
+	AdditionalMetrics   []SearcherMetric `json:"additional_metrics"`
+	NumRungs            int              `json:"num_rungs"`
+	MaxLength           Length           `json:"max_length"`
+	MaxTrials           int              `json:"max_trials"`
+	Divisor             float64          `json:"divisor"`
+	MaxConcurrentTrials int              `json:"max_concurrent_trials"`
 }
 
 // Validate implements the check.Validatable interface.
@@ -249,15 +301,16 @@ func (a AdaptiveSimpleConfig) Unit() Unit {
 
 // AdaptiveASHAConfig configures an adaptive searcher for use with ASHA.
 type AdaptiveASHAConfig struct {
-	Metric              string       `json:"metric"`
-	SmallerIsBetter     bool         `json:"smaller_is_better"`
-	MaxLength           Length       `json:"max_length"`
-	MaxTrials           int          `json:"max_trials"`
-	BracketRungs        []int        `json:"bracket_rungs"`
-	Divisor             float64      `json:"divisor"`
-	Mode                AdaptiveMode `json:"mode"`
-	MaxRungs            int          `json:"max_rungs"`
-	MaxConcurrentTrials int          `json:"max_concurrent_trials"`
+	Metric              string           `json:"metric"`
+	SmallerIsBetter     bool             `json:"smaller_is_better"`
+	AdditionalMetrics   []SearcherMetric `json:"additional_metrics"`
+	MaxLength           Length           `json:"max_length"`
+	MaxTrials           int              `json:"max_trials"`
+	BracketRungs        []int            `json:"bracket_rungs"`
+	Divisor             float64          `json:"divisor"`
+	Mode                AdaptiveMode     `json:"mode"`
+	MaxRungs            int              `json:"max_rungs"`
+	MaxConcurrentTrials int              `json:"max_concurrent_trials"`
 }
 
 // Validate implements the check.Validatable interface.
@@ -309,11 +362,12 @@ func (e PBTExploreConfig) Validate() []error {
 
 // PBTConfig configures a PBT search.
 type PBTConfig struct {
-	Metric          string `json:"metric"`
-	SmallerIsBetter bool   `json:"smaller_is_better"`
-	PopulationSize  int    `json:"population_size"`
-	NumRounds       int    `json:"num_rounds"`
-	LengthPerRound  Length `json:"length_per_round"`
+	Metric            string           `json:"metric"`
+	SmallerIsBetter   bool             `json:"smaller_is_better"`
+	AdditionalMetrics []SearcherMetric `json:"additional_metrics"`
+	PopulationSize    int              `json:"population_size"`
+	NumRounds         int              `json:"num_rounds"`
+	LengthPerRound    Length           `json:"length_per_round"`
 
 	PBTReplaceConfig `json:"replace_function"`
 	PBTExploreConfig `json:"explore_function"`
@@ -332,3 +386,33 @@ func (p PBTConfig) Validate() []error {
 func (p PBTConfig) Unit() Unit {
 	return p.LengthPerRound.Unit
 }
+
+// BayesianConfig configures a model-based search using a tree-structured Parzen estimator (TPE).
+type BayesianConfig struct {
+	Metric              string  `json:"metric"`
+	SmallerIsBetter     bool    `json:"smaller_is_better"`
+	MaxLength           Length  `json:"max_length"`
+	MaxTrials           int     `json:"max_trials"`
+	MaxConcurrentTrials int     `json:"max_concurrent_trials"`
+	NumStartupTrials    int     `json:"num_startup_trials"`
+	NumCandidates       int     `json:"num_candidates"`
+	Gamma               float64 `json:"gamma"`
+}
+
+// Validate implements the check.Validatable interface.
+func (b BayesianConfig) Validate() []error {
+	return []error{
+		check.GreaterThan(b.MaxLength.Units, 0, "max_length must be > 0"),
+		check.GreaterThan(b.MaxTrials, 0, "max_trials must be > 0"),
+		check.GreaterThanOrEqualTo(b.MaxConcurrentTrials, 0, "max_concurrent_trials must be >= 0"),
+		check.GreaterThan(b.NumStartupTrials, 0, "num_startup_trials must be > 0"),
+		check.GreaterThan(b.NumCandidates, 0, "num_candidates must be > 0"),
+		check.GreaterThan(b.Gamma, 0.0, "gamma must be > 0"),
+		check.LessThan(b.Gamma, 1.0, "gamma must be < 1"),
+	}
+}
+
+// Unit implements the model.InUnits interface.
+func (b BayesianConfig) Unit() Unit {
+	return b.MaxLength.Unit
+}
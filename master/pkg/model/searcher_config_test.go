@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/check"
 )
 
 func TestASHAMaxConcurrentTrials(t *testing.T) {
@@ -257,3 +259,41 @@ func TestLength(t *testing.T) {
 		})
 	}
 }
+
+func TestSearcherAdditionalMetrics(t *testing.T) {
+	var actual = DefaultExperimentConfig(nil).Searcher
+	assert.NilError(t, json.Unmarshal([]byte(`
+{
+  "name": "async_halving",
+  "metric": "accuracy",
+  "smaller_is_better": false,
+  "num_rungs": 3,
+  "max_length": {"batches": 1000},
+  "max_trials": 10,
+  "additional_metrics": [{"name": "latency"}, {"name": "throughput", "smaller_is_better": false}]
+}
+`), &actual))
+	expected := []SearcherMetric{
+		{Name: "accuracy", SmallerIsBetter: false},
+		{Name: "latency", SmallerIsBetter: true},
+		{Name: "throughput", SmallerIsBetter: false},
+	}
+	assert.DeepEqual(t, actual.Metrics(), expected)
+	assert.DeepEqual(t, actual.AsyncHalvingConfig.AdditionalMetrics, expected[1:])
+	assert.NilError(t, check.Validate(actual))
+
+	duplicate := actual
+	duplicate.AdditionalMetrics = []SearcherMetric{{Name: "accuracy"}}
+	assert.ErrorContains(t, check.Validate(duplicate), "specified more than once")
+
+	var grid = DefaultExperimentConfig(nil).Searcher
+	assert.NilError(t, json.Unmarshal([]byte(`
+{
+  "name": "grid",
+  "metric": "accuracy",
+  "max_length": {"batches": 1000},
+  "additional_metrics": [{"name": "latency"}]
+}
+`), &grid))
+	assert.ErrorContains(t, check.Validate(grid), "additional_metrics is only supported")
+}
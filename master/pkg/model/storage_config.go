@@ -23,11 +23,15 @@ type CheckpointStorageConfig struct {
 	SaveExperimentBest int `json:"save_experiment_best"`
 	SaveTrialBest      int `json:"save_trial_best"`
 	SaveTrialLatest    int `json:"save_trial_latest"`
+	// MaxStorageBytes caps the total size of the checkpoints of an experiment; the lowest-ranked
+	// checkpoints are deleted to stay within it.
+	MaxStorageBytes *int64 `json:"max_storage_bytes"`
 
 	SharedFSConfig *SharedFSConfig `union:"type,shared_fs" json:"-"`
 	HDFSConfig     *HDFSConfig     `union:"type,hdfs" json:"-"`
 	S3Config       *S3Config       `union:"type,s3" json:"-"`
 	GCSConfig      *GCSConfig      `union:"type,gcs" json:"-"`
+	AzureConfig    *AzureConfig    `union:"type,azure" json:"-"`
 }
 
 // Validate implements the check.Validatable interface.
@@ -36,6 +40,7 @@ func (c CheckpointStorageConfig) Validate() []error {
 		check.GreaterThanOrEqualTo(c.SaveExperimentBest, 0, "save_experiment_best must be >= 0"),
 		check.GreaterThanOrEqualTo(c.SaveTrialBest, 0, "save_trial_best must be >= 0"),
 		check.GreaterThanOrEqualTo(c.SaveTrialLatest, 0, "save_trial_latest must be >= 0"),
+		check.GreaterThanOrEqualTo(c.MaxStorageBytes, int64(0), "max_storage_bytes must be >= 0"),
 	}
 }
 
@@ -59,6 +64,7 @@ type TensorboardStorageConfig struct {
 	HDFSConfig     *HDFSConfig     `union:"type,hdfs" json:"-"`
 	S3Config       *S3Config       `union:"type,s3" json:"-"`
 	GCSConfig      *GCSConfig      `union:"type,gcs" json:"-"`
+	AzureConfig    *AzureConfig    `union:"type,azure" json:"-"`
 }
 
 // MarshalJSON implements the json.Marshaler interface.
@@ -152,3 +158,24 @@ type GCSConfig struct {
 
 // Validate implements the check.Validatable interface.
 func (GCSConfig) Validate() []error { return nil }
+
+// AzureConfig configures storing checkpoints on Azure Blob Storage. The storage account is given
+// either by a connection string or by an account URL and an optional credential, such as an
+// account key or a SAS token.
+type AzureConfig struct {
+	Container        string  `json:"container"`
+	ConnectionString *string `json:"connection_string,omitempty"`
+	AccountURL       *string `json:"account_url,omitempty"`
+	Credential       *string `json:"credential,omitempty"`
+}
+
+// Validate implements the check.Validatable interface.
+func (a AzureConfig) Validate() []error {
+	return []error{
+		check.NotEmpty(a.Container, "container must be set"),
+		check.True((a.ConnectionString == nil) != (a.AccountURL == nil),
+			"exactly one of connection_string and account_url must be set"),
+		check.True(a.Credential == nil || a.AccountURL != nil,
+			"credential can only be set with account_url"),
+	}
+}
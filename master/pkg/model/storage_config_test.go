@@ -103,3 +103,36 @@ func TestSharedFSConfigValidate(t *testing.T) {
 		runTestCase(t, tc)
 	}
 }
+
+func TestAzureConfigValidate(t *testing.T) {
+	connectionString := "DefaultEndpointsProtocol=http;AccountName=devstoreaccount1"
+	accountURL := "https://myaccount.blob.core.windows.net"
+	credential := "key"
+
+	tests := []struct {
+		name    string
+		config  AzureConfig
+		wantErr bool
+	}{
+		{"connection string", AzureConfig{
+			Container: "c", ConnectionString: &connectionString,
+		}, false},
+		{"account URL", AzureConfig{Container: "c", AccountURL: &accountURL}, false},
+		{"account URL and credential", AzureConfig{
+			Container: "c", AccountURL: &accountURL, Credential: &credential,
+		}, false},
+		{"no account", AzureConfig{Container: "c"}, true},
+		{"both accounts", AzureConfig{
+			Container: "c", ConnectionString: &connectionString, AccountURL: &accountURL,
+		}, true},
+		{"credential without account URL", AzureConfig{
+			Container: "c", ConnectionString: &connectionString, Credential: &credential,
+		}, true},
+		{"no container", AzureConfig{ConnectionString: &connectionString}, true},
+	}
+	for _, tt := range tests {
+		if err := check.Validate(tt.config); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
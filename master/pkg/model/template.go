@@ -4,4 +4,9 @@ package model
 type Template struct {
 	Name   string `db:"name" json:"name"`
 	Config []byte `db:"config" json:"config"`
+	// OwnerID is the user that created the template; templates created before templates had
+	// owners have none.
+	OwnerID *UserID `db:"owner_id" json:"owner_id"`
+	// ProjectID is the project that the template belongs to, if any.
+	ProjectID *int `db:"project_id" json:"project_id"`
 }
@@ -3,6 +3,7 @@ package model
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -37,6 +38,39 @@ func (j *JSONObj) Scan(src interface{}) error {
 	return nil
 }
 
+// StringList is a list of strings that converts to a JSON array in SQL queries.
+type StringList []string
+
+// Value marshals a []byte.
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		l = StringList{}
+	}
+	bytes, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshaling StringList")
+	}
+	return bytes, nil
+}
+
+// Scan unmarshals a JSON array in []byte to []string.
+func (l *StringList) Scan(src interface{}) error {
+	if src == nil {
+		*l = nil
+		return nil
+	}
+	bytes, ok := src.([]byte)
+	if !ok {
+		return errors.Errorf("unable to convert to []byte: %v", src)
+	}
+	var list []string
+	if err := json.Unmarshal(bytes, &list); err != nil {
+		return errors.Wrapf(err, "unable to unmarshal StringList: %v", src)
+	}
+	*l = StringList(list)
+	return nil
+}
+
 // RawString is a string that encodes as a byte array when read or written to a
 // database yet is represented as a string otherwise.
 //
@@ -62,3 +96,30 @@ func (s *RawString) Scan(src interface{}) error {
 	}
 	return nil
 }
+
+// Duration is a JSON (un)marshallable version of time.Duration.
+type Duration time.Duration
+
+// MarshalJSON implements the json.Marshaler interface.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	switch value := v.(type) {
+	case string:
+		tmp, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.Wrap(err, "error parsing duration")
+		}
+		*d = Duration(tmp)
+		return nil
+	default:
+		return errors.Errorf("invalid duration: %s", b)
+	}
+}
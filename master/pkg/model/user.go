@@ -13,6 +13,40 @@ const BCryptCost = 15
 // UserID is the type for user IDs.
 type UserID int
 
+// Role is the role of a user, which determines what they are allowed to do.
+type Role string
+
+const (
+	// RoleViewer users can see everything in the cluster but cannot change anything.
+	RoleViewer Role = "viewer"
+	// RoleEditor users can also create experiments, commands and templates and modify the ones
+	// that they own.
+	RoleEditor Role = "editor"
+	// RoleAdmin users can also modify anything that other users own and administer the cluster.
+	RoleAdmin Role = "admin"
+)
+
+var roleRanks = map[Role]int{RoleViewer: 1, RoleEditor: 2, RoleAdmin: 3}
+
+// Valid returns whether the role is one of the known roles.
+func (r Role) Valid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// Includes returns whether the role grants everything that the other role grants.
+func (r Role) Includes(other Role) bool {
+	return roleRanks[r] >= roleRanks[other]
+}
+
+// RoleFromAdmin returns the role that corresponds to the admin flag of a user.
+func RoleFromAdmin(admin bool) Role {
+	if admin {
+		return RoleAdmin
+	}
+	return RoleEditor
+}
+
 // SessionID is the type for user session IDs.
 type SessionID int
 
@@ -23,6 +57,11 @@ type User struct {
 	PasswordHash null.String `db:"password_hash" json:"-"`
 	Admin        bool        `db:"admin" json:"admin"`
 	Active       bool        `db:"active" json:"active"`
+	// Role is what the user is allowed to do; Admin is set exactly when it is RoleAdmin.
+	Role Role `db:"role" json:"role"`
+	// LoginProvider is the name of the login provider that created the user, if any. Only that
+	// provider may log the user in.
+	LoginProvider null.String `db:"login_provider" json:"-"`
 }
 
 // UserSession corresponds to a row in the "user_sessions" DB table.
@@ -30,6 +69,11 @@ type UserSession struct {
 	ID     SessionID `db:"id" json:"id"`
 	UserID UserID    `db:"user_id" json:"user_id"`
 	Expiry time.Time `db:"expiry" json:"expiry"`
+
+	// APITokenID is set when the user authenticated with an API token rather than a session.
+	APITokenID *int `db:"-" json:"-"`
+	// ReadOnly is set when the user authenticated with a read-only API token.
+	ReadOnly bool `db:"-" json:"-"`
 }
 
 // A FullUser is a User joined with any other user relations.
@@ -38,6 +82,7 @@ type FullUser struct {
 	Username string `db:"username" json:"username"`
 	Admin    bool   `db:"admin" json:"admin"`
 	Active   bool   `db:"active" json:"active"`
+	Role     Role   `db:"role" json:"role"`
 
 	AgentUID   null.Int    `db:"agent_uid" json:"agent_uid"`
 	AgentGID   null.Int    `db:"agent_gid" json:"agent_gid"`
@@ -98,6 +143,25 @@ func (user User) ActiveCanBeModifiedBy(other User) bool {
 	return other.Admin
 }
 
+// SetRole sets the role of the user along with the admin flag that corresponds to it.
+func (user *User) SetRole(role Role) {
+	user.Role = role
+	user.Admin = role == RoleAdmin
+}
+
+// CanModify returns whether the user may modify an object that is owned by the given user; nil
+// means that the object has no owner, so only admins may modify it.
+func (user User) CanModify(owner *UserID) bool {
+	switch {
+	case user.Role.Includes(RoleAdmin):
+		return true
+	case !user.Role.Includes(RoleEditor):
+		return false
+	default:
+		return owner != nil && *owner == user.ID
+	}
+}
+
 // UpdatePasswordHash updates the model's password hash employing necessary cryptographic
 // techniques.
 func (user *User) UpdatePasswordHash(password string) error {
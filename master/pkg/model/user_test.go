@@ -0,0 +1,29 @@
+package model
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestUserCanModify(t *testing.T) {
+	owner, other := UserID(1), UserID(2)
+	user := func(role Role) User {
+		u := User{ID: owner}
+		u.SetRole(role)
+		return u
+	}
+
+	assert.Assert(t, user(RoleAdmin).Admin)
+	assert.Assert(t, !user(RoleEditor).Admin)
+	assert.Assert(t, RoleAdmin.Includes(RoleEditor))
+	assert.Assert(t, !RoleViewer.Includes(RoleEditor))
+	assert.Assert(t, !Role("owner").Valid())
+
+	assert.Assert(t, user(RoleAdmin).CanModify(&other))
+	assert.Assert(t, user(RoleAdmin).CanModify(nil))
+	assert.Assert(t, user(RoleEditor).CanModify(&owner))
+	assert.Assert(t, !user(RoleEditor).CanModify(&other))
+	assert.Assert(t, !user(RoleEditor).CanModify(nil))
+	assert.Assert(t, !user(RoleViewer).CanModify(&owner))
+}
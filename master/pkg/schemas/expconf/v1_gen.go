@@ -5,6 +5,79 @@ package expconf
 import "encoding/json"
 
 var (
+	textAzureConfigV1 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v1/azure.json",
+    "title": "AzureConfig",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [
+        "type",
+        "container"
+    ],
+    "properties": {
+        "type": {
+            "const": "azure"
+        },
+        "container": {
+            "type": "string"
+        },
+        "connection_string": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "account_url": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "credential": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "save_experiment_best": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": 0,
+            "minimum": 0
+        },
+        "save_trial_best": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": 1,
+            "minimum": 0
+        },
+        "save_trial_latest": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": 1,
+            "minimum": 0
+        },
+        "max_storage_bytes": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null,
+            "minimum": 0
+        }
+    }
+}
+`)
 	textBindMountV1 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
     "$id": "http://determined.ai/schemas/expconf/v1/bind-mount.json",
@@ -278,7 +351,7 @@ var (
     "$id": "http://determined.ai/schemas/expconf/v1/checkpoint-storage.json",
     "title": "CheckpointStorageConfig",
     "union": {
-        "defaultMessage": "is not an object where object[\"type\"] is one of 'shared_fs', 'hdfs', 's3', or 'gcs'",
+        "defaultMessage": "is not an object where object[\"type\"] is one of 'shared_fs', 'hdfs', 's3', 'gcs', or 'azure'",
         "items": [
             {
                 "unionKey": "const:type=shared_fs",
@@ -295,6 +368,10 @@ var (
             {
                 "unionKey": "const:type=gcs",
                 "$ref": "http://determined.ai/schemas/expconf/v1/gcs.json"
+            },
+            {
+                "unionKey": "const:type=azure",
+                "$ref": "http://determined.ai/schemas/expconf/v1/azure.json"
             }
         ]
     }
@@ -999,6 +1076,14 @@ var (
             ],
             "default": 1,
             "minimum": 0
+        },
+        "max_storage_bytes": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null,
+            "minimum": 0
         }
     }
 }
@@ -1059,6 +1144,14 @@ var (
             ],
             "default": 1,
             "minimum": 0
+        },
+        "max_storage_bytes": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null,
+            "minimum": 0
         }
     }
 }
@@ -1080,9 +1173,27 @@ var (
         "vals": {
             "type": "array",
             "minLength": 1
+        },
+        "conditions": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "$ref": "http://determined.ai/schemas/expconf/v1/hyperparameter-conditions.json",
+            "default": null
         }
     }
 }
+`)
+	textHyperparameterConditionsV1 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v1/hyperparameter-conditions.json",
+    "title": "HyperparameterConditions",
+    "type": "object",
+    "additionalProperties": {
+        "type": "array"
+    }
+}
 `)
 	textConstHyperparameterV1 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -1098,7 +1209,15 @@ var (
         "type": {
             "const": "const"
         },
-        "val": true
+        "val": true,
+        "conditions": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "$ref": "http://determined.ai/schemas/expconf/v1/hyperparameter-conditions.json",
+            "default": null
+        }
     }
 }
 `)
@@ -1130,6 +1249,14 @@ var (
             ],
             "default": null,
             "minimum": 1
+        },
+        "conditions": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "$ref": "http://determined.ai/schemas/expconf/v1/hyperparameter-conditions.json",
+            "default": null
         }
     },
     "compareProperties": {
@@ -1167,6 +1294,14 @@ var (
             ],
             "default": null,
             "minimum": 1
+        },
+        "conditions": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "$ref": "http://determined.ai/schemas/expconf/v1/hyperparameter-conditions.json",
+            "default": null
         }
     },
     "compareProperties": {
@@ -1209,6 +1344,14 @@ var (
             ],
             "default": null,
             "minimum": 1
+        },
+        "conditions": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "$ref": "http://determined.ai/schemas/expconf/v1/hyperparameter-conditions.json",
+            "default": null
         }
     },
     "compareProperties": {
@@ -1445,6 +1588,13 @@ var (
             ],
             "default": ""
         },
+        "expected_runtime": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
         "max_slots": {
             "type": [
                 "integer",
@@ -1555,6 +1705,14 @@ var (
             ],
             "default": 1,
             "minimum": 0
+        },
+        "max_storage_bytes": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null,
+            "minimum": 0
         }
     }
 }
@@ -1635,6 +1793,16 @@ var (
             ],
             "default": true
         },
+        "additional_metrics": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "items": {
+                "$ref": "http://determined.ai/schemas/expconf/v1/searcher-metric.json"
+            },
+            "default": []
+        },
         "source_trial_id": {
             "type": [
                 "integer",
@@ -1889,6 +2057,99 @@ var (
             ],
             "default": true
         },
+        "additional_metrics": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "items": {
+                "$ref": "http://determined.ai/schemas/expconf/v1/searcher-metric.json"
+            },
+            "default": []
+        },
+        "source_trial_id": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null
+        },
+        "source_checkpoint_uuid": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        }
+    }
+}
+`)
+	textBayesianSearcherConfigV1 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v1/searcher-bayesian.json",
+    "title": "BayesianSearcherConfig",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [
+        "name",
+        "max_trials",
+        "max_length",
+        "metric"
+    ],
+    "properties": {
+        "name": {
+            "const": "bayesian"
+        },
+        "max_trials": {
+            "type": "integer",
+            "minimum": 1
+        },
+        "max_concurrent_trials": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": 0,
+            "minimum": 0
+        },
+        "num_startup_trials": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": 10,
+            "minimum": 1
+        },
+        "num_candidates": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": 24,
+            "minimum": 1
+        },
+        "gamma": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "default": 0.25,
+            "exclusiveMinimum": 0.0,
+            "exclusiveMaximum": 1.0
+        },
+        "max_length": {
+            "$ref": "http://determined.ai/schemas/expconf/v1/check-positive-length.json"
+        },
+        "metric": {
+            "type": "string"
+        },
+        "smaller_is_better": {
+            "type": [
+                "boolean",
+                "null"
+            ],
+            "default": true
+        },
         "source_trial_id": {
             "type": [
                 "integer",
@@ -1950,6 +2211,29 @@ var (
         }
     }
 }
+`)
+	textSearcherMetricV1 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v1/searcher-metric.json",
+    "title": "SearcherMetric",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [
+        "name"
+    ],
+    "properties": {
+        "name": {
+            "type": "string"
+        },
+        "smaller_is_better": {
+            "type": [
+                "boolean",
+                "null"
+            ],
+            "default": true
+        }
+    }
+}
 `)
 	textPBTSearcherConfigV1 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -2033,6 +2317,16 @@ var (
             ],
             "default": true
         },
+        "additional_metrics": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "items": {
+                "$ref": "http://determined.ai/schemas/expconf/v1/searcher-metric.json"
+            },
+            "default": []
+        },
         "source_trial_id": {
             "type": [
                 "integer",
@@ -2083,6 +2377,16 @@ var (
             ],
             "default": true
         },
+        "additional_metrics": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "items": {
+                "$ref": "http://determined.ai/schemas/expconf/v1/searcher-metric.json"
+            },
+            "default": []
+        },
         "source_trial_id": {
             "type": [
                 "integer",
@@ -2219,7 +2523,7 @@ var (
     "$id": "http://determined.ai/schemas/expconf/v1/searcher.json",
     "title": "SearcherConfig",
     "union": {
-        "defaultMessage": "is not an object where object[\"name\"] is one of 'single', 'random', 'grid', 'adaptive', 'adaptive_asha', 'adaptive_simple', or 'pbt'",
+        "defaultMessage": "is not an object where object[\"name\"] is one of 'single', 'random', 'grid', 'adaptive', 'adaptive_asha', 'adaptive_simple', 'pbt', or 'bayesian'",
         "items": [
             {
                 "unionKey": "const:name=single",
@@ -2249,6 +2553,10 @@ var (
                 "unionKey": "const:name=pbt",
                 "$ref": "http://determined.ai/schemas/expconf/v1/searcher-pbt.json"
             },
+            {
+                "unionKey": "const:name=bayesian",
+                "$ref": "http://determined.ai/schemas/expconf/v1/searcher-bayesian.json"
+            },
             {
                 "unionKey": "const:name=sync_halving",
                 "$ref": "http://determined.ai/schemas/expconf/v1/searcher-sync-halving.json"
@@ -2336,6 +2644,14 @@ var (
             ],
             "default": 1,
             "minimum": 0
+        },
+        "max_storage_bytes": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null,
+            "minimum": 0
         }
     },
     "checks": {
@@ -2349,6 +2665,7 @@ var (
     }
 }
 `)
+	schemaAzureConfigV1                  interface{}
 	schemaBindMountV1                    interface{}
 	schemaCheckDataLayerCacheV1          interface{}
 	schemaCheckEpochNotUsedV1            interface{}
@@ -2367,6 +2684,7 @@ var (
 	schemaGCSConfigV1                    interface{}
 	schemaHDFSConfigV1                   interface{}
 	schemaCategoricalHyperparameterV1    interface{}
+	schemaHyperparameterConditionsV1     interface{}
 	schemaConstHyperparameterV1          interface{}
 	schemaDoubleHyperparameterV1         interface{}
 	schemaIntHyperparameterV1            interface{}
@@ -2381,7 +2699,9 @@ var (
 	schemaAdaptiveSimpleSearcherConfigV1 interface{}
 	schemaAdaptiveSearcherConfigV1       interface{}
 	schemaAsyncHalvingSearcherConfigV1   interface{}
+	schemaBayesianSearcherConfigV1       interface{}
 	schemaGridSearcherConfigV1           interface{}
+	schemaSearcherMetricV1               interface{}
 	schemaPBTSearcherConfigV1            interface{}
 	schemaRandomSearcherConfigV1         interface{}
 	schemaSingleSearcherConfigV1         interface{}
@@ -2392,6 +2712,17 @@ var (
 	cachedSchemaBytesMap                 map[string][]byte
 )
 
+func parsedAzureConfigV1() interface{} {
+	if schemaAzureConfigV1 != nil {
+		return schemaAzureConfigV1
+	}
+	err := json.Unmarshal(textAzureConfigV1, &schemaAzureConfigV1)
+	if err != nil {
+		panic("invalid embedded json for AzureConfigV1")
+	}
+	return schemaAzureConfigV1
+}
+
 func parsedBindMountV1() interface{} {
 	if schemaBindMountV1 != nil {
 		return schemaBindMountV1
@@ -2590,6 +2921,17 @@ func parsedCategoricalHyperparameterV1() interface{} {
 	return schemaCategoricalHyperparameterV1
 }
 
+func parsedHyperparameterConditionsV1() interface{} {
+	if schemaHyperparameterConditionsV1 != nil {
+		return schemaHyperparameterConditionsV1
+	}
+	err := json.Unmarshal(textHyperparameterConditionsV1, &schemaHyperparameterConditionsV1)
+	if err != nil {
+		panic("invalid embedded json for HyperparameterConditionsV1")
+	}
+	return schemaHyperparameterConditionsV1
+}
+
 func parsedConstHyperparameterV1() interface{} {
 	if schemaConstHyperparameterV1 != nil {
 		return schemaConstHyperparameterV1
@@ -2744,6 +3086,17 @@ func parsedAsyncHalvingSearcherConfigV1() interface{} {
 	return schemaAsyncHalvingSearcherConfigV1
 }
 
+func parsedBayesianSearcherConfigV1() interface{} {
+	if schemaBayesianSearcherConfigV1 != nil {
+		return schemaBayesianSearcherConfigV1
+	}
+	err := json.Unmarshal(textBayesianSearcherConfigV1, &schemaBayesianSearcherConfigV1)
+	if err != nil {
+		panic("invalid embedded json for BayesianSearcherConfigV1")
+	}
+	return schemaBayesianSearcherConfigV1
+}
+
 func parsedGridSearcherConfigV1() interface{} {
 	if schemaGridSearcherConfigV1 != nil {
 		return schemaGridSearcherConfigV1
@@ -2755,6 +3108,17 @@ func parsedGridSearcherConfigV1() interface{} {
 	return schemaGridSearcherConfigV1
 }
 
+func parsedSearcherMetricV1() interface{} {
+	if schemaSearcherMetricV1 != nil {
+		return schemaSearcherMetricV1
+	}
+	err := json.Unmarshal(textSearcherMetricV1, &schemaSearcherMetricV1)
+	if err != nil {
+		panic("invalid embedded json for SearcherMetricV1")
+	}
+	return schemaSearcherMetricV1
+}
+
 func parsedPBTSearcherConfigV1() interface{} {
 	if schemaPBTSearcherConfigV1 != nil {
 		return schemaPBTSearcherConfigV1
@@ -2827,6 +3191,8 @@ func schemaBytesMap() map[string][]byte {
 	}
 	var url string
 	cachedSchemaBytesMap = map[string][]byte{}
+	url = "http://determined.ai/schemas/expconf/v1/azure.json"
+	cachedSchemaBytesMap[url] = textAzureConfigV1
 	url = "http://determined.ai/schemas/expconf/v1/bind-mount.json"
 	cachedSchemaBytesMap[url] = textBindMountV1
 	url = "http://determined.ai/schemas/expconf/v1/check-data-layer-cache.json"
@@ -2863,6 +3229,8 @@ func schemaBytesMap() map[string][]byte {
 	cachedSchemaBytesMap[url] = textHDFSConfigV1
 	url = "http://determined.ai/schemas/expconf/v1/hyperparameter-categorical.json"
 	cachedSchemaBytesMap[url] = textCategoricalHyperparameterV1
+	url = "http://determined.ai/schemas/expconf/v1/hyperparameter-conditions.json"
+	cachedSchemaBytesMap[url] = textHyperparameterConditionsV1
 	url = "http://determined.ai/schemas/expconf/v1/hyperparameter-const.json"
 	cachedSchemaBytesMap[url] = textConstHyperparameterV1
 	url = "http://determined.ai/schemas/expconf/v1/hyperparameter-double.json"
@@ -2891,8 +3259,12 @@ func schemaBytesMap() map[string][]byte {
 	cachedSchemaBytesMap[url] = textAdaptiveSearcherConfigV1
 	url = "http://determined.ai/schemas/expconf/v1/searcher-async-halving.json"
 	cachedSchemaBytesMap[url] = textAsyncHalvingSearcherConfigV1
+	url = "http://determined.ai/schemas/expconf/v1/searcher-bayesian.json"
+	cachedSchemaBytesMap[url] = textBayesianSearcherConfigV1
 	url = "http://determined.ai/schemas/expconf/v1/searcher-grid.json"
 	cachedSchemaBytesMap[url] = textGridSearcherConfigV1
+	url = "http://determined.ai/schemas/expconf/v1/searcher-metric.json"
+	cachedSchemaBytesMap[url] = textSearcherMetricV1
 	url = "http://determined.ai/schemas/expconf/v1/searcher-pbt.json"
 	cachedSchemaBytesMap[url] = textPBTSearcherConfigV1
 	url = "http://determined.ai/schemas/expconf/v1/searcher-random.json"
@@ -2913,6 +3285,8 @@ func schemaMap() map[string]interface{} {
 	}
 	var url string
 	cachedSchemaMap = map[string]interface{}{}
+	url = "http://determined.ai/schemas/expconf/v1/azure.json"
+	cachedSchemaMap[url] = parsedAzureConfigV1()
 	url = "http://determined.ai/schemas/expconf/v1/bind-mount.json"
 	cachedSchemaMap[url] = parsedBindMountV1()
 	url = "http://determined.ai/schemas/expconf/v1/check-data-layer-cache.json"
@@ -2949,6 +3323,8 @@ func schemaMap() map[string]interface{} {
 	cachedSchemaMap[url] = parsedHDFSConfigV1()
 	url = "http://determined.ai/schemas/expconf/v1/hyperparameter-categorical.json"
 	cachedSchemaMap[url] = parsedCategoricalHyperparameterV1()
+	url = "http://determined.ai/schemas/expconf/v1/hyperparameter-conditions.json"
+	cachedSchemaMap[url] = parsedHyperparameterConditionsV1()
 	url = "http://determined.ai/schemas/expconf/v1/hyperparameter-const.json"
 	cachedSchemaMap[url] = parsedConstHyperparameterV1()
 	url = "http://determined.ai/schemas/expconf/v1/hyperparameter-double.json"
@@ -2977,8 +3353,12 @@ func schemaMap() map[string]interface{} {
 	cachedSchemaMap[url] = parsedAdaptiveSearcherConfigV1()
 	url = "http://determined.ai/schemas/expconf/v1/searcher-async-halving.json"
 	cachedSchemaMap[url] = parsedAsyncHalvingSearcherConfigV1()
+	url = "http://determined.ai/schemas/expconf/v1/searcher-bayesian.json"
+	cachedSchemaMap[url] = parsedBayesianSearcherConfigV1()
 	url = "http://determined.ai/schemas/expconf/v1/searcher-grid.json"
 	cachedSchemaMap[url] = parsedGridSearcherConfigV1()
+	url = "http://determined.ai/schemas/expconf/v1/searcher-metric.json"
+	cachedSchemaMap[url] = parsedSearcherMetricV1()
 	url = "http://determined.ai/schemas/expconf/v1/searcher-pbt.json"
 	cachedSchemaMap[url] = parsedPBTSearcherConfigV1()
 	url = "http://determined.ai/schemas/expconf/v1/searcher-random.json"
@@ -2,8 +2,11 @@ package searcher
 
 import (
 	"math"
+	"reflect"
 	"sort"
 
+	"github.com/pkg/errors"
+
 	"github.com/determined-ai/determined/master/pkg/model"
 )
 
@@ -63,21 +66,33 @@ func getBracketMaxConcurrentTrials(
 	return bracketMaxConcurrentTrials
 }
 
-func newAdaptiveASHASearch(config model.AdaptiveASHAConfig) SearchMethod {
-	modeFunc := parseAdaptiveMode(config.Mode)
+// adaptiveASHASearch runs one asynchronous halving search per bracket in a tournament.
+type adaptiveASHASearch struct {
+	*tournamentSearch
+	brackets []int
+}
 
-	brackets := config.BracketRungs
+// AdaptiveASHABrackets returns the number of rungs in each bracket of an adaptive ASHA search, in
+// the order that the search prioritizes them.
+func AdaptiveASHABrackets(config model.AdaptiveASHAConfig) []int {
+	brackets := append([]int(nil), config.BracketRungs...)
 	if len(brackets) == 0 {
-		config.MaxRungs = min(
+		modeFunc := parseAdaptiveMode(config.Mode)
+		maxRungs := min(
 			config.MaxRungs,
 			int(math.Log(float64(config.MaxLength.Units))/math.Log(config.Divisor))+1)
-		config.MaxRungs = min(
-			config.MaxRungs,
+		maxRungs = min(
+			maxRungs,
 			int(math.Log(float64(config.MaxTrials))/math.Log(config.Divisor))+1)
-		brackets = modeFunc(config.MaxRungs)
+		brackets = modeFunc(maxRungs)
 	}
 	// We prioritize brackets that perform more early stopping to try to max speedups early on.
 	sort.Sort(sort.Reverse(sort.IntSlice(brackets)))
+	return brackets
+}
+
+func newAdaptiveASHASearch(config model.AdaptiveASHAConfig) SearchMethod {
+	brackets := AdaptiveASHABrackets(config)
 	bracketMaxTrials := getBracketMaxTrials(
 		config.MaxTrials, config.Divisor, brackets)
 	bracketMaxConcurrentTrials := getBracketMaxConcurrentTrials(
@@ -88,6 +103,7 @@ func newAdaptiveASHASearch(config model.AdaptiveASHAConfig) SearchMethod {
 		c := model.AsyncHalvingConfig{
 			Metric:              config.Metric,
 			SmallerIsBetter:     config.SmallerIsBetter,
+			AdditionalMetrics:   config.AdditionalMetrics,
 			NumRungs:            numRungs,
 			MaxLength:           config.MaxLength,
 			MaxTrials:           bracketMaxTrials[i],
@@ -97,5 +113,40 @@ func newAdaptiveASHASearch(config model.AdaptiveASHAConfig) SearchMethod {
 		methods = append(methods, newAsyncHalvingSearch(c))
 	}
 
-	return newTournamentSearch(methods...)
+	return &adaptiveASHASearch{tournamentSearch: newTournamentSearch(methods...), brackets: brackets}
+}
+
+// continueSearch splits the new maximum number of trials across the brackets of the search. The
+// brackets themselves cannot change, so a config that does not pin them with bracket_rungs must
+// yield the same brackets as before.
+func (s *adaptiveASHASearch) continueSearch(
+	ctx context, config model.SearcherConfig,
+) ([]Operation, error) {
+	if config.AdaptiveASHAConfig == nil {
+		return nil, errors.New(
+			"an adaptive ASHA search can only be continued with an adaptive_asha searcher")
+	}
+	c := *config.AdaptiveASHAConfig
+	if brackets := AdaptiveASHABrackets(c); !reflect.DeepEqual(brackets, s.brackets) {
+		return nil, errors.Errorf(
+			"brackets cannot change from %v to %v when continuing an adaptive ASHA search",
+			s.brackets, brackets)
+	}
+
+	var operations []Operation
+	bracketMaxTrials := getBracketMaxTrials(c.MaxTrials, c.Divisor, s.brackets)
+	for i, subSearch := range s.subSearches {
+		bracket := subSearch.(*asyncHalvingSearch)
+		bracketConfig := bracket.AsyncHalvingConfig
+		bracketConfig.MaxLength = c.MaxLength
+		bracketConfig.MaxTrials = bracketMaxTrials[i]
+		bracketConfig.Divisor = c.Divisor
+		ops, err := bracket.continueSearch(
+			ctx, model.SearcherConfig{AsyncHalvingConfig: &bracketConfig})
+		if err != nil {
+			return nil, err
+		}
+		operations = append(operations, s.markCreates(i, ops)...)
+	}
+	return operations, nil
 }
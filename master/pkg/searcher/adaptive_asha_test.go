@@ -127,3 +127,52 @@ func TestAdaptiveASHASearchMethod(t *testing.T) {
 
 	runValueSimulationTestCases(t, testCases)
 }
+
+func TestAdaptiveASHASearcherContinue(t *testing.T) {
+	config := model.AdaptiveASHAConfig{
+		Metric: defaultMetric, SmallerIsBetter: true,
+		MaxLength: model.NewLengthInBatches(800), MaxTrials: 4, Divisor: 2,
+		Mode: model.StandardMode, MaxRungs: 3,
+	}
+	search := NewSearcher(0, newAdaptiveASHASearch(config), nil)
+	ops, err := search.InitialOperations()
+	assert.NilError(t, err)
+	metric := func(trialID int) float64 { return float64(-trialID) }
+	before := runToShutdown(t, search, ops, metric)
+	assert.Equal(t, len(before), 4)
+
+	more := config
+	more.MaxTrials = 16
+	ops, err = search.Continue(model.SearcherConfig{AdaptiveASHAConfig: &more})
+	assert.NilError(t, err)
+	after := runToShutdown(t, search, ops, metric)
+	for requestID := range after {
+		_, existed := before[requestID]
+		assert.Assert(t, !existed)
+	}
+	assert.Equal(t, len(search.TrialsClosed), 16)
+}
+
+func TestAdaptiveASHASearcherContinueBrackets(t *testing.T) {
+	config := model.AdaptiveASHAConfig{
+		Metric: defaultMetric, SmallerIsBetter: true,
+		MaxLength: model.NewLengthInBatches(800), MaxTrials: 2, Divisor: 2,
+		Mode: model.StandardMode, MaxRungs: 3,
+	}
+	search := NewSearcher(0, newAdaptiveASHASearch(config), nil)
+	ops, err := search.InitialOperations()
+	assert.NilError(t, err)
+	runToShutdown(t, search, ops, func(int) float64 { return 1 })
+
+	// Without pinned brackets, the larger budget allows the search to use more rungs.
+	more := config
+	more.MaxTrials = 16
+	_, err = search.Continue(model.SearcherConfig{AdaptiveASHAConfig: &more})
+	assert.ErrorContains(t, err, "brackets cannot change")
+
+	more.BracketRungs = AdaptiveASHABrackets(config)
+	ops, err = search.Continue(model.SearcherConfig{AdaptiveASHAConfig: &more})
+	assert.NilError(t, err)
+	runToShutdown(t, search, ops, func(int) float64 { return 1 })
+	assert.Equal(t, len(search.TrialsClosed), 16)
+}
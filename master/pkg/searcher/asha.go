@@ -5,6 +5,8 @@ import (
 	"math"
 	"sort"
 
+	"github.com/pkg/errors"
+
 	"github.com/determined-ai/determined/master/pkg/workload"
 
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -65,8 +67,12 @@ func (s *asyncHalvingSearch) Restore(state json.RawMessage) error {
 // promotions handles bookkeeping of validation metrics and returns a RequestID to promote if
 // appropriate.
 func (r *rung) promotionsAsync(
-	requestID model.RequestID, metric float64, divisor float64,
+	requestID model.RequestID, metric float64, objectives []float64, divisor float64,
 ) []model.RequestID {
+	if objectives != nil {
+		return r.paretoPromotionsAsync(requestID, metric, objectives, divisor)
+	}
+
 	// See if there is a trial to promote. We are increasing the total number of trials seen by 1; the
 	// number of best trials that definitely should have been promoted so far (numPromote) can only
 	// stay the same or increase by 1.
@@ -103,6 +109,41 @@ func (r *rung) promotionsAsync(
 	}
 }
 
+// paretoPromotionsAsync is promotionsAsync for searches with more than one objective. The trials of
+// the rung are ranked by Pareto dominance, and the best ranked trial among the ones that should
+// have been promoted so far is promoted if it has not been already.
+func (r *rung) paretoPromotionsAsync(
+	requestID model.RequestID, metric float64, objectives []float64, divisor float64,
+) []model.RequestID {
+	r.Metrics = append(r.Metrics, trialMetric{
+		RequestID:  requestID,
+		Metric:     metric,
+		Objectives: objectives,
+	})
+	numPromote := int(float64(len(r.Metrics)) / divisor)
+
+	ids := make([]model.RequestID, 0, len(r.Metrics))
+	byID := make(map[model.RequestID]trialMetric, len(r.Metrics))
+	points := make(map[model.RequestID][]float64, len(r.Metrics))
+	for _, t := range r.Metrics {
+		ids = append(ids, t.RequestID)
+		byID[t.RequestID] = t
+		points[t.RequestID] = t.Objectives
+	}
+	sortByParetoRank(ids, points)
+	for i, id := range ids {
+		r.Metrics[i] = byID[id]
+	}
+
+	for i := 0; i < numPromote; i++ {
+		if t := &r.Metrics[i]; !t.Promoted {
+			t.Promoted = true
+			return []model.RequestID{t.RequestID}
+		}
+	}
+	return nil
+}
+
 func (s *asyncHalvingSearch) initialOperations(ctx context) ([]Operation, error) {
 	// The number of initialOperations will control the degree of parallelism
 	// of the search experiment since we guarantee that each validationComplete
@@ -112,17 +153,55 @@ func (s *asyncHalvingSearch) initialOperations(ctx context) ([]Operation, error)
 	// Otherwise we will default to a number of trials that will
 	// guarantee at least one trial at the top rung.
 	var ops []Operation
-	var maxConcurrentTrials int
+	for trial := 0; trial < s.maxConcurrentTrials(); trial++ {
+		create := NewCreate(
+			ctx.rand, sampleAll(ctx.hparams, ctx.rand), model.TrialWorkloadSequencerType)
+		s.TrialRungs[create.RequestID] = 0
+		ops = append(ops, create)
+		ops = append(ops, NewTrain(create.RequestID, s.Rungs[0].UnitsNeeded))
+		ops = append(ops, NewValidate(create.RequestID))
+	}
+	return ops, nil
+}
 
+func (s *asyncHalvingSearch) maxConcurrentTrials() int {
 	if s.MaxConcurrentTrials > 0 {
-		maxConcurrentTrials = min(s.MaxConcurrentTrials, s.MaxTrials)
-	} else {
-		maxConcurrentTrials = max(
-			min(int(math.Pow(s.Divisor, float64(s.NumRungs-1))), s.MaxTrials),
-			1)
+		return min(s.MaxConcurrentTrials, s.MaxTrials)
+	}
+	return max(min(int(math.Pow(s.Divisor, float64(s.NumRungs-1))), s.MaxTrials), 1)
+}
+
+// continueSearch raises the maximum number of trials of the search and creates as many new trials
+// as the search would run concurrently. The trials of the finished search have all been closed, so
+// they are marked as promoted and new trials are promoted only when they rank among the best of
+// every trial in the rung.
+func (s *asyncHalvingSearch) continueSearch(
+	ctx context, config model.SearcherConfig,
+) ([]Operation, error) {
+	if config.AsyncHalvingConfig == nil {
+		return nil, errors.New(
+			"an asynchronous halving search can only be continued with an async_halving searcher")
+	}
+	c := *config.AsyncHalvingConfig
+	switch {
+	case c.NumRungs != s.NumRungs || c.Divisor != s.Divisor:
+		return nil, errors.New(
+			"num_rungs and divisor cannot change when continuing an asynchronous halving search")
+	case c.MaxLength != s.MaxLength:
+		return nil, errors.New(
+			"max_length cannot change when continuing an asynchronous halving search")
+	}
+	s.AsyncHalvingConfig = c
+
+	for _, r := range s.Rungs {
+		for i := range r.Metrics {
+			r.Metrics[i].Promoted = true
+		}
 	}
 
-	for trial := 0; trial < maxConcurrentTrials; trial++ {
+	var ops []Operation
+	allTrials := len(s.TrialRungs) - s.InvalidTrials
+	for trial := 0; trial < min(s.maxConcurrentTrials(), s.MaxTrials-allTrials); trial++ {
 		create := NewCreate(
 			ctx.rand, sampleAll(ctx.hparams, ctx.rand), model.TrialWorkloadSequencerType)
 		s.TrialRungs[create.RequestID] = 0
@@ -161,11 +240,31 @@ func (s *asyncHalvingSearch) validationCompleted(
 		metric *= -1
 	}
 
-	return s.promoteAsync(ctx, requestID, metric), nil
+	var objectives []float64
+	if searcherMetrics := s.searcherMetrics(); searcherMetrics != nil {
+		if objectives, err = Objectives(metrics, searcherMetrics); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.promoteAsync(ctx, requestID, metric, objectives), nil
+}
+
+func (s *asyncHalvingSearch) searcherMetrics() []model.SearcherMetric {
+	return multiObjective(s.Metric, s.SmallerIsBetter, s.AdditionalMetrics)
+}
+
+// exitedObjectives returns the objectives of a trial that exited early, if the search has more than
+// one objective.
+func (s *asyncHalvingSearch) exitedObjectives() []float64 {
+	if searcherMetrics := s.searcherMetrics(); searcherMetrics != nil {
+		return exitedObjectives(len(searcherMetrics))
+	}
+	return nil
 }
 
 func (s *asyncHalvingSearch) promoteAsync(
-	ctx context, requestID model.RequestID, metric float64,
+	ctx context, requestID model.RequestID, metric float64, objectives []float64,
 ) []Operation {
 	// Upon a validation complete, we should return at least one more train&val workload
 	// unless the bracket of successive halving is finished.
@@ -179,8 +278,9 @@ func (s *asyncHalvingSearch) promoteAsync(
 	if rungIndex == s.NumRungs-1 {
 		rung.Metrics = append(rung.Metrics,
 			trialMetric{
-				RequestID: requestID,
-				Metric:    metric,
+				RequestID:  requestID,
+				Metric:     metric,
+				Objectives: objectives,
 			},
 		)
 
@@ -194,6 +294,7 @@ func (s *asyncHalvingSearch) promoteAsync(
 		for _, promotionID := range rung.promotionsAsync(
 			requestID,
 			metric,
+			objectives,
 			s.Divisor,
 		) {
 			s.TrialRungs[promotionID] = rungIndex + 1
@@ -207,7 +308,8 @@ func (s *asyncHalvingSearch) promoteAsync(
 				// We make a recursive call that will behave the same
 				// as if we'd actually run the promoted job and received
 				// the worse possible result in return.
-				return s.promoteAsync(ctx, promotionID, ashaExitedMetricValue)
+				return s.promoteAsync(
+					ctx, promotionID, ashaExitedMetricValue, s.exitedObjectives())
 			}
 		}
 	}
@@ -292,5 +394,5 @@ func (s *asyncHalvingSearch) trialExitedEarly(
 	}
 	s.EarlyExitTrials[requestID] = true
 	s.ClosedTrials[requestID] = true
-	return s.promoteAsync(ctx, requestID, ashaExitedMetricValue), nil
+	return s.promoteAsync(ctx, requestID, ashaExitedMetricValue, s.exitedObjectives()), nil
 }
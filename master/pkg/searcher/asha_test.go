@@ -3,6 +3,8 @@ package searcher
 import (
 	"testing"
 
+	"gotest.tools/assert"
+
 	"github.com/determined-ai/determined/master/pkg/model"
 )
 
@@ -236,3 +238,43 @@ func TestASHASearchMethod(t *testing.T) {
 
 	runValueSimulationTestCases(t, testCases)
 }
+
+func TestASHASearcherContinue(t *testing.T) {
+	config := model.AsyncHalvingConfig{
+		Metric: defaultMetric, SmallerIsBetter: true, NumRungs: 2,
+		MaxLength: model.NewLengthInBatches(400),
+		Divisor:   2,
+		MaxTrials: 4,
+	}
+	search := NewSearcher(0, newAsyncHalvingSearch(config), nil)
+	ops, err := search.InitialOperations()
+	assert.NilError(t, err)
+	// Later trials are better, so new trials outrank every trial of the finished search.
+	metric := func(trialID int) float64 { return float64(-trialID) }
+	before := runToShutdown(t, search, ops, metric)
+	assert.Equal(t, len(before), 4)
+
+	longer := config
+	longer.MaxLength = model.NewLengthInBatches(800)
+	_, err = search.Continue(model.SearcherConfig{AsyncHalvingConfig: &longer})
+	assert.ErrorContains(t, err, "max_length cannot change")
+
+	more := config
+	more.MaxTrials = 8
+	ops, err = search.Continue(model.SearcherConfig{AsyncHalvingConfig: &more})
+	assert.NilError(t, err)
+	after := runToShutdown(t, search, ops, metric)
+	assert.Equal(t, len(after), 4)
+	promoted := 0
+	for requestID, workloads := range after {
+		_, existed := before[requestID]
+		assert.Assert(t, !existed)
+		if isExpected(workloads, toOps("200B V 200B V")) {
+			promoted++
+		} else {
+			assert.Assert(t, isExpected(workloads, toOps("200B V")))
+		}
+	}
+	assert.Assert(t, promoted > 0)
+	assert.Equal(t, len(search.TrialsClosed), 8)
+}
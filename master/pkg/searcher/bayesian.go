@@ -0,0 +1,400 @@
+package searcher
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/nprand"
+	"github.com/determined-ai/determined/master/pkg/workload"
+)
+
+// BayesianSearch implements a sequential model-based search using a tree-structured Parzen
+// estimator (TPE). See https://papers.nips.cc/paper/4443-algorithms-for-hyper-parameter-optimization
+// for details. The first NumStartupTrials trials are sampled at random; every later trial is
+// sampled to maximize the ratio between the densities of the best and the remaining completed
+// trials.
+type (
+	bayesianObservation struct {
+		RequestID model.RequestID `json:"request_id"`
+		Params    tpeCoordinates  `json:"params"`
+		Metric    float64         `json:"metric"`
+	}
+
+	bayesianSearchState struct {
+		// TrialParams contains the coordinates of trials that have not reported a result yet.
+		TrialParams   map[model.RequestID]tpeCoordinates `json:"trial_params"`
+		Observations  []bayesianObservation              `json:"observations"`
+		CreatedTrials int                                `json:"created_trials"`
+		InvalidTrials int                                `json:"invalid_trials"`
+	}
+
+	bayesianSearch struct {
+		defaultSearchMethod
+		model.BayesianConfig
+		bayesianSearchState
+	}
+)
+
+func newBayesianSearch(config model.BayesianConfig) SearchMethod {
+	return &bayesianSearch{
+		BayesianConfig: config,
+		bayesianSearchState: bayesianSearchState{
+			TrialParams: make(map[model.RequestID]tpeCoordinates),
+		},
+	}
+}
+
+func (s *bayesianSearch) Snapshot() (json.RawMessage, error) {
+	return json.Marshal(s.bayesianSearchState)
+}
+
+func (s *bayesianSearch) Restore(state json.RawMessage) error {
+	if state == nil {
+		return nil
+	}
+	return json.Unmarshal(state, &s.bayesianSearchState)
+}
+
+func (s *bayesianSearch) initialOperations(ctx context) ([]Operation, error) {
+	// The model can only improve as results come in, so the number of initial trials bounds how
+	// many trials are in flight at once; every result then triggers exactly one new trial.
+	maxConcurrentTrials := min(s.NumStartupTrials, s.MaxTrials)
+	if s.MaxConcurrentTrials > 0 {
+		maxConcurrentTrials = min(s.MaxConcurrentTrials, s.MaxTrials)
+	}
+
+	var ops []Operation
+	for trial := 0; trial < maxConcurrentTrials; trial++ {
+		ops = append(ops, s.newTrial(ctx)...)
+	}
+	return ops, nil
+}
+
+func (s *bayesianSearch) validationCompleted(
+	ctx context, requestID model.RequestID, validate Validate, metrics workload.ValidationMetrics,
+) ([]Operation, error) {
+	params, ok := s.TrialParams[requestID]
+	if !ok {
+		return nil, nil
+	}
+	metric, err := metrics.Metric(s.Metric)
+	if err != nil {
+		return nil, err
+	}
+	if !s.SmallerIsBetter {
+		metric *= -1
+	}
+	delete(s.TrialParams, requestID)
+	s.Observations = append(s.Observations, bayesianObservation{
+		RequestID: requestID,
+		Params:    params,
+		Metric:    metric,
+	})
+	return s.nextTrial(ctx), nil
+}
+
+func (s *bayesianSearch) progress(unitsCompleted float64) float64 {
+	return unitsCompleted / float64(s.MaxLength.MultInt(s.MaxTrials).Units)
+}
+
+// trialExitedEarly replaces the trial with a new one. Trials that exited because of invalid
+// hyperparameters do not count towards MaxTrials.
+func (s *bayesianSearch) trialExitedEarly(
+	ctx context, requestID model.RequestID, exitedReason workload.ExitedReason,
+) ([]Operation, error) {
+	if _, ok := s.TrialParams[requestID]; !ok {
+		return nil, nil
+	}
+	delete(s.TrialParams, requestID)
+	if exitedReason == workload.InvalidHP {
+		s.InvalidTrials++
+	}
+	return s.nextTrial(ctx), nil
+}
+
+func (s *bayesianSearch) nextTrial(ctx context) []Operation {
+	if s.CreatedTrials-s.InvalidTrials >= s.MaxTrials {
+		return nil
+	}
+	return s.newTrial(ctx)
+}
+
+func (s *bayesianSearch) newTrial(ctx context) []Operation {
+	dims := tpeDimensions(ctx.hparams)
+	params := s.suggest(ctx.rand, ctx.hparams, dims)
+	flat := decodeSample(ctx.hparams, dims, params)
+	// Hyperparameters whose conditions are not met do not affect the trial, so they are left out of
+	// the observation as well.
+	active := ctx.hparams.ActivePaths(flat)
+	for _, d := range dims {
+		if !active[d.name] {
+			delete(params, d.name)
+		}
+	}
+	create := NewCreate(
+		ctx.rand, resolve(ctx.hparams, flat), model.TrialWorkloadSequencerType)
+	s.TrialParams[create.RequestID] = params
+	s.CreatedTrials++
+	return []Operation{
+		create,
+		NewTrain(create.RequestID, s.MaxLength),
+		NewValidate(create.RequestID),
+		NewClose(create.RequestID),
+	}
+}
+
+// suggest proposes the coordinates of the next trial. Until enough observations have been made it
+// samples uniformly; afterwards it splits the observations into the best Gamma fraction and the
+// rest, fits a Parzen estimator to each, and picks the candidate drawn from the good estimator with
+// the highest density ratio between the two.
+func (s *bayesianSearch) suggest(
+	rand *nprand.State, h model.Hyperparameters, dims []tpeDimension,
+) tpeCoordinates {
+	if len(s.Observations) < max(s.NumStartupTrials, 2) {
+		params := make(tpeCoordinates)
+		for _, d := range dims {
+			params[d.name] = d.sampleUniform(rand)
+		}
+		return params
+	}
+
+	observations := make([]bayesianObservation, len(s.Observations))
+	copy(observations, s.Observations)
+	sort.SliceStable(observations, func(i, j int) bool {
+		return observations[i].Metric < observations[j].Metric
+	})
+	numGood := int(math.Ceil(s.Gamma * float64(len(observations))))
+	numGood = min(max(numGood, 1), len(observations)-1)
+
+	good := make([]parzenEstimator, 0, len(dims))
+	bad := make([]parzenEstimator, 0, len(dims))
+	for _, d := range dims {
+		good = append(good, newParzenEstimator(d, observations[:numGood]))
+		bad = append(bad, newParzenEstimator(d, observations[numGood:]))
+	}
+
+	var best tpeCoordinates
+	bestScore := math.Inf(-1)
+	for i := 0; i < s.NumCandidates; i++ {
+		candidate := make(tpeCoordinates)
+		for j, d := range dims {
+			candidate[d.name] = good[j].sample(rand)
+		}
+		flat := decodeSample(h, dims, candidate)
+		active := h.ActivePaths(flat)
+		score := 0.0
+		for j, d := range dims {
+			if active[d.name] {
+				x := candidate[d.name]
+				score += math.Log(good[j].density(x)) - math.Log(bad[j].density(x))
+			}
+		}
+		if best == nil || score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+	return best
+}
+
+// tpeCoordinates maps each non-constant hyperparameter to its position in the search space. Numeric
+// hyperparameters are searched over their range (log hyperparameters over their exponent), and
+// categorical ones over the index of their level.
+type tpeCoordinates map[string]float64
+
+type tpeDimension struct {
+	name  string
+	param model.Hyperparameter
+	low   float64
+	high  float64
+	// levels is the number of levels of a categorical hyperparameter, and 0 for numeric ones.
+	levels int
+}
+
+func tpeDimensions(h model.Hyperparameters) []tpeDimension {
+	var dims []tpeDimension
+	h.Each(func(name string, param model.Hyperparameter) {
+		d := tpeDimension{name: name, param: param}
+		switch {
+		case param.IntHyperparameter != nil:
+			d.low = float64(param.IntHyperparameter.Minval)
+			d.high = float64(param.IntHyperparameter.Maxval)
+		case param.DoubleHyperparameter != nil:
+			d.low = param.DoubleHyperparameter.Minval
+			d.high = param.DoubleHyperparameter.Maxval
+		case param.LogHyperparameter != nil:
+			d.low = param.LogHyperparameter.Minval
+			d.high = param.LogHyperparameter.Maxval
+		case param.CategoricalHyperparameter != nil:
+			d.levels = len(param.CategoricalHyperparameter.Vals)
+		default:
+			return
+		}
+		dims = append(dims, d)
+	})
+	return dims
+}
+
+func (d tpeDimension) sampleUniform(rand *nprand.State) float64 {
+	if d.levels > 0 {
+		return float64(rand.Intn(d.levels))
+	}
+	return rand.Uniform(d.low, d.high)
+}
+
+func (d tpeDimension) decode(x float64) interface{} {
+	switch {
+	case d.param.IntHyperparameter != nil:
+		p := d.param.IntHyperparameter
+		return intClamp(int(math.Floor(x)), p.Minval, p.Maxval-1)
+	case d.param.DoubleHyperparameter != nil:
+		return x
+	case d.param.LogHyperparameter != nil:
+		return math.Pow(d.param.LogHyperparameter.Base, x)
+	default:
+		vals := d.param.CategoricalHyperparameter.Vals
+		return vals[intClamp(int(x), 0, len(vals)-1)]
+	}
+}
+
+func decodeSample(
+	h model.Hyperparameters, dims []tpeDimension, params tpeCoordinates,
+) hparamSample {
+	sample := make(hparamSample)
+	h.Each(func(name string, param model.Hyperparameter) {
+		if param.ConstHyperparameter != nil {
+			sample[name] = param.ConstHyperparameter.Val
+		}
+	})
+	for _, d := range dims {
+		sample[d.name] = d.decode(params[d.name])
+	}
+	return sample
+}
+
+const (
+	// tpePriorWeight is the weight of the uniform prior relative to a single observation.
+	tpePriorWeight = 1.0
+	// tpeMinBandwidth is the smallest kernel bandwidth as a fraction of the range of a dimension.
+	tpeMinBandwidth = 0.01
+	// tpeMaxRejections bounds the attempts at drawing a kernel sample inside the range.
+	tpeMaxRejections = 100
+)
+
+// parzenEstimator is a one-dimensional density fit to a set of observations. Numeric dimensions
+// use a mixture of truncated Gaussian kernels centered on the observations; categorical dimensions
+// use smoothed level frequencies. Both are mixed with a uniform prior so that unexplored regions
+// keep a nonzero density.
+type parzenEstimator struct {
+	dim     tpeDimension
+	points  []float64
+	sigma   float64
+	weights []float64
+}
+
+func newParzenEstimator(d tpeDimension, observations []bayesianObservation) parzenEstimator {
+	e := parzenEstimator{dim: d}
+	for _, o := range observations {
+		// Observations of trials in which the hyperparameter was inactive say nothing about it.
+		if x, ok := o.Params[d.name]; ok {
+			e.points = append(e.points, x)
+		}
+	}
+
+	if d.levels > 0 {
+		e.weights = make([]float64, d.levels)
+		for i := range e.weights {
+			e.weights[i] = tpePriorWeight
+		}
+		for _, p := range e.points {
+			e.weights[intClamp(int(p), 0, d.levels-1)]++
+		}
+		return e
+	}
+
+	// Scott's rule, floored so that the kernels never collapse onto the observations.
+	width := d.high - d.low
+	n := float64(len(e.points))
+	e.sigma = width
+	if len(e.points) > 1 {
+		e.sigma = math.Min(stddev(e.points)*math.Pow(n, -1.0/5), width)
+	}
+	e.sigma = math.Max(e.sigma, tpeMinBandwidth*width)
+	return e
+}
+
+func (e parzenEstimator) sample(rand *nprand.State) float64 {
+	if e.dim.levels > 0 {
+		total := 0.0
+		for _, w := range e.weights {
+			total += w
+		}
+		target := rand.UnitInterval() * total
+		for i, w := range e.weights {
+			if target < w {
+				return float64(i)
+			}
+			target -= w
+		}
+		return float64(len(e.weights) - 1)
+	}
+
+	component := rand.Intn(len(e.points) + 1)
+	if component == len(e.points) {
+		return e.dim.sampleUniform(rand)
+	}
+	mu := e.points[component]
+	for i := 0; i < tpeMaxRejections; i++ {
+		if x := mu + e.sigma*normal(rand); x >= e.dim.low && x < e.dim.high {
+			return x
+		}
+	}
+	return doubleClamp(mu, e.dim.low, e.dim.high)
+}
+
+func (e parzenEstimator) density(x float64) float64 {
+	if e.dim.levels > 0 {
+		total := 0.0
+		for _, w := range e.weights {
+			total += w
+		}
+		return e.weights[intClamp(int(x), 0, e.dim.levels-1)] / total
+	}
+
+	width := e.dim.high - e.dim.low
+	density := tpePriorWeight / width
+	for _, mu := range e.points {
+		mass := normalCDF((e.dim.high-mu)/e.sigma) - normalCDF((e.dim.low-mu)/e.sigma)
+		density += normalPDF((x-mu)/e.sigma) / e.sigma / mass
+	}
+	return density / (float64(len(e.points)) + tpePriorWeight)
+}
+
+// normal draws a sample from the standard normal distribution using the Box-Muller transform.
+func normal(rand *nprand.State) float64 {
+	u1 := 1 - rand.UnitInterval()
+	u2 := rand.UnitInterval()
+	return math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
+}
+
+func normalPDF(z float64) float64 {
+	return math.Exp(-z*z/2) / math.Sqrt(2*math.Pi)
+}
+
+func normalCDF(z float64) float64 {
+	return (1 + math.Erf(z/math.Sqrt2)) / 2
+}
+
+func stddev(vals []float64) float64 {
+	mean := 0.0
+	for _, v := range vals {
+		mean += v
+	}
+	mean /= float64(len(vals))
+	variance := 0.0
+	for _, v := range vals {
+		variance += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(variance / float64(len(vals)))
+}
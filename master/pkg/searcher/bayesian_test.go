@@ -0,0 +1,220 @@
+package searcher
+
+import (
+	"math"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/nprand"
+	"github.com/determined-ai/determined/master/pkg/workload"
+)
+
+func defaultBayesianConfig() model.BayesianConfig {
+	return model.BayesianConfig{
+		Metric:           defaultMetric,
+		SmallerIsBetter:  true,
+		MaxLength:        model.NewLengthInRecords(19200),
+		MaxTrials:        4,
+		NumStartupTrials: 2,
+		NumCandidates:    24,
+		Gamma:            0.25,
+	}
+}
+
+func TestBayesianSearcherRecords(t *testing.T) {
+	actual := defaultBayesianConfig()
+	expected := [][]Runnable{
+		toOps("19200R V"),
+		toOps("19200R V"),
+		toOps("19200R V"),
+		toOps("19200R V"),
+	}
+	search := newBayesianSearch(actual)
+	checkSimulation(t, search, nil, RandomValidation, expected)
+}
+
+func TestBayesianSearcherBatches(t *testing.T) {
+	actual := defaultBayesianConfig()
+	actual.MaxLength = model.NewLengthInBatches(300)
+	expected := [][]Runnable{
+		toOps("300B V"),
+		toOps("300B V"),
+		toOps("300B V"),
+		toOps("300B V"),
+	}
+	search := newBayesianSearch(actual)
+	checkSimulation(t, search, nil, RandomValidation, expected)
+}
+
+func TestBayesianSearcherReproducibility(t *testing.T) {
+	conf := defaultBayesianConfig()
+	conf.MaxTrials = 16
+	hparams := model.Hyperparameters{
+		"cat": {CategoricalHyperparameter: &model.CategoricalHyperparameter{
+			Vals: []interface{}{"a", "b", "c"}}},
+		"double": {DoubleHyperparameter: &model.DoubleHyperparameter{Minval: 0, Maxval: 1}},
+		"int":    {IntHyperparameter: &model.IntHyperparameter{Minval: 1, Maxval: 64}},
+		"log":    {LogHyperparameter: &model.LogHyperparameter{Base: 10, Minval: -5, Maxval: -1}},
+	}
+	gen := func() SearchMethod { return newBayesianSearch(conf) }
+	checkReproducibility(t, gen, hparams, defaultMetric)
+}
+
+func TestBayesianSearchMethod(t *testing.T) {
+	testCases := []valueSimulationTestCase{
+		{
+			name: "test bayesian search method",
+			expectedTrials: []predefinedTrial{
+				newConstantPredefinedTrial(toOps("500B V"), .1),
+				newConstantPredefinedTrial(toOps("500B V"), .2),
+				newConstantPredefinedTrial(toOps("500B V"), .3),
+				newEarlyExitPredefinedTrial(toOps("500B"), .1),
+			},
+			config: model.SearcherConfig{
+				BayesianConfig: &model.BayesianConfig{
+					Metric:           "error",
+					SmallerIsBetter:  true,
+					MaxLength:        model.NewLengthInBatches(500),
+					MaxTrials:        4,
+					NumStartupTrials: 2,
+					NumCandidates:    24,
+					Gamma:            0.25,
+				},
+			},
+		},
+		{
+			name: "test bayesian search method with max concurrent trials",
+			expectedTrials: []predefinedTrial{
+				newConstantPredefinedTrial(toOps("32017R V"), .1),
+				newConstantPredefinedTrial(toOps("32017R V"), .1),
+				newConstantPredefinedTrial(toOps("32017R V"), .1),
+				newConstantPredefinedTrial(toOps("32017R V"), .1),
+				newConstantPredefinedTrial(toOps("32017R V"), .1),
+			},
+			config: model.SearcherConfig{
+				BayesianConfig: &model.BayesianConfig{
+					Metric:              "error",
+					SmallerIsBetter:     false,
+					MaxLength:           model.NewLengthInRecords(32017),
+					MaxTrials:           5,
+					MaxConcurrentTrials: 1,
+					NumStartupTrials:    2,
+					NumCandidates:       24,
+					Gamma:               0.25,
+				},
+			},
+		},
+	}
+
+	runValueSimulationTestCases(t, testCases)
+}
+
+func TestBayesianSearcherInvalidHP(t *testing.T) {
+	method := newBayesianSearch(defaultBayesianConfig())
+	ctx := context{rand: nprand.New(0)}
+
+	ops, err := method.initialOperations(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, len(ops), 8)
+
+	ops, err = method.trialExitedEarly(ctx, ops[0].(Create).RequestID, workload.InvalidHP)
+	assert.NilError(t, err)
+	assert.Equal(t, len(ops), 4, "invalid trial should be replaced")
+
+	ops, err = method.trialExitedEarly(ctx, ops[0].(Create).RequestID, workload.Errored)
+	assert.NilError(t, err)
+	assert.Equal(t, len(ops), 4, "errored trial should be replaced")
+
+	ops, err = method.trialExitedEarly(ctx, ops[0].(Create).RequestID, workload.Errored)
+	assert.NilError(t, err)
+	assert.Equal(t, len(ops), 4, "errored trial should count towards max_trials")
+
+	ops, err = method.trialExitedEarly(ctx, ops[0].(Create).RequestID, workload.Errored)
+	assert.NilError(t, err)
+	assert.Equal(t, len(ops), 0, "max_trials should have been reached")
+}
+
+func TestBayesianSearcherConverges(t *testing.T) {
+	const optimum = 7.0
+	conf := defaultBayesianConfig()
+	conf.MaxTrials = 40
+	conf.NumStartupTrials = 10
+	conf.MaxConcurrentTrials = 1
+	hparams := model.Hyperparameters{
+		"x": {DoubleHyperparameter: &model.DoubleHyperparameter{Minval: 0, Maxval: 10}},
+	}
+
+	method := newBayesianSearch(conf)
+	ctx := context{rand: nprand.New(0), hparams: hparams}
+	pending, err := method.initialOperations(ctx)
+	assert.NilError(t, err)
+
+	var distances []float64
+	for len(pending) > 0 {
+		op := pending[0]
+		pending = pending[1:]
+		create, ok := op.(Create)
+		if !ok {
+			continue
+		}
+		distance := math.Abs(create.Hparams["x"].(float64) - optimum)
+		distances = append(distances, distance)
+		metrics := workload.ValidationMetrics{
+			Metrics: map[string]interface{}{defaultMetric: distance * distance},
+		}
+		ops, err := method.validationCompleted(
+			ctx, create.RequestID, NewValidate(create.RequestID), metrics)
+		assert.NilError(t, err)
+		assert.NilError(t, saveAndReload(method))
+		pending = append(pending, ops...)
+	}
+	assert.Equal(t, len(distances), conf.MaxTrials)
+
+	mean := func(vals []float64) float64 {
+		sum := 0.0
+		for _, v := range vals {
+			sum += v
+		}
+		return sum / float64(len(vals))
+	}
+	random := mean(distances[:conf.NumStartupTrials])
+	guided := mean(distances[len(distances)-conf.NumStartupTrials:])
+	assert.Assert(t, guided < random/2,
+		"model-based trials (%f) should be closer to the optimum than random ones (%f)",
+		guided, random)
+}
+
+func TestBayesianSearcherConditional(t *testing.T) {
+	conf := defaultBayesianConfig()
+	conf.MaxTrials = 12
+	conf.MaxConcurrentTrials = 1
+	hparams := conditionalHyperparameters()
+
+	method := newBayesianSearch(conf).(*bayesianSearch)
+	ctx := context{rand: nprand.New(0), hparams: hparams}
+	pending, err := method.initialOperations(ctx)
+	assert.NilError(t, err)
+	for len(pending) > 0 {
+		op := pending[0]
+		pending = pending[1:]
+		create, ok := op.(Create)
+		if !ok {
+			continue
+		}
+		_, nested := create.Hparams["sgd"]
+		assert.Equal(t, nested, create.Hparams["optimizer"] == "sgd")
+		metrics := workload.ValidationMetrics{Metrics: map[string]interface{}{defaultMetric: 1.0}}
+		ops, err := method.validationCompleted(
+			ctx, create.RequestID, NewValidate(create.RequestID), metrics)
+		assert.NilError(t, err)
+		pending = append(pending, ops...)
+	}
+
+	assert.Equal(t, len(method.Observations), conf.MaxTrials)
+	for _, o := range method.Observations {
+		_, ok := o.Params["sgd.momentum"]
+		assert.Equal(t, ok, o.Params["optimizer"] == 0, "inactive coordinates should not be observed")
+	}
+}
@@ -1,6 +1,7 @@
 package searcher
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 
@@ -53,7 +54,21 @@ func newHyperparameterGrid(params model.Hyperparameters) []hparamSample {
 		names = append(names, name)
 		values = append(values, grid(param))
 	})
-	return cartesianProduct(names, values)
+	// Points that only differ in hyperparameters whose conditions are not met are the same trial.
+	var points []hparamSample
+	seen := make(map[string]bool)
+	for _, flat := range cartesianProduct(names, values) {
+		point := resolve(params, flat)
+		key, err := json.Marshal(point)
+		if err != nil {
+			panic(fmt.Sprintf("unable to marshal hyperparameters: %v", err))
+		}
+		if !seen[string(key)] {
+			seen[string(key)] = true
+			points = append(points, point)
+		}
+	}
+	return points
 }
 
 func cartesianProduct(names []string, valueSets [][]interface{}) []hparamSample {
@@ -77,6 +77,17 @@ func TestGrid(t *testing.T) {
 	assert.DeepEqual(t, actual, expected)
 }
 
+func TestGridConditional(t *testing.T) {
+	// Points that only differ in the momentum of adam trials collapse into one.
+	hparams := conditionalHyperparameters()
+	grid := newHyperparameterGrid(hparams)
+	assert.Equal(t, len(grid), 4)
+
+	// The size that validation checks matches the grid that is searched.
+	counts := map[string]int{"optimizer": 2, "sgd.momentum": 3}
+	assert.Equal(t, hparams.GridSize(counts, model.MaxAllowedTrials), len(grid))
+}
+
 func TestGridIntCount(t *testing.T) {
 	hparams := model.Hyperparameters{
 		"1": model.Hyperparameter{
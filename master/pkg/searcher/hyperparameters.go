@@ -22,6 +22,12 @@ func (h hparamSample) GlobalBatchSize() int {
 }
 
 func sampleAll(h model.Hyperparameters, rand *nprand.State) hparamSample {
+	return resolve(h, sampleFlat(h, rand))
+}
+
+// sampleFlat samples every hyperparameter, including ones whose conditions are not met, keyed by
+// its dot-separated path.
+func sampleFlat(h model.Hyperparameters, rand *nprand.State) hparamSample {
 	results := make(hparamSample)
 	h.Each(func(name string, param model.Hyperparameter) {
 		results[name] = sampleOne(param, rand)
@@ -29,6 +35,35 @@ func sampleAll(h model.Hyperparameters, rand *nprand.State) hparamSample {
 	return results
 }
 
+// resolve converts a flat sample into the sample a trial sees: hyperparameters whose conditions
+// are not met are dropped and nested hyperparameters are grouped into maps.
+func resolve(h model.Hyperparameters, flat hparamSample) hparamSample {
+	return resolveNested(h.ActivePaths(flat), flat, h, "")
+}
+
+func resolveNested(
+	active map[string]bool, flat hparamSample, h model.Hyperparameters, prefix string,
+) hparamSample {
+	results := make(hparamSample)
+	for name, param := range h {
+		path := prefix + name
+		if param.NestedHyperparameter != nil {
+			nested := resolveNested(
+				active, flat, model.Hyperparameters(*param.NestedHyperparameter), path+".")
+			// Groups are left out when all of their hyperparameters are inactive, but an empty
+			// group is kept as an empty map.
+			if len(nested) > 0 || len(*param.NestedHyperparameter) == 0 {
+				results[name] = map[string]interface{}(nested)
+			}
+			continue
+		}
+		if val, ok := flat[path]; ok && active[path] {
+			results[name] = val
+		}
+	}
+	return results
+}
+
 func sampleOne(h model.Hyperparameter, rand *nprand.State) interface{} {
 	switch {
 	case h.ConstHyperparameter != nil:
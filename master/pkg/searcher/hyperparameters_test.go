@@ -42,3 +42,37 @@ func TestSamplingReproducibility(t *testing.T) {
 		assert.Equal(t, rand1.Bits64(), rand2.Bits64())
 	}
 }
+
+func conditionalHyperparameters() model.Hyperparameters {
+	return model.Hyperparameters{
+		"optimizer": {CategoricalHyperparameter: &model.CategoricalHyperparameter{
+			Vals: []interface{}{"sgd", "adam"}}},
+		"sgd": {NestedHyperparameter: &map[string]model.Hyperparameter{
+			"momentum": {
+				DoubleHyperparameter: &model.DoubleHyperparameter{Minval: 0, Maxval: 1, Count: intP(3)},
+				Conditions:           model.HyperparameterConditions{"optimizer": {"sgd"}},
+			},
+		}},
+	}
+}
+
+func TestSampleConditional(t *testing.T) {
+	spec := conditionalHyperparameters()
+	rand := nprand.New(0)
+	for i := 0; i < 20; i++ {
+		sample := sampleAll(spec, rand)
+		switch sample["optimizer"] {
+		case "sgd":
+			nested, ok := sample["sgd"].(map[string]interface{})
+			assert.Assert(t, ok, "sgd hyperparameters should be nested")
+			assert.Equal(t, len(nested), 1)
+			_, ok = nested["momentum"].(float64)
+			assert.Assert(t, ok)
+		case "adam":
+			_, ok := sample["sgd"]
+			assert.Assert(t, !ok, "inactive hyperparameters should not be sampled")
+		default:
+			t.Fatalf("unexpected optimizer: %v", sample["optimizer"])
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package searcher
+
+import (
+	"math"
+	"sort"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/workload"
+)
+
+// Objectives returns the values of the given searcher metrics in a set of validation metrics,
+// negated where larger values are better so that smaller values are always better.
+func Objectives(
+	metrics workload.ValidationMetrics, searcherMetrics []model.SearcherMetric,
+) ([]float64, error) {
+	objectives := make([]float64, 0, len(searcherMetrics))
+	for _, m := range searcherMetrics {
+		val, err := metrics.Metric(m.Name)
+		if err != nil {
+			return nil, err
+		}
+		if !m.SmallerIsBetter {
+			val *= -1
+		}
+		objectives = append(objectives, val)
+	}
+	return objectives, nil
+}
+
+// dominates returns whether a is at least as good as b in every objective and strictly better in
+// at least one.
+func dominates(a, b []float64) bool {
+	strictly := false
+	for i := range a {
+		switch {
+		case a[i] > b[i]:
+			return false
+		case a[i] < b[i]:
+			strictly = true
+		}
+	}
+	return strictly
+}
+
+// ParetoRanks returns the index of the nondominated front that each point belongs to: points on
+// the Pareto front have rank 0, points on the front of the remaining points have rank 1, and so on.
+func ParetoRanks(points [][]float64) []int {
+	ranks := make([]int, len(points))
+	// dominatedBy counts the points that dominate each point; dominating lists the points that each
+	// point dominates.
+	dominatedBy := make([]int, len(points))
+	dominating := make([][]int, len(points))
+	for i := range points {
+		for j := range points {
+			if dominates(points[i], points[j]) {
+				dominating[i] = append(dominating[i], j)
+				dominatedBy[j]++
+			}
+		}
+	}
+
+	var front []int
+	for i := range points {
+		if dominatedBy[i] == 0 {
+			front = append(front, i)
+		}
+	}
+	for rank := 0; len(front) > 0; rank++ {
+		var next []int
+		for _, i := range front {
+			ranks[i] = rank
+			for _, j := range dominating[i] {
+				dominatedBy[j]--
+				if dominatedBy[j] == 0 {
+					next = append(next, j)
+				}
+			}
+		}
+		front = next
+	}
+	return ranks
+}
+
+// exitedObjectives returns the objectives of a trial that exited before it could be evaluated,
+// which every evaluated trial dominates.
+func exitedObjectives(n int) []float64 {
+	objectives := make([]float64, n)
+	for i := range objectives {
+		objectives[i] = math.MaxFloat64
+	}
+	return objectives
+}
+
+// sortByParetoRank sorts trials by the rank of their objectives, breaking ties by the first
+// objective and then by creation order.
+func sortByParetoRank(ids []model.RequestID, objectives map[model.RequestID][]float64) {
+	points := make([][]float64, 0, len(ids))
+	for _, id := range ids {
+		points = append(points, objectives[id])
+	}
+	ranks := ParetoRanks(points)
+	rank := make(map[model.RequestID]int, len(ids))
+	for i, id := range ids {
+		rank[id] = ranks[i]
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		id1, id2 := ids[i], ids[j]
+		switch o1, o2 := objectives[id1], objectives[id2]; {
+		case rank[id1] != rank[id2]:
+			return rank[id1] < rank[id2]
+		case o1[0] != o2[0]:
+			return o1[0] < o2[0]
+		default:
+			return id1.Before(id2)
+		}
+	})
+}
+
+// multiObjective returns the metrics a search optimizes, or nil if it only optimizes the searcher
+// metric and so ranks trials by that metric alone.
+func multiObjective(
+	metric string, smallerIsBetter bool, additional []model.SearcherMetric,
+) []model.SearcherMetric {
+	if len(additional) == 0 {
+		return nil
+	}
+	return append([]model.SearcherMetric{{Name: metric, SmallerIsBetter: smallerIsBetter}},
+		additional...)
+}
@@ -0,0 +1,64 @@
+package searcher
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/nprand"
+	"github.com/determined-ai/determined/master/pkg/workload"
+)
+
+func TestParetoRanks(t *testing.T) {
+	points := [][]float64{
+		{1, 4},
+		{2, 2},
+		{4, 1},
+		{3, 3},
+		{2, 2},
+		{5, 5},
+	}
+	assert.DeepEqual(t, ParetoRanks(points), []int{0, 0, 0, 1, 0, 2})
+}
+
+func TestObjectives(t *testing.T) {
+	metrics := workload.ValidationMetrics{Metrics: map[string]interface{}{
+		"accuracy": 0.9,
+		"latency":  12.0,
+	}}
+	objectives, err := Objectives(metrics, []model.SearcherMetric{
+		{Name: "accuracy", SmallerIsBetter: false},
+		{Name: "latency", SmallerIsBetter: true},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, objectives, []float64{-0.9, 12.0})
+
+	_, err = Objectives(metrics, []model.SearcherMetric{{Name: "size", SmallerIsBetter: true}})
+	assert.ErrorContains(t, err, "'size' could not be found")
+}
+
+func TestASHAParetoPromotions(t *testing.T) {
+	r := &rung{}
+	rand := nprand.New(0)
+	promote := func(metric float64, objectives ...float64) (model.RequestID, []model.RequestID) {
+		requestID := model.NewRequestID(rand)
+		return requestID, r.promotionsAsync(requestID, metric, objectives, 2)
+	}
+
+	// With a divisor of 2, the second trial makes room for one promotion. Neither of the first two
+	// trials dominates the other, so the one that is better in the searcher metric is promoted.
+	_, promoted := promote(3, 3, 1)
+	assert.Equal(t, len(promoted), 0)
+	second, promoted := promote(1, 1, 3)
+	assert.DeepEqual(t, promoted, []model.RequestID{second})
+
+	// A dominated trial does not displace the promoted one.
+	_, promoted = promote(4, 4, 4)
+	assert.Equal(t, len(promoted), 0)
+
+	// A nondominated trial ranks ahead of the dominated one and ties with the first trial, which it
+	// beats in the searcher metric.
+	fourth, promoted := promote(2, 2, 2)
+	assert.DeepEqual(t, promoted, []model.RequestID{fourth})
+}
@@ -21,6 +21,9 @@ type (
 		TrialRoundsCompleted map[model.RequestID]int           `json:"trial_rounds_completed"`
 		TrialParams          map[model.RequestID]hparamSample  `json:"trial_params"`
 		WaitingCheckpoints   map[model.RequestID]OperationList `json:"waiting_checkpoints"`
+		// Objectives contains the values of all searcher metrics, oriented so that smaller is better,
+		// of the trials in Metrics when the search has more than one.
+		Objectives map[model.RequestID][]float64 `json:"objectives"`
 
 		// EarlyExitTrials contains trials that exited early that are still considered in the search.
 		EarlyExitTrials map[model.RequestID]bool `json:"early_exit_trials"`
@@ -43,6 +46,7 @@ func newPBTSearch(config model.PBTConfig) SearchMethod {
 			TrialRoundsCompleted: make(map[model.RequestID]int),
 			TrialParams:          make(map[model.RequestID]hparamSample),
 			WaitingCheckpoints:   make(map[model.RequestID]OperationList),
+			Objectives:           make(map[model.RequestID][]float64),
 			EarlyExitTrials:      make(map[model.RequestID]bool),
 		},
 	}
@@ -62,9 +66,9 @@ func (s *pbtSearch) Restore(state json.RawMessage) error {
 func (s *pbtSearch) initialOperations(ctx context) ([]Operation, error) {
 	var ops []Operation
 	for trial := 0; trial < s.PopulationSize; trial++ {
-		create := NewCreate(
-			ctx.rand, sampleAll(ctx.hparams, ctx.rand), model.TrialWorkloadSequencerType)
-		s.TrialParams[create.RequestID] = create.Hparams
+		params := sampleFlat(ctx.hparams, ctx.rand)
+		create := NewCreate(ctx.rand, resolve(ctx.hparams, params), model.TrialWorkloadSequencerType)
+		s.TrialParams[create.RequestID] = params
 		ops = append(ops, create)
 		ops = append(ops, NewTrain(create.RequestID, s.LengthPerRound))
 		ops = append(ops, NewValidate(create.RequestID))
@@ -91,9 +95,21 @@ func (s *pbtSearch) validationCompleted(
 	}
 	s.Metrics[requestID] = metric * sign
 
+	if searcherMetrics := s.searcherMetrics(); searcherMetrics != nil {
+		objectives, err := Objectives(metrics, searcherMetrics)
+		if err != nil {
+			return nil, err
+		}
+		s.Objectives[requestID] = objectives
+	}
+
 	return s.runNewTrials(ctx, requestID)
 }
 
+func (s *pbtSearch) searcherMetrics() []model.SearcherMetric {
+	return multiObjective(s.Metric, s.SmallerIsBetter, s.AdditionalMetrics)
+}
+
 func (s *pbtSearch) runNewTrials(ctx context, requestID model.RequestID) ([]Operation, error) {
 	var ops []Operation
 
@@ -122,17 +138,31 @@ func (s *pbtSearch) runNewTrials(ctx context, requestID model.RequestID) ([]Oper
 	for trialID := range s.Metrics {
 		trialIDs = append(trialIDs, trialID)
 	}
-	sort.Slice(trialIDs, func(i, j int) bool {
-		id1 := trialIDs[i]
-		id2 := trialIDs[j]
-		m1 := s.Metrics[id1]
-		m2 := s.Metrics[id2]
-		if m1 != m2 {
-			return m1 < m2
+	if searcherMetrics := s.searcherMetrics(); searcherMetrics != nil {
+		// Rank trials by Pareto dominance; trials that exited early have no objectives and rank last.
+		objectives := make(map[model.RequestID][]float64, len(trialIDs))
+		for _, trialID := range trialIDs {
+			if o, ok := s.Objectives[trialID]; ok && !s.EarlyExitTrials[trialID] {
+				objectives[trialID] = o
+			} else {
+				objectives[trialID] = exitedObjectives(len(searcherMetrics))
+			}
 		}
-		return id1.Before(id2)
-	})
+		sortByParetoRank(trialIDs, objectives)
+	} else {
+		sort.Slice(trialIDs, func(i, j int) bool {
+			id1 := trialIDs[i]
+			id2 := trialIDs[j]
+			m1 := s.Metrics[id1]
+			m2 := s.Metrics[id2]
+			if m1 != m2 {
+				return m1 < m2
+			}
+			return id1.Before(id2)
+		})
+	}
 	s.Metrics = make(map[model.RequestID]float64)
+	s.Objectives = make(map[model.RequestID][]float64)
 
 	// Close the worst trials.
 	for i := len(trialIDs) - numTruncate; i < len(trialIDs); i++ {
@@ -152,7 +182,7 @@ func (s *pbtSearch) runNewTrials(ctx context, requestID model.RequestID) ([]Oper
 			newParams := s.exploreParams(ctx, origParams)
 
 			create := NewCreateFromCheckpoint(
-				ctx.rand, newParams, checkpoint, model.TrialWorkloadSequencerType)
+				ctx.rand, resolve(ctx.hparams, newParams), checkpoint, model.TrialWorkloadSequencerType)
 			s.TrialParams[create.RequestID] = newParams
 
 			// The new trial cannot begin until the checkpoint has been completed.
@@ -9,6 +9,7 @@ import (
 	"github.com/determined-ai/determined/master/pkg/check"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/nprand"
+	"github.com/determined-ai/determined/master/pkg/workload"
 )
 
 func TestPBTSearcherWorkloads(t *testing.T) {
@@ -478,3 +479,51 @@ func TestPBTSearchMethod(t *testing.T) {
 
 	runValueSimulationTestCases(t, testCases)
 }
+
+func TestPBTParetoTruncation(t *testing.T) {
+	config := model.PBTConfig{
+		Metric:            "loss",
+		SmallerIsBetter:   true,
+		AdditionalMetrics: []model.SearcherMetric{{Name: "latency", SmallerIsBetter: true}},
+		PopulationSize:    4,
+		NumRounds:         2,
+		LengthPerRound:    model.NewLengthInBatches(100),
+		PBTReplaceConfig:  model.PBTReplaceConfig{TruncateFraction: .25},
+	}
+	hparams := model.Hyperparameters{
+		"x": {ConstHyperparameter: &model.ConstHyperparameter{Val: 1}},
+	}
+	method := newPBTSearch(config)
+	ctx := context{rand: nprand.New(0), hparams: hparams}
+	ops, err := method.initialOperations(ctx)
+	assert.NilError(t, err)
+
+	var creates []Create
+	for _, op := range ops {
+		if create, ok := op.(Create); ok {
+			creates = append(creates, create)
+		}
+	}
+	assert.Equal(t, len(creates), 4)
+
+	// The last trial has the worst loss but the best latency, so it is on the Pareto front; the
+	// third trial is dominated by the second and is the one replaced.
+	results := [][2]float64{{1, 10}, {2, 1}, {2.5, 5}, {3, .5}}
+	for i, create := range creates {
+		metrics := workload.ValidationMetrics{Metrics: map[string]interface{}{
+			"loss":    results[i][0],
+			"latency": results[i][1],
+		}}
+		ops, err = method.validationCompleted(
+			ctx, create.RequestID, NewValidate(create.RequestID), metrics)
+		assert.NilError(t, err)
+	}
+
+	var closed []model.RequestID
+	for _, op := range ops {
+		if c, ok := op.(Close); ok {
+			closed = append(closed, c.RequestID)
+		}
+	}
+	assert.DeepEqual(t, closed, []model.RequestID{creates[2].RequestID})
+}
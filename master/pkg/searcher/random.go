@@ -1,6 +1,8 @@
 package searcher
 
 import (
+	"github.com/pkg/errors"
+
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/workload"
 )
@@ -34,6 +36,39 @@ func (s *randomSearch) initialOperations(ctx context) ([]Operation, error) {
 	return ops, nil
 }
 
+// continueSearch creates the trials needed to reach the new maximum number of trials. A larger
+// max_length only applies to the trials created from now on.
+func (s *randomSearch) continueSearch(
+	ctx context, config model.SearcherConfig,
+) ([]Operation, error) {
+	if config.RandomConfig == nil {
+		return nil, errors.New("a random search can only be continued with a random searcher")
+	}
+	c := *config.RandomConfig
+	switch {
+	case c.MaxTrials < s.MaxTrials:
+		return nil, errors.Errorf(
+			"max_trials cannot be decreased from %d to %d", s.MaxTrials, c.MaxTrials)
+	case c.MaxLength.Unit != s.MaxLength.Unit:
+		return nil, errors.Errorf(
+			"max_length cannot change units from %s to %s", s.MaxLength.Unit, c.MaxLength.Unit)
+	case c.MaxLength.Units < s.MaxLength.Units:
+		return nil, errors.Errorf(
+			"max_length cannot be decreased from %d to %d", s.MaxLength.Units, c.MaxLength.Units)
+	}
+
+	var ops []Operation
+	for trial := s.MaxTrials; trial < c.MaxTrials; trial++ {
+		create := NewCreate(ctx.rand, sampleAll(ctx.hparams, ctx.rand), model.TrialWorkloadSequencerType)
+		ops = append(ops, create)
+		ops = append(ops, NewTrain(create.RequestID, c.MaxLength))
+		ops = append(ops, NewValidate(create.RequestID))
+		ops = append(ops, NewClose(create.RequestID))
+	}
+	s.RandomConfig = c
+	return ops, nil
+}
+
 func (s *randomSearch) progress(unitsCompleted float64) float64 {
 	return unitsCompleted / float64(s.MaxLength.MultInt(s.MaxTrials).Units)
 }
@@ -3,6 +3,8 @@ package searcher
 import (
 	"testing"
 
+	"gotest.tools/assert"
+
 	"github.com/determined-ai/determined/master/pkg/model"
 )
 
@@ -90,3 +92,39 @@ func TestSingleSearchMethod(t *testing.T) {
 
 	runValueSimulationTestCases(t, testCases)
 }
+
+func TestRandomSearcherContinue(t *testing.T) {
+	config := model.RandomConfig{MaxTrials: 2, MaxLength: model.NewLengthInBatches(100)}
+	search := NewSearcher(0, newRandomSearch(config), nil)
+	ops, err := search.InitialOperations()
+	assert.NilError(t, err)
+	before := runToShutdown(t, search, ops, func(int) float64 { return 1 })
+	assert.Equal(t, len(before), 2)
+
+	_, err = search.Continue(model.SearcherConfig{RandomConfig: &model.RandomConfig{
+		MaxTrials: 1, MaxLength: config.MaxLength,
+	}})
+	assert.ErrorContains(t, err, "max_trials cannot be decreased")
+	_, err = search.Continue(model.SearcherConfig{RandomConfig: &config})
+	assert.ErrorContains(t, err, "does not allow any more trials")
+
+	ops, err = search.Continue(model.SearcherConfig{RandomConfig: &model.RandomConfig{
+		MaxTrials: 4, MaxLength: model.NewLengthInBatches(200),
+	}})
+	assert.NilError(t, err)
+	after := runToShutdown(t, search, ops, func(int) float64 { return 1 })
+	assert.Equal(t, len(after), 2)
+	for requestID, workloads := range after {
+		_, existed := before[requestID]
+		assert.Assert(t, !existed)
+		assert.Assert(t, isExpected(workloads, toOps("200B V")))
+	}
+	assert.Equal(t, search.TrialsRequested, 4)
+}
+
+func TestSingleSearcherContinue(t *testing.T) {
+	config := model.SingleConfig{MaxLength: model.NewLengthInBatches(100)}
+	search := NewSearcher(0, newSingleSearch(config), nil)
+	_, err := search.Continue(model.SearcherConfig{SingleConfig: &config})
+	assert.ErrorContains(t, err, "can only be continued with a random searcher")
+}
@@ -0,0 +1,115 @@
+package searcher
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/workload"
+)
+
+// LearningCurvePoint is a set of validation metrics that a trial reported after training for a
+// total length, in the units of the searcher.
+type LearningCurvePoint struct {
+	Length  int                    `json:"length"`
+	Metrics map[string]interface{} `json:"metrics"`
+}
+
+// LearningCurve is the validation history of a trial.
+type LearningCurve []LearningCurvePoint
+
+// at returns the metrics of the last point of the curve at or before the given length, or of the
+// first point if the curve starts after it.
+func (c LearningCurve) at(length int) map[string]interface{} {
+	i := sort.Search(len(c), func(i int) bool { return c[i].Length > length })
+	if i == 0 {
+		return c[0].Metrics
+	}
+	return c[i-1].Metrics
+}
+
+// ReplayPoint is the best searcher metric that a replayed search has found after its trials have
+// trained for a total length.
+type ReplayPoint struct {
+	TotalUnits int     `json:"total_units"`
+	BestMetric float64 `json:"best_metric"`
+}
+
+// Replay holds the results of replaying a searcher against recorded learning curves.
+type Replay struct {
+	Simulation
+	// Progress holds a point for every validation, so that it traces the best searcher metric
+	// against the total compute that the search has used.
+	Progress []ReplayPoint `json:"progress"`
+}
+
+// ReplayLearningCurves simulates the searcher with trials that report metrics from recorded
+// learning curves instead of training. Each trial follows one of the curves, which are dealt out
+// in a random order and reshuffled once all of them have been used. A validation reports the metrics
+// of the last point of the curve at or before the length that the trial has trained for.
+func ReplayLearningCurves(
+	s *Searcher, curves []LearningCurve, seed int64, metric string, smallerIsBetter bool,
+) (Replay, error) {
+	if len(curves) == 0 {
+		return Replay{}, errors.New("at least one learning curve is required")
+	}
+	sorted := make([]LearningCurve, 0, len(curves))
+	for i, c := range curves {
+		if len(c) == 0 {
+			return Replay{}, errors.Errorf("learning curve %d has no points", i)
+		}
+		c = append(LearningCurve(nil), c...)
+		sort.SliceStable(c, func(i, j int) bool { return c[i].Length < c[j].Length })
+		sorted = append(sorted, c)
+	}
+
+	replay := Replay{Simulation: Simulation{Results: make(SimulationResults), Seed: seed}}
+	random := rand.New(rand.NewSource(seed))
+
+	var deck []int
+	assigned := map[model.RequestID]LearningCurve{}
+	trained := map[model.RequestID]int{}
+	totalUnits := 0
+	var best *float64
+	err := simulate(s, &replay.Simulation, random, true,
+		func(requestID model.RequestID, _, _ int, operation Runnable) (interface{}, error) {
+			curve, ok := assigned[requestID]
+			if !ok {
+				if len(deck) == 0 {
+					deck = random.Perm(len(sorted))
+				}
+				curve, deck = sorted[deck[0]], deck[1:]
+				assigned[requestID] = curve
+			}
+
+			switch operation := operation.(type) {
+			case Train:
+				trained[requestID] += operation.Length.Units
+				totalUnits += operation.Length.Units
+				return make(map[string]interface{}), nil
+			case Validate:
+				metrics := workload.ValidationMetrics{
+					NumInputs: 1,
+					Metrics:   curve.at(trained[requestID]),
+				}
+				value, err := metrics.Metric(metric)
+				if err != nil {
+					return nil, err
+				}
+				if best == nil || (smallerIsBetter && value < *best) ||
+					(!smallerIsBetter && value > *best) {
+					best = &value
+				}
+				replay.Progress = append(replay.Progress, ReplayPoint{
+					TotalUnits: totalUnits,
+					BestMetric: *best,
+				})
+				return &metrics, nil
+			default:
+				return generateMetrics(random, 0, 0, operation, nil, metric)
+			}
+		})
+	return replay, err
+}
@@ -0,0 +1,86 @@
+package searcher
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestLearningCurveAt(t *testing.T) {
+	curve := LearningCurve{
+		{Length: 100, Metrics: map[string]interface{}{defaultMetric: 0.5}},
+		{Length: 200, Metrics: map[string]interface{}{defaultMetric: 0.3}},
+	}
+	assert.Equal(t, curve.at(50)[defaultMetric], 0.5)
+	assert.Equal(t, curve.at(100)[defaultMetric], 0.5)
+	assert.Equal(t, curve.at(199)[defaultMetric], 0.5)
+	assert.Equal(t, curve.at(200)[defaultMetric], 0.3)
+	assert.Equal(t, curve.at(1000)[defaultMetric], 0.3)
+}
+
+func TestReplayLearningCurvesRandom(t *testing.T) {
+	config := model.RandomConfig{MaxTrials: 2, MaxLength: model.NewLengthInBatches(200)}
+	curves := []LearningCurve{
+		{
+			{Length: 200, Metrics: map[string]interface{}{defaultMetric: 0.3}},
+			{Length: 100, Metrics: map[string]interface{}{defaultMetric: 0.5}},
+		},
+		{{Length: 200, Metrics: map[string]interface{}{defaultMetric: 0.4}}},
+	}
+	replay, err := ReplayLearningCurves(
+		NewSearcher(0, newRandomSearch(config), nil), curves, 0, defaultMetric, true)
+	assert.NilError(t, err)
+	assert.Equal(t, len(replay.Results), 2)
+	assert.Equal(t, len(replay.Progress), 2)
+	assert.Equal(t, replay.Progress[1], ReplayPoint{TotalUnits: 400, BestMetric: 0.3})
+}
+
+func TestReplayLearningCurvesASHA(t *testing.T) {
+	config := model.AsyncHalvingConfig{
+		Metric: defaultMetric, SmallerIsBetter: false, NumRungs: 3,
+		MaxLength: model.NewLengthInBatches(900),
+		Divisor:   3,
+		MaxTrials: 9,
+	}
+	var curves []LearningCurve
+	for i := 0; i < 9; i++ {
+		var curve LearningCurve
+		for length := 100; length <= 900; length += 100 {
+			curve = append(curve, LearningCurvePoint{
+				Length:  length,
+				Metrics: map[string]interface{}{defaultMetric: float64(i * length)},
+			})
+		}
+		curves = append(curves, curve)
+	}
+	replay, err := ReplayLearningCurves(
+		NewSearcher(0, newAsyncHalvingSearch(config), nil), curves, 0, defaultMetric, false)
+	assert.NilError(t, err)
+
+	// Every curve is dealt out once, and the best curve is promoted to the top rung.
+	assert.Equal(t, len(replay.Results), 9)
+	last := replay.Progress[len(replay.Progress)-1]
+	assert.Equal(t, last.BestMetric, float64(8*900))
+	for i := 1; i < len(replay.Progress); i++ {
+		assert.Assert(t, replay.Progress[i].TotalUnits >= replay.Progress[i-1].TotalUnits)
+		assert.Assert(t, replay.Progress[i].BestMetric >= replay.Progress[i-1].BestMetric)
+	}
+}
+
+func TestReplayLearningCurvesErrors(t *testing.T) {
+	config := model.RandomConfig{MaxTrials: 1, MaxLength: model.NewLengthInBatches(100)}
+	_, err := ReplayLearningCurves(
+		NewSearcher(0, newRandomSearch(config), nil), nil, 0, defaultMetric, true)
+	assert.ErrorContains(t, err, "at least one learning curve")
+
+	_, err = ReplayLearningCurves(
+		NewSearcher(0, newRandomSearch(config), nil), []LearningCurve{{}}, 0, defaultMetric, true)
+	assert.ErrorContains(t, err, "has no points")
+
+	curves := []LearningCurve{{{Length: 100, Metrics: map[string]interface{}{"other": 1.0}}}}
+	_, err = ReplayLearningCurves(
+		NewSearcher(0, newRandomSearch(config), nil), curves, 0, defaultMetric, true)
+	assert.ErrorContains(t, err, "could not be found")
+}
@@ -53,6 +53,14 @@ type SearchMethod interface {
 	model.InUnits
 }
 
+// continuableSearchMethod is implemented by search methods that can resume a finished search with
+// a larger budget.
+type continuableSearchMethod interface {
+	// continueSearch updates the search method to the budget of the provided searcher config and
+	// returns the operations for any trials that the larger budget allows.
+	continueSearch(ctx context, config model.SearcherConfig) ([]Operation, error)
+}
+
 // NewSearchMethod returns a new search method for the provided searcher configuration.
 func NewSearchMethod(c model.SearcherConfig) SearchMethod {
 	switch {
@@ -74,6 +82,8 @@ func NewSearchMethod(c model.SearcherConfig) SearchMethod {
 		return newAdaptiveASHASearch(*c.AdaptiveASHAConfig)
 	case c.PBTConfig != nil:
 		return newPBTSearch(*c.PBTConfig)
+	case c.BayesianConfig != nil:
+		return newBayesianSearch(*c.BayesianConfig)
 	default:
 		panic("no searcher type specified")
 	}
@@ -166,6 +166,31 @@ func (s *Searcher) TrialClosed(requestID model.RequestID) ([]Operation, error) {
 	return operations, nil
 }
 
+// Continue resumes a search that has shut down with the budget of the provided searcher config. It
+// returns the operations for the trials that the search creates as a result.
+func (s *Searcher) Continue(config model.SearcherConfig) ([]Operation, error) {
+	method, ok := s.method.(continuableSearchMethod)
+	if !ok {
+		return nil, errors.New("search method does not support continuing")
+	}
+	operations, err := method.continueSearch(s.context(), config)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while continuing search method")
+	}
+	created := false
+	for _, op := range operations {
+		if _, ok := op.(Create); ok {
+			created = true
+		}
+	}
+	if !created {
+		return nil, errors.New("searcher config does not allow any more trials")
+	}
+	s.Shutdown = false
+	s.Record(operations)
+	return operations, nil
+}
+
 // Progress returns experiment progress as a float between 0.0 and 1.0.
 func (s *Searcher) Progress() float64 {
 	progress := s.method.progress(s.TotalUnitsCompleted)
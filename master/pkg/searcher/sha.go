@@ -93,6 +93,9 @@ type trialMetric struct {
 	Metric    float64         `json:"metric"`
 	// fields below used by asha.go.
 	Promoted bool `json:"promoted"`
+	// Objectives holds the values of all searcher metrics, oriented so that smaller is better, when
+	// the search has more than one.
+	Objectives []float64 `json:"objectives,omitempty"`
 }
 
 // rung describes a set of trials that are to be trained for the same number of units.
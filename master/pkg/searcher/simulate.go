@@ -66,6 +66,11 @@ type Simulation struct {
 	Seed    int64             `json:"seed"`
 }
 
+// metricsFunction returns the metrics that a simulated trial reports for a completed workload.
+type metricsFunction func(
+	requestID model.RequestID, trialID, opIdx int, operation Runnable,
+) (interface{}, error)
+
 // Simulate simulates the searcher.
 func Simulate(
 	s *Searcher, seed *int64, valFunc ValidationFunction, randomOrder bool, metricName string,
@@ -80,22 +85,35 @@ func Simulate(
 		random = rand.New(rand.NewSource(*seed))
 	}
 
+	err := simulate(s, &simulation, random, randomOrder,
+		func(_ model.RequestID, trialID, opIdx int, operation Runnable) (interface{}, error) {
+			return generateMetrics(random, trialID, opIdx, operation, valFunc, metricName)
+		})
+	return simulation, err
+}
+
+// simulate runs the searcher until it shuts down, recording the workloads that each trial runs in
+// the results of the simulation.
+func simulate(
+	s *Searcher, simulation *Simulation, random *rand.Rand, randomOrder bool,
+	metricsFunc metricsFunction,
+) error {
 	pending := make(map[model.RequestID][]Operation)
 	trialIDs := make(map[model.RequestID]int)
 	var requestIDs []model.RequestID
 	ops, err := s.InitialOperations()
 	if err != nil {
-		return simulation, err
+		return err
 	}
 
 	lastProgress := s.Progress()
 	if lastProgress != 0.0 {
-		return simulation, errors.Errorf("Initial searcher progress started at %f", lastProgress)
+		return errors.Errorf("Initial searcher progress started at %f", lastProgress)
 	}
 
 	shutdown, err := handleOperations(pending, &requestIDs, ops)
 	if err != nil {
-		return simulation, err
+		return err
 	}
 
 	nextTrialID := 1
@@ -103,7 +121,7 @@ func Simulate(
 	for !shutdown {
 		requestID, err := pickTrial(random, pending, requestIDs, randomOrder)
 		if err != nil {
-			return simulation, err
+			return err
 		}
 		operation := pending[requestID][0]
 		pending[requestID] = pending[requestID][1:]
@@ -114,19 +132,19 @@ func Simulate(
 			trialIDs[requestID] = nextTrialID
 			ops, err := s.TrialCreated(operation, nextTrialID)
 			if err != nil {
-				return simulation, err
+				return err
 			}
 			trialOpIdxs[requestID] = 0
 			shutdown, err = handleOperations(pending, &requestIDs, ops)
 			if err != nil {
-				return simulation, err
+				return err
 			}
 			nextTrialID++
 		case Runnable:
-			metrics, err := generateMetrics(
-				random, trialIDs[requestID], trialOpIdxs[requestID], operation, valFunc, metricName)
+			metrics, err := metricsFunc(
+				requestID, trialIDs[requestID], trialOpIdxs[requestID], operation)
 			if err != nil {
-				return simulation, err
+				return err
 			}
 			simulation.Results[requestID] = append(simulation.Results[requestID], operation)
 			if train, ok := operation.(Train); ok {
@@ -134,36 +152,36 @@ func Simulate(
 			}
 			ops, err := s.OperationCompleted(trialIDs[requestID], operation, metrics)
 			if err != nil {
-				return simulation, err
+				return err
 			}
 			trialOpIdxs[requestID]++
 			shutdown, err = handleOperations(pending, &requestIDs, ops)
 			if err != nil {
-				return simulation, err
+				return err
 			}
 		case Close:
 			delete(pending, requestID)
 			ops, err := s.TrialClosed(requestID)
 			if err != nil {
-				return simulation, err
+				return err
 			}
 			shutdown, err = handleOperations(pending, &requestIDs, ops)
 			if err != nil {
-				return simulation, err
+				return err
 			}
 		default:
-			return simulation, errors.Errorf("unexpected searcher operation: %T", operation)
+			return errors.Errorf("unexpected searcher operation: %T", operation)
 		}
 		if shutdown {
 			if len(pending) != 0 {
-				return simulation, errors.New("searcher shutdown prematurely")
+				return errors.New("searcher shutdown prematurely")
 			}
 			break
 		}
 
 		progress := s.Progress()
 		if progress < lastProgress {
-			return simulation, errors.Errorf(
+			return errors.Errorf(
 				"searcher progress dropped from %f%% to %f%%", lastProgress*100, progress*100)
 		}
 		lastProgress = progress
@@ -171,13 +189,13 @@ func Simulate(
 
 	lastProgress = s.Progress()
 	if lastProgress != 1.0 {
-		return simulation, errors.Errorf(
+		return errors.Errorf(
 			"searcher progress did not end at 100%%: %f%%", lastProgress*100)
 	}
 	if len(simulation.Results) != len(requestIDs) {
-		return simulation, errors.New("more trials created than completed")
+		return errors.New("more trials created than completed")
 	}
-	return simulation, nil
+	return nil
 }
 
 func handleOperations(
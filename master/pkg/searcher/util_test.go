@@ -410,3 +410,48 @@ func saveAndReload(method SearchMethod) error {
 	}
 	return nil
 }
+
+// runToShutdown drives a searcher through the given operations, and every operation that follows
+// from them, until the searcher shuts down. Each trial reports metric(trialID) as its validation
+// metric. It returns the workloads that each trial ran.
+func runToShutdown(
+	t *testing.T, s *Searcher, ops []Operation, metric func(trialID int) float64,
+) map[model.RequestID][]Runnable {
+	results := map[model.RequestID][]Runnable{}
+	pending := map[model.RequestID][]Operation{}
+	var requestIDs []model.RequestID
+	shutdown, err := handleOperations(pending, &requestIDs, ops)
+	assert.NilError(t, err)
+	for !shutdown {
+		requestID, err := pickTrial(nil, pending, requestIDs, false)
+		assert.NilError(t, err)
+		operation := pending[requestID][0]
+		pending[requestID] = pending[requestID][1:]
+		trialID, _ := s.TrialID(requestID)
+
+		var next []Operation
+		switch operation := operation.(type) {
+		case Create:
+			results[requestID] = []Runnable{}
+			next, err = s.TrialCreated(operation, len(s.TrialIDs)+1)
+		case Train:
+			results[requestID] = append(results[requestID], operation)
+			s.WorkloadCompleted(requestID, float64(operation.Length.Units))
+			next, err = s.OperationCompleted(trialID, operation, nil)
+		case Validate:
+			results[requestID] = append(results[requestID], operation)
+			next, err = s.OperationCompleted(trialID, operation, &workload.ValidationMetrics{
+				Metrics: map[string]interface{}{defaultMetric: metric(trialID)},
+			})
+		case Close:
+			delete(pending, requestID)
+			next, err = s.TrialClosed(requestID)
+		default:
+			t.Fatalf("unexpected searcher operation: %T", operation)
+		}
+		assert.NilError(t, err)
+		shutdown, err = handleOperations(pending, &requestIDs, next)
+		assert.NilError(t, err)
+	}
+	return results
+}
@@ -8,6 +8,10 @@ import (
 	"github.com/pkg/errors"
 )
 
+// ResentCompletedMessageType is the type of a CompletedMessage that a trial container resends
+// after reconnecting to the master, since the master may have missed it.
+const ResentCompletedMessageType = "WORKLOAD_COMPLETED_RESENT"
+
 // CompletedMessage is the wrapping message returned by the trial runner when a workload
 // is completed.
 type CompletedMessage struct {
@@ -0,0 +1,5 @@
+ALTER TABLE public.templates DROP COLUMN owner_id;
+
+ALTER TABLE public.users DROP COLUMN role;
+
+DROP TYPE public.user_role;
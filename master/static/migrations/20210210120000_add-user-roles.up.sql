@@ -0,0 +1,13 @@
+CREATE TYPE public.user_role AS ENUM (
+    'viewer',
+    'editor',
+    'admin'
+);
+
+ALTER TABLE public.users
+    ADD COLUMN role public.user_role NOT NULL DEFAULT 'editor';
+
+UPDATE public.users SET role = 'admin' WHERE admin;
+
+ALTER TABLE public.templates
+    ADD COLUMN owner_id integer NULL REFERENCES public.users(id);
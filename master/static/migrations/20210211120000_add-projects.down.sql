@@ -0,0 +1,6 @@
+ALTER TABLE public.templates DROP COLUMN project_id;
+ALTER TABLE public.models DROP COLUMN project_id;
+ALTER TABLE public.experiments DROP COLUMN project_id;
+
+DROP TABLE public.project_members;
+DROP TABLE public.projects;
@@ -0,0 +1,28 @@
+CREATE TABLE public.projects (
+    id integer NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    name character varying UNIQUE NOT NULL,
+    description character varying NOT NULL DEFAULT '',
+    owner_id integer NOT NULL REFERENCES public.users(id),
+    checkpoint_storage jsonb NULL,
+    resource_pool character varying NOT NULL DEFAULT '',
+    creation_time timestamp with time zone NOT NULL DEFAULT now(),
+
+    CONSTRAINT projects_pkey PRIMARY KEY (id)
+);
+
+CREATE TABLE public.project_members (
+    project_id integer NOT NULL REFERENCES public.projects(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+
+    CONSTRAINT project_members_pkey PRIMARY KEY (project_id, user_id)
+);
+
+ALTER TABLE public.experiments
+    ADD COLUMN project_id integer NULL REFERENCES public.projects(id);
+CREATE INDEX ix_experiments_project_id ON public.experiments USING btree (project_id);
+
+ALTER TABLE public.models
+    ADD COLUMN project_id integer NULL REFERENCES public.projects(id);
+
+ALTER TABLE public.templates
+    ADD COLUMN project_id integer NULL REFERENCES public.projects(id);
@@ -0,0 +1,14 @@
+CREATE TABLE public.api_tokens (
+    id integer NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    user_id integer NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    token_hash character varying UNIQUE NOT NULL,
+    description character varying NOT NULL DEFAULT '',
+    read_only boolean NOT NULL DEFAULT false,
+    expiry timestamp with time zone NULL,
+    creation_time timestamp with time zone NOT NULL DEFAULT now(),
+    last_used timestamp with time zone NULL,
+
+    CONSTRAINT api_tokens_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX ix_api_tokens_user_id ON public.api_tokens USING btree (user_id);
@@ -0,0 +1,18 @@
+CREATE TABLE public.audit_events (
+    id bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    time timestamp with time zone NOT NULL DEFAULT now(),
+    user_id integer NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    username character varying NOT NULL DEFAULT '',
+    method character varying NOT NULL,
+    resource_type character varying NOT NULL DEFAULT '',
+    resource_id character varying NOT NULL DEFAULT '',
+    request jsonb NULL,
+    success boolean NOT NULL,
+    error character varying NOT NULL DEFAULT '',
+
+    CONSTRAINT audit_events_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX ix_audit_events_time ON public.audit_events USING btree (time);
+CREATE INDEX ix_audit_events_resource
+    ON public.audit_events USING btree (resource_type, resource_id);
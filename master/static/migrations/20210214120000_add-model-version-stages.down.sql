@@ -0,0 +1,8 @@
+DROP TABLE public.model_aliases;
+
+ALTER TABLE public.model_versions
+    DROP COLUMN tags,
+    DROP COLUMN notes,
+    DROP COLUMN stage;
+
+DROP TYPE public.model_version_stage;
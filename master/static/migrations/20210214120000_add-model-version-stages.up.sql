@@ -0,0 +1,21 @@
+CREATE TYPE public.model_version_stage AS ENUM (
+    'NONE',
+    'STAGING',
+    'PRODUCTION',
+    'ARCHIVED'
+);
+
+ALTER TABLE public.model_versions
+    ADD COLUMN stage public.model_version_stage NOT NULL DEFAULT 'NONE',
+    ADD COLUMN notes character varying NOT NULL DEFAULT '',
+    ADD COLUMN tags jsonb NOT NULL DEFAULT '[]';
+
+CREATE TABLE public.model_aliases (
+    model_name character varying NOT NULL,
+    alias character varying NOT NULL,
+    version integer NOT NULL,
+
+    CONSTRAINT model_aliases_pkey PRIMARY KEY (model_name, alias),
+    FOREIGN KEY (model_name, version)
+        REFERENCES public.model_versions(model_name, version) ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+ALTER TABLE public.checkpoints DROP COLUMN size;
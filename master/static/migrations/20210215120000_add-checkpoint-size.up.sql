@@ -0,0 +1,8 @@
+ALTER TABLE public.checkpoints ADD COLUMN size bigint NOT NULL DEFAULT 0;
+
+UPDATE public.checkpoints
+SET size = (
+    SELECT coalesce(sum(value::bigint), 0) FROM jsonb_each_text(resources)
+    WHERE value ~ '^[0-9]{1,18}$'
+)
+WHERE resources IS NOT NULL AND jsonb_typeof(resources) = 'object';
@@ -0,0 +1,2 @@
+ALTER TABLE public.users
+    ADD COLUMN login_provider text NULL;
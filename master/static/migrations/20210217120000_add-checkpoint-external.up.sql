@@ -0,0 +1 @@
+ALTER TABLE public.checkpoints ADD COLUMN external boolean NOT NULL DEFAULT false;
@@ -1,7 +1,10 @@
 #!/bin/bash
 
-STDOUT_FILE=/run/determined/train/logs/stdout.log
-STDERR_FILE=/run/determined/train/logs/stderr.log
+# DET_CONTAINER_ROOT is unset in containers. Agents that run tasks as plain
+# processes set it to the directory that the files of the task were unpacked
+# into, so that the paths below resolve to that directory.
+STDOUT_FILE=${DET_CONTAINER_ROOT}/run/determined/train/logs/stdout.log
+STDERR_FILE=${DET_CONTAINER_ROOT}/run/determined/train/logs/stderr.log
 
 mkdir -p "$(dirname "$STDOUT_FILE")" "$(dirname "$STDERR_FILE")"
 
@@ -26,9 +29,9 @@ fi
 set -e
 set -x
 
-WORKING_DIR="/run/determined/workdir"
+WORKING_DIR="${DET_CONTAINER_ROOT}/run/determined/workdir"
 STARTUP_HOOK="startup-hook.sh"
-export PATH="/run/determined/pythonuserbase/bin:$PATH"
+export PATH="${DET_CONTAINER_ROOT}/run/determined/pythonuserbase/bin:$PATH"
 
 # If HOME is not explicitly set for a container, libcontainer (Docker) will
 # try to guess it by reading /etc/password directly, which will not work with
@@ -41,7 +44,7 @@ if [ "$HOME" = "/" ] ; then
     export HOME
 fi
 
-python3.6 -m pip install -q --user /opt/determined/wheels/determined*.whl
+python3.6 -m pip install -q --user ${DET_CONTAINER_ROOT}/opt/determined/wheels/determined*.whl
 
 cd ${WORKING_DIR} && test -f "${STARTUP_HOOK}" && source "${STARTUP_HOOK}"
 exec python3.6 -m determined.exec.harness "$@"
@@ -2,8 +2,8 @@
 
 set -e
 
-export PATH="/run/determined/pythonuserbase/bin:$PATH"
+export PATH="${DET_CONTAINER_ROOT}/run/determined/pythonuserbase/bin:$PATH"
 
-python3.6 -m pip install -q --user /opt/determined/wheels/determined*.whl
+python3.6 -m pip install -q --user ${DET_CONTAINER_ROOT}/opt/determined/wheels/determined*.whl
 
 exec python3.6 -m determined.exec.gc_checkpoints "$@"
@@ -0,0 +1,11 @@
+SELECT
+    t.id,
+    u.username,
+    t.description,
+    t.read_only,
+    t.expiry,
+    t.creation_time,
+    t.last_used
+FROM api_tokens t
+JOIN users u ON t.user_id = u.id
+WHERE t.id = $1;
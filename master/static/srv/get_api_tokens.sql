@@ -0,0 +1,12 @@
+SELECT
+    t.id,
+    u.username,
+    t.description,
+    t.read_only,
+    t.expiry,
+    t.creation_time,
+    t.last_used
+FROM api_tokens t
+JOIN users u ON t.user_id = u.id
+WHERE t.user_id = $1
+ORDER BY t.id;
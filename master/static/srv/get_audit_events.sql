@@ -0,0 +1,17 @@
+SELECT
+    e.id,
+    e.time,
+    e.username,
+    e.method,
+    e.resource_type,
+    e.resource_id,
+    e.request,
+    e.success,
+    e.error
+FROM audit_events e
+WHERE ($1::timestamptz IS NULL OR e.time >= $1)
+    AND ($2::timestamptz IS NULL OR e.time < $2)
+    AND ($3 = '' OR e.username = $3)
+    AND ($4 = '' OR e.resource_type = $4)
+    AND ($5 = '' OR e.resource_id = $5)
+ORDER BY e.id;
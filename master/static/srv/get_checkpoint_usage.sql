@@ -0,0 +1,36 @@
+WITH c AS (
+  SELECT
+    c.size,
+    e.id AS experiment_id,
+    COALESCE(u.username, '') AS username,
+    COALESCE(e.config->'checkpoint_storage'->>'type', '') AS storage_type,
+    (e.config->'checkpoint_storage'->>'max_storage_bytes')::bigint AS quota_bytes
+  FROM checkpoints c
+  JOIN trials t ON c.trial_id = t.id
+  JOIN experiments e ON t.experiment_id = e.id
+  LEFT JOIN users u ON e.owner_id = u.id
+  WHERE c.state = 'COMPLETED' AND NOT c.external
+)
+SELECT
+  (SELECT COALESCE(jsonb_agg(x ORDER BY x.experiment_id), '[]'::jsonb) FROM (
+    SELECT experiment_id, username, storage_type, jsonb_build_object(
+      'size_bytes', sum(size),
+      'num_checkpoints', count(*),
+      'quota_bytes', max(quota_bytes)
+    ) AS usage
+    FROM c GROUP BY experiment_id, username, storage_type
+  ) x) AS experiments,
+  (SELECT COALESCE(jsonb_agg(x ORDER BY x.username), '[]'::jsonb) FROM (
+    SELECT username, jsonb_build_object(
+      'size_bytes', sum(size),
+      'num_checkpoints', count(*)
+    ) AS usage
+    FROM c GROUP BY username
+  ) x) AS users,
+  (SELECT COALESCE(jsonb_agg(x ORDER BY x.storage_type), '[]'::jsonb) FROM (
+    SELECT storage_type, jsonb_build_object(
+      'size_bytes', sum(size),
+      'num_checkpoints', count(*)
+    ) AS usage
+    FROM c GROUP BY storage_type
+  ) x) AS storage_backends
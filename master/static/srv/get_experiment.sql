@@ -9,7 +9,8 @@ SELECT
     (SELECT COUNT(*) FROM trials t WHERE e.id = t.experiment_id) AS num_trials,
     e.archived AS archived,
     COALESCE(e.progress, 0) AS progress,
-    u.username AS username
+    u.username AS username,
+    e.project_id AS project_id
 FROM
     experiments e
 JOIN users u ON e.owner_id = u.id
@@ -10,7 +10,8 @@ WITH filtered_exps AS (
         (SELECT COUNT(*) FROM trials t WHERE e.id = t.experiment_id) AS num_trials,
         e.archived AS archived,
         COALESCE(e.progress, 0) AS progress,
-        u.username AS username
+        u.username AS username,
+        e.project_id AS project_id
     FROM experiments e
     JOIN users u ON e.owner_id = u.id
     WHERE
@@ -22,6 +23,7 @@ WITH filtered_exps AS (
                 OR string_to_array($4, ',') <@ ARRAY(SELECT jsonb_array_elements_text(e.config->'labels'))
             )
         AND ($5 = '' OR POSITION($5 IN (e.config->>'description')) > 0)
+        AND ($8 = 0 OR e.project_id = $8)
 ), page_info AS (
     SELECT public.page_info((SELECT COUNT(*) AS count FROM filtered_exps), $6, $7) AS page_info
 )
@@ -1 +1 @@
-SELECT name, description, metadata, creation_time, last_updated_time FROM models WHERE name = $1;
+SELECT name, description, metadata, creation_time, last_updated_time, project_id FROM models WHERE name = $1;
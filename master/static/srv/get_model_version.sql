@@ -1,5 +1,5 @@
 WITH mv AS (
-  SELECT version, checkpoint_uuid
+  SELECT version, checkpoint_uuid, creation_time, last_updated_time, stage, notes, tags
     FROM model_versions
     WHERE model_name = $1 AND version = $2
 ),
@@ -34,6 +34,18 @@ c AS (
 SELECT
     to_json(c) AS checkpoint,
     to_json(m) AS model,
-    version AS version,
-    creation_time
+    mv.version AS version,
+    mv.creation_time,
+    mv.last_updated_time,
+    'STAGE_' || mv.stage AS stage,
+    mv.notes,
+    mv.tags,
+    array_to_json(ARRAY(
+        SELECT a.alias FROM model_aliases a
+        WHERE a.model_name = $1 AND a.version = mv.version
+        ORDER BY a.alias
+    )) AS aliases,
+    c.experiment_id,
+    c.trial_id,
+    c.hparams
     FROM c, m, mv
@@ -1,7 +1,7 @@
 WITH mv AS (
-  SELECT version, checkpoint_uuid
+  SELECT version, checkpoint_uuid, creation_time, last_updated_time, stage, notes, tags
     FROM model_versions
-    WHERE model_name = $1
+    WHERE model_name = $1 AND ($2 = '' OR stage::text = $2)
 ),
 m AS (
   Select * FROM models WHERE name = $1
@@ -33,6 +33,20 @@ c AS (
 )
 SELECT
     to_json(c) AS checkpoint,
-    version AS version,
-    creation_time
-    FROM c, m, mv
+    mv.version AS version,
+    mv.creation_time,
+    mv.last_updated_time,
+    'STAGE_' || mv.stage AS stage,
+    mv.notes,
+    mv.tags,
+    array_to_json(ARRAY(
+        SELECT a.alias FROM model_aliases a
+        WHERE a.model_name = $1 AND a.version = mv.version
+        ORDER BY a.alias
+    )) AS aliases,
+    c.experiment_id,
+    c.trial_id,
+    c.hparams
+    FROM mv
+    JOIN c ON c.uuid = mv.checkpoint_uuid::text
+    CROSS JOIN m
@@ -0,0 +1,17 @@
+SELECT
+    p.id,
+    p.name,
+    p.description,
+    u.username AS owner,
+    array_to_json(ARRAY(
+        SELECT mu.username FROM project_members m
+        JOIN users mu ON m.user_id = mu.id
+        WHERE m.project_id = p.id
+        ORDER BY mu.username
+    )) AS members,
+    p.checkpoint_storage,
+    p.resource_pool,
+    p.creation_time
+FROM projects p
+JOIN users u ON p.owner_id = u.id
+WHERE p.id = $1;
@@ -1 +1 @@
-SELECT name, config FROM templates WHERE name = $1;
+SELECT name, config, project_id FROM templates WHERE name = $1;
@@ -1 +1 @@
-SELECT name, config FROM templates
+SELECT name, config, project_id FROM templates
@@ -1,3 +1,3 @@
-INSERT INTO models (name, description, metadata, creation_time, last_updated_time)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING name, description, metadata, creation_time, last_updated_time
+INSERT INTO models (name, description, metadata, creation_time, last_updated_time, project_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING name, description, metadata, creation_time, last_updated_time, project_id
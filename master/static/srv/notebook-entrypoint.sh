@@ -2,7 +2,7 @@
 
 set -e
 
-export PATH="/run/determined/pythonuserbase/bin:$PATH"
+export PATH="${DET_CONTAINER_ROOT}/run/determined/pythonuserbase/bin:$PATH"
 
 # If HOME is not explicitly set for a container, libcontainer (Docker) will
 # try to guess it by reading /etc/password directly, which will not work with
@@ -15,6 +15,6 @@ if [ "$HOME" = "/" ] ; then
     export HOME
 fi
 
-python3.6 -m pip install -q --user /opt/determined/wheels/determined*.whl
+python3.6 -m pip install -q --user ${DET_CONTAINER_ROOT}/opt/determined/wheels/determined*.whl
 
-jupyter lab --config /run/determined/workdir/jupyter-conf.py --port=${NOTEBOOK_PORT}
+jupyter lab --config ${DET_CONTAINER_ROOT}/run/determined/workdir/jupyter-conf.py --port=${NOTEBOOK_PORT}
@@ -1,4 +1,4 @@
-INSERT INTO templates (name, config)
-VALUES ($1, $2)
+INSERT INTO templates (name, config, owner_id, project_id)
+VALUES ($1, $2, $3, $4)
 ON CONFLICT (name) DO UPDATE SET config=$2
-RETURNING name, config
+RETURNING name, config, project_id
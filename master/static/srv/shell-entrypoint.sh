@@ -2,13 +2,13 @@
 
 set -e
 
-export PATH="/run/determined/pythonuserbase/bin:$PATH"
+export PATH="${DET_CONTAINER_ROOT}/run/determined/pythonuserbase/bin:$PATH"
 
 # Unlike trial and notebook entrypoints, the HOME directory does not need to be
 # modified in this entrypoint because the HOME in the user's ssh session is set
 # by sshd at a later time.
 
-python3.6 -m pip install -q --user /opt/determined/wheels/determined*.whl
+python3.6 -m pip install -q --user ${DET_CONTAINER_ROOT}/opt/determined/wheels/determined*.whl
 
 # Prepend each key in authorized_keys with a set of environment="KEY=VALUE"
 # options to inject the entire docker environment into the eventual ssh
@@ -45,8 +45,8 @@ options="$(
 # In k8s, the files we inject into the container are injected via individual
 # file-level bind mounts, which are effectively read-only in docker, so we are
 # unable to edit authorized_keys in place.
-unmodified="/run/determined/ssh/authorized_keys_unmodified"
-modified="/run/determined/ssh/authorized_keys"
+unmodified="${DET_CONTAINER_ROOT}/run/determined/ssh/authorized_keys_unmodified"
+modified="${DET_CONTAINER_ROOT}/run/determined/ssh/authorized_keys"
 sed -e "s/^/$options /" "$unmodified" > "$modified"
 
 exec /usr/sbin/sshd "$@"
@@ -3,11 +3,11 @@
 set -e
 set -x
 
-WORKING_DIR="/run/determined/workdir"
+WORKING_DIR="${DET_CONTAINER_ROOT}/run/determined/workdir"
 STARTUP_HOOK="startup-hook.sh"
-export PATH="/run/determined/pythonuserbase/bin:$PATH"
+export PATH="${DET_CONTAINER_ROOT}/run/determined/pythonuserbase/bin:$PATH"
 
-python3.6 -m pip install -q --user /opt/determined/wheels/determined*.whl
+python3.6 -m pip install -q --user ${DET_CONTAINER_ROOT}/opt/determined/wheels/determined*.whl
 
 cd ${WORKING_DIR} && test -f "${STARTUP_HOOK}" && source "${STARTUP_HOOK}"
 
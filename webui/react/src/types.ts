@@ -207,6 +207,7 @@ export enum ExperimentSearcherName {
   AdaptiveAdvanced = 'adaptive',
   AdaptiveAsha = 'adaptive_asha',
   AdaptiveSimple = 'adaptive_simple',
+  Bayesian = 'bayesian',
   Grid = 'grid',
   Pbt = 'pbt',
   Random = 'random',
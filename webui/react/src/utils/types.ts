@@ -104,6 +104,7 @@ export const V1ResourcePoolTypeToLabel: {[key in V1ResourcePoolType]: string} =
 };
 
 export const V1SchedulerTypeToLabel : {[key in V1SchedulerType]: string} = {
+  [V1SchedulerType.BACKFILL]: 'Backfill',
   [V1SchedulerType.FAIRSHARE]: 'Fairshare',
   [V1SchedulerType.KUBERNETES]: 'Kubernetes',
   [V1SchedulerType.PRIORITY]: 'Priority',